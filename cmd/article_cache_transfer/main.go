@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/ray31245/seo_cluster/pkg/db"
+	articleCacheManager "github.com/ray31245/seo_cluster/service/article_cache_manager"
+	"github.com/ray31245/seo_cluster/service/article_cache_manager/model"
+)
+
+// usage:
+//
+//	article_cache_transfer -export article_cache.jsonl
+//	article_cache_transfer -import article_cache.csv -duplicate overwrite
+func main() {
+	exportPath := flag.String("export", "", "export article cache to file")
+	importPath := flag.String("import", "", "import article cache from file")
+	format := flag.String("format", "", "jsonl or csv, inferred from file name if empty")
+	duplicate := flag.String("duplicate", "skip", "skip, overwrite or allow duplicate article cache when import")
+	flag.Parse()
+
+	if (*exportPath == "") == (*importPath == "") {
+		flag.Usage()
+		os.Exit(2) //nolint:mnd // exit code of invalid usage
+	}
+
+	dsn := "publish_manager.db"
+	if s, ok := os.LookupEnv("DSN"); ok {
+		dsn = s
+	}
+
+	publishDB, err := db.NewDB(dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer publishDB.Close()
+
+	articleCacheDAO, err := publishDB.NewArticleCacheDAO()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	manager := articleCacheManager.NewArticleCacheManager(articleCacheDAO)
+
+	if *exportPath != "" {
+		exportArticleCache(manager, *exportPath, *format)
+	} else {
+		importArticleCache(manager, *importPath, *format, *duplicate)
+	}
+}
+
+func exportArticleCache(manager *articleCacheManager.ArticleCacheManager, path string, format string) {
+	f, err := articleCacheManager.ParseFormat(format, path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	count, err := manager.ExportArticleCache(file, f)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("exported %d article cache to %s", count, path)
+}
+
+func importArticleCache(manager *articleCacheManager.ArticleCacheManager, path string, format string, duplicate string) {
+	f, err := articleCacheManager.ParseFormat(format, path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mode, err := articleCacheManager.ParseDuplicateMode(duplicate)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	progress, err := manager.ImportArticleCache(context.Background(), file, f, mode, func(p model.ImportProgress) {
+		log.Printf("processed %d, created %d, overwrote %d, skipped %d, failed %d", p.Processed, p.Created, p.Overwrote, p.Skipped, p.Failed)
+	})
+
+	for _, e := range progress.Errors {
+		log.Println(e)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ray31245/seo_cluster/pkg/db"
 	"github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/pkg/secret"
 )
 
 func main() {
@@ -15,6 +16,15 @@ func main() {
 		panic(err)
 	}
 
+	keyring, err := secret.LoadKeyring()
+	if err != nil {
+		panic(err)
+	}
+
+	if keyring != nil {
+		db.UseSecretKeyring(keyring)
+	}
+
 	userDAO, err := db.NewCommentUserDAO()
 	if err != nil {
 		panic(err)
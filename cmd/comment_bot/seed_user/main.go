@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/ray31245/seo_cluster/pkg/db"
+	"github.com/ray31245/seo_cluster/pkg/secret"
 	zblogapi "github.com/ray31245/seo_cluster/pkg/z_blog_api"
 	zModel "github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
 )
@@ -14,6 +15,15 @@ func main() {
 		panic(err)
 	}
 
+	keyring, err := secret.LoadKeyring()
+	if err != nil {
+		panic(err)
+	}
+
+	if keyring != nil {
+		db.UseSecretKeyring(keyring)
+	}
+
 	userDAO, err := db.NewCommentUserDAO()
 	if err != nil {
 		panic(err)
@@ -10,6 +10,8 @@ import (
 
 	aiAssist "github.com/ray31245/seo_cluster/pkg/ai_assist"
 	model "github.com/ray31245/seo_cluster/pkg/ai_assist/model"
+	clientpool "github.com/ray31245/seo_cluster/pkg/client_pool"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
 	zBlogApi "github.com/ray31245/seo_cluster/pkg/z_blog_api"
 	zModel "github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
 )
@@ -24,13 +26,14 @@ func main() {
 	defer file.Close()
 
 	token := os.Getenv("AI_ASSIST_TOKEN")
+	lang := model.Language(os.Getenv("LANGUAGE"))
 
 	aiAssistClient, err := aiAssist.NewAIAssist(ctx, token, false)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	client := zBlogApi.NewZBlogAPI().NewAnonymousClient(ctx, "https://www.example.com/")
+	client := zBlogApi.NewZBlogAPI(clientpool.Options{}, httpclient.NewDefaultFactory()).NewAnonymousClient(ctx, "https://www.example.com/")
 
 	count, err := client.GetCountOfArticle(ctx, zModel.ListArticleRequest{})
 	if err != nil {
@@ -53,7 +56,7 @@ func main() {
 			retryMax := 300
 
 			for {
-				keywords, err = aiAssistClient.FindKeyWords(ctx, []byte(art.Content))
+				keywords, err = aiAssistClient.FindKeyWords(ctx, lang, []byte(art.Content))
 				if err != nil && retryMax > 0 {
 					log.Println(err)
 
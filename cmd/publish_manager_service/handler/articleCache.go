@@ -1,15 +1,22 @@
 package handler
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ray31245/seo_cluster/cmd/publish_manager_service/helper"
 	"github.com/ray31245/seo_cluster/cmd/publish_manager_service/model"
 	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
 	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/pkg/logger"
 	articleCacheManager "github.com/ray31245/seo_cluster/service/article_cache_manager"
+	articleCacheModel "github.com/ray31245/seo_cluster/service/article_cache_manager/model"
 )
 
 type articleCacheHandler struct {
@@ -73,6 +80,35 @@ func (a *articleCacheHandler) ListPublishLaterArticleCacheHandler(c *gin.Context
 	})
 }
 
+// ListExpiredArticleCacheHandler report the articles dropped by expiry
+func (a *articleCacheHandler) ListExpiredArticleCacheHandler(c *gin.Context) {
+	titleKeywords, contentKeywords, operator := helper.ParseArticleCacheFuzzySearchQuery(c)
+
+	page, pageSize, err := helper.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	articles, totalPage, totalRows, err := a.articleCacheManager.ListExpiredArticleCache(titleKeywords, contentKeywords, dbModel.Operator(operator), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"articles":   articles,
+		"total_page": totalPage,
+		"total_rows": totalRows,
+	})
+}
+
 func (a *articleCacheHandler) ListEditAbleArticleCacheHandler(c *gin.Context) {
 	titleKeywords, contentKeywords, operator := helper.ParseArticleCacheFuzzySearchQuery(c)
 
@@ -121,7 +157,7 @@ func (a *articleCacheHandler) UpdateArticleCacheStatusHandler(c *gin.Context) {
 		return
 	}
 
-	err = a.articleCacheManager.UpdateArticleCacheStatus(req.IDs, dbModel.ArticleCacheStatus(req.Status))
+	err = a.articleCacheManager.UpdateArticleCacheStatus(req.IDs, dbModel.ArticleCacheStatus(req.Status), helper.GetLoginUserID(c))
 	if errors.Is(err, dbErr.ErrInvalidArticleCacheStatus) {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": err.Error(),
@@ -141,6 +177,74 @@ func (a *articleCacheHandler) UpdateArticleCacheStatusHandler(c *gin.Context) {
 	})
 }
 
+func (a *articleCacheHandler) UpdateArticleCachePriorityHandler(c *gin.Context) {
+	req := model.UpdateArticleCachePriorityRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	if len(req.IDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "ids is empty",
+		})
+
+		return
+	}
+
+	err = a.articleCacheManager.UpdateArticleCachePriority(req.IDs, req.Priority)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
+func (a *articleCacheHandler) UpdateArticleCacheExpiresAtHandler(c *gin.Context) {
+	req := model.UpdateArticleCacheExpiresAtRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	if len(req.IDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "ids is empty",
+		})
+
+		return
+	}
+
+	err = a.articleCacheManager.UpdateArticleCacheExpiresAt(req.IDs, req.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
 func (a *articleCacheHandler) EditArticleCacheHandler(c *gin.Context) {
 	req := model.EditArticleCacheRequest{}
 
@@ -169,7 +273,7 @@ func (a *articleCacheHandler) EditArticleCacheHandler(c *gin.Context) {
 		return
 	}
 
-	err = a.articleCacheManager.EditArticleCache(req.ID, req.Title, req.Content)
+	err = a.articleCacheManager.EditArticleCache(req.ID, req.Title, req.Content, helper.GetLoginUserID(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": err.Error(),
@@ -216,3 +320,190 @@ func (a *articleCacheHandler) DeleteArticleCacheHandler(c *gin.Context) {
 		"message": "ok",
 	})
 }
+
+func (a *articleCacheHandler) ExportArticleCacheHandler(c *gin.Context) {
+	format, err := articleCacheManager.ParseFormat(c.Query("format"), "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	contentType := "application/x-ndjson"
+	if format == articleCacheModel.FormatCSV {
+		contentType = "text/csv"
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=article_cache_%s.%s", time.Now().Format("20060102150405"), format))
+	c.Status(http.StatusOK)
+
+	// the response is streaming, error can only be logged after header is sent
+	count, err := a.articleCacheManager.ExportArticleCache(c.Writer, format)
+	if err != nil {
+		slog.ErrorContext(c, "ExportArticleCacheHandler", logger.Err(err))
+
+		return
+	}
+
+	slog.InfoContext(c, "exported article cache", slog.Int("count", count))
+}
+
+func (a *articleCacheHandler) ImportArticleCacheHandler(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	format, err := articleCacheManager.ParseFormat(c.Query("format"), fileHeader.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	mode, err := articleCacheManager.ParseDuplicateMode(c.Query("duplicate"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	// save the upload file, it will be imported in background after the request is done
+	tmp, err := os.CreateTemp("", "article_cache_import_*")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+	tmp.Close()
+
+	err = c.SaveUploadedFile(fileHeader, tmp.Name())
+	if err != nil {
+		os.Remove(tmp.Name())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	// gin.Context is reused by other requests after the handler returns, only the request context is kept by the job
+	jobID := a.articleCacheManager.StartImportJob(context.WithoutCancel(c.Request.Context()), tmp.Name(), format, mode)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+		"job_id":  jobID,
+	})
+}
+
+func (a *articleCacheHandler) GetImportArticleCacheJobHandler(c *gin.Context) {
+	progress, err := a.articleCacheManager.GetImportJob(c.Param("jobID"))
+	if errors.Is(err, articleCacheManager.ErrImportJobNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"progress": progress,
+	})
+}
+
+func (a *articleCacheHandler) ListArticleCacheRevisionsHandler(c *gin.Context) {
+	page, pageSize, err := helper.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	revisions, totalPage, totalRows, err := a.articleCacheManager.ListArticleCacheRevisions(c.Param("articleID"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revisions":  revisions,
+		"total_page": totalPage,
+		"total_rows": totalRows,
+	})
+}
+
+// DiffArticleCacheRevisionHandler diff revision "from" with revision "to", or with the current article if "to" is empty
+func (a *articleCacheHandler) DiffArticleCacheRevisionHandler(c *gin.Context) {
+	from := c.Query("from")
+	if from == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "from is empty",
+		})
+
+		return
+	}
+
+	diff, err := a.articleCacheManager.DiffArticleCacheRevision(from, c.Query("to"))
+	if dbErr.IsNotfoundErr(err) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"diff": diff,
+	})
+}
+
+func (a *articleCacheHandler) RestoreArticleCacheRevisionHandler(c *gin.Context) {
+	err := a.articleCacheManager.RestoreArticleCacheRevision(c.Param("revisionID"), helper.GetLoginUserID(c))
+	if dbErr.IsNotfoundErr(err) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
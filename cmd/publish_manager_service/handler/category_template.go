@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ray31245/seo_cluster/cmd/publish_manager_service/model"
+	cmsdriver "github.com/ray31245/seo_cluster/pkg/cms_driver"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	sitemanager "github.com/ray31245/seo_cluster/service/site_manager"
+)
+
+type CategoryTemplateHandler struct {
+	sitemanager *sitemanager.SiteManager
+}
+
+func NewCategoryTemplateHandler(sitemanager *sitemanager.SiteManager) *CategoryTemplateHandler {
+	return &CategoryTemplateHandler{
+		sitemanager: sitemanager,
+	}
+}
+
+func categoryTemplateErrStatus(err error) int {
+	switch {
+	case errors.Is(err, sitemanager.ErrCategoryTemplateNotFound), errors.Is(err, sitemanager.ErrSiteNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, sitemanager.ErrInvalidCategoryTemplate), errors.Is(err, cmsdriver.ErrOperationNotSupport):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *CategoryTemplateHandler) CreateCategoryTemplateHandler(c *gin.Context) {
+	req := model.CategoryTemplateRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "CreateCategoryTemplateHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	template, err := h.sitemanager.CreateCategoryTemplate(req.Name, req.ToDBItems())
+	if err != nil {
+		slog.ErrorContext(c, "CreateCategoryTemplateHandler", logger.Err(err))
+		c.JSON(categoryTemplateErrStatus(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"template": template,
+	})
+}
+
+func (h *CategoryTemplateHandler) ListCategoryTemplatesHandler(c *gin.Context) {
+	templates, err := h.sitemanager.ListCategoryTemplates()
+	if err != nil {
+		slog.ErrorContext(c, "ListCategoryTemplatesHandler", logger.Err(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+	})
+}
+
+func (h *CategoryTemplateHandler) GetCategoryTemplateHandler(c *gin.Context) {
+	template, err := h.sitemanager.GetCategoryTemplate(c.Param("id"))
+	if err != nil {
+		slog.ErrorContext(c, "GetCategoryTemplateHandler", logger.Err(err))
+		c.JSON(categoryTemplateErrStatus(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"template": template,
+	})
+}
+
+func (h *CategoryTemplateHandler) UpdateCategoryTemplateHandler(c *gin.Context) {
+	req := model.CategoryTemplateRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "UpdateCategoryTemplateHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	err = h.sitemanager.UpdateCategoryTemplate(c.Param("id"), req.Name, req.ToDBItems())
+	if err != nil {
+		slog.ErrorContext(c, "UpdateCategoryTemplateHandler", logger.Err(err))
+		c.JSON(categoryTemplateErrStatus(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
+func (h *CategoryTemplateHandler) DeleteCategoryTemplateHandler(c *gin.Context) {
+	err := h.sitemanager.DeleteCategoryTemplate(c.Param("id"))
+	if err != nil {
+		slog.ErrorContext(c, "DeleteCategoryTemplateHandler", logger.Err(err))
+		c.JSON(categoryTemplateErrStatus(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
+// ApplyCategoryTemplateHandler create the missing categories of template in site and report what was created or existed
+func (h *CategoryTemplateHandler) ApplyCategoryTemplateHandler(c *gin.Context) {
+	req := model.ApplyCategoryTemplateRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "ApplyCategoryTemplateHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	if req.TemplateID == "" {
+		slog.WarnContext(c, "ApplyCategoryTemplateHandler: data is not complete")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", "data is not complete"),
+		})
+
+		return
+	}
+
+	res, err := h.sitemanager.ApplyCategoryTemplate(c, c.Param("siteID"), req.TemplateID)
+	if err != nil {
+		slog.ErrorContext(c, "ApplyCategoryTemplateHandler", logger.Err(err))
+		c.JSON(categoryTemplateErrStatus(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+			"data":    res,
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+		"data":    res,
+	})
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	clientpool "github.com/ray31245/seo_cluster/pkg/client_pool"
+	zModel "github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
+)
+
+// PoolStatser is the api keeping the clients of sites in pool
+type PoolStatser interface {
+	PoolStats() clientpool.Stats
+}
+
+// ZBlogClientStatser is the api reporting the login state of zblog clients
+type ZBlogClientStatser interface {
+	ClientStats() map[uuid.UUID]zModel.ClientStats
+}
+
+type ClientPoolHandler struct {
+	// pools key is cms type
+	pools        map[string]PoolStatser
+	zblogClients ZBlogClientStatser
+}
+
+func NewClientPoolHandler(pools map[string]PoolStatser, zblogClients ZBlogClientStatser) *ClientPoolHandler {
+	return &ClientPoolHandler{
+		pools:        pools,
+		zblogClients: zblogClients,
+	}
+}
+
+func (h *ClientPoolHandler) GetClientPoolStatsHandler(c *gin.Context) {
+	stats := make(map[string]clientpool.Stats, len(h.pools))
+	for cmsType, pool := range h.pools {
+		stats[cmsType] = pool.PoolStats()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+		"data":    stats,
+	})
+}
+
+func (h *ClientPoolHandler) GetZBlogClientStatsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+		"data":    h.zblogClients.ClientStats(),
+	})
+}
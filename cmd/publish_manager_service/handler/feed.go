@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ray31245/seo_cluster/cmd/publish_manager_service/model"
+	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	feedManager "github.com/ray31245/seo_cluster/service/feed_manager"
+)
+
+type FeedHandler struct {
+	feedManager *feedManager.FeedManager
+}
+
+func NewFeedHandler(feedManager *feedManager.FeedManager) *FeedHandler {
+	return &FeedHandler{
+		feedManager: feedManager,
+	}
+}
+
+func feedErrStatus(err error) int {
+	switch {
+	case dbErr.IsNotfoundErr(err):
+		return http.StatusNotFound
+	case errors.Is(err, feedManager.ErrInvalidDefaultStatus), errors.Is(err, feedManager.ErrInvalidURL):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (f *FeedHandler) CreateFeedHandler(c *gin.Context) {
+	req := model.CreateFeedRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "CreateFeedHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	// check data
+	if req.URL == "" {
+		slog.WarnContext(c, "CreateFeedHandler: data is not complete")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", "data is not complete"),
+		})
+
+		return
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	feed, err := f.feedManager.CreateFeed(req.Name, req.URL, req.IntervalMinutes, dbModel.ArticleCacheStatus(req.DefaultStatus), req.IsRewrite, req.TTLHours, isActive)
+	if err != nil {
+		slog.ErrorContext(c, "CreateFeedHandler", logger.Err(err))
+		c.JSON(feedErrStatus(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"feed": feed,
+	})
+}
+
+func (f *FeedHandler) ListFeedsHandler(c *gin.Context) {
+	feeds, err := f.feedManager.ListFeeds()
+	if err != nil {
+		slog.ErrorContext(c, "ListFeedsHandler", logger.Err(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"feeds": feeds,
+	})
+}
+
+func (f *FeedHandler) GetFeedHandler(c *gin.Context) {
+	feed, err := f.feedManager.GetFeed(c.Param("id"))
+	if err != nil {
+		slog.ErrorContext(c, "GetFeedHandler", logger.Err(err))
+		c.JSON(feedErrStatus(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"feed": feed,
+	})
+}
+
+func (f *FeedHandler) UpdateFeedHandler(c *gin.Context) {
+	req := model.UpdateFeedRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "UpdateFeedHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	// check data
+	if req.URL == "" {
+		slog.WarnContext(c, "UpdateFeedHandler: data is not complete")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", "data is not complete"),
+		})
+
+		return
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	err = f.feedManager.UpdateFeed(c.Param("id"), req.Name, req.URL, req.IntervalMinutes, dbModel.ArticleCacheStatus(req.DefaultStatus), req.IsRewrite, req.TTLHours, isActive)
+	if err != nil {
+		slog.ErrorContext(c, "UpdateFeedHandler", logger.Err(err))
+		c.JSON(feedErrStatus(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
+func (f *FeedHandler) DeleteFeedHandler(c *gin.Context) {
+	err := f.feedManager.DeleteFeed(c.Param("id"))
+	if err != nil {
+		slog.ErrorContext(c, "DeleteFeedHandler", logger.Err(err))
+		c.JSON(feedErrStatus(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
+func (f *FeedHandler) FetchFeedHandler(c *gin.Context) {
+	newItems, err := f.feedManager.FetchFeed(c, c.Param("id"))
+	if err != nil {
+		slog.ErrorContext(c, "FetchFeedHandler", logger.Err(err))
+		c.JSON(feedErrStatus(err), gin.H{
+			"message":   fmt.Sprintf("error: %v", err),
+			"new_items": newItems,
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "ok",
+		"new_items": newItems,
+	})
+}
@@ -4,10 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/ray31245/seo_cluster/cmd/publish_manager_service/model"
+	cmsdriver "github.com/ray31245/seo_cluster/pkg/cms_driver"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	wordpressError "github.com/ray31245/seo_cluster/pkg/wordpress_api/error"
 	commentbot "github.com/ray31245/seo_cluster/service/comment_bot"
 	sitemanager "github.com/ray31245/seo_cluster/service/site_manager"
 	usermanager "github.com/ray31245/seo_cluster/service/user_manager"
@@ -15,11 +19,6 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-const (
-	minSrcLength = 1000
-	minArtLength = 500
-)
-
 // TODO: error handling on middleware
 
 type SiteHandler struct {
@@ -38,7 +37,7 @@ func (s *SiteHandler) AddSiteHandler(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "AddSiteHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -48,7 +47,7 @@ func (s *SiteHandler) AddSiteHandler(c *gin.Context) {
 
 	// check data
 	if req.URL == "" || req.UserName == "" || req.Password == "" {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "AddSiteHandler: data is not complete")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", "data is not complete"),
 		})
@@ -56,18 +55,36 @@ func (s *SiteHandler) AddSiteHandler(c *gin.Context) {
 		return
 	}
 
-	err = s.sitemanager.AddSite(c, req.CMSType, req.URL, req.UserName, req.Password, req.ExpectCategoryNum)
+	res, err := s.sitemanager.AddSite(c, req.CMSType, req.URL, req.UserName, req.Password, req.AuthMode, req.Language, req.ExpectCategoryNum, req.TemplateID)
 	if err != nil {
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
+		slog.ErrorContext(c, "AddSiteHandler", logger.Err(err))
+
+		errCode := http.StatusInternalServerError
+		if errors.Is(err, sitemanager.ErrLanguageNotSupport) || errors.Is(err, cmsdriver.ErrCMSTypeNotSupport) ||
+			errors.Is(err, wordpressError.ErrAuthModeNotSupport) {
+			errCode = http.StatusBadRequest
+		} else if errors.Is(err, sitemanager.ErrCategoryTemplateNotFound) {
+			errCode = http.StatusNotFound
+		}
+
+		c.JSON(errCode, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
 
 		return
 	}
 
+	if req.TemplateID == "" {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "ok",
+		})
+
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "ok",
+		"data":    res,
 	})
 }
 
@@ -76,7 +93,7 @@ func (s *SiteHandler) DeleteSiteHandler(c *gin.Context) {
 
 	err := s.sitemanager.DeleteSite(id)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "DeleteSiteHandler", logger.Err(err))
 
 		errCode := http.StatusInternalServerError
 		if errors.Is(err, sitemanager.ErrSiteNotFound) {
@@ -101,7 +118,7 @@ func (s *SiteHandler) UpdateSiteHandler(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "UpdateSiteHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -111,7 +128,7 @@ func (s *SiteHandler) UpdateSiteHandler(c *gin.Context) {
 
 	// check data
 	if req.SiteID == "" {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "UpdateSiteHandler: data is not complete")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", "data is not complete"),
 		})
@@ -119,13 +136,15 @@ func (s *SiteHandler) UpdateSiteHandler(c *gin.Context) {
 		return
 	}
 
-	err = s.sitemanager.UpdateSite(c, req.SiteID, req.URL, req.UserName, req.Password)
+	err = s.sitemanager.UpdateSite(c, req.SiteID, req.URL, req.UserName, req.Password, req.AuthMode, req.Language)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "UpdateSiteHandler", logger.Err(err))
 
 		errCode := http.StatusInternalServerError
 		if errors.Is(err, sitemanager.ErrSiteNotFound) {
 			errCode = http.StatusNotFound
+		} else if errors.Is(err, sitemanager.ErrLanguageNotSupport) || errors.Is(err, wordpressError.ErrAuthModeNotSupport) {
+			errCode = http.StatusBadRequest
 		}
 
 		c.JSON(errCode, gin.H{
@@ -143,7 +162,7 @@ func (s *SiteHandler) UpdateSiteHandler(c *gin.Context) {
 func (s *SiteHandler) ListSitesHandler(c *gin.Context) {
 	sites, err := s.sitemanager.ListSites()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "ListSitesHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -165,7 +184,7 @@ func (s *SiteHandler) GetSiteHandler(c *gin.Context) {
 
 	site, err := s.sitemanager.GetSite(id)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "GetSiteHandler", logger.Err(err))
 
 		errCode := http.StatusInternalServerError
 		if errors.Is(err, sitemanager.ErrSiteNotFound) {
@@ -191,7 +210,7 @@ func (s *SiteHandler) GetSiteHandler(c *gin.Context) {
 func (s *SiteHandler) SyncCategoryFromAllSiteHandler(c *gin.Context) {
 	err := s.sitemanager.SyncCategoryFromAllSite(c)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SyncCategoryFromAllSiteHandler", logger.Err(err))
 
 		errCode := http.StatusMultiStatus
 		c.JSON(errCode, gin.H{
@@ -211,7 +230,7 @@ func (s *SiteHandler) SyncCategoryFromSiteHandler(c *gin.Context) {
 
 	err := s.sitemanager.SyncCategoryFromSite(c, id)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SyncCategoryFromSiteHandler", logger.Err(err))
 
 		errCode := http.StatusInternalServerError
 		if errors.Is(err, sitemanager.ErrSiteNotFound) {
@@ -230,12 +249,203 @@ func (s *SiteHandler) SyncCategoryFromSiteHandler(c *gin.Context) {
 	})
 }
 
+func (s *SiteHandler) CreateSiteCategoryHandler(c *gin.Context) {
+	siteID := c.Param("siteID")
+
+	req := model.CreateSiteCategoryRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "CreateSiteCategoryHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	if req.Name == "" {
+		slog.WarnContext(c, "CreateSiteCategoryHandler: data is not complete")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", "data is not complete"),
+		})
+
+		return
+	}
+
+	category, err := s.sitemanager.CreateSiteCategory(c, siteID, req.Name)
+	if err != nil {
+		slog.ErrorContext(c, "CreateSiteCategoryHandler", logger.Err(err))
+
+		c.JSON(siteCategoryErrorCode(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+		"data":    category,
+	})
+}
+
+func (s *SiteHandler) RenameSiteCategoryHandler(c *gin.Context) {
+	siteID := c.Param("siteID")
+	categoryID := c.Param("categoryID")
+
+	req := model.RenameSiteCategoryRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "RenameSiteCategoryHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	if req.Name == "" {
+		slog.WarnContext(c, "RenameSiteCategoryHandler: data is not complete")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", "data is not complete"),
+		})
+
+		return
+	}
+
+	err = s.sitemanager.RenameSiteCategory(c, siteID, categoryID, req.Name)
+	if err != nil {
+		slog.ErrorContext(c, "RenameSiteCategoryHandler", logger.Err(err))
+
+		c.JSON(siteCategoryErrorCode(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
+func (s *SiteHandler) DeleteSiteCategoryHandler(c *gin.Context) {
+	siteID := c.Param("siteID")
+	categoryID := c.Param("categoryID")
+
+	err := s.sitemanager.DeleteSiteCategory(c, siteID, categoryID)
+	if err != nil {
+		slog.ErrorContext(c, "DeleteSiteCategoryHandler", logger.Err(err))
+
+		c.JSON(siteCategoryErrorCode(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
+// siteCategoryErrorCode return the status code of error from changing the category of site
+func siteCategoryErrorCode(err error) int {
+	switch {
+	case errors.Is(err, sitemanager.ErrSiteNotFound) || errors.Is(err, sitemanager.ErrCategoryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, cmsdriver.ErrOperationNotSupport) || errors.Is(err, sitemanager.ErrCloneSameSite):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (s *SiteHandler) SetSiteHTTPOptionsHandler(c *gin.Context) {
+	siteID := c.Param("siteID")
+
+	req := model.SetSiteHTTPOptionsRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "SetSiteHTTPOptionsHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	err = s.sitemanager.SetSiteHTTPOptions(c, siteID, req.Options)
+	if err != nil {
+		slog.ErrorContext(c, "SetSiteHTTPOptionsHandler", logger.Err(err))
+
+		errCode := http.StatusInternalServerError
+		if errors.Is(err, sitemanager.ErrSiteNotFound) {
+			errCode = http.StatusNotFound
+		} else if errors.Is(err, httpclient.ErrInvalidProxy) {
+			errCode = http.StatusBadRequest
+		}
+
+		c.JSON(errCode, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
+func (s *SiteHandler) CloneSiteHandler(c *gin.Context) {
+	siteID := c.Param("siteID")
+
+	req := model.CloneSiteRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "CloneSiteHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	var plan sitemanager.CloneSitePlan
+	if req.DryRun {
+		plan, err = s.sitemanager.PreviewCloneSite(c, req.SourceSiteID, siteID)
+	} else {
+		plan, err = s.sitemanager.CloneSite(c, req.SourceSiteID, siteID)
+	}
+
+	if err != nil {
+		slog.ErrorContext(c, "CloneSiteHandler", logger.Err(err))
+
+		c.JSON(siteCategoryErrorCode(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+			"data":    plan,
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+		"data":    plan,
+	})
+}
+
 func (s *SiteHandler) IncreaseLackCountHandler(c *gin.Context) {
 	req := model.IncreaseLackCountRequest{}
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "IncreaseLackCountHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -245,7 +455,7 @@ func (s *SiteHandler) IncreaseLackCountHandler(c *gin.Context) {
 
 	err = s.sitemanager.IncreaseLackCount(req.SiteID, req.Count)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "IncreaseLackCountHandler", logger.Err(err))
 
 		errCode := http.StatusInternalServerError
 		if errors.Is(err, sitemanager.ErrSiteNotFound) {
@@ -280,7 +490,7 @@ func (u *UserHandler) AddFirstAdminUser(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "AddFirstAdminUser", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -290,7 +500,7 @@ func (u *UserHandler) AddFirstAdminUser(c *gin.Context) {
 
 	// check data
 	if req.UserName == "" || req.Password == "" {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "AddFirstAdminUser: data is not complete")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", "data is not complete"),
 		})
@@ -300,7 +510,7 @@ func (u *UserHandler) AddFirstAdminUser(c *gin.Context) {
 
 	err = u.usermanager.CreateFirstAdminUser(c, req.UserName, req.Password)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "AddFirstAdminUser", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -326,7 +536,7 @@ func NewCommentBotHandler(commentBot *commentbot.CommentBot) *CommentBotHandler
 func (b *CommentBotHandler) StartAutoCommentHandler(c *gin.Context) {
 	err := b.commentBot.StartAutoComment(c)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "StartAutoCommentHandler", logger.Err(err))
 
 		errCode := http.StatusInternalServerError
 		c.JSON(errCode, gin.H{
@@ -344,7 +554,7 @@ func (b *CommentBotHandler) StartAutoCommentHandler(c *gin.Context) {
 func (b *CommentBotHandler) StopAutoCommentHandler(c *gin.Context) {
 	err := b.commentBot.StopAutoComment(c)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "StopAutoCommentHandler", logger.Err(err))
 
 		errCode := http.StatusInternalServerError
 		c.JSON(errCode, gin.H{
@@ -362,7 +572,7 @@ func (b *CommentBotHandler) StopAutoCommentHandler(c *gin.Context) {
 func (b *CommentBotHandler) GetStopAutoCommentStatusHandler(c *gin.Context) {
 	status, err := b.commentBot.IsAutoCommentStopped()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "GetStopAutoCommentStatusHandler", logger.Err(err))
 
 		errCode := http.StatusInternalServerError
 		c.JSON(errCode, gin.H{
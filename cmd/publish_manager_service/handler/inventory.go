@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ray31245/seo_cluster/cmd/publish_manager_service/model"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	inventoryManager "github.com/ray31245/seo_cluster/service/inventory_manager"
+)
+
+type InventoryHandler struct {
+	inventoryManager *inventoryManager.InventoryManager
+}
+
+func NewInventoryHandler(inventoryManager *inventoryManager.InventoryManager) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryManager: inventoryManager,
+	}
+}
+
+func (i *InventoryHandler) GetInventoryForecastHandler(c *gin.Context) {
+	forecast, err := i.inventoryManager.Forecast(time.Now())
+	if err != nil {
+		slog.ErrorContext(c, "GetInventoryForecastHandler", logger.Err(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+		"data":    forecast,
+	})
+}
+
+func (i *InventoryHandler) SetInventoryConfigHandler(c *gin.Context) {
+	req := model.SetInventoryConfigRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "SetInventoryConfigHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	// check data
+	if req.AlertThresholdDays != nil && *req.AlertThresholdDays < 0 {
+		slog.WarnContext(c, "SetInventoryConfigHandler: alert threshold days must be non-negative")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", "alert threshold days must be non-negative"),
+		})
+
+		return
+	}
+
+	if req.WindowDays != nil {
+		err = i.inventoryManager.SetWindowDays(*req.WindowDays)
+		if err != nil {
+			slog.ErrorContext(c, "SetInventoryConfigHandler", logger.Err(err))
+
+			errCode := http.StatusInternalServerError
+			if errors.Is(err, inventoryManager.ErrInvalidWindowDays) {
+				errCode = http.StatusBadRequest
+			}
+
+			c.JSON(errCode, gin.H{
+				"message": fmt.Sprintf("error: %v", err),
+			})
+
+			return
+		}
+	}
+
+	if req.AlertThresholdDays != nil {
+		err = i.inventoryManager.SetAlertThresholdDays(*req.AlertThresholdDays)
+		if err != nil {
+			slog.ErrorContext(c, "SetInventoryConfigHandler", logger.Err(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"message": fmt.Sprintf("error: %v", err),
+			})
+
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
+func (i *InventoryHandler) GetInventoryConfigHandler(c *gin.Context) {
+	thresholdDays, err := i.inventoryManager.GetAlertThresholdDays()
+	if err != nil {
+		slog.ErrorContext(c, "GetInventoryConfigHandler", logger.Err(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	windowDays, err := i.inventoryManager.GetWindowDays()
+	if err != nil {
+		slog.ErrorContext(c, "GetInventoryConfigHandler", logger.Err(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alert_threshold_days": thresholdDays,
+		"window_days":          windowDays,
+	})
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	leaderManager "github.com/ray31245/seo_cluster/service/leader_manager"
+)
+
+type LeaderHandler struct {
+	leaderManager *leaderManager.LeaderManager
+}
+
+func NewLeaderHandler(leaderManager *leaderManager.LeaderManager) *LeaderHandler {
+	return &LeaderHandler{
+		leaderManager: leaderManager,
+	}
+}
+
+func (l *LeaderHandler) GetLeaderStatusHandler(c *gin.Context) {
+	status, err := l.leaderManager.Status()
+	if err != nil {
+		slog.ErrorContext(c, "GetLeaderStatusHandler", logger.Err(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+		"data":    status,
+	})
+}
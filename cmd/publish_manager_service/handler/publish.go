@@ -3,11 +3,12 @@ package handler
 import (
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ray31245/seo_cluster/cmd/publish_manager_service/model"
+	"github.com/ray31245/seo_cluster/pkg/logger"
 	"github.com/ray31245/seo_cluster/pkg/util"
 	publishManager "github.com/ray31245/seo_cluster/service/publish_manager"
 )
@@ -28,7 +29,7 @@ func (p *PublishHandler) AveragePublishHandler(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "AveragePublishHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -38,7 +39,7 @@ func (p *PublishHandler) AveragePublishHandler(c *gin.Context) {
 
 	// check data
 	if req.Title == "" || req.Content == "" {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "AveragePublishHandler: data is not complete")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", "data is not complete"),
 		})
@@ -48,7 +49,7 @@ func (p *PublishHandler) AveragePublishHandler(c *gin.Context) {
 
 	req.Content, err = util.DecodeImageListDivFromHTMl([]byte(req.Content))
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "AveragePublishHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -58,7 +59,7 @@ func (p *PublishHandler) AveragePublishHandler(c *gin.Context) {
 
 	err = p.publisher.AveragePublish(c, req.ToPublishManager())
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "AveragePublishHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -77,7 +78,7 @@ func (p *PublishHandler) PrePublishHandler(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "PrePublishHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -87,7 +88,7 @@ func (p *PublishHandler) PrePublishHandler(c *gin.Context) {
 
 	// check data
 	if req.Title == "" || req.Content == "" {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "PrePublishHandler: data is not complete")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", "data is not complete"),
 		})
@@ -97,7 +98,7 @@ func (p *PublishHandler) PrePublishHandler(c *gin.Context) {
 
 	req.Content, err = util.DecodeImageListDivFromHTMl([]byte(req.Content))
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "PrePublishHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -107,7 +108,7 @@ func (p *PublishHandler) PrePublishHandler(c *gin.Context) {
 
 	err = p.publisher.PrePublish(req.ToPublishManager())
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "PrePublishHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -126,7 +127,7 @@ func (p *PublishHandler) FlexiblePublishHandler(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "FlexiblePublishHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -136,7 +137,7 @@ func (p *PublishHandler) FlexiblePublishHandler(c *gin.Context) {
 
 	// check data
 	if req.Title == "" || req.Content == "" {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "FlexiblePublishHandler: data is not complete")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", "data is not complete"),
 		})
@@ -146,7 +147,7 @@ func (p *PublishHandler) FlexiblePublishHandler(c *gin.Context) {
 
 	req.Content, err = util.DecodeImageListDivFromHTMl([]byte(req.Content))
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "FlexiblePublishHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -158,7 +159,7 @@ func (p *PublishHandler) FlexiblePublishHandler(c *gin.Context) {
 	if errors.Is(err, publishManager.ErrNoCategoryNeedToBePublished) {
 		err = p.publisher.PrePublish(req.ToPublishManager())
 		if err != nil {
-			log.Println(err)
+			slog.ErrorContext(c, "FlexiblePublishHandler", logger.Err(err))
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"message": fmt.Sprintf("error: %v", err),
 			})
@@ -166,7 +167,7 @@ func (p *PublishHandler) FlexiblePublishHandler(c *gin.Context) {
 			return
 		}
 	} else if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "FlexiblePublishHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -185,7 +186,7 @@ func (p *PublishHandler) DirectPublishHandler(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "DirectPublishHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -195,7 +196,7 @@ func (p *PublishHandler) DirectPublishHandler(c *gin.Context) {
 
 	// check data
 	if req.Title == "" || req.Content == "" {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "DirectPublishHandler: data is not complete")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", "data is not complete"),
 		})
@@ -205,7 +206,7 @@ func (p *PublishHandler) DirectPublishHandler(c *gin.Context) {
 
 	req.Content, err = util.DecodeImageListDivFromHTMl([]byte(req.Content))
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "DirectPublishHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -217,7 +218,7 @@ func (p *PublishHandler) DirectPublishHandler(c *gin.Context) {
 
 	err = p.publisher.DirectPublish(c, cateID, req.ToPublishManager())
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "DirectPublishHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -236,7 +237,7 @@ func (p *PublishHandler) BroadcastPublishHandler(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "BroadcastPublishHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -246,7 +247,7 @@ func (p *PublishHandler) BroadcastPublishHandler(c *gin.Context) {
 
 	// check data
 	if req.Title == "" || req.Content == "" {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "BroadcastPublishHandler: data is not complete")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", "data is not complete"),
 		})
@@ -256,7 +257,7 @@ func (p *PublishHandler) BroadcastPublishHandler(c *gin.Context) {
 
 	req.Content, err = util.DecodeImageListDivFromHTMl([]byte(req.Content))
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "BroadcastPublishHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -266,7 +267,7 @@ func (p *PublishHandler) BroadcastPublishHandler(c *gin.Context) {
 
 	err = p.publisher.BroadcastPublish(c, req.ToPublishManager())
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "BroadcastPublishHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -284,7 +285,7 @@ func (p *PublishHandler) SpecifyPublishHandler(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SpecifyPublishHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -294,7 +295,7 @@ func (p *PublishHandler) SpecifyPublishHandler(c *gin.Context) {
 
 	// check data
 	if req.ArticleID == "" || req.CateID == "" {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "SpecifyPublishHandler: data is not complete")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", "data is not complete"),
 		})
@@ -304,7 +305,7 @@ func (p *PublishHandler) SpecifyPublishHandler(c *gin.Context) {
 
 	err = p.publisher.SpecifyPublish(c, req.CateID, req.ArticleID)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SpecifyPublishHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -320,7 +321,7 @@ func (p *PublishHandler) SpecifyPublishHandler(c *gin.Context) {
 func (p *PublishHandler) GetArticleCacheCountHandler(c *gin.Context) {
 	count, err := p.publisher.CountArticleCache()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "GetArticleCacheCountHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -333,13 +334,20 @@ func (p *PublishHandler) GetArticleCacheCountHandler(c *gin.Context) {
 	})
 }
 
+func (p *PublishHandler) GetPublishLockStatsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+		"data":    p.publisher.PublishLockStats(),
+	})
+}
+
 func (p *PublishHandler) SetConfigUnCategoryNameHandler(c *gin.Context) {
 	// get data body from request
 	req := model.SetUnconfigCategoryNameRequest{}
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SetConfigUnCategoryNameHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -349,7 +357,7 @@ func (p *PublishHandler) SetConfigUnCategoryNameHandler(c *gin.Context) {
 
 	// check data
 	if req.Name == "" {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "SetConfigUnCategoryNameHandler: data is not complete")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", "data is not complete"),
 		})
@@ -359,7 +367,7 @@ func (p *PublishHandler) SetConfigUnCategoryNameHandler(c *gin.Context) {
 
 	err = p.publisher.SetConfigUnCateName(c, req.Name)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SetConfigUnCategoryNameHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -375,7 +383,7 @@ func (p *PublishHandler) SetConfigUnCategoryNameHandler(c *gin.Context) {
 func (p *PublishHandler) GetConfigUnCateNameHandler(c *gin.Context) {
 	name, err := p.publisher.GetConfigUnCateName()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "GetConfigUnCateNameHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -394,7 +402,7 @@ func (p *PublishHandler) SetConfigTagBlackList(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SetConfigTagBlackList", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -404,7 +412,7 @@ func (p *PublishHandler) SetConfigTagBlackList(c *gin.Context) {
 
 	// check data
 	if req.Tags == nil {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "SetConfigTagBlackList: data is not complete")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", "data is not complete"),
 		})
@@ -414,7 +422,7 @@ func (p *PublishHandler) SetConfigTagBlackList(c *gin.Context) {
 
 	err = p.publisher.SetTagsBlockList(c, req.Tags)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SetConfigTagBlackList", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -430,7 +438,7 @@ func (p *PublishHandler) SetConfigTagBlackList(c *gin.Context) {
 func (p *PublishHandler) GetConfigTagBlackList(c *gin.Context) {
 	tags, err := p.publisher.GetTagsBlockList()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "GetConfigTagBlackList", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -443,10 +451,64 @@ func (p *PublishHandler) GetConfigTagBlackList(c *gin.Context) {
 	})
 }
 
+func (p *PublishHandler) SetConfigArticleCacheTTLHandler(c *gin.Context) {
+	req := model.SetArticleCacheTTLRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "SetConfigArticleCacheTTLHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	// check data
+	if req.Hours == nil || *req.Hours < 0 {
+		slog.WarnContext(c, "SetConfigArticleCacheTTLHandler: hours must be non-negative")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", "hours must be non-negative"),
+		})
+
+		return
+	}
+
+	err = p.publisher.SetArticleCacheTTL(*req.Hours)
+	if err != nil {
+		slog.ErrorContext(c, "SetConfigArticleCacheTTLHandler", logger.Err(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
+func (p *PublishHandler) GetConfigArticleCacheTTLHandler(c *gin.Context) {
+	hours, err := p.publisher.GetArticleCacheTTL()
+	if err != nil {
+		slog.ErrorContext(c, "GetConfigArticleCacheTTLHandler", logger.Err(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"hours": hours,
+	})
+}
+
 func (p *PublishHandler) StopAutoPublishHandler(c *gin.Context) {
 	err := p.publisher.StopAutoPublish()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "StopAutoPublishHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -462,7 +524,7 @@ func (p *PublishHandler) StopAutoPublishHandler(c *gin.Context) {
 func (p *PublishHandler) StartAutoPublishHandler(c *gin.Context) {
 	err := p.publisher.StartAutoPublish()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "StartAutoPublishHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -478,7 +540,7 @@ func (p *PublishHandler) StartAutoPublishHandler(c *gin.Context) {
 func (p *PublishHandler) GetStopAutoPublishStatusHandler(c *gin.Context) {
 	status, err := p.publisher.StopAutoPublishStatus()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "GetStopAutoPublishStatusHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -4,12 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
-	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/ray31245/seo_cluster/cmd/publish_manager_service/model"
+	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
+	"github.com/ray31245/seo_cluster/pkg/logger"
 	"github.com/ray31245/seo_cluster/pkg/util"
 	rewritemanager "github.com/ray31245/seo_cluster/service/rewrite_manager"
 )
@@ -28,7 +30,7 @@ func (r *RewriteHandler) RewriteHandler(c *gin.Context) {
 	// get data body from request
 	req, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "RewriteHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -65,7 +67,7 @@ func (r *RewriteHandler) RewriteHandler(c *gin.Context) {
 
 	res, err := r.rewriteWorkFlow(string(req), rewriteF, extendRewriteF, makeTitleF, true)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "RewriteHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -81,7 +83,7 @@ func (r *RewriteHandler) RewriteTestHandler(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "RewriteTestHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -132,7 +134,7 @@ func (r *RewriteHandler) RewriteTestHandler(c *gin.Context) {
 
 	newArt, err := r.rewriteWorkFlow(req.Content, rewriteF, extendRewriteF, makeTitleF, true)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "RewriteTestHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 			"steps":   steps,
@@ -143,7 +145,7 @@ func (r *RewriteHandler) RewriteTestHandler(c *gin.Context) {
 
 	newArt.Content, err = util.DecodeImageListDivFromHTMl([]byte(newArt.Content))
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "RewriteTestHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 			"steps":   steps,
@@ -162,7 +164,7 @@ func (r *RewriteHandler) MultiSectionsRewriteHandler(c *gin.Context) {
 	// get data body from request
 	req, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "MultiSectionsRewriteHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -199,7 +201,7 @@ func (r *RewriteHandler) MultiSectionsRewriteHandler(c *gin.Context) {
 
 	res, err := r.rewriteWorkFlow(string(req), rewriteF, extendRewriteF, makeTitleF, false)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "MultiSectionsRewriteHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -215,7 +217,7 @@ func (r *RewriteHandler) MultiSectionsRewriteTestHandler(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "MultiSectionsRewriteTestHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -266,7 +268,7 @@ func (r *RewriteHandler) MultiSectionsRewriteTestHandler(c *gin.Context) {
 
 	newArt, err := r.rewriteWorkFlow(req.Content, rewriteF, extendRewriteF, makeTitleF, false)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "MultiSectionsRewriteTestHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 			"steps":   steps,
@@ -296,60 +298,12 @@ func (r *RewriteHandler) rewriteWorkFlow(
 	makeTitleF func(string) (string, error),
 	isGenImageList bool,
 ) (model.RewriteResponse, error) {
-	originalArticle, err := util.HTMLToMd(articleContent)
+	res, err := r.rewritemanager.RewriteWorkFlow(articleContent, rewriteF, extendRewriteF, makeTitleF, isGenImageList)
 	if err != nil {
-		log.Println(err)
-
 		return model.RewriteResponse{}, fmt.Errorf("rewriteWorkFlow: %w", err)
 	}
 
-	// check data
-	if utf8.RuneCount([]byte(originalArticle)) < minSrcLength {
-		log.Println("data is not complete")
-
-		return model.RewriteResponse{}, fmt.Errorf("rewriteWorkFlow: %w", errors.New("data is not complete"))
-	}
-
-	res := struct {
-		Title   string `json:"Title"`
-		Content string `json:"Content"`
-	}{}
-
-	art, err := rewriteF(originalArticle)
-	if err != nil {
-		return model.RewriteResponse{}, fmt.Errorf("rewriteWorkFlow: %w", err)
-	}
-
-	if utf8.RuneCountInString(art) < minArtLength {
-		extArt, err := extendRewriteF(articleContent)
-		if err != nil {
-			return model.RewriteResponse{}, fmt.Errorf("rewriteWorkFlow: %w", err)
-		}
-
-		art = extArt
-	}
-
-	title, err := makeTitleF(art)
-	if err != nil {
-		return model.RewriteResponse{}, fmt.Errorf("rewriteWorkFlow: %w", err)
-	}
-
-	res.Title = title
-
-	art = string(util.MdToHTML([]byte(art)))
-
-	if isGenImageList {
-		imgDiv, err := util.GenImageListEncodeDiv([]byte(articleContent))
-		if err != nil {
-			log.Printf("error: %v", err)
-		} else {
-			art += imgDiv
-		}
-	}
-
-	res.Content = art
-
-	return res, nil
+	return model.RewriteResponse{Title: res.Title, Content: res.Content}, nil
 }
 
 func (r *RewriteHandler) CreateRewriteTestCaseHandler(c *gin.Context) {
@@ -357,7 +311,7 @@ func (r *RewriteHandler) CreateRewriteTestCaseHandler(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "CreateRewriteTestCaseHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -366,7 +320,7 @@ func (r *RewriteHandler) CreateRewriteTestCaseHandler(c *gin.Context) {
 	}
 
 	if req.Name == "" || req.Content == "" {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "CreateRewriteTestCaseHandler: data is not complete")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", "data is not complete"),
 		})
@@ -376,7 +330,7 @@ func (r *RewriteHandler) CreateRewriteTestCaseHandler(c *gin.Context) {
 
 	testCase, err := r.rewritemanager.CreateRewriteTestCase(req.Name, req.Source, req.Content)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "CreateRewriteTestCaseHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -393,7 +347,7 @@ func (r *RewriteHandler) CreateRewriteTestCaseHandler(c *gin.Context) {
 func (r *RewriteHandler) ListRewriteTestCaseHandler(c *gin.Context) {
 	testCases, err := r.rewritemanager.ListRewriteTestCases()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "ListRewriteTestCaseHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -412,7 +366,7 @@ func (r *RewriteHandler) UpdateRewriteTestCaseHandler(c *gin.Context) {
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "UpdateRewriteTestCaseHandler", logger.Err(err))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -421,7 +375,7 @@ func (r *RewriteHandler) UpdateRewriteTestCaseHandler(c *gin.Context) {
 	}
 
 	if req.Name == "" || req.Content == "" {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "UpdateRewriteTestCaseHandler: data is not complete")
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": fmt.Sprintf("error: %v", "data is not complete"),
 		})
@@ -433,7 +387,7 @@ func (r *RewriteHandler) UpdateRewriteTestCaseHandler(c *gin.Context) {
 
 	err = r.rewritemanager.UpdateRewriteTestCase(ID, req.Name, req.Source, req.Content)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "UpdateRewriteTestCaseHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -451,7 +405,7 @@ func (r *RewriteHandler) DeleteRewriteTestCaseHandler(c *gin.Context) {
 
 	err := r.rewritemanager.DeleteRewriteTestCase(ID)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "DeleteRewriteTestCaseHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -467,7 +421,7 @@ func (r *RewriteHandler) DeleteRewriteTestCaseHandler(c *gin.Context) {
 func (r *RewriteHandler) GetDefaultSystemPromptHandler(c *gin.Context) {
 	systemPrompt, err := r.rewritemanager.GetDefaultSystemPrompt()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "GetDefaultSystemPromptHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -488,7 +442,7 @@ func (r *RewriteHandler) SetDefaultSystemPromptHandler(c *gin.Context) {
 
 	code, err := setDefaultRewritePrompt(c, f)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SetDefaultSystemPromptHandler", logger.Err(err))
 		c.JSON(code, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -504,7 +458,7 @@ func (r *RewriteHandler) SetDefaultSystemPromptHandler(c *gin.Context) {
 func (r *RewriteHandler) GetDefaultPromptHandler(c *gin.Context) {
 	prompt, err := r.rewritemanager.GetDefaultPrompt()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "GetDefaultPromptHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -525,7 +479,7 @@ func (r *RewriteHandler) SetDefaultPromptHandler(c *gin.Context) {
 
 	code, err := setDefaultRewritePrompt(c, f)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SetDefaultPromptHandler", logger.Err(err))
 		c.JSON(code, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -541,7 +495,7 @@ func (r *RewriteHandler) SetDefaultPromptHandler(c *gin.Context) {
 func (r *RewriteHandler) GetDefaultExtendSystemPromptHandler(c *gin.Context) {
 	systemPrompt, err := r.rewritemanager.GetDefaultExtendSystemPrompt()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "GetDefaultExtendSystemPromptHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -562,7 +516,7 @@ func (r *RewriteHandler) SetDefaultExtendSystemPromptHandler(c *gin.Context) {
 
 	code, err := setDefaultRewritePrompt(c, f)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SetDefaultExtendSystemPromptHandler", logger.Err(err))
 		c.JSON(code, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -578,7 +532,7 @@ func (r *RewriteHandler) SetDefaultExtendSystemPromptHandler(c *gin.Context) {
 func (r *RewriteHandler) GetDefaultExtendPromptHandler(c *gin.Context) {
 	prompt, err := r.rewritemanager.GetDefaultExtendPrompt()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "GetDefaultExtendPromptHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -599,7 +553,7 @@ func (r *RewriteHandler) SetDefaultExtendPromptHandler(c *gin.Context) {
 
 	code, err := setDefaultRewritePrompt(c, f)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SetDefaultExtendPromptHandler", logger.Err(err))
 		c.JSON(code, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -615,7 +569,7 @@ func (r *RewriteHandler) SetDefaultExtendPromptHandler(c *gin.Context) {
 func (r *RewriteHandler) GetDefaultMakeTitleSystemPromptHandler(c *gin.Context) {
 	systemPrompt, err := r.rewritemanager.GetDefaultMakeTitleSystemPrompt()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "GetDefaultMakeTitleSystemPromptHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -636,7 +590,7 @@ func (r *RewriteHandler) SetDefaultMakeTitleSystemPromptHandler(c *gin.Context)
 
 	code, err := setDefaultRewritePrompt(c, f)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SetDefaultMakeTitleSystemPromptHandler", logger.Err(err))
 		c.JSON(code, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -652,7 +606,7 @@ func (r *RewriteHandler) SetDefaultMakeTitleSystemPromptHandler(c *gin.Context)
 func (r *RewriteHandler) GetDefaultMakeTitlePromptHandler(c *gin.Context) {
 	prompt, err := r.rewritemanager.GetDefaultMakeTitlePrompt()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "GetDefaultMakeTitlePromptHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -673,7 +627,7 @@ func (r *RewriteHandler) SetDefaultMakeTitlePromptHandler(c *gin.Context) {
 
 	code, err := setDefaultRewritePrompt(c, f)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SetDefaultMakeTitlePromptHandler", logger.Err(err))
 		c.JSON(code, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -689,7 +643,7 @@ func (r *RewriteHandler) SetDefaultMakeTitlePromptHandler(c *gin.Context) {
 func (r *RewriteHandler) GetDefaultMultiSectionsSystemPromptHandler(c *gin.Context) {
 	systemPrompt, err := r.rewritemanager.GetDefaultMultiSectionsSystemPrompt()
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "GetDefaultMultiSectionsSystemPromptHandler", logger.Err(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -710,7 +664,7 @@ func (r *RewriteHandler) SetDefaultMultiSectionsSystemPromptHandler(c *gin.Conte
 
 	code, err := setDefaultRewritePrompt(c, f)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SetDefaultMultiSectionsSystemPromptHandler", logger.Err(err))
 		c.JSON(code, gin.H{
 			"message": fmt.Sprintf("error: %v", err),
 		})
@@ -734,23 +688,100 @@ func setDefaultRewritePrompt[T setPromptRequest](c *gin.Context, f setPromptF[T]
 
 	err := c.ShouldBind(&req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SetDefaultMultiSectionsSystemPromptHandler", logger.Err(err))
 
 		return http.StatusBadRequest, fmt.Errorf("setPromptRequest: %w", err)
 	}
 
 	if req.GetPrompt() == "" {
-		log.Println("data is not complete")
+		slog.WarnContext(c, "SetDefaultMultiSectionsSystemPromptHandler: data is not complete")
 
 		return http.StatusBadRequest, fmt.Errorf("setPromptRequest: %w", errors.New("data is not complete"))
 	}
 
 	err = f(req)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(c, "SetDefaultMultiSectionsSystemPromptHandler", logger.Err(err))
 
 		return http.StatusInternalServerError, fmt.Errorf("setPromptRequest: %w", err)
 	}
 
 	return http.StatusOK, nil
 }
+
+func (r *RewriteHandler) GetSiteRewriteProfileHandler(c *gin.Context) {
+	profile, err := r.rewritemanager.GetSiteRewriteProfile(c.Param("siteID"))
+	if err != nil {
+		slog.ErrorContext(c, "GetSiteRewriteProfileHandler", logger.Err(err))
+
+		errCode := http.StatusInternalServerError
+		if dbErr.IsNotfoundErr(err) {
+			errCode = http.StatusNotFound
+		}
+
+		c.JSON(errCode, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+		"data":    profile,
+	})
+}
+
+func (r *RewriteHandler) UpsertSiteRewriteProfileHandler(c *gin.Context) {
+	siteID, err := uuid.Parse(c.Param("siteID"))
+	if err != nil {
+		slog.ErrorContext(c, "UpsertSiteRewriteProfileHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	req := model.UpsertSiteRewriteProfileRequest{}
+
+	err = c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "UpsertSiteRewriteProfileHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	err = r.rewritemanager.UpsertSiteRewriteProfile(req.ToDBModel(siteID))
+	if err != nil {
+		slog.ErrorContext(c, "UpsertSiteRewriteProfileHandler", logger.Err(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
+func (r *RewriteHandler) DeleteSiteRewriteProfileHandler(c *gin.Context) {
+	err := r.rewritemanager.DeleteSiteRewriteProfile(c.Param("siteID"))
+	if err != nil {
+		slog.ErrorContext(c, "DeleteSiteRewriteProfileHandler", logger.Err(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ray31245/seo_cluster/cmd/publish_manager_service/helper"
+	"github.com/ray31245/seo_cluster/cmd/publish_manager_service/model"
+	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	webhookManager "github.com/ray31245/seo_cluster/service/webhook_manager"
+)
+
+type WebhookHandler struct {
+	webhookManager *webhookManager.WebhookManager
+}
+
+func NewWebhookHandler(webhookManager *webhookManager.WebhookManager) *WebhookHandler {
+	return &WebhookHandler{
+		webhookManager: webhookManager,
+	}
+}
+
+func webhookErrStatus(err error) int {
+	switch {
+	case dbErr.IsNotfoundErr(err):
+		return http.StatusNotFound
+	case errors.Is(err, webhookManager.ErrInvalidEvent), errors.Is(err, webhookManager.ErrInvalidURL):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (w *WebhookHandler) CreateWebhookHandler(c *gin.Context) {
+	req := model.CreateWebhookRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "CreateWebhookHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	// check data
+	if req.URL == "" {
+		slog.WarnContext(c, "CreateWebhookHandler: data is not complete")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", "data is not complete"),
+		})
+
+		return
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	webhook, err := w.webhookManager.CreateWebhook(req.Name, req.URL, req.Secret, req.Events, isActive)
+	if err != nil {
+		slog.ErrorContext(c, "CreateWebhookHandler", logger.Err(err))
+		c.JSON(webhookErrStatus(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	res := model.CreateWebhookResponse{}
+	res.FromDBWebhook(webhook)
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (w *WebhookHandler) ListWebhooksHandler(c *gin.Context) {
+	webhooks, err := w.webhookManager.ListWebhooks()
+	if err != nil {
+		slog.ErrorContext(c, "ListWebhooksHandler", logger.Err(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	res := model.ListWebhooksResponse{}
+	res.FromDBWebhooks(webhooks)
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (w *WebhookHandler) UpdateWebhookHandler(c *gin.Context) {
+	req := model.UpdateWebhookRequest{}
+
+	err := c.ShouldBind(&req)
+	if err != nil {
+		slog.ErrorContext(c, "UpdateWebhookHandler", logger.Err(err))
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	// check data
+	if req.URL == "" {
+		slog.WarnContext(c, "UpdateWebhookHandler: data is not complete")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", "data is not complete"),
+		})
+
+		return
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	err = w.webhookManager.UpdateWebhook(c.Param("id"), req.Name, req.URL, req.Secret, req.Events, isActive)
+	if err != nil {
+		slog.ErrorContext(c, "UpdateWebhookHandler", logger.Err(err))
+		c.JSON(webhookErrStatus(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
+func (w *WebhookHandler) DeleteWebhookHandler(c *gin.Context) {
+	err := w.webhookManager.DeleteWebhook(c.Param("id"))
+	if err != nil {
+		slog.ErrorContext(c, "DeleteWebhookHandler", logger.Err(err))
+		c.JSON(webhookErrStatus(err), gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "ok",
+	})
+}
+
+func (w *WebhookHandler) ListWebhookDeliveriesHandler(c *gin.Context) {
+	page, pageSize, err := helper.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	deliveries, totalPage, totalRows, err := w.webhookManager.ListDeliveries(c.Param("id"), page, pageSize)
+	if err != nil {
+		slog.ErrorContext(c, "ListWebhookDeliveriesHandler", logger.Err(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"total_page": totalPage,
+		"total_rows": totalRows,
+	})
+}
+
+func (w *WebhookHandler) TestWebhookHandler(c *gin.Context) {
+	delivery, err := w.webhookManager.TestDelivery(c, c.Param("id"))
+	if dbErr.IsNotfoundErr(err) {
+		slog.ErrorContext(c, "TestWebhookHandler", logger.Err(err))
+		c.JSON(http.StatusNotFound, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	} else if err != nil && delivery.Attempts == 0 {
+		slog.ErrorContext(c, "TestWebhookHandler", logger.Err(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": fmt.Sprintf("error: %v", err),
+		})
+
+		return
+	}
+
+	// delivery failure is reported in the delivery log, not as a server error
+	c.JSON(http.StatusOK, gin.H{
+		"delivery": delivery,
+	})
+}
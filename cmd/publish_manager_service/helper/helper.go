@@ -8,6 +8,24 @@ import (
 	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
 )
 
+// IdentityKey key of login user in gin context
+const IdentityKey = "loginInfo"
+
+// GetLoginUserID return the id of login user, empty if not login
+func GetLoginUserID(c *gin.Context) string {
+	identity, ok := c.Get(IdentityKey)
+	if !ok {
+		return ""
+	}
+
+	user, ok := identity.(*dbModel.User)
+	if !ok || user == nil {
+		return ""
+	}
+
+	return user.ID.String()
+}
+
 func ParsePagination(c *gin.Context) (page, pageSize int, err error) {
 	pageQ, pageSizeQ := ParsePaginationQuery(c)
 
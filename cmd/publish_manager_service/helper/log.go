@@ -0,0 +1,55 @@
+package helper
+
+import (
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+)
+
+// RequestIDHeader is the header of request id, the one from client is kept if it is valid
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limit the request id from client, so it can not inject content into log
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`) //nolint:gochecknoglobals // compiled once
+
+// LogMiddleware add request id, site id and article id of path to the logs written with the context of request,
+// and log every request after it is handled.
+// The engine should enable ContextWithFallback, so gin.Context can be used as the context of logs.
+func LogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Header(RequestIDHeader, requestID)
+
+		attrs := []slog.Attr{slog.String(logger.KeyRequestID, requestID)}
+		if siteID := c.Param("siteID"); siteID != "" {
+			attrs = append(attrs, logger.SiteID(siteID))
+		}
+
+		if articleID := c.Param("articleID"); articleID != "" {
+			attrs = append(attrs, logger.ArticleID(articleID))
+		}
+
+		c.Request = c.Request.WithContext(logger.WithAttrs(c.Request.Context(), attrs...))
+
+		start := time.Now()
+
+		c.Next()
+
+		// query is not logged, it may contain token
+		slog.InfoContext(c, "request",
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", c.Writer.Status()),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+		)
+	}
+}
@@ -2,37 +2,72 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/ray31245/seo_cluster/cmd/publish_manager_service/handler"
+	"github.com/ray31245/seo_cluster/cmd/publish_manager_service/helper"
 	aiassist "github.com/ray31245/seo_cluster/pkg/ai_assist"
 	"github.com/ray31245/seo_cluster/pkg/auth"
+	clientpool "github.com/ray31245/seo_cluster/pkg/client_pool"
+	cmsdriver "github.com/ray31245/seo_cluster/pkg/cms_driver"
 	"github.com/ray31245/seo_cluster/pkg/db"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	feedreader "github.com/ray31245/seo_cluster/pkg/feed_reader"
+	ghostApi "github.com/ray31245/seo_cluster/pkg/ghost_api"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
 	jwt_kit "github.com/ray31245/seo_cluster/pkg/jwt_kit"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	metaweblogApi "github.com/ray31245/seo_cluster/pkg/metaweblog_api"
+	"github.com/ray31245/seo_cluster/pkg/secret"
+	staticSite "github.com/ray31245/seo_cluster/pkg/static_site"
 	util "github.com/ray31245/seo_cluster/pkg/util"
 	wordpressApi "github.com/ray31245/seo_cluster/pkg/wordpress_api"
 	zBlogApi "github.com/ray31245/seo_cluster/pkg/z_blog_api"
 	articleCacheManager "github.com/ray31245/seo_cluster/service/article_cache_manager"
 	commentbot "github.com/ray31245/seo_cluster/service/comment_bot"
+	feedmanager "github.com/ray31245/seo_cluster/service/feed_manager"
+	inventorymanager "github.com/ray31245/seo_cluster/service/inventory_manager"
+	leadermanager "github.com/ray31245/seo_cluster/service/leader_manager"
 	publishManager "github.com/ray31245/seo_cluster/service/publish_manager"
 	rewritemanager "github.com/ray31245/seo_cluster/service/rewrite_manager"
 	sitemanager "github.com/ray31245/seo_cluster/service/site_manager"
 	usermanager "github.com/ray31245/seo_cluster/service/user_manager"
+	webhookmanager "github.com/ray31245/seo_cluster/service/webhook_manager"
 
 	"github.com/gin-gonic/gin"
 )
 
 var APIKey string //nolint:gochecknoglobals // APIKey can input from ldflags
 
+const (
+	readHeaderTimeout = 10 * time.Second
+	// shutdownTimeout is the time waiting for the requests in progress on shutdown
+	shutdownTimeout = 30 * time.Second
+)
+
 func main() {
 	port := flag.Int("port", 7259, "port")
 	flag.Parse()
 
-	mainCtx := context.TODO()
+	logOptions, err := logger.LoadOptions()
+	if err != nil {
+		panic(err)
+	}
+
+	logger.Init(logOptions)
+
+	// the leader lease is released on shutdown, so other instance take over without waiting for expiry
+	mainCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	configDSN := "config.db"
 	if s, ok := os.LookupEnv("CONFIG_DSN"); ok {
@@ -56,6 +91,18 @@ func main() {
 	}
 	defer publishDB.Close()
 
+	// passwords of sites and comment users are encrypted at rest when secret key is configured
+	keyring, err := secret.LoadKeyring()
+	if err != nil {
+		panic(err)
+	}
+
+	if keyring != nil {
+		publishDB.UseSecretKeyring(keyring)
+	} else {
+		slog.Warn("SECRET_KEY or SECRET_KEY_FILE is not set, passwords are stored as plaintext")
+	}
+
 	commentBotDSN := "comment_bot.db"
 	if s, ok := os.LookupEnv("COMMENT_BOT_DSN"); ok {
 		commentBotDSN = s
@@ -67,6 +114,10 @@ func main() {
 	}
 	defer commentBotDB.Close()
 
+	if keyring != nil {
+		commentBotDB.UseSecretKeyring(keyring)
+	}
+
 	userDSN := "user.db"
 	if s, ok := os.LookupEnv("USER_DSN"); ok {
 		userDSN = s
@@ -98,6 +149,31 @@ func main() {
 		panic(err)
 	}
 
+	categoryTemplateDAO, err := publishDB.NewCategoryTemplateDAO()
+	if err != nil {
+		panic(err)
+	}
+
+	webhookDAO, err := publishDB.NewWebhookDAO()
+	if err != nil {
+		panic(err)
+	}
+
+	feedDAO, err := publishDB.NewFeedDAO()
+	if err != nil {
+		panic(err)
+	}
+
+	inventoryDAO, err := publishDB.NewInventoryDAO()
+	if err != nil {
+		panic(err)
+	}
+
+	leaderLeaseDAO, err := publishDB.NewLeaderLeaseDAO()
+	if err != nil {
+		panic(err)
+	}
+
 	commentUserDAO, err := commentBotDB.NewCommentUserDAO()
 	if err != nil {
 		panic(err)
@@ -122,43 +198,103 @@ func main() {
 	}
 	defer ai.Close()
 
-	zAPI := zBlogApi.NewZBlogAPI()
-	wordpressAPI := wordpressApi.NewWordpressApi()
+	httpClients, err := httpclient.NewFactory(cmsHTTPOptions())
+	if err != nil {
+		panic(err)
+	}
+
+	poolOptions := clientPoolOptions()
+	zAPI := zBlogApi.NewZBlogAPI(poolOptions, httpClients)
+	wordpressAPI := wordpressApi.NewWordpressApi(poolOptions, httpClients)
+	metaWeblogAPI := metaweblogApi.NewMetaWeblogAPI(poolOptions, httpClients)
+	ghostAPI := ghostApi.NewGhostAPI(poolOptions, httpClients)
+	// STATIC_SITE_BASE_DIR contain the roots of static sites, static site is rejected if it is not set
+	staticSiteAPI := staticSite.NewStaticSiteAPI(os.Getenv("STATIC_SITE_BASE_DIR"))
+	ghostDriver := cmsdriver.NewGhostDriver(ghostAPI)
+	// GHOST_CATEGORY_SLUG_PREFIX is the slug prefix of ghost tags used as categories
+	if s, ok := os.LookupEnv("GHOST_CATEGORY_SLUG_PREFIX"); ok && s != "" {
+		ghostDriver.SetCategorySlugPrefix(s)
+	}
+
+	cmsDrivers := cmsdriver.NewRegistry(
+		cmsdriver.NewZBlogDriver(zAPI),
+		cmsdriver.NewWordpressDriver(wordpressAPI),
+		cmsdriver.NewMetaWeblogDriver(metaWeblogAPI),
+		ghostDriver,
+		cmsdriver.NewStaticSiteDriver(staticSiteAPI),
+	)
 
-	secret := util.GenerateRandomString(32)
-	jwtKit := jwt_kit.NewJWTKit([]byte(secret), time.Hour, time.Hour, "loginInfo", nil, nil, nil, nil, nil)
+	jwtSecret := jwtSecretKey()
+	jwtKit := jwt_kit.NewJWTKit([]byte(jwtSecret), time.Hour, time.Hour, helper.IdentityKey, nil, nil, nil, nil, nil)
 	auth := auth.NewAuth(userDAO)
 
 	auth.SetUpJWTKit(jwtKit)
 
-	publisher := publishManager.NewPublishManager(zAPI, wordpressAPI, publishManager.DAO{ArticleCacheDAOInterface: articleCacheDAO, SiteDAOInterface: siteDAO, KVConfigDAOInterface: configDAO}, ai)
-	siteManager := sitemanager.NewSiteManager(zAPI, wordpressAPI, siteDAO)
+	holderID := instanceID()
+
+	publisher := publishManager.NewPublishManager(cmsDrivers, publishManager.DAO{ArticleCacheDAOInterface: articleCacheDAO, SiteDAOInterface: siteDAO, KVConfigDAOInterface: configDAO}, ai)
+	if s, ok := os.LookupEnv("PUBLISH_CONCURRENCY"); ok {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			panic(err)
+		}
+
+		publisher.SetMaxPublishConcurrency(n)
+	}
+
+	publisher.SetInstanceID(holderID)
+
+	siteManager := sitemanager.NewSiteManager(cmsDrivers, siteDAO, categoryTemplateDAO, siteDAO)
 	userManager := usermanager.NewUserManager(userDAO, auth)
 	articleCacheManager := articleCacheManager.NewArticleCacheManager(articleCacheDAO)
-	rewriteManager := rewritemanager.NewRewriteManager(ai, configDAO, rewriteTestCaseDAO)
+	rewriteManager := rewritemanager.NewRewriteManager(ai, configDAO, rewriteTestCaseDAO, siteDAO)
+	webhookManager := webhookmanager.NewWebhookManager(webhookDAO)
+	inventoryManager := inventorymanager.NewInventoryManager(inventorymanager.DAO{InventoryDAOInterface: inventoryDAO, ArticleCacheDAOInterface: articleCacheDAO, SiteDAOInterface: siteDAO, KVConfigDAOInterface: configDAO}, publisher)
 
-	err = publisher.StartRandomCyclePublishZblog(mainCtx)
-	if err != nil {
-		panic(err)
-	}
+	publisher.SetNotifier(webhookManager)
+	siteManager.SetNotifier(webhookManager)
+	rewriteManager.SetNotifier(webhookManager)
+	inventoryManager.SetNotifier(webhookManager)
 
-	err = publisher.StartRandomCyclePublishWordPress(mainCtx)
-	if err != nil {
-		panic(err)
-	}
+	publisher.SetSiteRewriter(rewriteManager)
+	publisher.SetInventoryRecorder(inventoryManager)
+	articleCacheManager.SetInventoryRecorder(inventoryManager)
 
-	err = publisher.StartUpdateArticleTagSignalLoop(mainCtx, 1, 5)
-	if err != nil {
-		panic(err)
-	}
+	feedManager := feedmanager.NewFeedManager(feedDAO, feedreader.NewFeedReader(), publisher, rewriteManager)
+
+	commentBot := commentbot.NewCommentBot(cmsDrivers, configDAO, siteDAO, commentUserDAO, ai)
 
-	publisher.StartPublishByLack(mainCtx)
+	// only the leader runs the background loops, all instances serve the api
+	leaderManager := leadermanager.NewLeaderManager(leaderLeaseDAO, holderID, func(leaderCtx context.Context) {
+		err := publisher.StartRandomCyclePublishZblog(leaderCtx)
+		if err != nil {
+			slog.ErrorContext(leaderCtx, "StartRandomCyclePublishZblog", logger.Err(err))
+		}
 
-	commentBot := commentbot.NewCommentBot(zAPI, configDAO, siteDAO, commentUserDAO, ai)
-	commentBot.StartCycleComment(mainCtx)
+		err = publisher.StartRandomCyclePublishWordPress(leaderCtx)
+		if err != nil {
+			slog.ErrorContext(leaderCtx, "StartRandomCyclePublishWordPress", logger.Err(err))
+		}
 
-	r := gin.Default()
-	r.Use(gin.Recovery())
+		err = publisher.StartUpdateArticleTagSignalLoop(leaderCtx, 1, 5)
+		if err != nil {
+			slog.ErrorContext(leaderCtx, "StartUpdateArticleTagSignalLoop", logger.Err(err))
+		}
+
+		publisher.StartPublishByLack(leaderCtx)
+
+		feedManager.StartCycleFetchFeed(leaderCtx)
+		articleCacheManager.StartExpireArticleCache(leaderCtx)
+		inventoryManager.StartInventoryCheck(leaderCtx)
+
+		commentBot.StartCycleComment(leaderCtx)
+	})
+	leaderManager.Start(mainCtx)
+
+	r := gin.New()
+	// handlers pass gin.Context as context, it carry the log fields of request
+	r.ContextWithFallback = true
+	r.Use(helper.LogMiddleware(), gin.Recovery())
 
 	workingDir := ""
 	if wd, ok := os.LookupEnv("WORKING_DIR"); ok {
@@ -180,18 +316,27 @@ func main() {
 
 	r.POST("/first_user", userHandler.AddFirstAdminUser)
 
+	leaderHandler := handler.NewLeaderHandler(leaderManager)
+
 	publishHandler := handler.NewPublishHandler(publisher)
 	rewriteHandler := handler.NewRewriteHandler(rewriteManager)
 	articleCacheHandler := handler.NewArticleCacheHandler(articleCacheManager)
+	inventoryHandler := handler.NewInventoryHandler(inventoryManager)
 
 	r.Use(jwtKit.InitMiddleWare())
 	r.Use(jwtKit.MiddlewareFunc())
 
+	r.GET("/leader", leaderHandler.GetLeaderStatusHandler)
+
 	configRoute := r.Group("/config")
 	configRoute.PUT("/set_un_cate_Name", publishHandler.SetConfigUnCategoryNameHandler)
 	configRoute.GET("/get_un_cate_Name", publishHandler.GetConfigUnCateNameHandler)
 	configRoute.PUT("/set_tag_blacklist", publishHandler.SetConfigTagBlackList)
 	configRoute.GET("/get_tag_blacklist", publishHandler.GetConfigTagBlackList)
+	configRoute.PUT("/set_article_cache_ttl", publishHandler.SetConfigArticleCacheTTLHandler)
+	configRoute.GET("/get_article_cache_ttl", publishHandler.GetConfigArticleCacheTTLHandler)
+	configRoute.PUT("/set_inventory_config", inventoryHandler.SetInventoryConfigHandler)
+	configRoute.GET("/get_inventory_config", inventoryHandler.GetInventoryConfigHandler)
 
 	articleRoute := r.Group("/article")
 	articleRoute.POST("/publish", publishHandler.AveragePublishHandler)
@@ -203,14 +348,27 @@ func main() {
 	articleRoute.PUT("/startAutoPublish", publishHandler.StartAutoPublishHandler)
 	articleRoute.GET("/stopAutoPublishStatus", publishHandler.GetStopAutoPublishStatusHandler)
 	articleRoute.GET("/cacheCount", publishHandler.GetArticleCacheCountHandler)
+	articleRoute.GET("/inventoryForecast", inventoryHandler.GetInventoryForecastHandler)
+	articleRoute.GET("/publishLockStats", publishHandler.GetPublishLockStatsHandler)
 	articleRoute.GET("/listPublishLaterArticleCache", articleCacheHandler.ListPublishLaterArticleCacheHandler)
 	articleRoute.GET("/listEditAbleArticleCache", articleCacheHandler.ListEditAbleArticleCacheHandler)
 	articleRoute.PUT("/updateArticleCacheStatus", articleCacheHandler.UpdateArticleCacheStatusHandler)
 	articleRoute.PUT("/editArticleCache", articleCacheHandler.EditArticleCacheHandler)
+	articleRoute.PUT("/updateArticleCachePriority", articleCacheHandler.UpdateArticleCachePriorityHandler)
+	articleRoute.PUT("/updateArticleCacheExpiresAt", articleCacheHandler.UpdateArticleCacheExpiresAtHandler)
+	articleRoute.GET("/listExpiredArticleCache", articleCacheHandler.ListExpiredArticleCacheHandler)
 	articleRoute.POST("/specifyPublish", publishHandler.SpecifyPublishHandler)
 	articleRoute.DELETE("/deleteArticleCache", articleCacheHandler.DeleteArticleCacheHandler)
+	articleRoute.GET("/export", articleCacheHandler.ExportArticleCacheHandler)
+	articleRoute.POST("/import", articleCacheHandler.ImportArticleCacheHandler)
+	articleRoute.GET("/import/:jobID", articleCacheHandler.GetImportArticleCacheJobHandler)
 	articleRoute.POST("/render", handler.RenderHandler)
 
+	articleRevisionRoute := articleRoute.Group("/revision")
+	articleRevisionRoute.GET("/list/:articleID", articleCacheHandler.ListArticleCacheRevisionsHandler)
+	articleRevisionRoute.GET("/diff", articleCacheHandler.DiffArticleCacheRevisionHandler)
+	articleRevisionRoute.POST("/restore/:revisionID", articleCacheHandler.RestoreArticleCacheRevisionHandler)
+
 	articleRewriteRoute := articleRoute.Group("/rewrite")
 	articleRewriteRoute.POST("/", rewriteHandler.RewriteHandler)
 	articleRewriteRoute.POST(("/multi_sections_rewrite"), rewriteHandler.MultiSectionsRewriteHandler)
@@ -238,6 +396,13 @@ func main() {
 	articleRewriteTestCaseRoute.DELETE("/:id", rewriteHandler.DeleteRewriteTestCaseHandler)
 
 	siteHandler := handler.NewSiteHandler(siteManager)
+	categoryTemplateHandler := handler.NewCategoryTemplateHandler(siteManager)
+	clientPoolHandler := handler.NewClientPoolHandler(map[string]handler.PoolStatser{
+		string(dbModel.CMSTypeZBlog):      zAPI,
+		string(dbModel.CMSTypeWordPress):  wordpressAPI,
+		string(dbModel.CMSTypeMetaWeblog): metaWeblogAPI,
+		string(dbModel.CMSTypeGhost):      ghostAPI,
+	}, zAPI)
 
 	siteRoute := r.Group("/site")
 	siteRoute.POST("/", siteHandler.AddSiteHandler)
@@ -247,7 +412,45 @@ func main() {
 	siteRoute.GET("/:siteID", siteHandler.GetSiteHandler)
 	siteRoute.PUT("/syncCateFromSite/:siteID", siteHandler.SyncCategoryFromSiteHandler)
 	siteRoute.PUT("/syncCateFromAllSite", siteHandler.SyncCategoryFromAllSiteHandler)
+	siteRoute.POST("/:siteID/category", siteHandler.CreateSiteCategoryHandler)
+	siteRoute.PUT("/:siteID/category/:categoryID", siteHandler.RenameSiteCategoryHandler)
+	siteRoute.DELETE("/:siteID/category/:categoryID", siteHandler.DeleteSiteCategoryHandler)
+	siteRoute.POST("/:siteID/apply_template", categoryTemplateHandler.ApplyCategoryTemplateHandler)
+	siteRoute.POST("/:siteID/clone", siteHandler.CloneSiteHandler)
+	siteRoute.PUT("/:siteID/http_options", siteHandler.SetSiteHTTPOptionsHandler)
 	siteRoute.POST("/increase_lack", siteHandler.IncreaseLackCountHandler)
+	siteRoute.GET("/client_pool_stats", clientPoolHandler.GetClientPoolStatsHandler)
+	siteRoute.GET("/zblog_client_stats", clientPoolHandler.GetZBlogClientStatsHandler)
+	siteRoute.GET("/:siteID/rewrite_profile", rewriteHandler.GetSiteRewriteProfileHandler)
+	siteRoute.PUT("/:siteID/rewrite_profile", rewriteHandler.UpsertSiteRewriteProfileHandler)
+	siteRoute.DELETE("/:siteID/rewrite_profile", rewriteHandler.DeleteSiteRewriteProfileHandler)
+
+	categoryTemplateRoute := r.Group("/category_template")
+	categoryTemplateRoute.POST("/", categoryTemplateHandler.CreateCategoryTemplateHandler)
+	categoryTemplateRoute.GET("/", categoryTemplateHandler.ListCategoryTemplatesHandler)
+	categoryTemplateRoute.GET("/:id", categoryTemplateHandler.GetCategoryTemplateHandler)
+	categoryTemplateRoute.PUT("/:id", categoryTemplateHandler.UpdateCategoryTemplateHandler)
+	categoryTemplateRoute.DELETE("/:id", categoryTemplateHandler.DeleteCategoryTemplateHandler)
+
+	webhookHandler := handler.NewWebhookHandler(webhookManager)
+
+	webhookRoute := r.Group("/webhook")
+	webhookRoute.POST("/", webhookHandler.CreateWebhookHandler)
+	webhookRoute.GET("/", webhookHandler.ListWebhooksHandler)
+	webhookRoute.PUT("/:id", webhookHandler.UpdateWebhookHandler)
+	webhookRoute.DELETE("/:id", webhookHandler.DeleteWebhookHandler)
+	webhookRoute.GET("/:id/deliveries", webhookHandler.ListWebhookDeliveriesHandler)
+	webhookRoute.POST("/:id/test", webhookHandler.TestWebhookHandler)
+
+	feedHandler := handler.NewFeedHandler(feedManager)
+
+	feedRoute := r.Group("/feed")
+	feedRoute.POST("/", feedHandler.CreateFeedHandler)
+	feedRoute.GET("/", feedHandler.ListFeedsHandler)
+	feedRoute.GET("/:id", feedHandler.GetFeedHandler)
+	feedRoute.PUT("/:id", feedHandler.UpdateFeedHandler)
+	feedRoute.DELETE("/:id", feedHandler.DeleteFeedHandler)
+	feedRoute.POST("/:id/fetch", feedHandler.FetchFeedHandler)
 
 	commentBotHandler := handler.NewCommentBotHandler(commentBot)
 
@@ -256,8 +459,117 @@ func main() {
 	commentBotRoute.PUT("/startAutoComment", commentBotHandler.StartAutoCommentHandler)
 	commentBotRoute.GET("/getStopAutoCommentStatus", commentBotHandler.GetStopAutoCommentStatusHandler)
 
-	err = r.Run(fmt.Sprintf(":%d", *port))
-	if err != nil {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", *port),
+		Handler:           r,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		<-mainCtx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		err := server.Shutdown(shutdownCtx)
+		if err != nil {
+			slog.Error("Shutdown", logger.Err(err))
+		}
+	}()
+
+	err = server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		panic(err)
 	}
+
+	leaderManager.Wait()
+	slog.Info("service is stopped")
+}
+
+// clientPoolOptions of the CMS clients kept per site, idle ttl is set by CLIENT_POOL_IDLE_TTL, e.g. "30m",
+// max size is set by CLIENT_POOL_MAX_SIZE, 0 means not limited
+func clientPoolOptions() clientpool.Options {
+	options := clientpool.Options{IdleTTL: 30 * time.Minute}
+
+	if s, ok := os.LookupEnv("CLIENT_POOL_IDLE_TTL"); ok {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			panic(err)
+		}
+
+		options.IdleTTL = d
+	}
+
+	if s, ok := os.LookupEnv("CLIENT_POOL_MAX_SIZE"); ok {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			panic(err)
+		}
+
+		options.MaxSize = n
+	}
+
+	return options
+}
+
+// cmsHTTPOptions of the requests to CMS sites, they can be overridden by site.
+// CMS_HTTP_TIMEOUT_SECONDS limit the whole request, CMS_HTTP_PROXY is the outbound proxy,
+// CMS_HTTP_USER_AGENT replace the user agent, CMS_HTTP_INSECURE_SKIP_VERIFY skip verifying the certificate
+func cmsHTTPOptions() httpclient.Options {
+	options := httpclient.Options{
+		Proxy:     os.Getenv("CMS_HTTP_PROXY"),
+		UserAgent: os.Getenv("CMS_HTTP_USER_AGENT"),
+	}
+
+	if s, ok := os.LookupEnv("CMS_HTTP_TIMEOUT_SECONDS"); ok {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			panic(err)
+		}
+
+		options.TimeoutSeconds = n
+	}
+
+	if s, ok := os.LookupEnv("CMS_HTTP_INSECURE_SKIP_VERIFY"); ok {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			panic(err)
+		}
+
+		options.InsecureSkipVerify = b
+	}
+
+	return options
+}
+
+// minJWTSecretLength is the minimum bytes of JWT_SECRET
+const minJWTSecretLength = 32
+
+// jwtSecretKey return JWT_SECRET which sign the tokens, it must be shared by every instance
+// behind the same api endpoint, so the token issued by one instance is accepted by the others
+func jwtSecretKey() string {
+	s := os.Getenv("JWT_SECRET")
+	if s == "" {
+		panic("jwt secret is not set")
+	}
+
+	if len(s) < minJWTSecretLength {
+		panic(fmt.Sprintf("jwt secret is shorter than %d bytes", minJWTSecretLength))
+	}
+
+	return s
+}
+
+// instanceID identify this instance in leader election and article claims, use INSTANCE_ID if set
+func instanceID() string {
+	if s, ok := os.LookupEnv("INSTANCE_ID"); ok && s != "" {
+		return s
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), util.GenerateRandomString(8))
 }
@@ -1,6 +1,12 @@
 package model
 
 import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
 	zModel "github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
 	publishModel "github.com/ray31245/seo_cluster/service/publish_manager/model"
 )
@@ -11,6 +17,10 @@ type PublishArticleRequest struct {
 	Content string `json:"Content"`
 	Intro   string `json:"Intro"`
 	CateID  uint32 `json:"CateID"`
+	// Priority of article cache when prepublish, higher value will be published earlier
+	Priority int `json:"Priority"`
+	// ExpiresAt article cache will not be published after this time, empty means use the default ttl
+	ExpiresAt *time.Time `json:"ExpiresAt"`
 }
 
 func (p *PublishArticleRequest) ToZBlogAPI() zModel.PostArticleRequest {
@@ -24,19 +34,29 @@ func (p *PublishArticleRequest) ToZBlogAPI() zModel.PostArticleRequest {
 
 func (p *PublishArticleRequest) ToPublishManager() publishModel.Article {
 	return publishModel.Article{
-		Title:   p.Title,
-		IsTop:   p.IsTop,
-		Content: p.Content,
-		CateID:  p.CateID,
+		Title:     p.Title,
+		IsTop:     p.IsTop,
+		Content:   p.Content,
+		CateID:    strconv.FormatUint(uint64(p.CateID), 10),
+		Priority:  p.Priority,
+		ExpiresAt: p.ExpiresAt,
 	}
 }
 
+// AddSiteRequest use the admin api key in format of {id}:{secret} as password for ghost site,
+// and the local directory as url, the git author as user name for static site.
+// AuthMode of wordpress site is one of basic, application_password, jwt and cookie, empty means basic
 type AddSiteRequest struct {
+	// URL is the xml-rpc endpoint for metaweblog site, e.g. https://example.com/action/xmlrpc
 	URL               string `json:"url"`
 	CMSType           string `json:"cms_type"`
 	UserName          string `json:"user_name"`
 	Password          string `json:"password"`
+	AuthMode          string `json:"auth_mode"`
+	Language          string `json:"language"`
 	ExpectCategoryNum uint8  `json:"expect_category_num"`
+	// TemplateID is the category template applied to the site after it is added
+	TemplateID string `json:"template_id"`
 }
 
 type UpdateSiteRequest struct {
@@ -44,6 +64,52 @@ type UpdateSiteRequest struct {
 	URL      string `json:"url"`
 	UserName string `json:"user_name"`
 	Password string `json:"password"`
+	AuthMode string `json:"auth_mode"`
+	Language string `json:"language"`
+}
+
+type CreateSiteCategoryRequest struct {
+	Name string `json:"name"`
+}
+
+type RenameSiteCategoryRequest struct {
+	Name string `json:"name"`
+}
+
+// CategoryTemplateItemRequest is a category of template, Parent is the name of another category placed before it
+type CategoryTemplateItemRequest struct {
+	Name        string `json:"name"`
+	Parent      string `json:"parent"`
+	Description string `json:"description"`
+}
+
+type CategoryTemplateRequest struct {
+	Name       string                        `json:"name"`
+	Categories []CategoryTemplateItemRequest `json:"categories"`
+}
+
+func (c *CategoryTemplateRequest) ToDBItems() []dbModel.CategoryTemplateItem {
+	res := make([]dbModel.CategoryTemplateItem, 0, len(c.Categories))
+	for _, item := range c.Categories {
+		res = append(res, dbModel.CategoryTemplateItem{Name: item.Name, Parent: item.Parent, Description: item.Description})
+	}
+
+	return res
+}
+
+type ApplyCategoryTemplateRequest struct {
+	TemplateID string `json:"template_id"`
+}
+
+// SetSiteHTTPOptionsRequest override the http options of site, empty body clear them
+type SetSiteHTTPOptionsRequest struct {
+	httpclient.Options
+}
+
+// CloneSiteRequest copy the configuration of source site to the site, DryRun only preview the changes
+type CloneSiteRequest struct {
+	SourceSiteID string `json:"source_site_id"`
+	DryRun       bool   `json:"dry_run"`
 }
 
 type IncreaseLackCountRequest struct {
@@ -69,6 +135,21 @@ type UpdateArticleCacheStatusRequest struct {
 	Status string   `json:"status"`
 }
 
+type UpdateArticleCachePriorityRequest struct {
+	IDs      []string `json:"ids"`
+	Priority int      `json:"priority"`
+}
+
+type UpdateArticleCacheExpiresAtRequest struct {
+	IDs []string `json:"ids"`
+	// ExpiresAt null means never expire
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type SetArticleCacheTTLRequest struct {
+	Hours *int `json:"hours"`
+}
+
 type EditArticleCacheRequest struct {
 	ID      string `json:"id"`
 	Title   string `json:"title"`
@@ -148,3 +229,73 @@ type SpecifyPublishRequest struct {
 	ArticleID string `json:"article_id"`
 	CateID    string `json:"cate_id"`
 }
+
+type CreateWebhookRequest struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	Secret   string   `json:"secret"`
+	Events   []string `json:"events"`
+	IsActive *bool    `json:"is_active"`
+}
+
+type UpdateWebhookRequest struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	Secret   string   `json:"secret"`
+	Events   []string `json:"events"`
+	IsActive *bool    `json:"is_active"`
+}
+
+type CreateFeedRequest struct {
+	Name            string `json:"name"`
+	URL             string `json:"url"`
+	IntervalMinutes int    `json:"interval_minutes"`
+	DefaultStatus   string `json:"default_status"`
+	IsRewrite       bool   `json:"is_rewrite"`
+	TTLHours        int    `json:"ttl_hours"`
+	IsActive        *bool  `json:"is_active"`
+}
+
+type UpdateFeedRequest struct {
+	Name            string `json:"name"`
+	URL             string `json:"url"`
+	IntervalMinutes int    `json:"interval_minutes"`
+	DefaultStatus   string `json:"default_status"`
+	IsRewrite       bool   `json:"is_rewrite"`
+	TTLHours        int    `json:"ttl_hours"`
+	IsActive        *bool  `json:"is_active"`
+}
+
+type UpsertSiteRewriteProfileRequest struct {
+	SystemPrompt              string `json:"system_prompt"`
+	Prompt                    string `json:"prompt"`
+	ExtendSystemPrompt        string `json:"extend_system_prompt"`
+	ExtendPrompt              string `json:"extend_prompt"`
+	MakeTitleSystemPrompt     string `json:"make_title_system_prompt"`
+	MakeTitlePrompt           string `json:"make_title_prompt"`
+	MultiSectionsSystemPrompt string `json:"multi_sections_system_prompt"`
+	Tone                      string `json:"tone"`
+	TargetLength              int    `json:"target_length"`
+	IsRewriteOnPublish        bool   `json:"is_rewrite_on_publish"`
+}
+
+func (r *UpsertSiteRewriteProfileRequest) ToDBModel(siteID uuid.UUID) dbModel.SiteRewriteProfile {
+	return dbModel.SiteRewriteProfile{
+		SiteID:                    siteID,
+		SystemPrompt:              r.SystemPrompt,
+		Prompt:                    r.Prompt,
+		ExtendSystemPrompt:        r.ExtendSystemPrompt,
+		ExtendPrompt:              r.ExtendPrompt,
+		MakeTitleSystemPrompt:     r.MakeTitleSystemPrompt,
+		MakeTitlePrompt:           r.MakeTitlePrompt,
+		MultiSectionsSystemPrompt: r.MultiSectionsSystemPrompt,
+		Tone:                      r.Tone,
+		TargetLength:              r.TargetLength,
+		IsRewriteOnPublish:        r.IsRewriteOnPublish,
+	}
+}
+
+type SetInventoryConfigRequest struct {
+	AlertThresholdDays *int `json:"alert_threshold_days"`
+	WindowDays         *int `json:"window_days"`
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"strings"
+
 	"github.com/google/uuid"
 	"github.com/ray31245/seo_cluster/pkg/db/model"
 )
@@ -13,10 +15,9 @@ type site struct {
 }
 
 type category struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	ZblogID     uint32    `json:"z_blog_id"`
-	WordpressID uint32    `json:"wordpress_id"`
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	CMSCategoryID string    `json:"cms_category_id"`
 }
 
 type ListSitesResponse struct {
@@ -37,7 +38,7 @@ type GetSiteResponse struct {
 func (g *GetSiteResponse) FromDBSite(s model.Site) {
 	g.Site = site{ID: s.ID, URL: s.URL, Lack: s.LackCount, CMSType: string(s.CmsType)}
 	for _, c := range s.Categories {
-		g.Categories = append(g.Categories, category{ID: c.ID, Name: c.Name, ZblogID: c.ZBlogID, WordpressID: c.WordpressID})
+		g.Categories = append(g.Categories, category{ID: c.ID, Name: c.Name, CMSCategoryID: c.CMSCategoryID})
 	}
 }
 
@@ -50,3 +51,42 @@ type RewriteTestResponse struct {
 	RewriteResponse
 	Steps []string `json:"steps"`
 }
+
+type webhook struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	URL      string    `json:"url"`
+	Events   []string  `json:"events"`
+	IsActive bool      `json:"is_active"`
+}
+
+func fromDBWebhook(w model.Webhook) webhook {
+	events := []string{}
+	if w.Events != "" {
+		events = strings.Split(w.Events, ",")
+	}
+
+	return webhook{ID: w.ID, Name: w.Name, URL: w.URL, Events: events, IsActive: w.IsActive}
+}
+
+type ListWebhooksResponse struct {
+	Webhooks []webhook `json:"webhooks"`
+}
+
+func (l *ListWebhooksResponse) FromDBWebhooks(webhooks []model.Webhook) {
+	l.Webhooks = []webhook{}
+	for _, w := range webhooks {
+		l.Webhooks = append(l.Webhooks, fromDBWebhook(w))
+	}
+}
+
+type CreateWebhookResponse struct {
+	Webhook webhook `json:"webhook"`
+	// Secret is only returned once when the webhook is created
+	Secret string `json:"secret"`
+}
+
+func (c *CreateWebhookResponse) FromDBWebhook(w model.Webhook) {
+	c.Webhook = fromDBWebhook(w)
+	c.Secret = w.Secret
+}
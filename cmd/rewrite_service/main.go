@@ -5,18 +5,26 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"time"
 
 	aiassist "github.com/ray31245/seo_cluster/pkg/ai_assist"
+	aiModel "github.com/ray31245/seo_cluster/pkg/ai_assist/model"
+	"github.com/ray31245/seo_cluster/pkg/logger"
 	"github.com/ray31245/seo_cluster/pkg/util"
 )
 
 var APIKey string //nolint:gochecknoglobals // APIKey can input from ldflags
 
 func main() {
-	log.SetFlags(log.Lshortfile | log.LstdFlags)
+	logOptions, err := logger.LoadOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger.Init(logOptions)
 
 	ctx := context.Background()
 
@@ -46,26 +54,27 @@ func main() {
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
-			log.Println(err)
+			slog.ErrorContext(r.Context(), "read body", logger.Err(err))
 
 			return
 		}
 
-		art, err := ai.Rewrite(ctx, body)
+		// output language can be selected by query, e.g. ?lang=en
+		art, err := ai.Rewrite(ctx, aiModel.Language(r.URL.Query().Get("lang")), body)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
-			log.Println(err)
+			slog.ErrorContext(r.Context(), "Rewrite", logger.Err(err))
 
 			return
 		}
 
 		art.Content = string(util.MdToHTML([]byte(art.Content)))
-		log.Printf("%s", art.Content)
+		slog.DebugContext(r.Context(), "rewritten", slog.String("title", art.Title), slog.String("content", art.Content))
 
 		result, err := util.EscapeHTMLMarshal(art)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
-			log.Println(err)
+			slog.ErrorContext(r.Context(), "EscapeHTMLMarshal", logger.Err(err))
 
 			return
 		}
@@ -74,10 +83,10 @@ func main() {
 		fmt.Fprintf(w, "%s", result)
 	})
 
-	log.Printf("Server is running on port %d\n", port)
+	slog.Info("server is running", slog.Int("port", port))
 	// listen on port specified port
 	err = server.ListenAndServe()
 	if err != nil {
-		log.Println("Error: ", err)
+		slog.Error("ListenAndServe", logger.Err(err))
 	}
 }
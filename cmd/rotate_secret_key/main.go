@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ray31245/seo_cluster/pkg/db"
+	"github.com/ray31245/seo_cluster/pkg/secret"
+)
+
+// rotate_secret_key encrypt the passwords of sites and comment users by the primary key again.
+// The keys are loaded like publish_manager_service, rotate the key by appending a new key to SECRET_KEY_FILE,
+// running this command and then removing the old key after every row is rotated.
+// The plaintext passwords stored before encryption is enabled are encrypted too.
+//
+// usage:
+//
+//	rotate_secret_key
+//	rotate_secret_key -generate
+func main() {
+	generate := flag.Bool("generate", false, "print a new random key for SECRET_KEY or SECRET_KEY_FILE")
+	flag.Parse()
+
+	if *generate {
+		key, err := secret.GenerateKey()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println(key)
+
+		return
+	}
+
+	keyring, err := secret.LoadKeyring()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if keyring == nil {
+		log.Fatal("SECRET_KEY or SECRET_KEY_FILE is not set")
+	}
+
+	dsn := "publish_manager.db"
+	if s, ok := os.LookupEnv("DSN"); ok {
+		dsn = s
+	}
+
+	publishDB, err := db.NewDB(dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer publishDB.Close()
+
+	publishDB.UseSecretKeyring(keyring)
+
+	siteDAO, err := publishDB.NewSiteDAO()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, err := siteDAO.RotateSiteSecrets()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("rotated %d sites to key %q", count, keyring.PrimaryID())
+
+	commentBotDSN := "comment_bot.db"
+	if s, ok := os.LookupEnv("COMMENT_BOT_DSN"); ok {
+		commentBotDSN = s
+	}
+
+	commentBotDB, err := db.NewDB(commentBotDSN)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer commentBotDB.Close()
+
+	commentBotDB.UseSecretKeyring(keyring)
+
+	commentUserDAO, err := commentBotDB.NewCommentUserDAO()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, err = commentUserDAO.RotateCommentUserSecrets()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("rotated %d comment users to key %q", count, keyring.PrimaryID())
+}
@@ -5,6 +5,8 @@ import (
 	"log"
 	"time"
 
+	clientpool "github.com/ray31245/seo_cluster/pkg/client_pool"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
 	zBlogApi "github.com/ray31245/seo_cluster/pkg/z_blog_api"
 	zModel "github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
 
@@ -75,7 +77,7 @@ func watchNumbersOfCategory(ctx context.Context) {
 		password string
 	}
 
-	a := zBlogApi.NewZBlogAPI()
+	a := zBlogApi.NewZBlogAPI(clientpool.Options{}, httpclient.NewDefaultFactory())
 	sites := []site{
 		{id: uuid.New(), url: "http://www.test.com", userName: "bevis", password: "3cc31cd246149aec68079241e71e98f6"},
 		{id: uuid.New(), url: "http://www.test2.com", userName: "bevis", password: "3cc31cd246149aec68079241e71e98f6"},
@@ -88,7 +90,7 @@ func watchNumbersOfCategory(ctx context.Context) {
 		counts := make([]int, len(sites))
 
 		for i, v := range sites {
-			test, err := a.GetClient(ctx, v.id, v.url, v.userName, v.password)
+			test, err := a.GetClient(ctx, v.id, v.url, v.userName, v.password, httpclient.Options{})
 			if err != nil {
 				log.Fatalln(err)
 			}
@@ -7,6 +7,8 @@ import (
 	"sync"
 	"time"
 
+	clientpool "github.com/ray31245/seo_cluster/pkg/client_pool"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
 	zBlogApi "github.com/ray31245/seo_cluster/pkg/z_blog_api"
 	zModel "github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
 
@@ -27,7 +29,7 @@ type site struct {
 func watchNumbersOfCategory() {
 	ctx := context.Background()
 
-	a := zBlogApi.NewZBlogAPI()
+	a := zBlogApi.NewZBlogAPI(clientpool.Options{}, httpclient.NewDefaultFactory())
 	sites := []site{
 		{id: uuid.New(), url: "https://www.example.com", userName: "usr", password: "pwd"},
 	}
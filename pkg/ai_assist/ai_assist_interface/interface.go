@@ -8,11 +8,11 @@ import (
 
 type AIAssistInterface interface {
 	CustomRewrite(ctx context.Context, systemPrompt string, prompt string, content []byte) (string, error)
-	Rewrite(ctx context.Context, text []byte) (model.RewriteResponse, error)
-	ExtendRewrite(ctx context.Context, text []byte) (model.ExtendRewriteResponse, error)
+	Rewrite(ctx context.Context, lang model.Language, text []byte) (model.RewriteResponse, error)
+	ExtendRewrite(ctx context.Context, lang model.Language, text []byte) (model.ExtendRewriteResponse, error)
 	MultiSectionsRewrite(ctx context.Context, systemPrompt string, content string) (string, error)
-	Comment(ctx context.Context, text []byte) (model.CommentResponse, error)
-	FindKeyWords(ctx context.Context, text []byte) (model.FindKeyWordsResponse, error)
+	Comment(ctx context.Context, lang model.Language, text []byte) (model.CommentResponse, error)
+	FindKeyWords(ctx context.Context, lang model.Language, text []byte) (model.FindKeyWordsResponse, error)
 	SelectCategory(ctx context.Context, req model.SelectCategoryRequest) (model.SelectCategoryResponse, error)
 	MakeTitle(ctx context.Context, systemPrompt string, prompt string, content []byte) (string, error)
 	Lock()
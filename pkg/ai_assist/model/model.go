@@ -1,5 +1,7 @@
 package model
 
+import "slices"
+
 type RewriteResponse struct {
 	Title   string `json:"Title"`
 	Content string `json:"Content"`
@@ -33,9 +35,28 @@ type CategoryOption struct {
 type SelectCategoryRequest struct {
 	Text             []byte
 	CategoriesOption []CategoryOption
+	// Language of the prompt, empty means DefaultLanguage
+	Language Language
 }
 
 type SelectCategoryResponse struct {
 	ID     string `json:"id"`
 	IsFind bool   `json:"isFind"`
 }
+
+// Language of the generated content, value is BCP 47 language tag
+type Language string
+
+const (
+	LanguageSimplifiedChinese  Language = "zh-Hans"
+	LanguageTraditionalChinese Language = "zh-Hant"
+	LanguageEnglish            Language = "en"
+
+	DefaultLanguage = LanguageSimplifiedChinese
+)
+
+var Languages = []Language{LanguageSimplifiedChinese, LanguageTraditionalChinese, LanguageEnglish} //nolint:gochecknoglobals // supported languages
+
+func IsValidLanguage(lang Language) bool {
+	return slices.Contains(Languages, lang)
+}
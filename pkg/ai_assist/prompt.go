@@ -0,0 +1,80 @@
+package aiassist
+
+import "github.com/ray31245/seo_cluster/pkg/ai_assist/model"
+
+// promptSet prompts for generating content in one language
+type promptSet struct {
+	rewrite        string
+	extendRewrite  string
+	comment        string
+	findKeyWords   string
+	selectCategory selectCategoryPrompt
+}
+
+// selectCategoryPrompt prompts of SelectCategory, examples show the model how to choose
+type selectCategoryPrompt struct {
+	examples []string
+	// article format of article to select category, with %s for content
+	article string
+	options string
+}
+
+//nolint:gosmopolitan // prompt is a string
+var chineseSelectCategory = selectCategoryPrompt{ //nolint:gochecknoglobals // prompt of select category
+	examples: []string{
+		"請幫我對以下文章根據提供的選項選擇最適合的分類，如果有多個分類選擇順序最優先的分類，例如選項（币安交易所、加密货币交易所、火币交易所、加密货币再质押）那順序最優先的選項就是\"币安交易所\"。",
+		"完整的例子如下：",
+		"文章：<p>本文分析了埃隆·马斯克在2024年美国大选中对唐纳德·特朗普胜选所起到的关键作用，以及这种合作关系对美国政治和社会可能产生的深远影响。马斯克利用其在科技、商业和社交媒体上的巨大影响力，为特朗普的竞选提供了资金、地面组织和宣传支持，吸引了大量对特朗普的政策感兴趣但对其性格感到厌倦的年轻选民。</p>\n\n<p>文章指出，马斯克与特朗普的合作关系并非完全一致，两人的个性都强势，未来可能出现冲突。马斯克的动机也值得深思，其最终目标可能是为了实现其在太空探索方面的宏伟计划，而将美国政府作为实现这一目标的工具。</p>\n\n<p>马斯克被特朗普任命领导一个名为“政府效率部 (DOGE)”的新部门，其目标是精简联邦政府机构，削减开支。文章质疑了这一目标的可行性以及对社会福利计划可能造成的负面影响，特别是对弱势群体的冲击。马斯克的“效率”承诺可能导致医疗、教育等公共服务的削减，对依赖政府支持的民众造成严重后果。</p>\n\n<p>文章还探讨了马斯克与特朗普合作关系中潜在的利益冲突，以及马斯克在影响政府监管机构方面可能扮演的角色。马斯克旗下公司特斯拉和SpaceX此前都与政府监管机构发生过冲突，而“政府效率部”的成立可能使其更容易影响甚至规避这些监管。</p>\n\n<p>文章最后指出，马斯克的政治立场并不明确，其与特朗普的关系也曾经历过动荡。马斯克所宣扬的“言论自由”理念与其在Twitter上的行为存在矛盾，其对“工作思维病毒”的批评也反映出其某种意识形态立场。文章警告说，马斯克和特朗普的合作可能导致一种“寡头政治”的局面，对美国的民主和社会公平造成威胁，普通民众可能无法从这种权力结合中获益，反而可能遭受损失。</p>\n<img src=\"https://img.jinse.cn/7324717_watermarknone.png\"/><img src=\"https://img.jinse.cn/7324724_watermarknone.png\"/><img src=\"https://img.jinse.cn/7324738_watermarknone.png\"/><img src=\"https://img.jinse.cn/7324746_watermarknone.png\"/>",
+		"選項：[{name:DOGE,id:ba9f1ec9-bfd7-405f-967b-45449011fbe5},{name:马斯克,id:22f585dd-c50d-4d8f-93c6-534eb89682c7},{name:時事,id:c32084ff-b335-4c60-8804-8fa9d54cf216},{name:美食,id:dcdc2fe2-6a1b-4231-9179-48ee3520de2a},{name:美国政府,id:48fb04ce-1834-474b-94e6-addaaedf6dc2}]，這些選項中的id為ba9f1ec9-bfd7-405f-967b-45449011fbe5或22f585dd-c50d-4d8f-93c6-534eb89682c7或c32084ff-b335-4c60-8804-8fa9d54cf216或48fb04ce-1834-474b-94e6-addaaedf6dc2都符合這個文章的分類，但是順序最優先的分類為ba9f1ec9-bfd7-405f-967b-45449011fbe5。那回傳結果會是：{id:\"ba9f1ec9-bfd7-405f-967b-45449011fbe5\",isFind:true}",
+		"另外如果選項中找不到符合的分類則回傳空字串，以上面同一例子來說，選項為[{name:沙丁魚,id:ba9f1ec9-bfd7-405f-967b-45449011fbe5},{name:園藝景觀,id:22f585dd-c50d-4d8f-93c6-534eb89682c7},{name:咖啡,id:c32084ff-b335-4c60-8804-8fa9d54cf216},{name:美食,id:dcdc2fe2-6a1b-4231-9179-48ee3520de2a},{name:宮崎英高,id:48fb04ce-1834-474b-94e6-addaaedf6dc2}]，這些選項都不符合此文章的分類則回傳空字串。那回傳結果會是：{id:\"\",isFind:false}",
+		"請注意，以上的文章和選項只是範例，請勿使用述的選項及文章做爲回答。",
+	},
+	article: "文章：%s\n",
+	options: "選項：",
+}
+
+//nolint:gosmopolitan // prompt is a string
+var promptSets = map[model.Language]promptSet{ //nolint:gochecknoglobals // prompt set of each language
+	model.LanguageSimplifiedChinese: {
+		rewrite:        "你是一位收悉区块链的简体中文语系专栏作家，请你将以下内容用你的话重新阐述文章中的内容，并且以你认为没有AI痕迹的方式表达，并订一个标题。请使用json格式输出：{Title: string,Content: string}",
+		extendRewrite:  "你是一位收悉区块链的简体中文语系专栏作家，你需要对这篇文章进行扩展，并且以你认为没有AI痕迹的方式表达。请使用json格式输出：{Title: string,Content: string}",
+		comment:        "你是一位简体中文语系读者,你在网路上看到以下文章，请随性且简洁地在这篇文章下留言。并且以一位看新闻的人的角度记录这个文章能够为你提供的价值。最低0分滿分100分。请使用json格式输出：{Comment: string, Score: int}\n。",
+		findKeyWords:   "你是一位区块链专栏作家并且擅长seo，你需要列出这篇文章中与区块链、数位货币、投资相关的中文核心关键字。请使用json格式输出：{KeyWords: []string}。",
+		selectCategory: chineseSelectCategory,
+	},
+	model.LanguageTraditionalChinese: {
+		rewrite:        "你是一位熟悉區塊鏈的繁體中文語系專欄作家，請你將以下內容用你的話重新闡述文章中的內容，並且以你認為沒有AI痕跡的方式表達，並訂一個標題，標題及內容都必須使用繁體中文。請使用json格式輸出：{Title: string,Content: string}",
+		extendRewrite:  "你是一位熟悉區塊鏈的繁體中文語系專欄作家，你需要對這篇文章進行擴展，並且以你認為沒有AI痕跡的方式表達，標題及內容都必須使用繁體中文。請使用json格式輸出：{Title: string,Content: string}",
+		comment:        "你是一位繁體中文語系讀者，你在網路上看到以下文章，請隨性且簡潔地用繁體中文在這篇文章下留言。並且以一位看新聞的人的角度記錄這個文章能夠為你提供的價值。最低0分滿分100分。請使用json格式輸出：{Comment: string, Score: int}\n。",
+		findKeyWords:   "你是一位區塊鏈專欄作家並且擅長seo，你需要列出這篇文章中與區塊鏈、數位貨幣、投資相關的繁體中文核心關鍵字。請使用json格式輸出：{KeyWords: []string}。",
+		selectCategory: chineseSelectCategory,
+	},
+	model.LanguageEnglish: {
+		rewrite:       "You are an English-language columnist familiar with blockchain. Restate the following content in your own words, in a way you believe shows no trace of AI, and give it a title. Both title and content must be written in English, even if the source is not. Output in json format: {Title: string,Content: string}",
+		extendRewrite: "You are an English-language columnist familiar with blockchain. Expand the following article, in a way you believe shows no trace of AI. Both title and content must be written in English, even if the source is not. Output in json format: {Title: string,Content: string}",
+		comment:       "You are an English-speaking reader who came across the following article online. Leave a casual and concise comment in English under the article. Also rate, from the point of view of someone reading the news, the value this article provides to you, from 0 to 100. Output in json format: {Comment: string, Score: int}\n",
+		findKeyWords:  "You are a blockchain columnist skilled in SEO. List the core English keywords in this article related to blockchain, digital currency and investment. Output in json format: {KeyWords: []string}",
+		selectCategory: selectCategoryPrompt{
+			examples: []string{
+				"Please choose the most suitable category for the following article from the provided options. If several categories fit, choose the one that comes first in the options, e.g. for the options (Binance, Crypto Exchanges, Huobi, Crypto Restaking) the first fitting option is \"Binance\".",
+				"A complete example:",
+				"Article: <p>Binance announced on Monday that it will list a new staking product for Ethereum holders, allowing users of the exchange to earn rewards without running their own validator.</p>",
+				"Options: [{name:Binance,id:ba9f1ec9-bfd7-405f-967b-45449011fbe5},{name:Crypto Exchanges,id:22f585dd-c50d-4d8f-93c6-534eb89682c7},{name:Cooking,id:dcdc2fe2-6a1b-4231-9179-48ee3520de2a}], the options with id ba9f1ec9-bfd7-405f-967b-45449011fbe5 or 22f585dd-c50d-4d8f-93c6-534eb89682c7 both fit this article, but the first one is ba9f1ec9-bfd7-405f-967b-45449011fbe5. So the result is: {id:\"ba9f1ec9-bfd7-405f-967b-45449011fbe5\",isFind:true}",
+				"If no option fits, return an empty string. With the same article and the options [{name:Sardines,id:ba9f1ec9-bfd7-405f-967b-45449011fbe5},{name:Gardening,id:22f585dd-c50d-4d8f-93c6-534eb89682c7}], no option fits this article, so the result is: {id:\"\",isFind:false}",
+				"Note that the article and options above are only examples, do not use them in your answer.",
+			},
+			article: "Article: %s\n",
+			options: "Options: ",
+		},
+	},
+}
+
+// getPromptSet return prompts of the language, fall back to the default language if not supported
+func getPromptSet(lang model.Language) promptSet {
+	set, ok := promptSets[lang]
+	if !ok {
+		return promptSets[model.DefaultLanguage]
+	}
+
+	return set
+}
@@ -0,0 +1,36 @@
+package aiassist
+
+import (
+	"testing"
+
+	"github.com/ray31245/seo_cluster/pkg/ai_assist/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getPromptSet(t *testing.T) {
+	t.Parallel()
+
+	for _, lang := range model.Languages {
+		t.Run(string(lang), func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			set, ok := promptSets[lang]
+			assert.True(ok)
+			assert.NotEmpty(set.rewrite)
+			assert.NotEmpty(set.extendRewrite)
+			assert.NotEmpty(set.comment)
+			assert.NotEmpty(set.findKeyWords)
+			assert.NotEmpty(set.selectCategory.examples)
+			assert.Contains(set.selectCategory.article, "%s")
+			assert.Equal(set, getPromptSet(lang))
+		})
+	}
+
+	t.Run("fall back to default language", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, promptSets[model.DefaultLanguage], getPromptSet(""))
+		assert.Equal(t, promptSets[model.DefaultLanguage], getPromptSet("fr"))
+	})
+}
@@ -0,0 +1,238 @@
+package clientpool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Options of pool, zero value means the client is never evicted and the pool is not limited
+type Options struct {
+	// IdleTTL evict the client not used for this duration
+	IdleTTL time.Duration
+	// MaxSize evict the least recently used client when the pool is full
+	MaxSize int
+}
+
+// Stats of pool
+type Stats struct {
+	// Size is the number of clients in pool, including the ones being created
+	Size int `json:"size"`
+	// Hits is the number of GetClient served by a client in pool
+	Hits uint64 `json:"hits"`
+	// Misses is the number of clients created by GetClient
+	Misses uint64 `json:"misses"`
+	// Waits is the number of GetClient waiting the client being created by another GetClient
+	Waits uint64 `json:"waits"`
+	// Evictions is the number of clients evicted by idle ttl or max size
+	Evictions uint64 `json:"evictions"`
+}
+
+type entry[T any] struct {
+	client   T
+	err      error
+	lastUsed time.Time
+	// ready is closed after the client is created
+	ready chan struct{}
+}
+
+func (e *entry[T]) isReady() bool {
+	select {
+	case <-e.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// Pool keep the clients by site id or user id, it is safe for concurrent use.
+// Concurrent Get of the same id share one creation of client.
+type Pool[T any] struct {
+	lock    sync.Mutex
+	options Options
+	entries map[uuid.UUID]*entry[T]
+	stats   Stats
+	// now is replaced in test
+	now func() time.Time
+}
+
+func NewPool[T any](options Options) *Pool[T] {
+	return &Pool[T]{
+		options: options,
+		entries: make(map[uuid.UUID]*entry[T]),
+		now:     time.Now,
+	}
+}
+
+// Get return the client of ID in pool, or create it by create if it is not in pool or idle too long.
+// The client is not kept if create return error.
+// create run with the context not canceled with ctx, so the other callers waiting the same creation
+// are not failed by the caller started it, and every caller stop waiting when its own ctx is done.
+func (p *Pool[T]) Get(ctx context.Context, ID uuid.UUID, create func(ctx context.Context) (T, error)) (T, error) {
+	p.lock.Lock()
+
+	e, ok := p.entries[ID]
+	if ok && e.isReady() && p.isExpired(e) {
+		delete(p.entries, ID)
+		p.stats.Evictions++
+
+		ok = false
+	}
+
+	if ok {
+		if e.isReady() {
+			p.stats.Hits++
+			e.lastUsed = p.now()
+		} else {
+			p.stats.Waits++
+		}
+
+		p.lock.Unlock()
+
+		return wait(ctx, e)
+	}
+
+	e = &entry[T]{ready: make(chan struct{})}
+	p.entries[ID] = e
+	p.stats.Misses++
+	p.lock.Unlock()
+
+	createCtx := context.WithoutCancel(ctx)
+
+	go func() {
+		client, err := create(createCtx)
+		p.finish(ID, e, client, err)
+	}()
+
+	return wait(ctx, e)
+}
+
+// Put keep the client of ID in pool, the previous one is replaced
+func (p *Pool[T]) Put(ID uuid.UUID, client T) {
+	e := &entry[T]{ready: make(chan struct{})}
+
+	p.lock.Lock()
+	p.entries[ID] = e
+	p.lock.Unlock()
+
+	p.finish(ID, e, client, nil)
+}
+
+func (p *Pool[T]) Delete(ID uuid.UUID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.entries, ID)
+}
+
+// Range call f for each client in pool, the clients being created or failed are skipped
+func (p *Pool[T]) Range(f func(ID uuid.UUID, client T)) {
+	p.lock.Lock()
+
+	clients := make(map[uuid.UUID]T, len(p.entries))
+	for ID, e := range p.entries {
+		if e.isReady() && e.err == nil {
+			clients[ID] = e.client
+		}
+	}
+
+	p.lock.Unlock()
+
+	for ID, client := range clients {
+		f(ID, client)
+	}
+}
+
+func (p *Pool[T]) Stats() Stats {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.evictExpired()
+
+	stats := p.stats
+	stats.Size = len(p.entries)
+
+	return stats
+}
+
+// finish store the result of creation to entry and wake up the waiters
+func (p *Pool[T]) finish(ID uuid.UUID, e *entry[T], client T, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	e.client = client
+	e.err = err
+	e.lastUsed = p.now()
+	close(e.ready)
+
+	if err != nil {
+		// the entry may be replaced by Put or removed by Delete during creation
+		if p.entries[ID] == e {
+			delete(p.entries, ID)
+		}
+
+		return
+	}
+
+	p.evictExpired()
+	p.evictOverflow()
+}
+
+func (p *Pool[T]) isExpired(e *entry[T]) bool {
+	return p.options.IdleTTL > 0 && p.now().Sub(e.lastUsed) > p.options.IdleTTL
+}
+
+func (p *Pool[T]) evictExpired() {
+	if p.options.IdleTTL <= 0 {
+		return
+	}
+
+	for ID, e := range p.entries {
+		if e.isReady() && p.isExpired(e) {
+			delete(p.entries, ID)
+			p.stats.Evictions++
+		}
+	}
+}
+
+// evictOverflow evict the least recently used clients until the pool is not over max size,
+// the clients being created are not evicted
+func (p *Pool[T]) evictOverflow() {
+	if p.options.MaxSize <= 0 {
+		return
+	}
+
+	for len(p.entries) > p.options.MaxSize {
+		var (
+			oldestID uuid.UUID
+			oldest   *entry[T]
+		)
+
+		for ID, e := range p.entries {
+			if e.isReady() && (oldest == nil || e.lastUsed.Before(oldest.lastUsed)) {
+				oldestID, oldest = ID, e
+			}
+		}
+
+		if oldest == nil {
+			return
+		}
+
+		delete(p.entries, oldestID)
+		p.stats.Evictions++
+	}
+}
+
+// wait the client of entry is created, or ctx is done
+func wait[T any](ctx context.Context, e *entry[T]) (T, error) {
+	select {
+	case <-e.ready:
+		return e.client, e.err
+	case <-ctx.Done():
+		var zero T
+
+		return zero, ctx.Err()
+	}
+}
@@ -0,0 +1,257 @@
+package clientpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testClient struct {
+	name string
+}
+
+// fakeClock is the time of pool moved by test
+type fakeClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newTestPool(options Options) (*Pool[*testClient], *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	pool := NewPool[*testClient](options)
+	pool.now = clock.Now
+
+	return pool, clock
+}
+
+func create(name string) func(context.Context) (*testClient, error) {
+	return func(context.Context) (*testClient, error) { return &testClient{name: name}, nil }
+}
+
+func TestPool_SingleFlight(t *testing.T) {
+	pool, _ := newTestPool(Options{})
+	ID := uuid.New()
+
+	var creations atomic.Int32
+
+	release := make(chan struct{})
+	slowCreate := func(context.Context) (*testClient, error) {
+		creations.Add(1)
+		<-release
+
+		return &testClient{name: "slow"}, nil
+	}
+
+	const n = 20
+
+	var wg sync.WaitGroup
+
+	clients := make([]*testClient, n)
+
+	for i := range n {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			client, err := pool.Get(context.Background(), ID, slowCreate)
+			assert.NoError(t, err)
+
+			clients[i] = client
+		}()
+	}
+
+	// let the goroutines wait for the creation
+	assert.Eventually(t, func() bool { return pool.Stats().Waits+pool.Stats().Misses == n }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), creations.Load())
+
+	for _, client := range clients {
+		assert.Same(t, clients[0], client)
+	}
+
+	// the callers waiting the creation are not hits
+	assert.Equal(t, Stats{Size: 1, Waits: n - 1, Misses: 1}, pool.Stats())
+}
+
+func TestPool_CreatorCanceled(t *testing.T) {
+	pool, _ := newTestPool(Options{})
+	ID := uuid.New()
+
+	release := make(chan struct{})
+	slowCreate := func(ctx context.Context) (*testClient, error) {
+		<-release
+
+		// the creation is not canceled with the caller started it
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		return &testClient{name: "slow"}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	creatorErr := make(chan error, 1)
+
+	go func() {
+		_, err := pool.Get(ctx, ID, slowCreate)
+		creatorErr <- err
+	}()
+
+	assert.Eventually(t, func() bool { return pool.Stats().Misses == 1 }, time.Second, time.Millisecond)
+
+	waiter := make(chan *testClient, 1)
+
+	go func() {
+		client, err := pool.Get(context.Background(), ID, create("b"))
+		assert.NoError(t, err)
+
+		waiter <- client
+	}()
+
+	assert.Eventually(t, func() bool { return pool.Stats().Waits == 1 }, time.Second, time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-creatorErr, context.Canceled)
+
+	close(release)
+	assert.Equal(t, "slow", (<-waiter).name)
+}
+
+func TestPool_CreateError(t *testing.T) {
+	pool, _ := newTestPool(Options{})
+	ID := uuid.New()
+	errCreate := errors.New("create error")
+
+	_, err := pool.Get(context.Background(), ID, func(context.Context) (*testClient, error) { return nil, errCreate })
+	require.ErrorIs(t, err, errCreate)
+	assert.Equal(t, 0, pool.Stats().Size)
+
+	client, err := pool.Get(context.Background(), ID, create("a"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", client.name)
+}
+
+func TestPool_WaitCanceled(t *testing.T) {
+	pool, _ := newTestPool(Options{})
+	ID := uuid.New()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	go func() {
+		_, _ = pool.Get(context.Background(), ID, func(context.Context) (*testClient, error) {
+			<-release
+
+			return &testClient{}, nil
+		})
+	}()
+
+	assert.Eventually(t, func() bool { return pool.Stats().Misses == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := pool.Get(ctx, ID, create("b"))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPool_IdleTTL(t *testing.T) {
+	pool, clock := newTestPool(Options{IdleTTL: time.Minute})
+	ID := uuid.New()
+
+	_, err := pool.Get(context.Background(), ID, create("a"))
+	require.NoError(t, err)
+
+	// used again before ttl keep the client
+	clock.Add(50 * time.Second)
+
+	client, err := pool.Get(context.Background(), ID, create("b"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", client.name)
+
+	clock.Add(50 * time.Second)
+
+	client, err = pool.Get(context.Background(), ID, create("b"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", client.name)
+
+	clock.Add(2 * time.Minute)
+
+	client, err = pool.Get(context.Background(), ID, create("b"))
+	require.NoError(t, err)
+	assert.Equal(t, "b", client.name)
+	assert.Equal(t, uint64(1), pool.Stats().Evictions)
+
+	clock.Add(2 * time.Minute)
+	assert.Equal(t, Stats{Size: 0, Hits: 2, Misses: 2, Evictions: 2}, pool.Stats())
+}
+
+func TestPool_MaxSize(t *testing.T) {
+	pool, clock := newTestPool(Options{MaxSize: 2})
+	IDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	for _, ID := range IDs[:2] {
+		_, err := pool.Get(context.Background(), ID, create(ID.String()))
+		require.NoError(t, err)
+		clock.Add(time.Second)
+	}
+
+	// IDs[0] is used recently, so IDs[1] is evicted
+	_, err := pool.Get(context.Background(), IDs[0], create("new"))
+	require.NoError(t, err)
+	clock.Add(time.Second)
+
+	_, err = pool.Get(context.Background(), IDs[2], create(IDs[2].String()))
+	require.NoError(t, err)
+
+	assert.Equal(t, Stats{Size: 2, Hits: 1, Misses: 3, Evictions: 1}, pool.Stats())
+
+	client, err := pool.Get(context.Background(), IDs[1], create("new"))
+	require.NoError(t, err)
+	assert.Equal(t, "new", client.name)
+}
+
+func TestPool_PutDelete(t *testing.T) {
+	pool, _ := newTestPool(Options{})
+	ID := uuid.New()
+
+	pool.Put(ID, &testClient{name: "put"})
+
+	client, err := pool.Get(context.Background(), ID, create("a"))
+	require.NoError(t, err)
+	assert.Equal(t, "put", client.name)
+
+	names := map[uuid.UUID]string{}
+	pool.Range(func(ID uuid.UUID, client *testClient) { names[ID] = client.name })
+	assert.Equal(t, map[uuid.UUID]string{ID: "put"}, names)
+
+	pool.Delete(ID)
+
+	client, err = pool.Get(context.Background(), ID, create("a"))
+	require.NoError(t, err)
+	assert.Equal(t, "a", client.name)
+}
@@ -0,0 +1,10 @@
+package cmsdriver
+
+const (
+	// randomCycleDailyDemand is the articles a site of random cycle consumes per day,
+	// the cycle runs every 14.4 hours and adds 0.5 lack in average
+	randomCycleDailyDemand = 24.0 / 14.4 * 0.5
+	// timePointsDailyDemand is the articles a site of time points consumes per day,
+	// 4 or 5 time points a day and 1 lack each
+	timePointsDailyDemand = 4.5
+)
@@ -0,0 +1,53 @@
+package cmsdriverinterface
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+)
+
+// Registry look up the driver of a CMS type
+type Registry interface {
+	Driver(cmsType dbModel.CMSType) (Driver, error)
+	// CMSTypes return the registered CMS types
+	CMSTypes() []dbModel.CMSType
+}
+
+// Driver is the entry of a CMS platform, it manages the clients of sites
+// and knows how often its sites are published.
+type Driver interface {
+	CMSType() dbModel.CMSType
+	// NewClient login to site, it is used to validate the site before it is added
+	NewClient(ctx context.Context, cred model.Credential) (Client, error)
+	// GetClient return the cached client of ID, login if not cached
+	GetClient(ctx context.Context, ID uuid.UUID, cred model.Credential) (Client, error)
+	UpdateClient(ctx context.Context, ID uuid.UUID, cred model.Credential) (Client, error)
+	DeleteClient(ID uuid.UUID)
+	NewAnonymousClient(ctx context.Context, urlStr string) Client
+	// PublishCadence return how the random publish cycles add lack to the sites of CMS
+	PublishCadence() model.PublishCadence
+	// ExpectedDailyDemand return the expected number of articles a site consumes per day by its cadence
+	ExpectedDailyDemand() float64
+}
+
+// Client is the client of a site in CMS
+type Client interface {
+	ListCategory(ctx context.Context) ([]model.Category, error)
+	CreateCategory(ctx context.Context, args model.CreateCategoryArgs) (model.Category, error)
+	// RenameCategory rename the category of CMS id
+	RenameCategory(ctx context.Context, ID string, name string) (model.Category, error)
+	// DeleteCategory delete the category of CMS id, the articles of it are moved to default category by CMS
+	DeleteCategory(ctx context.Context, ID string) error
+	GetArticle(ctx context.Context, ID string) (model.Article, error)
+	// ListArticle list the latest articles of site
+	ListArticle(ctx context.Context) ([]model.Article, error)
+	PostArticle(ctx context.Context, article model.Article) (model.Article, error)
+	// UpdateArticleTags replace the tags of article
+	UpdateArticleTags(ctx context.Context, ID string, tags []model.Tag) error
+	DeleteArticle(ctx context.Context, ID string) error
+	ListTagAll(ctx context.Context) ([]model.Tag, error)
+	CreateTag(ctx context.Context, name string) (model.Tag, error)
+	PostComment(ctx context.Context, articleID string, content string) error
+}
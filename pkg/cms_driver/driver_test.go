@@ -0,0 +1,320 @@
+package cmsdriver_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	clientpool "github.com/ray31245/seo_cluster/pkg/client_pool"
+	cmsdriver "github.com/ray31245/seo_cluster/pkg/cms_driver"
+	"github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	wordpressApi "github.com/ray31245/seo_cluster/pkg/wordpress_api"
+	zModel "github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
+	zInterface "github.com/ray31245/seo_cluster/pkg/z_blog_api/z_blog_Interface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeZBlogAPI return the same fake client for every site
+type fakeZBlogAPI struct {
+	client *fakeZBlogClient
+}
+
+func (f *fakeZBlogAPI) GetClient(_ context.Context, _ uuid.UUID, _ string, _ string, _ string, _ httpclient.Options) (zInterface.ZBlogAPIClient, error) {
+	return f.client, nil
+}
+
+func (f *fakeZBlogAPI) UpdateClient(_ context.Context, _ uuid.UUID, _ string, _ string, _ string, _ httpclient.Options) (zInterface.ZBlogAPIClient, error) {
+	return f.client, nil
+}
+
+func (f *fakeZBlogAPI) DeleteClient(_ uuid.UUID) {}
+
+func (f *fakeZBlogAPI) NewClient(_ context.Context, _ string, _ string, _ string, _ httpclient.Options) (zInterface.ZBlogAPIClient, error) {
+	return f.client, nil
+}
+
+func (f *fakeZBlogAPI) NewAnonymousClient(_ context.Context, _ string) zInterface.ZBlogAPIClient {
+	return f.client
+}
+
+// fakeZBlogClient record the posted articles and comments
+type fakeZBlogClient struct {
+	posted     []zModel.PostArticleRequest
+	comments   []zModel.PostCommentRequest
+	deleted    []string
+	categories []zModel.Category
+	// deletedCategories is the id of deleted categories
+	deletedCategories []uint32
+}
+
+func (f *fakeZBlogClient) ListCategory(_ context.Context) ([]zModel.Category, error) {
+	return f.categories, nil
+}
+
+func (f *fakeZBlogClient) PostCategory(_ context.Context, cate zModel.PostCategoryRequest) (zModel.Category, error) {
+	if cate.ID != 0 {
+		return zModel.Category{ID: strconv.Itoa(int(cate.ID)), Name: cate.Name}, nil
+	}
+
+	return zModel.Category{ID: "9", Name: cate.Name}, nil
+}
+
+func (f *fakeZBlogClient) DeleteCategory(_ context.Context, id uint32) error {
+	f.deletedCategories = append(f.deletedCategories, id)
+
+	return nil
+}
+
+func (f *fakeZBlogClient) GetArticle(_ context.Context, id string) (zModel.Article, error) {
+	return zModel.Article{ID: "1", CateID: "2", Title: "title", Content: "content"}, nil
+}
+
+func (f *fakeZBlogClient) ListArticle(_ context.Context, _ zModel.ListArticleRequest) ([]zModel.Article, error) {
+	return []zModel.Article{{ID: "1", CateID: "2", CommNums: 3}}, nil
+}
+
+func (f *fakeZBlogClient) PostArticle(_ context.Context, art zModel.PostArticleRequest) (zModel.Article, error) {
+	f.posted = append(f.posted, art)
+
+	return zModel.Article{ID: "15", Title: art.Title}, nil
+}
+
+func (f *fakeZBlogClient) DeleteArticle(_ context.Context, id string) error {
+	f.deleted = append(f.deleted, id)
+
+	return nil
+}
+
+func (f *fakeZBlogClient) PostComment(_ context.Context, comment zModel.PostCommentRequest) error {
+	f.comments = append(f.comments, comment)
+
+	return nil
+}
+
+func (f *fakeZBlogClient) GetCountOfArticle(_ context.Context, _ zModel.ListArticleRequest) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeZBlogClient) ListTag(_ context.Context, _ zModel.ListTagRequest) ([]zModel.Tag, error) {
+	return nil, nil
+}
+
+func (f *fakeZBlogClient) ListTagAll(_ context.Context) ([]zModel.Tag, error) {
+	return []zModel.Tag{{ID: "4", Name: "go", Count: "8"}}, nil
+}
+
+func (f *fakeZBlogClient) PostTag(_ context.Context, tag zModel.PostTagRequest) (zModel.Tag, error) {
+	return zModel.Tag{ID: "5", Name: tag.Name}, nil
+}
+
+func TestZBlogDriver(t *testing.T) {
+	ctx := context.Background()
+
+	fakeClient := &fakeZBlogClient{categories: []zModel.Category{{ID: "1", Name: "news"}, {ID: "2", Name: "tech"}}}
+	driver := cmsdriver.NewZBlogDriver(&fakeZBlogAPI{client: fakeClient})
+
+	client, err := driver.GetClient(ctx, uuid.New(), model.Credential{URL: "http://zblog.test", UserName: "admin", Password: "admin"})
+	require.NoError(t, err)
+
+	categories, err := client.ListCategory(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Category{{ID: "1", Name: "news"}, {ID: "2", Name: "tech"}}, categories)
+
+	cate, err := client.CreateCategory(ctx, model.CreateCategoryArgs{Name: "life"})
+	require.NoError(t, err)
+	assert.Equal(t, model.Category{ID: "9", Name: "life"}, cate)
+
+	cate, err = client.RenameCategory(ctx, cate.ID, "living")
+	require.NoError(t, err)
+	assert.Equal(t, model.Category{ID: "9", Name: "living"}, cate)
+
+	err = client.DeleteCategory(ctx, cate.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{9}, fakeClient.deletedCategories)
+
+	posted, err := client.PostArticle(ctx, model.Article{Title: "title", Content: "content", CateID: "2", IsTop: true})
+	require.NoError(t, err)
+	assert.Equal(t, "15", posted.ID)
+	require.Len(t, fakeClient.posted, 1)
+	assert.Equal(t, uint32(2), fakeClient.posted[0].CateID)
+	assert.Equal(t, uint8(1), fakeClient.posted[0].IsTop)
+
+	tags, err := client.ListTagAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Tag{{ID: "4", Name: "go", Count: 8}}, tags)
+
+	// zblog set tags by names
+	err = client.UpdateArticleTags(ctx, posted.ID, []model.Tag{{ID: "4", Name: "go"}, {ID: "5", Name: "rust"}})
+	require.NoError(t, err)
+	require.Len(t, fakeClient.posted, 2)
+	assert.Equal(t, uint32(15), fakeClient.posted[1].ID)
+	assert.Equal(t, "go,rust", fakeClient.posted[1].Tag)
+
+	err = client.UpdateArticleTags(ctx, "not a number", nil)
+	require.Error(t, err)
+
+	err = client.PostComment(ctx, posted.ID, "nice")
+	require.NoError(t, err)
+	assert.Equal(t, []zModel.PostCommentRequest{{LogID: "15", Content: "nice"}}, fakeClient.comments)
+
+	err = client.DeleteArticle(ctx, posted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"15"}, fakeClient.deleted)
+}
+
+// wordpressRequest is the request received by fake wordpress server
+type wordpressRequest struct {
+	Method string
+	Path   string
+	Body   map[string]interface{}
+}
+
+func newFakeWordpressServer(t *testing.T) (*httptest.Server, func() []wordpressRequest) {
+	t.Helper()
+
+	lock := sync.Mutex{}
+	requests := []wordpressRequest{}
+
+	responses := map[string]string{
+		"GET /wp-json/wp/v2/users/me":         `{"id":1,"name":"admin"}`,
+		"GET /wp-json/wp/v2/categories":       `[{"id":3,"name":"news"}]`,
+		"POST /wp-json/wp/v2/categories":      `{"id":11,"name":"life"}`,
+		"POST /wp-json/wp/v2/categories/11":   `{"id":11,"name":"living"}`,
+		"DELETE /wp-json/wp/v2/categories/11": `{"deleted":true}`,
+		"POST /wp-json/wp/v2/posts":           `{"id":12,"date_gmt":"2024-01-02T03:04:05","categories":[3],"title":{"rendered":"title"}}`,
+		"POST /wp-json/wp/v2/posts/12":        `{"id":12}`,
+		"DELETE /wp-json/wp/v2/posts/12":      `{"id":12}`,
+		"POST /wp-json/wp/v2/comments":        `{"id":21}`,
+		"GET /wp-json/wp/v2/tags":             `[{"id":4,"name":"go","count":8}]`,
+		"POST /wp-json/wp/v2/tags":            `{"id":5}`,
+		"GET /wp-json/wp/v2/posts/12":         `{"id":12,"content":{"rendered":"content"}}`,
+		"GET /wp-json/wp/v2/posts":            `[{"id":12},{"id":13}]`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := wordpressRequest{Method: r.Method, Path: r.URL.Path}
+
+		body, _ := io.ReadAll(r.Body)
+		if len(body) > 0 {
+			_ = json.Unmarshal(body, &req.Body)
+		}
+
+		lock.Lock()
+		requests = append(requests, req)
+		lock.Unlock()
+
+		res, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			http.Error(w, `{"code":"rest_no_route","message":"not found"}`, http.StatusNotFound)
+
+			return
+		}
+
+		if r.URL.Path == "/wp-json/wp/v2/tags" && r.Method == http.MethodGet {
+			w.Header().Set("X-WP-Total", "1")
+		}
+
+		_, _ = w.Write([]byte(res))
+	}))
+
+	getRequests := func() []wordpressRequest {
+		lock.Lock()
+		defer lock.Unlock()
+
+		return append([]wordpressRequest{}, requests...)
+	}
+
+	return server, getRequests
+}
+
+func TestWordpressDriver(t *testing.T) {
+	ctx := context.Background()
+
+	server, getRequests := newFakeWordpressServer(t)
+	defer server.Close()
+
+	driver := cmsdriver.NewWordpressDriver(wordpressApi.NewWordpressApi(clientpool.Options{}, httpclient.NewDefaultFactory()))
+
+	client, err := driver.NewClient(ctx, model.Credential{URL: server.URL, UserName: "admin", Password: "admin"})
+	require.NoError(t, err)
+
+	categories, err := client.ListCategory(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Category{{ID: "3", Name: "news"}}, categories)
+
+	cate, err := client.CreateCategory(ctx, model.CreateCategoryArgs{Name: "life"})
+	require.NoError(t, err)
+	assert.Equal(t, model.Category{ID: "11", Name: "life"}, cate)
+
+	cate, err = client.RenameCategory(ctx, cate.ID, "living")
+	require.NoError(t, err)
+	assert.Equal(t, model.Category{ID: "11", Name: "living"}, cate)
+
+	err = client.DeleteCategory(ctx, cate.ID)
+	require.NoError(t, err)
+
+	posted, err := client.PostArticle(ctx, model.Article{Title: "title", Content: "content", CateID: "3"})
+	require.NoError(t, err)
+	assert.Equal(t, "12", posted.ID)
+	assert.Equal(t, "3", posted.CateID)
+	assert.Equal(t, 2024, posted.PostTime.Year())
+
+	tags, err := client.ListTagAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Tag{{ID: "4", Name: "go", Count: 8}}, tags)
+
+	newTag, err := client.CreateTag(ctx, "rust")
+	require.NoError(t, err)
+	assert.Equal(t, model.Tag{ID: "5", Name: "rust"}, newTag)
+
+	// wordpress set tags by ids
+	err = client.UpdateArticleTags(ctx, posted.ID, append(tags, newTag))
+	require.NoError(t, err)
+
+	article, err := client.GetArticle(ctx, posted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "content", article.Content)
+
+	articles, err := client.ListArticle(ctx)
+	require.NoError(t, err)
+	assert.Len(t, articles, 2)
+
+	err = client.PostComment(ctx, posted.ID, "nice")
+	require.NoError(t, err)
+
+	err = client.DeleteArticle(ctx, posted.ID)
+	require.NoError(t, err)
+
+	err = client.DeleteArticle(ctx, "not a number")
+	require.Error(t, err)
+
+	requests := getRequests()
+
+	find := func(method, path string) wordpressRequest {
+		for _, req := range requests {
+			if req.Method == method && req.Path == path {
+				return req
+			}
+		}
+
+		t.Fatalf("request %s %s not found", method, path)
+
+		return wordpressRequest{}
+	}
+
+	assert.Equal(t, "life", find(http.MethodPost, "/wp-json/wp/v2/categories").Body["name"])
+	assert.Equal(t, "living", find(http.MethodPost, "/wp-json/wp/v2/categories/11").Body["name"])
+	find(http.MethodDelete, "/wp-json/wp/v2/categories/11")
+	assert.Equal(t, []interface{}{float64(3)}, find(http.MethodPost, "/wp-json/wp/v2/posts").Body["categories"])
+	assert.Equal(t, []interface{}{float64(4), float64(5)}, find(http.MethodPost, "/wp-json/wp/v2/posts/12").Body["tags"])
+	assert.Equal(t, float64(12), find(http.MethodPost, "/wp-json/wp/v2/comments").Body["post"])
+	find(http.MethodDelete, "/wp-json/wp/v2/posts/12")
+}
@@ -0,0 +1,296 @@
+package cmsdriver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	cmsDriverInterface "github.com/ray31245/seo_cluster/pkg/cms_driver/cms_driver_interface"
+	"github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	ghostInterface "github.com/ray31245/seo_cluster/pkg/ghost_api/ghost_interface"
+	ghostModel "github.com/ray31245/seo_cluster/pkg/ghost_api/model"
+)
+
+const (
+	// DefaultGhostCategorySlugPrefix is the slug prefix of the tags used as categories
+	DefaultGhostCategorySlugPrefix = "category-"
+	// recentGhostPostsForComment is the number of latest posts listed for comment
+	recentGhostPostsForComment = "20"
+	ghostPostInclude           = "tags"
+	ghostPostFormats           = "html"
+)
+
+// GhostDriver is a struct to implement Driver interface
+var _ cmsDriverInterface.Driver = &GhostDriver{}
+
+// GhostDriver drive the ghost sites by admin api, the tags of ghost with category slug prefix are used as categories,
+// so the keyword tags set to articles are not published to. The admin api key of site is used as password
+type GhostDriver struct {
+	ghostAPI           ghostInterface.GhostAPI
+	categorySlugPrefix string
+}
+
+func NewGhostDriver(ghostAPI ghostInterface.GhostAPI) *GhostDriver {
+	return &GhostDriver{
+		ghostAPI:           ghostAPI,
+		categorySlugPrefix: DefaultGhostCategorySlugPrefix,
+	}
+}
+
+// SetCategorySlugPrefix set the slug prefix of the tags used as categories, it must not be empty
+func (d *GhostDriver) SetCategorySlugPrefix(prefix string) {
+	d.categorySlugPrefix = prefix
+}
+
+func (d *GhostDriver) CMSType() dbModel.CMSType {
+	return dbModel.CMSTypeGhost
+}
+
+func (d *GhostDriver) NewClient(ctx context.Context, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.ghostAPI.NewClient(ctx, cred.URL, cred.UserName, cred.Password, cred.HTTPOptions)
+	if err != nil {
+		return nil, fmt.Errorf("NewClient: %w", err)
+	}
+
+	return &ghostClient{client: client, categorySlugPrefix: d.categorySlugPrefix}, nil
+}
+
+func (d *GhostDriver) GetClient(ctx context.Context, ID uuid.UUID, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.ghostAPI.GetClient(ctx, ID, cred.URL, cred.UserName, cred.Password, cred.HTTPOptions)
+	if err != nil {
+		return nil, fmt.Errorf("GetClient: %w", err)
+	}
+
+	return &ghostClient{client: client, categorySlugPrefix: d.categorySlugPrefix}, nil
+}
+
+func (d *GhostDriver) UpdateClient(ctx context.Context, ID uuid.UUID, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.ghostAPI.UpdateClient(ctx, ID, cred.URL, cred.UserName, cred.Password, cred.HTTPOptions)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateClient: %w", err)
+	}
+
+	return &ghostClient{client: client, categorySlugPrefix: d.categorySlugPrefix}, nil
+}
+
+func (d *GhostDriver) DeleteClient(ID uuid.UUID) {
+	d.ghostAPI.DeleteClient(ID)
+}
+
+func (d *GhostDriver) NewAnonymousClient(ctx context.Context, urlStr string) cmsDriverInterface.Client {
+	return &ghostClient{client: d.ghostAPI.NewAnonymousClient(ctx, urlStr), categorySlugPrefix: d.categorySlugPrefix, isAnonymous: true}
+}
+
+func (d *GhostDriver) PublishCadence() model.PublishCadence {
+	return model.PublishCadenceRandomCycle
+}
+
+func (d *GhostDriver) ExpectedDailyDemand() float64 {
+	return randomCycleDailyDemand
+}
+
+type ghostClient struct {
+	client             ghostInterface.GhostClient
+	categorySlugPrefix string
+	// every admin api require admin api key
+	isAnonymous bool
+}
+
+func (c *ghostClient) ListCategory(ctx context.Context) ([]model.Category, error) {
+	tags, err := c.client.ListTagAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ListCategory: %w", err)
+	}
+
+	res := make([]model.Category, 0, len(tags))
+	for _, tag := range tags {
+		if !c.isCategory(tag) {
+			continue
+		}
+
+		res = append(res, model.Category{ID: tag.ID, Name: tag.Name})
+	}
+
+	return res, nil
+}
+
+func (c *ghostClient) CreateCategory(ctx context.Context, args model.CreateCategoryArgs) (model.Category, error) {
+	tag, err := c.client.CreateTag(ctx, ghostModel.CreateTagArgs{
+		Name:        args.Name,
+		Slug:        c.categorySlugPrefix + args.Name,
+		Description: args.Description,
+	})
+	if err != nil {
+		return model.Category{}, fmt.Errorf("CreateCategory: %w", err)
+	}
+
+	return model.Category{ID: tag.ID, Name: tag.Name}, nil
+}
+
+func (c *ghostClient) RenameCategory(_ context.Context, _ string, _ string) (model.Category, error) {
+	return model.Category{}, fmt.Errorf("RenameCategory: %w", ErrOperationNotSupport)
+}
+
+func (c *ghostClient) DeleteCategory(_ context.Context, _ string) error {
+	return fmt.Errorf("DeleteCategory: %w", ErrOperationNotSupport)
+}
+
+func (c *ghostClient) GetArticle(ctx context.Context, ID string) (model.Article, error) {
+	post, err := c.client.RetrievePost(ctx, ghostModel.RetrievePostArgs{
+		ID:      ID,
+		Include: ghostPostInclude,
+		Formats: ghostPostFormats,
+	})
+	if err != nil {
+		return model.Article{}, fmt.Errorf("GetArticle: %w", err)
+	}
+
+	return c.toArticle(ghostModel.Post(post)), nil
+}
+
+func (c *ghostClient) ListArticle(ctx context.Context) ([]model.Article, error) {
+	if c.isAnonymous {
+		return nil, fmt.Errorf("ListArticle: %w", ErrOperationNotSupport)
+	}
+
+	posts, err := c.client.ListPost(ctx, ghostModel.ListPostArgs{
+		Limit:   recentGhostPostsForComment,
+		Filter:  fmt.Sprintf("status:%s", ghostModel.StatusPublished),
+		Order:   "published_at desc",
+		Include: ghostPostInclude,
+		Formats: ghostPostFormats,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ListArticle: %w", err)
+	}
+
+	res := make([]model.Article, 0, len(posts.Posts))
+	for _, post := range posts.Posts {
+		res = append(res, c.toArticle(post))
+	}
+
+	return res, nil
+}
+
+// PostArticle post article with the tag of category as primary tag, the id of category is the id of tag
+func (c *ghostClient) PostArticle(ctx context.Context, article model.Article) (model.Article, error) {
+	post, err := c.client.CreatePost(ctx, ghostModel.CreatePostArgs{
+		Title:    article.Title,
+		HTML:     article.Content,
+		Status:   ghostModel.StatusPublished,
+		Featured: article.IsTop,
+		Tags:     []ghostModel.PostTag{{ID: article.CateID}},
+	})
+	if err != nil {
+		return model.Article{}, fmt.Errorf("PostArticle: %w", err)
+	}
+
+	return c.toArticle(ghostModel.Post(post)), nil
+}
+
+// UpdateArticleTags set tags of article by names after the primary tag,
+// ghost create the tags not exist
+func (c *ghostClient) UpdateArticleTags(ctx context.Context, ID string, tags []model.Tag) error {
+	post, err := c.client.RetrievePost(ctx, ghostModel.RetrievePostArgs{
+		ID:      ID,
+		Include: ghostPostInclude,
+	})
+	if err != nil {
+		return fmt.Errorf("UpdateArticleTags: %w", err)
+	}
+
+	postTags := make([]ghostModel.PostTag, 0, len(tags)+1)
+	// keep the primary tag which is the category
+	if post.PrimaryTag != nil {
+		postTags = append(postTags, ghostModel.PostTag{ID: post.PrimaryTag.ID})
+	}
+
+	for _, tag := range tags {
+		if post.PrimaryTag != nil && tag.Name == post.PrimaryTag.Name {
+			continue
+		}
+
+		postTags = append(postTags, ghostModel.PostTag{Name: tag.Name})
+	}
+
+	_, err = c.client.UpdatePost(ctx, ghostModel.UpdatePostArgs{
+		ID:        ID,
+		Tags:      postTags,
+		UpdatedAt: post.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("UpdateArticleTags: %w", err)
+	}
+
+	return nil
+}
+
+func (c *ghostClient) DeleteArticle(ctx context.Context, ID string) error {
+	err := c.client.DeletePost(ctx, ID)
+	if err != nil {
+		return fmt.Errorf("DeleteArticle: %w", err)
+	}
+
+	return nil
+}
+
+func (c *ghostClient) ListTagAll(ctx context.Context) ([]model.Tag, error) {
+	tags, err := c.client.ListTagAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ListTagAll: %w", err)
+	}
+
+	res := make([]model.Tag, 0, len(tags))
+
+	for _, tag := range tags {
+		count := 0
+		if tag.Count != nil {
+			count = tag.Count.Posts
+		}
+
+		res = append(res, model.Tag{ID: tag.ID, Name: tag.Name, Count: count})
+	}
+
+	return res, nil
+}
+
+func (c *ghostClient) CreateTag(ctx context.Context, name string) (model.Tag, error) {
+	tag, err := c.client.CreateTag(ctx, ghostModel.CreateTagArgs{Name: name})
+	if err != nil {
+		return model.Tag{}, fmt.Errorf("CreateTag: %w", err)
+	}
+
+	return model.Tag{ID: tag.ID, Name: tag.Name}, nil
+}
+
+// PostComment is not supported, comments of ghost are posted by members only
+func (c *ghostClient) PostComment(_ context.Context, _ string, _ string) error {
+	return fmt.Errorf("PostComment: %w", ErrOperationNotSupport)
+}
+
+// isCategory report the tag is used as category by its slug
+func (c *ghostClient) isCategory(tag ghostModel.Tag) bool {
+	return strings.HasPrefix(tag.Slug, c.categorySlugPrefix)
+}
+
+// toArticle convert the post, its category is the primary tag if the tag is category
+func (c *ghostClient) toArticle(post ghostModel.Post) model.Article {
+	res := model.Article{
+		ID:      post.ID,
+		Title:   post.Title,
+		Content: post.HTML,
+		IsTop:   post.Featured,
+	}
+
+	if post.PrimaryTag != nil && c.isCategory(*post.PrimaryTag) {
+		res.CateID = post.PrimaryTag.ID
+	}
+
+	if post.PublishedAt != nil {
+		res.PostTime = *post.PublishedAt
+	}
+
+	return res
+}
@@ -0,0 +1,209 @@
+package cmsdriver_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	cmsdriver "github.com/ray31245/seo_cluster/pkg/cms_driver"
+	"github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	ghostInterface "github.com/ray31245/seo_cluster/pkg/ghost_api/ghost_interface"
+	ghostModel "github.com/ray31245/seo_cluster/pkg/ghost_api/model"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGhostAPI return the same fake client for every site
+type fakeGhostAPI struct {
+	client *fakeGhostClient
+}
+
+func (f *fakeGhostAPI) GetClient(_ context.Context, _ uuid.UUID, _ string, _ string, _ string, _ httpclient.Options) (ghostInterface.GhostClient, error) {
+	return f.client, nil
+}
+
+func (f *fakeGhostAPI) UpdateClient(_ context.Context, _ uuid.UUID, _ string, _ string, _ string, _ httpclient.Options) (ghostInterface.GhostClient, error) {
+	return f.client, nil
+}
+
+func (f *fakeGhostAPI) DeleteClient(_ uuid.UUID) {}
+
+func (f *fakeGhostAPI) NewClient(_ context.Context, _ string, _ string, _ string, _ httpclient.Options) (ghostInterface.GhostClient, error) {
+	return f.client, nil
+}
+
+func (f *fakeGhostAPI) NewAnonymousClient(_ context.Context, _ string) ghostInterface.GhostClient {
+	return f.client
+}
+
+// fakeGhostClient record the posts sent to it
+type fakeGhostClient struct {
+	tags    []ghostModel.Tag
+	created []ghostModel.CreatePostArgs
+	updated []ghostModel.UpdatePostArgs
+	post    ghostModel.Post
+}
+
+func (f *fakeGhostClient) ListTag(_ context.Context, _ ghostModel.ListTagArgs) (ghostModel.ListTagResponse, error) {
+	return ghostModel.ListTagResponse{Tags: f.tags}, nil
+}
+
+func (f *fakeGhostClient) ListTagAll(_ context.Context) ([]ghostModel.Tag, error) {
+	return f.tags, nil
+}
+
+func (f *fakeGhostClient) CreateTag(_ context.Context, args ghostModel.CreateTagArgs) (ghostModel.CreateTagResponse, error) {
+	tag := ghostModel.Tag{ID: fmt.Sprintf("65000000000000000000%04x", len(f.tags)), Name: args.Name, Slug: args.Slug}
+	if tag.Slug == "" {
+		tag.Slug = strings.ToLower(args.Name)
+	}
+
+	f.tags = append(f.tags, tag)
+
+	return ghostModel.CreateTagResponse(tag), nil
+}
+
+func (f *fakeGhostClient) ListPost(_ context.Context, _ ghostModel.ListPostArgs) (ghostModel.ListPostResponse, error) {
+	return ghostModel.ListPostResponse{Posts: []ghostModel.Post{f.post}}, nil
+}
+
+// CreatePost reject the post with unknown tag id as ghost validate the tags
+func (f *fakeGhostClient) CreatePost(_ context.Context, args ghostModel.CreatePostArgs) (ghostModel.CreatePostResponse, error) {
+	for _, postTag := range args.Tags {
+		if postTag.ID != "" && !slices.ContainsFunc(f.tags, func(tag ghostModel.Tag) bool { return tag.ID == postTag.ID }) {
+			return ghostModel.CreatePostResponse{}, fmt.Errorf("tag %s not found", postTag.ID)
+		}
+	}
+
+	f.created = append(f.created, args)
+
+	return ghostModel.CreatePostResponse(f.post), nil
+}
+
+func (f *fakeGhostClient) RetrievePost(_ context.Context, _ ghostModel.RetrievePostArgs) (ghostModel.RetrievePostResponse, error) {
+	return ghostModel.RetrievePostResponse(f.post), nil
+}
+
+// UpdatePost create the tags set by name as ghost do
+func (f *fakeGhostClient) UpdatePost(ctx context.Context, args ghostModel.UpdatePostArgs) (ghostModel.UpdatePostResponse, error) {
+	f.updated = append(f.updated, args)
+
+	for _, postTag := range args.Tags {
+		if postTag.ID == "" && !slices.ContainsFunc(f.tags, func(tag ghostModel.Tag) bool { return tag.Name == postTag.Name }) {
+			_, _ = f.CreateTag(ctx, ghostModel.CreateTagArgs{Name: postTag.Name})
+		}
+	}
+
+	return ghostModel.UpdatePostResponse(f.post), nil
+}
+
+func (f *fakeGhostClient) DeletePost(_ context.Context, _ string) error {
+	return nil
+}
+
+func (f *fakeGhostClient) UploadImage(_ context.Context, _ ghostModel.UploadImageArgs) (ghostModel.UploadImageResponse, error) {
+	return ghostModel.UploadImageResponse{}, nil
+}
+
+func TestGhostDriver(t *testing.T) {
+	news := ghostModel.Tag{ID: "64f0000000000000000000a1", Name: "News", Slug: "category-news"}
+	tech := ghostModel.Tag{ID: "64f0000000000000000000a2", Name: "Tech", Slug: "category-tech"}
+	// keyword tag of published article
+	golang := ghostModel.Tag{ID: "64f0000000000000000000a3", Name: "Go", Slug: "go"}
+	publishedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	fakeClient := &fakeGhostClient{
+		tags: []ghostModel.Tag{news, tech, golang},
+		post: ghostModel.Post{
+			ID:          "650000000000000000000001",
+			Title:       "title",
+			HTML:        "<p>content</p>",
+			Tags:        []ghostModel.Tag{tech},
+			PrimaryTag:  &tech,
+			UpdatedAt:   publishedAt,
+			PublishedAt: &publishedAt,
+		},
+	}
+	driver := cmsdriver.NewGhostDriver(&fakeGhostAPI{client: fakeClient})
+
+	client, err := driver.GetClient(context.Background(), uuid.New(), model.Credential{URL: "http://example.com", Password: "id:00ff"})
+	require.NoError(t, err)
+
+	t.Run("tags with category slug prefix are mapped to categories", func(t *testing.T) {
+		categories, err := client.ListCategory(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []model.Category{
+			{ID: news.ID, Name: "News"},
+			{ID: tech.ID, Name: "Tech"},
+		}, categories)
+	})
+
+	t.Run("post article with the tag of category", func(t *testing.T) {
+		article, err := client.PostArticle(context.Background(), model.Article{Title: "title", Content: "<p>content</p>", CateID: tech.ID})
+		require.NoError(t, err)
+		assert.Equal(t, "650000000000000000000001", article.ID)
+		assert.Equal(t, tech.ID, article.CateID)
+		assert.Equal(t, publishedAt, article.PostTime)
+
+		require.Len(t, fakeClient.created, 1)
+		assert.Equal(t, []ghostModel.PostTag{{ID: tech.ID}}, fakeClient.created[0].Tags)
+		assert.Equal(t, ghostModel.StatusPublished, fakeClient.created[0].Status)
+
+		_, err = client.PostArticle(context.Background(), model.Article{Title: "title", CateID: "64f0000000000000000000ff"})
+		require.Error(t, err)
+		assert.Len(t, fakeClient.created, 1)
+	})
+
+	t.Run("update tags keep the primary tag", func(t *testing.T) {
+		err := client.UpdateArticleTags(context.Background(), "650000000000000000000001", []model.Tag{{Name: "Tech"}, {Name: "golang"}})
+		require.NoError(t, err)
+
+		require.Len(t, fakeClient.updated, 1)
+		assert.Equal(t, []ghostModel.PostTag{{ID: tech.ID}, {Name: "golang"}}, fakeClient.updated[0].Tags)
+		assert.Equal(t, publishedAt, fakeClient.updated[0].UpdatedAt)
+	})
+
+	t.Run("keyword tags of published article are not categories", func(t *testing.T) {
+		newTag, err := client.CreateTag(context.Background(), "seo")
+		require.NoError(t, err)
+
+		err = client.UpdateArticleTags(context.Background(), "650000000000000000000001", []model.Tag{newTag, {Name: "rust"}})
+		require.NoError(t, err)
+
+		tags, err := client.ListTagAll(context.Background())
+		require.NoError(t, err)
+
+		names := []string{}
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+		}
+
+		assert.Subset(t, names, []string{"Go", "seo", "rust"})
+
+		categories, err := client.ListCategory(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []model.Category{{ID: news.ID, Name: "News"}, {ID: tech.ID, Name: "Tech"}}, categories)
+	})
+
+	t.Run("create category with category slug prefix", func(t *testing.T) {
+		category, err := client.CreateCategory(context.Background(), model.CreateCategoryArgs{Name: "Sport"})
+		require.NoError(t, err)
+
+		categories, err := client.ListCategory(context.Background())
+		require.NoError(t, err)
+		assert.Contains(t, categories, category)
+	})
+
+	t.Run("comment is not supported", func(t *testing.T) {
+		err := client.PostComment(context.Background(), "650000000000000000000001", "nice")
+		require.ErrorIs(t, err, cmsdriver.ErrOperationNotSupport)
+
+		_, err = driver.NewAnonymousClient(context.Background(), "http://example.com").ListArticle(context.Background())
+		require.ErrorIs(t, err, cmsdriver.ErrOperationNotSupport)
+	})
+}
@@ -0,0 +1,252 @@
+package cmsdriver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	cmsDriverInterface "github.com/ray31245/seo_cluster/pkg/cms_driver/cms_driver_interface"
+	"github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	metaWeblogInterface "github.com/ray31245/seo_cluster/pkg/metaweblog_api/metaweblog_interface"
+	metaWeblogModel "github.com/ray31245/seo_cluster/pkg/metaweblog_api/model"
+)
+
+// recentPostsForComment is the number of latest posts listed for comment
+const recentPostsForComment = 20
+
+// MetaWeblogDriver is a struct to implement Driver interface
+var _ cmsDriverInterface.Driver = &MetaWeblogDriver{}
+
+// MetaWeblogDriver drive the blogs only expose MetaWeblog XML-RPC, e.g. typecho and emlog,
+// the url of site is the xml-rpc endpoint
+type MetaWeblogDriver struct {
+	metaWeblogAPI metaWeblogInterface.MetaWeblogAPI
+}
+
+func NewMetaWeblogDriver(metaWeblogAPI metaWeblogInterface.MetaWeblogAPI) *MetaWeblogDriver {
+	return &MetaWeblogDriver{
+		metaWeblogAPI: metaWeblogAPI,
+	}
+}
+
+func (d *MetaWeblogDriver) CMSType() dbModel.CMSType {
+	return dbModel.CMSTypeMetaWeblog
+}
+
+func (d *MetaWeblogDriver) NewClient(ctx context.Context, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.metaWeblogAPI.NewClient(ctx, cred.URL, cred.UserName, cred.Password, cred.HTTPOptions)
+	if err != nil {
+		return nil, fmt.Errorf("NewClient: %w", err)
+	}
+
+	return &metaWeblogClient{client: client}, nil
+}
+
+func (d *MetaWeblogDriver) GetClient(ctx context.Context, ID uuid.UUID, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.metaWeblogAPI.GetClient(ctx, ID, cred.URL, cred.UserName, cred.Password, cred.HTTPOptions)
+	if err != nil {
+		return nil, fmt.Errorf("GetClient: %w", err)
+	}
+
+	return &metaWeblogClient{client: client}, nil
+}
+
+func (d *MetaWeblogDriver) UpdateClient(ctx context.Context, ID uuid.UUID, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.metaWeblogAPI.UpdateClient(ctx, ID, cred.URL, cred.UserName, cred.Password, cred.HTTPOptions)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateClient: %w", err)
+	}
+
+	return &metaWeblogClient{client: client}, nil
+}
+
+func (d *MetaWeblogDriver) DeleteClient(ID uuid.UUID) {
+	d.metaWeblogAPI.DeleteClient(ID)
+}
+
+func (d *MetaWeblogDriver) NewAnonymousClient(ctx context.Context, urlStr string) cmsDriverInterface.Client {
+	return &metaWeblogClient{client: d.metaWeblogAPI.NewAnonymousClient(ctx, urlStr), isAnonymous: true}
+}
+
+func (d *MetaWeblogDriver) PublishCadence() model.PublishCadence {
+	return model.PublishCadenceRandomCycle
+}
+
+func (d *MetaWeblogDriver) ExpectedDailyDemand() float64 {
+	return randomCycleDailyDemand
+}
+
+type metaWeblogClient struct {
+	client metaWeblogInterface.MetaWeblogClient
+	// every metaweblog method require credential
+	isAnonymous bool
+}
+
+func (c *metaWeblogClient) ListCategory(ctx context.Context) ([]model.Category, error) {
+	categories, err := c.client.GetCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ListCategory: %w", err)
+	}
+
+	res := make([]model.Category, 0, len(categories))
+	for _, cate := range categories {
+		res = append(res, model.Category{ID: string(cate.CategoryID), Name: cate.Name()})
+	}
+
+	return res, nil
+}
+
+func (c *metaWeblogClient) CreateCategory(ctx context.Context, args model.CreateCategoryArgs) (model.Category, error) {
+	var parentID int
+
+	if args.ParentID != "" {
+		ID, err := strconv.Atoi(args.ParentID)
+		if err != nil {
+			return model.Category{}, fmt.Errorf("CreateCategory: %w", err)
+		}
+
+		parentID = ID
+	}
+
+	ID, err := c.client.NewCategory(ctx, metaWeblogModel.NewCategoryRequest{
+		Name:        args.Name,
+		Description: args.Description,
+		ParentID:    parentID,
+	})
+	if err != nil {
+		return model.Category{}, fmt.Errorf("CreateCategory: %w", err)
+	}
+
+	return model.Category{ID: ID, Name: args.Name}, nil
+}
+
+func (c *metaWeblogClient) RenameCategory(_ context.Context, _ string, _ string) (model.Category, error) {
+	return model.Category{}, fmt.Errorf("RenameCategory: %w", ErrOperationNotSupport)
+}
+
+func (c *metaWeblogClient) DeleteCategory(_ context.Context, _ string) error {
+	return fmt.Errorf("DeleteCategory: %w", ErrOperationNotSupport)
+}
+
+func (c *metaWeblogClient) GetArticle(ctx context.Context, ID string) (model.Article, error) {
+	post, err := c.client.GetPost(ctx, ID)
+	if err != nil {
+		return model.Article{}, fmt.Errorf("GetArticle: %w", err)
+	}
+
+	return toMetaWeblogArticle(post), nil
+}
+
+func (c *metaWeblogClient) ListArticle(ctx context.Context) ([]model.Article, error) {
+	if c.isAnonymous {
+		return nil, fmt.Errorf("ListArticle: %w", ErrOperationNotSupport)
+	}
+
+	posts, err := c.client.GetRecentPosts(ctx, recentPostsForComment)
+	if err != nil {
+		return nil, fmt.Errorf("ListArticle: %w", err)
+	}
+
+	res := make([]model.Article, 0, len(posts))
+	for _, post := range posts {
+		res = append(res, toMetaWeblogArticle(post))
+	}
+
+	return res, nil
+}
+
+// PostArticle post article to the category, metaweblog refer category by name
+func (c *metaWeblogClient) PostArticle(ctx context.Context, article model.Article) (model.Article, error) {
+	categories, err := c.ListCategory(ctx)
+	if err != nil {
+		return model.Article{}, fmt.Errorf("PostArticle: %w", err)
+	}
+
+	idx := slices.IndexFunc(categories, func(cate model.Category) bool {
+		return cate.ID == article.CateID
+	})
+	if idx < 0 {
+		return model.Article{}, fmt.Errorf("PostArticle: category %s not found in site", article.CateID)
+	}
+
+	postID, err := c.client.NewPost(ctx, metaWeblogModel.NewPostRequest{
+		Title:       article.Title,
+		Description: article.Content,
+		Categories:  []string{categories[idx].Name},
+		Publish:     true,
+	})
+	if err != nil {
+		return model.Article{}, fmt.Errorf("PostArticle: %w", err)
+	}
+
+	article.ID = postID
+
+	return article, nil
+}
+
+// UpdateArticleTags set tags of article by names, the server create the tags not exist
+func (c *metaWeblogClient) UpdateArticleTags(ctx context.Context, ID string, tags []model.Tag) error {
+	// editPost replace the whole post, keep the other fields as they are
+	post, err := c.client.GetPost(ctx, ID)
+	if err != nil {
+		return fmt.Errorf("UpdateArticleTags: %w", err)
+	}
+
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+
+	err = c.client.EditPost(ctx, metaWeblogModel.EditPostRequest{
+		PostID: ID,
+		NewPostRequest: metaWeblogModel.NewPostRequest{
+			Title:       post.Title,
+			Description: post.Description,
+			Categories:  post.Categories,
+			Keywords:    strings.Join(names, ","),
+			Publish:     true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("UpdateArticleTags: %w", err)
+	}
+
+	return nil
+}
+
+func (c *metaWeblogClient) DeleteArticle(ctx context.Context, ID string) error {
+	err := c.client.DeletePost(ctx, ID)
+	if err != nil {
+		return fmt.Errorf("DeleteArticle: %w", err)
+	}
+
+	return nil
+}
+
+// ListTagAll return no tag, metaweblog has no method to list tags,
+// tags are created by name when they are set to article
+func (c *metaWeblogClient) ListTagAll(_ context.Context) ([]model.Tag, error) {
+	return []model.Tag{}, nil
+}
+
+// CreateTag return the tag of name, it is created when it is set to article
+func (c *metaWeblogClient) CreateTag(_ context.Context, name string) (model.Tag, error) {
+	return model.Tag{Name: name}, nil
+}
+
+func (c *metaWeblogClient) PostComment(_ context.Context, _ string, _ string) error {
+	return fmt.Errorf("PostComment: %w", ErrOperationNotSupport)
+}
+
+func toMetaWeblogArticle(post metaWeblogModel.Post) model.Article {
+	return model.Article{
+		ID:       string(post.PostID),
+		Title:    post.Title,
+		Content:  post.Description,
+		PostTime: post.DateCreated,
+	}
+}
@@ -0,0 +1,131 @@
+package cmsdriver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	cmsdriver "github.com/ray31245/seo_cluster/pkg/cms_driver"
+	"github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	metaWeblogInterface "github.com/ray31245/seo_cluster/pkg/metaweblog_api/metaweblog_interface"
+	metaWeblogModel "github.com/ray31245/seo_cluster/pkg/metaweblog_api/model"
+	"github.com/ray31245/seo_cluster/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMetaWeblogAPI return the same fake client for every site
+type fakeMetaWeblogAPI struct {
+	client *fakeMetaWeblogClient
+}
+
+func (f *fakeMetaWeblogAPI) GetClient(_ context.Context, _ uuid.UUID, _ string, _ string, _ string, _ httpclient.Options) (metaWeblogInterface.MetaWeblogClient, error) {
+	return f.client, nil
+}
+
+func (f *fakeMetaWeblogAPI) UpdateClient(_ context.Context, _ uuid.UUID, _ string, _ string, _ string, _ httpclient.Options) (metaWeblogInterface.MetaWeblogClient, error) {
+	return f.client, nil
+}
+
+func (f *fakeMetaWeblogAPI) DeleteClient(_ uuid.UUID) {}
+
+func (f *fakeMetaWeblogAPI) NewClient(_ context.Context, _ string, _ string, _ string, _ httpclient.Options) (metaWeblogInterface.MetaWeblogClient, error) {
+	return f.client, nil
+}
+
+func (f *fakeMetaWeblogAPI) NewAnonymousClient(_ context.Context, _ string) metaWeblogInterface.MetaWeblogClient {
+	return f.client
+}
+
+// fakeMetaWeblogClient record the posts sent to it
+type fakeMetaWeblogClient struct {
+	posts map[string]metaWeblogModel.NewPostRequest
+}
+
+func (f *fakeMetaWeblogClient) GetUsersBlogs(_ context.Context) ([]metaWeblogModel.Blog, error) {
+	return []metaWeblogModel.Blog{{BlogID: "1"}}, nil
+}
+
+func (f *fakeMetaWeblogClient) GetCategories(_ context.Context) ([]metaWeblogModel.Category, error) {
+	return []metaWeblogModel.Category{{CategoryID: "1", CategoryName: "news"}, {CategoryID: "2", Title: "tech"}}, nil
+}
+
+func (f *fakeMetaWeblogClient) NewCategory(_ context.Context, _ metaWeblogModel.NewCategoryRequest) (string, error) {
+	return "3", nil
+}
+
+func (f *fakeMetaWeblogClient) NewPost(_ context.Context, req metaWeblogModel.NewPostRequest) (string, error) {
+	f.posts["11"] = req
+
+	return "11", nil
+}
+
+func (f *fakeMetaWeblogClient) EditPost(_ context.Context, req metaWeblogModel.EditPostRequest) error {
+	f.posts[req.PostID] = req.NewPostRequest
+
+	return nil
+}
+
+func (f *fakeMetaWeblogClient) GetPost(_ context.Context, postID string) (metaWeblogModel.Post, error) {
+	post := f.posts[postID]
+
+	return metaWeblogModel.Post{
+		PostID:      util.NumberString(postID),
+		Title:       post.Title,
+		Description: post.Description,
+		Categories:  post.Categories,
+		Keywords:    post.Keywords,
+	}, nil
+}
+
+func (f *fakeMetaWeblogClient) GetRecentPosts(_ context.Context, _ int) ([]metaWeblogModel.Post, error) {
+	return []metaWeblogModel.Post{}, nil
+}
+
+func (f *fakeMetaWeblogClient) DeletePost(_ context.Context, postID string) error {
+	delete(f.posts, postID)
+
+	return nil
+}
+
+func (f *fakeMetaWeblogClient) NewMediaObject(_ context.Context, _ metaWeblogModel.NewMediaObjectRequest) (metaWeblogModel.MediaObjectResponse, error) {
+	return metaWeblogModel.MediaObjectResponse{}, nil
+}
+
+func TestMetaWeblogDriver(t *testing.T) {
+	fakeClient := &fakeMetaWeblogClient{posts: map[string]metaWeblogModel.NewPostRequest{}}
+	driver := cmsdriver.NewMetaWeblogDriver(&fakeMetaWeblogAPI{client: fakeClient})
+
+	client, err := driver.GetClient(context.Background(), uuid.New(), model.Credential{URL: "http://example.com/xmlrpc.php", UserName: "admin", Password: "admin"})
+	require.NoError(t, err)
+
+	categories, err := client.ListCategory(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []model.Category{{ID: "1", Name: "news"}, {ID: "2", Name: "tech"}}, categories)
+
+	t.Run("post article refer category by name", func(t *testing.T) {
+		article, err := client.PostArticle(context.Background(), model.Article{Title: "title", Content: "content", CateID: "2"})
+		require.NoError(t, err)
+		assert.Equal(t, "11", article.ID)
+		assert.Equal(t, []string{"tech"}, fakeClient.posts["11"].Categories)
+
+		_, err = client.PostArticle(context.Background(), model.Article{Title: "title", CateID: "9"})
+		require.Error(t, err)
+	})
+
+	t.Run("update tags keep the post", func(t *testing.T) {
+		err := client.UpdateArticleTags(context.Background(), "11", []model.Tag{{Name: "go"}, {Name: "rust"}})
+		require.NoError(t, err)
+
+		post := fakeClient.posts["11"]
+		assert.Equal(t, "go,rust", post.Keywords)
+		assert.Equal(t, "title", post.Title)
+		assert.Equal(t, []string{"tech"}, post.Categories)
+	})
+
+	t.Run("comment is not supported", func(t *testing.T) {
+		err := client.PostComment(context.Background(), "11", "nice")
+		require.ErrorIs(t, err, cmsdriver.ErrOperationNotSupport)
+	})
+}
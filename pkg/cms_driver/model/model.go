@@ -0,0 +1,91 @@
+package model
+
+import (
+	"time"
+
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+)
+
+// Category is the category of a site in CMS
+type Category struct {
+	// ID is the id of category in CMS, it is stored as the CMS category id of local category
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// CreateCategoryArgs is the category to create in CMS
+type CreateCategoryArgs struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// ParentID is the CMS id of parent category, empty for top level.
+	// It is ignored by the CMS without hierarchy of category
+	ParentID string `json:"parent_id"`
+}
+
+// PublishCadence is how the random publish cycles add lack to the sites of CMS
+type PublishCadence string
+
+const (
+	// PublishCadenceRandomCycle add 0 or 1 lack to site every 1 to 27.8 hours
+	PublishCadenceRandomCycle PublishCadence = "random_cycle"
+	// PublishCadenceTimePoints add 1 lack to site at 4 or 5 random time points a day
+	PublishCadenceTimePoints PublishCadence = "time_points"
+)
+
+// Tag is the tag of a site in CMS
+type Tag struct {
+	// ID is the id of tag in CMS, it is interpreted by the driver of CMS type
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func (t Tag) GetID() string {
+	return t.ID
+}
+
+func (t Tag) GetName() string {
+	return t.Name
+}
+
+func (t Tag) GetCount() int {
+	return t.Count
+}
+
+// Article is the article of a site in CMS
+type Article struct {
+	// ID is the id of article in CMS, empty when the article is not posted yet
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	IsTop   bool   `json:"is_top"`
+	// CateID is the id of category in CMS
+	CateID   string    `json:"cate_id"`
+	PostTime time.Time `json:"post_time"`
+	// CommNums is the number of comments, zero if the CMS does not provide it
+	CommNums int `json:"comm_nums"`
+}
+
+// Credential is the login information of a site
+type Credential struct {
+	URL      string `json:"url"`
+	UserName string `json:"user_name"`
+	Password string `json:"password"`
+	// AuthMode is the way to authenticate, empty for the default of CMS.
+	// Only wordpress support it now, see the AuthMode of wordpress api
+	AuthMode string `json:"auth_mode"`
+	// HTTPOptions override the http options of api, it is ignored by static site which send no request
+	HTTPOptions httpclient.Options `json:"http_options"`
+}
+
+// NewSiteCredential return the credential of site admin
+func NewSiteCredential(site dbModel.Site) Credential {
+	return Credential{
+		URL:         site.URL,
+		UserName:    site.UserName,
+		Password:    site.Password,
+		AuthMode:    site.AuthMode,
+		HTTPOptions: site.HTTPOptions,
+	}
+}
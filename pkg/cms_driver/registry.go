@@ -0,0 +1,75 @@
+package cmsdriver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	cmsDriverInterface "github.com/ray31245/seo_cluster/pkg/cms_driver/cms_driver_interface"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+)
+
+var (
+	ErrCMSTypeNotSupport = errors.New("cms type not support")
+	// ErrOperationNotSupport is returned by the client of CMS without the operation, e.g. comment by metaweblog
+	ErrOperationNotSupport = errors.New("operation not support by cms")
+)
+
+// Registry is a struct to implement Registry interface
+var _ cmsDriverInterface.Registry = &Registry{}
+
+// Registry keep the drivers keyed by CMS type,
+// adding a new CMS platform only need to register its driver
+type Registry struct {
+	lock    sync.RWMutex
+	drivers map[dbModel.CMSType]cmsDriverInterface.Driver
+}
+
+func NewRegistry(drivers ...cmsDriverInterface.Driver) *Registry {
+	res := &Registry{
+		drivers: make(map[dbModel.CMSType]cmsDriverInterface.Driver),
+	}
+
+	for _, driver := range drivers {
+		res.Register(driver)
+	}
+
+	return res
+}
+
+// Register add the driver of its CMS type, replace the registered one if exists
+func (r *Registry) Register(driver cmsDriverInterface.Driver) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.drivers[driver.CMSType()] = driver
+}
+
+// Driver return the driver of CMS type, ErrCMSTypeNotSupport if not registered
+func (r *Registry) Driver(cmsType dbModel.CMSType) (cmsDriverInterface.Driver, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	driver, ok := r.drivers[cmsType]
+	if !ok {
+		return nil, fmt.Errorf("Driver: %w: %q", ErrCMSTypeNotSupport, cmsType)
+	}
+
+	return driver, nil
+}
+
+// CMSTypes return the registered CMS types in order
+func (r *Registry) CMSTypes() []dbModel.CMSType {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	res := make([]dbModel.CMSType, 0, len(r.drivers))
+	for cmsType := range r.drivers {
+		res = append(res, cmsType)
+	}
+
+	slices.Sort(res)
+
+	return res
+}
@@ -0,0 +1,45 @@
+package cmsdriver_test
+
+import (
+	"testing"
+
+	cmsdriver "github.com/ray31245/seo_cluster/pkg/cms_driver"
+	"github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	zblogDriver := cmsdriver.NewZBlogDriver(nil)
+	wordpressDriver := cmsdriver.NewWordpressDriver(nil)
+
+	registry := cmsdriver.NewRegistry(zblogDriver, wordpressDriver)
+
+	t.Run("lookup registered driver", func(t *testing.T) {
+		driver, err := registry.Driver(dbModel.CMSTypeZBlog)
+		require.NoError(t, err)
+		assert.Equal(t, dbModel.CMSTypeZBlog, driver.CMSType())
+
+		driver, err = registry.Driver(dbModel.CMSTypeWordPress)
+		require.NoError(t, err)
+		assert.Equal(t, dbModel.CMSTypeWordPress, driver.CMSType())
+	})
+
+	t.Run("unknown cms type", func(t *testing.T) {
+		_, err := registry.Driver(dbModel.CMSType("unknown"))
+		require.ErrorIs(t, err, cmsdriver.ErrCMSTypeNotSupport)
+	})
+
+	t.Run("registered cms types", func(t *testing.T) {
+		assert.Equal(t, []dbModel.CMSType{dbModel.CMSTypeWordPress, dbModel.CMSTypeZBlog}, registry.CMSTypes())
+	})
+
+	t.Run("publish cadence", func(t *testing.T) {
+		assert.Equal(t, model.PublishCadenceRandomCycle, zblogDriver.PublishCadence())
+		assert.Equal(t, model.PublishCadenceTimePoints, wordpressDriver.PublishCadence())
+
+		assert.InDelta(t, 0.833, zblogDriver.ExpectedDailyDemand(), 0.001)
+		assert.InDelta(t, 4.5, wordpressDriver.ExpectedDailyDemand(), 0.001)
+	})
+}
@@ -0,0 +1,248 @@
+package cmsdriver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	cmsDriverInterface "github.com/ray31245/seo_cluster/pkg/cms_driver/cms_driver_interface"
+	"github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	staticSiteModel "github.com/ray31245/seo_cluster/pkg/static_site/model"
+	staticSiteInterface "github.com/ray31245/seo_cluster/pkg/static_site/static_site_interface"
+	"github.com/ray31245/seo_cluster/pkg/util"
+)
+
+// recentStaticPostsForComment is the number of latest posts listed
+const recentStaticPostsForComment = 20
+
+// StaticSiteDriver is a struct to implement Driver interface
+var _ cmsDriverInterface.Driver = &StaticSiteDriver{}
+
+// StaticSiteDriver publish markdown with front matter into the local directory of hugo or hexo site,
+// the url of site is the directory and user name is the git author
+type StaticSiteDriver struct {
+	staticSiteAPI staticSiteInterface.StaticSiteAPI
+}
+
+func NewStaticSiteDriver(staticSiteAPI staticSiteInterface.StaticSiteAPI) *StaticSiteDriver {
+	return &StaticSiteDriver{
+		staticSiteAPI: staticSiteAPI,
+	}
+}
+
+func (d *StaticSiteDriver) CMSType() dbModel.CMSType {
+	return dbModel.CMSTypeStaticSite
+}
+
+func (d *StaticSiteDriver) NewClient(ctx context.Context, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.staticSiteAPI.NewClient(ctx, cred.URL, cred.UserName, cred.Password)
+	if err != nil {
+		return nil, fmt.Errorf("NewClient: %w", err)
+	}
+
+	return &staticSiteClient{client: client}, nil
+}
+
+func (d *StaticSiteDriver) GetClient(ctx context.Context, ID uuid.UUID, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.staticSiteAPI.GetClient(ctx, ID, cred.URL, cred.UserName, cred.Password)
+	if err != nil {
+		return nil, fmt.Errorf("GetClient: %w", err)
+	}
+
+	return &staticSiteClient{client: client}, nil
+}
+
+func (d *StaticSiteDriver) UpdateClient(ctx context.Context, ID uuid.UUID, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.staticSiteAPI.UpdateClient(ctx, ID, cred.URL, cred.UserName, cred.Password)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateClient: %w", err)
+	}
+
+	return &staticSiteClient{client: client}, nil
+}
+
+func (d *StaticSiteDriver) DeleteClient(ID uuid.UUID) {
+	d.staticSiteAPI.DeleteClient(ID)
+}
+
+func (d *StaticSiteDriver) NewAnonymousClient(ctx context.Context, urlStr string) cmsDriverInterface.Client {
+	return &staticSiteClient{client: d.staticSiteAPI.NewAnonymousClient(ctx, urlStr), isAnonymous: true}
+}
+
+func (d *StaticSiteDriver) PublishCadence() model.PublishCadence {
+	return model.PublishCadenceRandomCycle
+}
+
+func (d *StaticSiteDriver) ExpectedDailyDemand() float64 {
+	return randomCycleDailyDemand
+}
+
+type staticSiteClient struct {
+	client staticSiteInterface.StaticSiteClient
+	// static site has no visitor to comment
+	isAnonymous bool
+}
+
+func (c *staticSiteClient) ListCategory(ctx context.Context) ([]model.Category, error) {
+	names, err := c.client.ListCategory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ListCategory: %w", err)
+	}
+
+	res := make([]model.Category, 0, len(names))
+	for _, name := range names {
+		// static site refer category by name
+		res = append(res, model.Category{ID: name, Name: name})
+	}
+
+	return res, nil
+}
+
+// CreateCategory add the name of category to manifest, static site has no hierarchy or description of category
+func (c *staticSiteClient) CreateCategory(ctx context.Context, args model.CreateCategoryArgs) (model.Category, error) {
+	err := c.client.CreateCategory(ctx, args.Name)
+	if err != nil {
+		return model.Category{}, fmt.Errorf("CreateCategory: %w", err)
+	}
+
+	return model.Category{ID: args.Name, Name: args.Name}, nil
+}
+
+func (c *staticSiteClient) RenameCategory(_ context.Context, _ string, _ string) (model.Category, error) {
+	return model.Category{}, fmt.Errorf("RenameCategory: %w", ErrOperationNotSupport)
+}
+
+func (c *staticSiteClient) DeleteCategory(_ context.Context, _ string) error {
+	return fmt.Errorf("DeleteCategory: %w", ErrOperationNotSupport)
+}
+
+func (c *staticSiteClient) GetArticle(ctx context.Context, ID string) (model.Article, error) {
+	post, err := c.client.GetPost(ctx, ID)
+	if err != nil {
+		return model.Article{}, fmt.Errorf("GetArticle: %w", err)
+	}
+
+	return toStaticSiteArticle(post), nil
+}
+
+func (c *staticSiteClient) ListArticle(ctx context.Context) ([]model.Article, error) {
+	if c.isAnonymous {
+		return nil, fmt.Errorf("ListArticle: %w", ErrOperationNotSupport)
+	}
+
+	posts, err := c.client.ListPost(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ListArticle: %w", err)
+	}
+
+	res := make([]model.Article, 0, min(len(posts), recentStaticPostsForComment))
+	for _, post := range posts[:min(len(posts), recentStaticPostsForComment)] {
+		res = append(res, toStaticSiteArticle(post))
+	}
+
+	return res, nil
+}
+
+// PostArticle convert the html of article to markdown and write it with the name of category
+func (c *staticSiteClient) PostArticle(ctx context.Context, article model.Article) (model.Article, error) {
+	// the id of category is its name
+	categories, err := c.client.ListCategory(ctx)
+	if err != nil {
+		return model.Article{}, fmt.Errorf("PostArticle: %w", err)
+	}
+
+	if !slices.Contains(categories, article.CateID) {
+		return model.Article{}, fmt.Errorf("PostArticle: category %s not found in site", article.CateID)
+	}
+
+	md, err := util.HTMLToMd(article.Content)
+	if err != nil {
+		return model.Article{}, fmt.Errorf("PostArticle: %w", err)
+	}
+
+	post, err := c.client.CreatePost(ctx, staticSiteModel.Post{
+		Title:      article.Title,
+		Date:       time.Now(),
+		Categories: []string{article.CateID},
+		Content:    md,
+	})
+	if err != nil {
+		return model.Article{}, fmt.Errorf("PostArticle: %w", err)
+	}
+
+	article.ID = post.ID
+	article.PostTime = post.Date
+
+	return article, nil
+}
+
+// UpdateArticleTags replace the tags in front matter of post
+func (c *staticSiteClient) UpdateArticleTags(ctx context.Context, ID string, tags []model.Tag) error {
+	post, err := c.client.GetPost(ctx, ID)
+	if err != nil {
+		return fmt.Errorf("UpdateArticleTags: %w", err)
+	}
+
+	post.Tags = make([]string, 0, len(tags))
+	for _, tag := range tags {
+		post.Tags = append(post.Tags, tag.Name)
+	}
+
+	err = c.client.UpdatePost(ctx, post)
+	if err != nil {
+		return fmt.Errorf("UpdateArticleTags: %w", err)
+	}
+
+	return nil
+}
+
+func (c *staticSiteClient) DeleteArticle(ctx context.Context, ID string) error {
+	err := c.client.DeletePost(ctx, ID)
+	if err != nil {
+		return fmt.Errorf("DeleteArticle: %w", err)
+	}
+
+	return nil
+}
+
+func (c *staticSiteClient) ListTagAll(ctx context.Context) ([]model.Tag, error) {
+	tags, err := c.client.ListTag(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ListTagAll: %w", err)
+	}
+
+	res := make([]model.Tag, 0, len(tags))
+	for _, tag := range tags {
+		// static site refer tag by name
+		res = append(res, model.Tag{ID: tag.Name, Name: tag.Name, Count: tag.Count})
+	}
+
+	return res, nil
+}
+
+// CreateTag return the tag of name, it is written when it is set to post
+func (c *staticSiteClient) CreateTag(_ context.Context, name string) (model.Tag, error) {
+	return model.Tag{ID: name, Name: name}, nil
+}
+
+func (c *staticSiteClient) PostComment(_ context.Context, _ string, _ string) error {
+	return fmt.Errorf("PostComment: %w", ErrOperationNotSupport)
+}
+
+func toStaticSiteArticle(post staticSiteModel.Post) model.Article {
+	res := model.Article{
+		ID:       post.ID,
+		Title:    post.Title,
+		Content:  string(util.MdToHTML([]byte(post.Content))),
+		PostTime: post.Date,
+	}
+
+	if len(post.Categories) > 0 {
+		res.CateID = post.Categories[0]
+	}
+
+	return res
+}
@@ -0,0 +1,76 @@
+package cmsdriver_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	cmsdriver "github.com/ray31245/seo_cluster/pkg/cms_driver"
+	"github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	staticSite "github.com/ray31245/seo_cluster/pkg/static_site"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticSiteDriver(t *testing.T) {
+	root := t.TempDir()
+	driver := cmsdriver.NewStaticSiteDriver(staticSite.NewStaticSiteAPI(filepath.Dir(root)))
+
+	client, err := driver.GetClient(context.Background(), uuid.New(), model.Credential{URL: root})
+	require.NoError(t, err)
+
+	news, err := client.CreateCategory(context.Background(), model.CreateCategoryArgs{Name: "news"})
+	require.NoError(t, err)
+	assert.Equal(t, "news", news.ID)
+
+	categories, err := client.ListCategory(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []model.Category{news}, categories)
+
+	article, err := client.PostArticle(context.Background(), model.Article{
+		Title:   "title",
+		Content: "<h2>heading</h2><p>some <strong>bold</strong> text</p>",
+		CateID:  news.ID,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, article.ID)
+
+	t.Run("html is written as markdown with front matter", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(root, "content", "posts", article.ID+".md"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "title: title\n")
+		assert.Contains(t, string(data), "categories:\n    - news\n")
+		assert.Contains(t, string(data), "## heading")
+		assert.Contains(t, string(data), "some **bold** text")
+	})
+
+	t.Run("tags are written to front matter", func(t *testing.T) {
+		tag, err := client.CreateTag(context.Background(), "golang")
+		require.NoError(t, err)
+
+		err = client.UpdateArticleTags(context.Background(), article.ID, []model.Tag{tag})
+		require.NoError(t, err)
+
+		tags, err := client.ListTagAll(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []model.Tag{{ID: tag.ID, Name: "golang", Count: 1}}, tags)
+
+		got, err := client.GetArticle(context.Background(), article.ID)
+		require.NoError(t, err)
+		assert.Equal(t, news.ID, got.CateID)
+		assert.True(t, strings.Contains(got.Content, "<strong>bold</strong>"))
+	})
+
+	t.Run("unknown category", func(t *testing.T) {
+		_, err := client.PostArticle(context.Background(), model.Article{Title: "title", CateID: "unknown"})
+		require.Error(t, err)
+	})
+
+	t.Run("comment is not supported", func(t *testing.T) {
+		err := client.PostComment(context.Background(), article.ID, "nice")
+		require.ErrorIs(t, err, cmsdriver.ErrOperationNotSupport)
+	})
+}
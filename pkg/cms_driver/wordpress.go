@@ -0,0 +1,311 @@
+package cmsdriver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	cmsDriverInterface "github.com/ray31245/seo_cluster/pkg/cms_driver/cms_driver_interface"
+	"github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	wordpressModel "github.com/ray31245/seo_cluster/pkg/wordpress_api/model"
+	wordpressInterface "github.com/ray31245/seo_cluster/pkg/wordpress_api/wordpress_interface"
+)
+
+// wordpressDateLayout is the layout of date_gmt in wordpress response
+const wordpressDateLayout = "2006-01-02T15:04:05"
+
+// WordpressDriver is a struct to implement Driver interface
+var _ cmsDriverInterface.Driver = &WordpressDriver{}
+
+type WordpressDriver struct {
+	wordpressAPI wordpressInterface.WordpressAPI
+}
+
+func NewWordpressDriver(wordpressAPI wordpressInterface.WordpressAPI) *WordpressDriver {
+	return &WordpressDriver{
+		wordpressAPI: wordpressAPI,
+	}
+}
+
+func (d *WordpressDriver) CMSType() dbModel.CMSType {
+	return dbModel.CMSTypeWordPress
+}
+
+func (d *WordpressDriver) NewClient(ctx context.Context, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.wordpressAPI.NewClient(ctx, cred.URL, wordpressAuthentication(cred), cred.HTTPOptions)
+	if err != nil {
+		return nil, fmt.Errorf("NewClient: %w", err)
+	}
+
+	return &wordpressClient{client: client}, nil
+}
+
+func (d *WordpressDriver) GetClient(ctx context.Context, ID uuid.UUID, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.wordpressAPI.GetClient(ctx, ID, cred.URL, wordpressAuthentication(cred), cred.HTTPOptions)
+	if err != nil {
+		return nil, fmt.Errorf("GetClient: %w", err)
+	}
+
+	return &wordpressClient{client: client}, nil
+}
+
+func (d *WordpressDriver) UpdateClient(ctx context.Context, ID uuid.UUID, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.wordpressAPI.UpdateClient(ctx, ID, cred.URL, wordpressAuthentication(cred), cred.HTTPOptions)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateClient: %w", err)
+	}
+
+	return &wordpressClient{client: client}, nil
+}
+
+func (d *WordpressDriver) DeleteClient(ID uuid.UUID) {
+	d.wordpressAPI.DeleteClient(ID)
+}
+
+func (d *WordpressDriver) NewAnonymousClient(ctx context.Context, urlStr string) cmsDriverInterface.Client {
+	return &wordpressClient{client: d.wordpressAPI.NewAnonymousClient(ctx, urlStr)}
+}
+
+func (d *WordpressDriver) PublishCadence() model.PublishCadence {
+	return model.PublishCadenceTimePoints
+}
+
+func (d *WordpressDriver) ExpectedDailyDemand() float64 {
+	return timePointsDailyDemand
+}
+
+// wordpressAuthentication convert the credential to the authentication of wordpress api
+func wordpressAuthentication(cred model.Credential) wordpressModel.Authentication {
+	return wordpressModel.Authentication{
+		Mode:     wordpressModel.AuthMode(cred.AuthMode),
+		Username: cred.UserName,
+		Password: cred.Password,
+	}
+}
+
+type wordpressClient struct {
+	client wordpressInterface.WordpressClient
+}
+
+func (c *wordpressClient) ListCategory(ctx context.Context) ([]model.Category, error) {
+	categories, err := c.client.ListCategory(ctx, wordpressModel.ListCategoryArgs{})
+	if err != nil {
+		return nil, fmt.Errorf("ListCategory: %w", err)
+	}
+
+	res := make([]model.Category, 0, len(categories))
+	for _, cate := range categories {
+		res = append(res, model.Category{ID: strconv.Itoa(cate.ID), Name: cate.Name})
+	}
+
+	return res, nil
+}
+
+func (c *wordpressClient) CreateCategory(ctx context.Context, args model.CreateCategoryArgs) (model.Category, error) {
+	var parentID int
+
+	if args.ParentID != "" {
+		ID, err := strconv.Atoi(args.ParentID)
+		if err != nil {
+			return model.Category{}, fmt.Errorf("CreateCategory: %w", err)
+		}
+
+		parentID = ID
+	}
+
+	cate, err := c.client.CreateCategory(ctx, wordpressModel.CreateCategoryArgs{
+		Name:        args.Name,
+		Description: args.Description,
+		Parent:      parentID,
+	})
+	if err != nil {
+		return model.Category{}, fmt.Errorf("CreateCategory: %w", err)
+	}
+
+	return model.Category{ID: strconv.Itoa(cate.ID), Name: cate.Name}, nil
+}
+
+func (c *wordpressClient) RenameCategory(ctx context.Context, ID string, name string) (model.Category, error) {
+	cateID, err := strconv.Atoi(ID)
+	if err != nil {
+		return model.Category{}, fmt.Errorf("RenameCategory: %w", err)
+	}
+
+	cate, err := c.client.UpdateCategory(ctx, wordpressModel.UpdateCategoryArgs{ID: cateID, Name: name})
+	if err != nil {
+		return model.Category{}, fmt.Errorf("RenameCategory: %w", err)
+	}
+
+	return model.Category{ID: strconv.Itoa(cate.ID), Name: cate.Name}, nil
+}
+
+func (c *wordpressClient) DeleteCategory(ctx context.Context, ID string) error {
+	cateID, err := strconv.Atoi(ID)
+	if err != nil {
+		return fmt.Errorf("DeleteCategory: %w", err)
+	}
+
+	err = c.client.DeleteCategory(ctx, wordpressModel.DeleteCategoryArgs{ID: cateID})
+	if err != nil {
+		return fmt.Errorf("DeleteCategory: %w", err)
+	}
+
+	return nil
+}
+
+func (c *wordpressClient) GetArticle(ctx context.Context, ID string) (model.Article, error) {
+	artID, err := strconv.Atoi(ID)
+	if err != nil {
+		return model.Article{}, fmt.Errorf("GetArticle: %w", err)
+	}
+
+	article, err := c.client.RetrieveArticle(ctx, wordpressModel.RetrieveArticleArgs{ID: artID})
+	if err != nil {
+		return model.Article{}, fmt.Errorf("GetArticle: %w", err)
+	}
+
+	return toWordpressArticle(wordpressModel.ArticleSchema(article)), nil
+}
+
+func (c *wordpressClient) ListArticle(ctx context.Context) ([]model.Article, error) {
+	articles, err := c.client.ListArticle(ctx, wordpressModel.ListArticleArgs{})
+	if err != nil {
+		return nil, fmt.Errorf("ListArticle: %w", err)
+	}
+
+	res := make([]model.Article, 0, len(articles))
+	for _, a := range articles {
+		res = append(res, toWordpressArticle(a))
+	}
+
+	return res, nil
+}
+
+func (c *wordpressClient) PostArticle(ctx context.Context, article model.Article) (model.Article, error) {
+	cateID, err := strconv.ParseUint(article.CateID, 10, 32)
+	if err != nil {
+		return model.Article{}, fmt.Errorf("PostArticle: %w", err)
+	}
+
+	date := wordpressModel.Date{
+		Time: time.Now(),
+	}
+
+	postArt, err := c.client.CreateArticle(ctx, wordpressModel.CreateArticleArgs{
+		Title:      article.Title,
+		Sticky:     article.IsTop,
+		Content:    article.Content,
+		Categories: []uint32{uint32(cateID)},
+		Status:     wordpressModel.StatusPublish,
+		Date:       &date,
+	})
+	if err != nil {
+		return model.Article{}, fmt.Errorf("PostArticle: %w", err)
+	}
+
+	return toWordpressArticle(wordpressModel.ArticleSchema(postArt)), nil
+}
+
+// UpdateArticleTags set tags of article by ids, the tags must exist in site
+func (c *wordpressClient) UpdateArticleTags(ctx context.Context, ID string, tags []model.Tag) error {
+	artID, err := strconv.Atoi(ID)
+	if err != nil {
+		return fmt.Errorf("UpdateArticleTags: %w", err)
+	}
+
+	tagIDs := make([]int, 0, len(tags))
+
+	for _, tag := range tags {
+		tagID, err := strconv.Atoi(tag.ID)
+		if err != nil {
+			return fmt.Errorf("UpdateArticleTags: %w", err)
+		}
+
+		tagIDs = append(tagIDs, tagID)
+	}
+
+	_, err = c.client.UpdateArticle(ctx, wordpressModel.UpdateArticleArgs{
+		ID:   artID,
+		Tags: tagIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("UpdateArticleTags: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteArticle move the article to trash
+func (c *wordpressClient) DeleteArticle(ctx context.Context, ID string) error {
+	artID, err := strconv.Atoi(ID)
+	if err != nil {
+		return fmt.Errorf("DeleteArticle: %w", err)
+	}
+
+	err = c.client.DeleteArticle(ctx, wordpressModel.DeleteArticleArgs{ID: artID})
+	if err != nil {
+		return fmt.Errorf("DeleteArticle: %w", err)
+	}
+
+	return nil
+}
+
+func (c *wordpressClient) ListTagAll(ctx context.Context) ([]model.Tag, error) {
+	tags, err := c.client.ListTagAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ListTagAll: %w", err)
+	}
+
+	res := make([]model.Tag, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, model.Tag{ID: strconv.Itoa(tag.GetID()), Name: tag.GetName(), Count: tag.GetCount()})
+	}
+
+	return res, nil
+}
+
+func (c *wordpressClient) CreateTag(ctx context.Context, name string) (model.Tag, error) {
+	tag, err := c.client.CreateTag(ctx, wordpressModel.CreateTagArgs{Name: name})
+	if err != nil {
+		return model.Tag{}, fmt.Errorf("CreateTag: %w", err)
+	}
+
+	return model.Tag{ID: strconv.Itoa(tag.ID), Name: name}, nil
+}
+
+func (c *wordpressClient) PostComment(ctx context.Context, articleID string, content string) error {
+	artID, err := strconv.Atoi(articleID)
+	if err != nil {
+		return fmt.Errorf("PostComment: %w", err)
+	}
+
+	_, err = c.client.CreateComment(ctx, wordpressModel.CreateCommentArgs{Post: artID, Content: content})
+	if err != nil {
+		return fmt.Errorf("PostComment: %w", err)
+	}
+
+	return nil
+}
+
+func toWordpressArticle(article wordpressModel.ArticleSchema) model.Article {
+	res := model.Article{
+		ID:      strconv.Itoa(article.ID),
+		Title:   article.Title.Rendered,
+		Content: article.Content.Rendered,
+		IsTop:   article.Sticky,
+	}
+
+	if len(article.Categories) > 0 {
+		res.CateID = strconv.Itoa(article.Categories[0])
+	}
+
+	// keep zero time if the date is absent
+	if postTime, err := time.Parse(wordpressDateLayout, article.DateGmt); err == nil {
+		res.PostTime = postTime
+	}
+
+	return res
+}
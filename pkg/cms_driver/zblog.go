@@ -0,0 +1,309 @@
+package cmsdriver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	cmsDriverInterface "github.com/ray31245/seo_cluster/pkg/cms_driver/cms_driver_interface"
+	"github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/pkg/util"
+	zModel "github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
+	zInterface "github.com/ray31245/seo_cluster/pkg/z_blog_api/z_blog_Interface"
+)
+
+// ZBlogDriver is a struct to implement Driver interface
+var _ cmsDriverInterface.Driver = &ZBlogDriver{}
+
+type ZBlogDriver struct {
+	zAPI zInterface.ZBlogAPI
+}
+
+func NewZBlogDriver(zAPI zInterface.ZBlogAPI) *ZBlogDriver {
+	return &ZBlogDriver{
+		zAPI: zAPI,
+	}
+}
+
+func (d *ZBlogDriver) CMSType() dbModel.CMSType {
+	return dbModel.CMSTypeZBlog
+}
+
+func (d *ZBlogDriver) NewClient(ctx context.Context, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.zAPI.NewClient(ctx, cred.URL, cred.UserName, cred.Password, cred.HTTPOptions)
+	if err != nil {
+		return nil, fmt.Errorf("NewClient: %w", err)
+	}
+
+	return &zBlogClient{client: client}, nil
+}
+
+func (d *ZBlogDriver) GetClient(ctx context.Context, ID uuid.UUID, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.zAPI.GetClient(ctx, ID, cred.URL, cred.UserName, cred.Password, cred.HTTPOptions)
+	if err != nil {
+		return nil, fmt.Errorf("GetClient: %w", err)
+	}
+
+	return &zBlogClient{client: client}, nil
+}
+
+func (d *ZBlogDriver) UpdateClient(ctx context.Context, ID uuid.UUID, cred model.Credential) (cmsDriverInterface.Client, error) {
+	client, err := d.zAPI.UpdateClient(ctx, ID, cred.URL, cred.UserName, cred.Password, cred.HTTPOptions)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateClient: %w", err)
+	}
+
+	return &zBlogClient{client: client}, nil
+}
+
+func (d *ZBlogDriver) DeleteClient(ID uuid.UUID) {
+	d.zAPI.DeleteClient(ID)
+}
+
+func (d *ZBlogDriver) NewAnonymousClient(ctx context.Context, urlStr string) cmsDriverInterface.Client {
+	return &zBlogClient{client: d.zAPI.NewAnonymousClient(ctx, urlStr)}
+}
+
+func (d *ZBlogDriver) PublishCadence() model.PublishCadence {
+	return model.PublishCadenceRandomCycle
+}
+
+func (d *ZBlogDriver) ExpectedDailyDemand() float64 {
+	return randomCycleDailyDemand
+}
+
+type zBlogClient struct {
+	client zInterface.ZBlogAPIClient
+}
+
+func (c *zBlogClient) ListCategory(ctx context.Context) ([]model.Category, error) {
+	categories, err := c.client.ListCategory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ListCategory: %w", err)
+	}
+
+	res := make([]model.Category, 0, len(categories))
+
+	for _, cate := range categories {
+		converted, err := toZBlogCategory(cate)
+		if err != nil {
+			return nil, fmt.Errorf("ListCategory: %w", err)
+		}
+
+		res = append(res, converted)
+	}
+
+	return res, nil
+}
+
+func (c *zBlogClient) CreateCategory(ctx context.Context, args model.CreateCategoryArgs) (model.Category, error) {
+	var parentID uint32
+
+	if args.ParentID != "" {
+		ID, err := parseZBlogID(args.ParentID)
+		if err != nil {
+			return model.Category{}, fmt.Errorf("CreateCategory: %w", err)
+		}
+
+		parentID = ID
+	}
+
+	cate, err := c.client.PostCategory(ctx, zModel.PostCategoryRequest{Name: args.Name, ParentID: parentID, Intro: args.Description})
+	if err != nil {
+		return model.Category{}, fmt.Errorf("CreateCategory: %w", err)
+	}
+
+	res, err := toZBlogCategory(cate)
+	if err != nil {
+		return model.Category{}, fmt.Errorf("CreateCategory: %w", err)
+	}
+
+	return res, nil
+}
+
+// RenameCategory post the category with ID to update it
+func (c *zBlogClient) RenameCategory(ctx context.Context, ID string, name string) (model.Category, error) {
+	cateID, err := parseZBlogID(ID)
+	if err != nil {
+		return model.Category{}, fmt.Errorf("RenameCategory: %w", err)
+	}
+
+	cate, err := c.client.PostCategory(ctx, zModel.PostCategoryRequest{ID: cateID, Name: name})
+	if err != nil {
+		return model.Category{}, fmt.Errorf("RenameCategory: %w", err)
+	}
+
+	res, err := toZBlogCategory(cate)
+	if err != nil {
+		return model.Category{}, fmt.Errorf("RenameCategory: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *zBlogClient) DeleteCategory(ctx context.Context, ID string) error {
+	cateID, err := parseZBlogID(ID)
+	if err != nil {
+		return fmt.Errorf("DeleteCategory: %w", err)
+	}
+
+	err = c.client.DeleteCategory(ctx, cateID)
+	if err != nil {
+		return fmt.Errorf("DeleteCategory: %w", err)
+	}
+
+	return nil
+}
+
+func (c *zBlogClient) GetArticle(ctx context.Context, ID string) (model.Article, error) {
+	article, err := c.client.GetArticle(ctx, ID)
+	if err != nil {
+		return model.Article{}, fmt.Errorf("GetArticle: %w", err)
+	}
+
+	return toZBlogArticle(article), nil
+}
+
+func (c *zBlogClient) ListArticle(ctx context.Context) ([]model.Article, error) {
+	articles, err := c.client.ListArticle(ctx, zModel.ListArticleRequest{
+		PageRequest: zModel.PageRequest{
+			SortBy: "PostTime",
+			Order:  "desc",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ListArticle: %w", err)
+	}
+
+	res := make([]model.Article, 0, len(articles))
+	for _, a := range articles {
+		res = append(res, toZBlogArticle(a))
+	}
+
+	return res, nil
+}
+
+func (c *zBlogClient) PostArticle(ctx context.Context, article model.Article) (model.Article, error) {
+	cateID, err := parseZBlogID(article.CateID)
+	if err != nil {
+		return model.Article{}, fmt.Errorf("PostArticle: %w", err)
+	}
+
+	var isTop uint8 = 0
+	if article.IsTop {
+		isTop = 1
+	}
+
+	postArt, err := c.client.PostArticle(ctx, zModel.PostArticleRequest{
+		Title:    article.Title,
+		IsTop:    isTop,
+		Content:  article.Content,
+		CateID:   cateID,
+		Intro:    article.Content,
+		PostTime: &util.UnixTime{Time: time.Now()},
+	})
+	if err != nil {
+		return model.Article{}, fmt.Errorf("PostArticle: %w", err)
+	}
+
+	return toZBlogArticle(postArt), nil
+}
+
+// UpdateArticleTags set tags of article by names, zblog create the tags not exist
+func (c *zBlogClient) UpdateArticleTags(ctx context.Context, ID string, tags []model.Tag) error {
+	artID, err := strconv.ParseUint(ID, 10, 32)
+	if err != nil {
+		return fmt.Errorf("UpdateArticleTags: %w", err)
+	}
+
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+
+	_, err = c.client.PostArticle(ctx, zModel.PostArticleRequest{
+		ID:  uint32(artID),
+		Tag: strings.Join(names, ","),
+	})
+	if err != nil {
+		return fmt.Errorf("UpdateArticleTags: %w", err)
+	}
+
+	return nil
+}
+
+func (c *zBlogClient) DeleteArticle(ctx context.Context, ID string) error {
+	err := c.client.DeleteArticle(ctx, ID)
+	if err != nil {
+		return fmt.Errorf("DeleteArticle: %w", err)
+	}
+
+	return nil
+}
+
+func (c *zBlogClient) ListTagAll(ctx context.Context) ([]model.Tag, error) {
+	tags, err := c.client.ListTagAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ListTagAll: %w", err)
+	}
+
+	res := make([]model.Tag, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, model.Tag{ID: string(tag.ID), Name: tag.GetName(), Count: tag.GetCount()})
+	}
+
+	return res, nil
+}
+
+func (c *zBlogClient) CreateTag(ctx context.Context, name string) (model.Tag, error) {
+	tag, err := c.client.PostTag(ctx, zModel.PostTagRequest{Name: name})
+	if err != nil {
+		return model.Tag{}, fmt.Errorf("CreateTag: %w", err)
+	}
+
+	return model.Tag{ID: string(tag.ID), Name: tag.GetName(), Count: tag.GetCount()}, nil
+}
+
+func (c *zBlogClient) PostComment(ctx context.Context, articleID string, content string) error {
+	err := c.client.PostComment(ctx, zModel.PostCommentRequest{LogID: articleID, Content: content})
+	if err != nil {
+		return fmt.Errorf("PostComment: %w", err)
+	}
+
+	return nil
+}
+
+// parseZBlogID parse the numeric id of zblog
+func parseZBlogID(ID string) (uint32, error) {
+	res, err := strconv.ParseUint(ID, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("parseZBlogID: %w", err)
+	}
+
+	return uint32(res), nil
+}
+
+func toZBlogCategory(cate zModel.Category) (model.Category, error) {
+	// validate the id is numeric, it is parsed again when it is used
+	_, err := parseZBlogID(cate.ID)
+	if err != nil {
+		return model.Category{}, fmt.Errorf("toZBlogCategory: %w", err)
+	}
+
+	return model.Category{ID: cate.ID, Name: cate.Name}, nil
+}
+
+func toZBlogArticle(article zModel.Article) model.Article {
+	return model.Article{
+		ID:       string(article.ID),
+		Title:    article.Title,
+		Content:  article.Content,
+		CateID:   string(article.CateID),
+		PostTime: article.PostTime.Time,
+		CommNums: int(article.CommNums),
+	}
+}
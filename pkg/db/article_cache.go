@@ -2,8 +2,9 @@ package db
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"slices"
+	"time"
 
 	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
 	"github.com/ray31245/seo_cluster/pkg/db/model"
@@ -16,7 +17,7 @@ type ArticleCacheDAO struct {
 }
 
 func (d *DB) NewArticleCacheDAO() (*ArticleCacheDAO, error) {
-	err := d.db.AutoMigrate(&model.ArticleCache{})
+	err := d.db.AutoMigrate(&model.ArticleCache{}, &model.ArticleCacheRevision{})
 	if err != nil {
 		return nil, fmt.Errorf("NewArticleCacheDAO: %w", err)
 	}
@@ -36,12 +37,19 @@ func (d *ArticleCacheDAO) AddArticleToCache(article model.ArticleCache) error {
 	return d.db.Create(&article).Error
 }
 
+// queryReadyToPublish query the articles that can be published automatically
+func (d *ArticleCacheDAO) queryReadyToPublish(now time.Time) *gorm.DB {
+	return d.db.Where("status NOT IN ?", []model.ArticleCacheStatus{model.ArticleCacheStatusReserved, model.ArticleCacheStatusExpired}).
+		Where("expires_at IS NULL OR expires_at > ?", now)
+}
+
 func (d *ArticleCacheDAO) ListReadyToPublishArticleCacheByLimit(limit int) ([]model.ArticleCache, error) {
 	var articles []model.ArticleCache
-	err := d.db.Where("status != ?", model.ArticleCacheStatusReserved).Limit(limit).Order("created_at").Find(&articles).Error
+	err := d.queryReadyToPublish(time.Now()).
+		Limit(limit).Order("priority desc").Order("created_at").Find(&articles).Error
 
 	if len(articles) < limit {
-		log.Println("ArticleCacheDAO: ListArticleCacheByLimit: less than limit")
+		slog.Debug("ListReadyToPublishArticleCacheByLimit: less than limit", slog.Int("limit", limit), slog.Int("count", len(articles)))
 	}
 
 	return articles, err
@@ -80,6 +88,45 @@ func (d *ArticleCacheDAO) ListEditAbleArticleCachePaginator(titleKeyword, conten
 	return articles, totalPage, totalRows, err
 }
 
+func (d *ArticleCacheDAO) ListExpiredArticleCachePaginator(titleKeyword, contentKeyword string, op model.Operator, page int, limit int) ([]model.ArticleCache, int, int64, error) {
+	q := d.db.Where("status = ?", model.ArticleCacheStatusExpired).Order("expires_at desc")
+
+	q = articleFuzzySearch(q, titleKeyword, contentKeyword, op)
+
+	articles, totalPage, totalRows, err := paginator(model.ArticleCache{}, q, page, limit)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("ListExpiredArticleCachePaginator: %w", err)
+	}
+
+	return articles, totalPage, totalRows, err
+}
+
+// ExpireArticleCache mark the article cache which is expired before now as expired and record the revisions,
+// article in buffer is being published, it will not be marked
+func (d *ArticleCacheDAO) ExpireArticleCache(now time.Time) (int64, error) {
+	var count int64
+
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		articles := []model.ArticleCache{}
+
+		err := tx.Where("status IN ?", []model.ArticleCacheStatus{model.ArticleCacheStatusDefault, model.ArticleCacheStatusReserved}).
+			Where("expires_at IS NOT NULL AND expires_at <= ?", now).
+			Find(&articles).Error
+		if err != nil {
+			return err
+		}
+
+		count, err = updateArticleCacheStatus(tx, articles, model.ArticleCacheStatusExpired, "")
+
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("ExpireArticleCache: %w", err)
+	}
+
+	return count, nil
+}
+
 func (d *ArticleCacheDAO) GetArticleCacheByID(id string) (*model.ArticleCache, error) {
 	article := model.ArticleCache{}
 
@@ -92,12 +139,25 @@ func (d *ArticleCacheDAO) GetArticleCacheByID(id string) (*model.ArticleCache, e
 }
 
 func (d *ArticleCacheDAO) DeleteArticleCacheByIDs(ids []string) error {
+	err := d.db.Where("article_cache_id IN ?", ids).Delete(&model.ArticleCacheRevision{}).Error
+	if err != nil {
+		return fmt.Errorf("DeleteArticleCacheByIDs: %w", err)
+	}
+
 	return d.db.Where("id IN ?", ids).Delete(&model.ArticleCache{}).Error
 }
 
 func (d *ArticleCacheDAO) CountArticleCache() (int64, error) {
 	var count int64
-	err := d.db.Model(&model.ArticleCache{}).Count(&count).Error
+	err := d.db.Model(&model.ArticleCache{}).Where("status != ?", model.ArticleCacheStatusExpired).Count(&count).Error
+
+	return count, err
+}
+
+// CountReadyToPublishArticleCache count the articles that can be published automatically
+func (d *ArticleCacheDAO) CountReadyToPublishArticleCache() (int64, error) {
+	var count int64
+	err := d.queryReadyToPublish(time.Now()).Model(&model.ArticleCache{}).Count(&count).Error
 
 	return count, err
 }
@@ -106,10 +166,224 @@ func (d *ArticleCacheDAO) EditArticleCache(id string, title string, content stri
 	return d.db.Model(&model.ArticleCache{}).Where("id = ?", id).Updates(map[string]interface{}{"title": title, "content": content}).Error
 }
 
-func (d *ArticleCacheDAO) UpdateArticleCacheStatusByIDs(ids []string, status model.ArticleCacheStatus) error {
+// UpdateArticleCacheStatusByIDs update the status and record the revisions, editor empty means changed by system
+func (d *ArticleCacheDAO) UpdateArticleCacheStatusByIDs(ids []string, status model.ArticleCacheStatus, editor string) error {
 	if !slices.Contains(model.ArticleCacheStatues, status) {
-		return fmt.Errorf("AddArticleToCache: %w", dbErr.ErrInvalidArticleCacheStatus)
+		return fmt.Errorf("UpdateArticleCacheStatusByIDs: %w", dbErr.ErrInvalidArticleCacheStatus)
+	}
+
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		articles := []model.ArticleCache{}
+
+		err := tx.Where("id IN ?", ids).Find(&articles).Error
+		if err != nil {
+			return err
+		}
+
+		_, err = updateArticleCacheStatus(tx, articles, status, editor)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("UpdateArticleCacheStatusByIDs: %w", err)
+	}
+
+	return nil
+}
+
+// updateArticleCacheStatus update the status of articles, and record the revisions of the articles whose status is changed
+func updateArticleCacheStatus(tx *gorm.DB, articles []model.ArticleCache, status model.ArticleCacheStatus, editor string) (int64, error) {
+	changed := slices.DeleteFunc(slices.Clone(articles), func(article model.ArticleCache) bool {
+		return article.Status == status
+	})
+
+	if len(changed) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]string, 0, len(changed))
+	for _, article := range changed {
+		ids = append(ids, article.ID.String())
+	}
+
+	res := tx.Model(&model.ArticleCache{}).Where("id IN ?", ids).Update("status", status)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+
+	err := createStatusRevisions(tx, changed, status, editor)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected, nil
+}
+
+// createStatusRevisions record the status change of articles, articles are the ones before change.
+// The article before its first change is kept as the original revision, so the change can be undone
+func createStatusRevisions(tx *gorm.DB, articles []model.ArticleCache, status model.ArticleCacheStatus, editor string) error {
+	ids := make([]string, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.ID.String())
+	}
+
+	// articles having revisions already
+	revised := []string{}
+
+	err := tx.Model(&model.ArticleCacheRevision{}).Where("article_cache_id IN ?", ids).Distinct().Pluck("article_cache_id", &revised).Error
+	if err != nil {
+		return err
+	}
+
+	originals := []model.ArticleCacheRevision{}
+	revisions := make([]model.ArticleCacheRevision, 0, len(articles))
+
+	for _, article := range articles {
+		if !slices.Contains(revised, article.ID.String()) {
+			originals = append(originals, newArticleCacheRevision(article, model.ArticleCacheRevisionActionOriginal, ""))
+		}
+
+		article.Status = status
+		revisions = append(revisions, newArticleCacheRevision(article, model.ArticleCacheRevisionActionStatus, editor))
+	}
+
+	// originals are created first, revisions are ordered by created time
+	if len(originals) > 0 {
+		err = tx.Create(&originals).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Create(&revisions).Error
+}
+
+// ClaimReadyToPublishArticleCache claim at most limit articles ready to publish for owner until the time, and move them in buffer.
+// The article claimed by others is skipped until its claim expires, so the instances sharing database do not publish the same article
+func (d *ArticleCacheDAO) ClaimReadyToPublishArticleCache(limit int, owner string, now time.Time, until time.Time) ([]model.ArticleCache, error) {
+	candidates := []model.ArticleCache{}
+
+	err := d.queryReadyToPublish(now).Where("claimed_until IS NULL OR claimed_until <= ?", now).
+		Limit(limit).Order("priority desc").Order("created_at").Find(&candidates).Error
+	if err != nil {
+		return nil, fmt.Errorf("ClaimReadyToPublishArticleCache: %w", err)
+	}
+
+	res := make([]model.ArticleCache, 0, len(candidates))
+
+	for _, article := range candidates {
+		isClaimed := false
+
+		err := d.db.Transaction(func(tx *gorm.DB) error {
+			// the claim is taken only if no one else claimed it after it is found
+			update := tx.Model(&model.ArticleCache{}).Where("id = ?", article.ID).
+				Where("claimed_until IS NULL OR claimed_until <= ?", now).
+				Where("status NOT IN ?", []model.ArticleCacheStatus{model.ArticleCacheStatusReserved, model.ArticleCacheStatusExpired}).
+				Updates(map[string]interface{}{"status": model.ArticleCacheStatusInBuffer, "claimed_by": owner, "claimed_until": until})
+			if update.Error != nil || update.RowsAffected == 0 {
+				return update.Error
+			}
+
+			isClaimed = true
+
+			if article.Status == model.ArticleCacheStatusInBuffer {
+				return nil
+			}
+
+			return createStatusRevisions(tx, []model.ArticleCache{article}, model.ArticleCacheStatusInBuffer, "")
+		})
+		if err != nil {
+			return res, fmt.Errorf("ClaimReadyToPublishArticleCache: %w", err)
+		}
+
+		if isClaimed {
+			article.Status = model.ArticleCacheStatusInBuffer
+			article.ClaimedBy = owner
+			article.ClaimedUntil = &until
+			res = append(res, article)
+		}
+	}
+
+	return res, nil
+}
+
+// ReleaseArticleCacheClaim release the claims of owner, the articles can be claimed again
+func (d *ArticleCacheDAO) ReleaseArticleCacheClaim(ids []string, owner string) error {
+	err := d.db.Model(&model.ArticleCache{}).Where("id IN ? AND claimed_by = ?", ids, owner).
+		Updates(map[string]interface{}{"claimed_by": "", "claimed_until": nil}).Error
+	if err != nil {
+		return fmt.Errorf("ReleaseArticleCacheClaim: %w", err)
+	}
+
+	return nil
+}
+
+// CountClaimedArticleCache count the articles being published by all instances
+func (d *ArticleCacheDAO) CountClaimedArticleCache(now time.Time) (int64, error) {
+	var count int64
+
+	err := d.db.Model(&model.ArticleCache{}).Where("claimed_until > ?", now).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("CountClaimedArticleCache: %w", err)
+	}
+
+	return count, nil
+}
+
+func (d *ArticleCacheDAO) UpdateArticleCachePriorityByIDs(ids []string, priority int) error {
+	return d.db.Model(&model.ArticleCache{}).Where("id IN ?", ids).Update("priority", priority).Error
+}
+
+// UpdateArticleCacheExpiresAtByIDs set expire time of articles, nil means never expire
+func (d *ArticleCacheDAO) UpdateArticleCacheExpiresAtByIDs(ids []string, expiresAt *time.Time) error {
+	return d.db.Model(&model.ArticleCache{}).Where("id IN ?", ids).Update("expires_at", expiresAt).Error
+}
+
+// IterateArticleCache walk through all article cache in batches, stop when fn return error
+func (d *ArticleCacheDAO) IterateArticleCache(batchSize int, fn func([]model.ArticleCache) error) error {
+	var articles []model.ArticleCache
+
+	err := d.db.FindInBatches(&articles, batchSize, func(_ *gorm.DB, _ int) error {
+		return fn(articles)
+	}).Error
+	if err != nil {
+		return fmt.Errorf("IterateArticleCache: %w", err)
+	}
+
+	return nil
+}
+
+func (d *ArticleCacheDAO) FindArticleCacheByTitle(title string) (*model.ArticleCache, error) {
+	article := model.ArticleCache{}
+
+	err := d.db.Where("title = ?", title).Order("created_at").First(&article).Error
+	if err != nil {
+		return nil, fmt.Errorf("FindArticleCacheByTitle: %w", err)
+	}
+
+	return &article, nil
+}
+
+// OverwriteArticleCache replace title, content and status of the article cache with same id
+func (d *ArticleCacheDAO) OverwriteArticleCache(article model.ArticleCache) error {
+	if !slices.Contains(model.ArticleCacheStatues, article.Status) {
+		return fmt.Errorf("OverwriteArticleCache: %w", dbErr.ErrInvalidArticleCacheStatus)
+	}
+
+	tx := d.db.Model(&model.ArticleCache{}).Where("id = ?", article.ID).Updates(map[string]interface{}{
+		"title":      article.Title,
+		"content":    article.Content,
+		"status":     article.Status,
+		"priority":   article.Priority,
+		"expires_at": article.ExpiresAt,
+	})
+	if tx.Error != nil {
+		return fmt.Errorf("OverwriteArticleCache: %w", tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return fmt.Errorf("OverwriteArticleCache: %w", dbErr.ErrNotFound)
 	}
 
-	return d.db.Model(&model.ArticleCache{}).Where("id IN ?", ids).Update("status", status).Error
+	return nil
 }
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/ray31245/seo_cluster/pkg/db/model"
+)
+
+func (d *ArticleCacheDAO) CreateArticleCacheRevision(revision *model.ArticleCacheRevision) error {
+	err := d.db.Create(revision).Error
+	if err != nil {
+		return fmt.Errorf("CreateArticleCacheRevision: %w", err)
+	}
+
+	return nil
+}
+
+func (d *ArticleCacheDAO) GetArticleCacheRevision(id string) (*model.ArticleCacheRevision, error) {
+	revision := model.ArticleCacheRevision{}
+
+	err := d.db.Where("id = ?", id).First(&revision).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetArticleCacheRevision: %w", err)
+	}
+
+	return &revision, nil
+}
+
+func (d *ArticleCacheDAO) CountArticleCacheRevisions(articleCacheID string) (int64, error) {
+	var count int64
+
+	err := d.db.Model(&model.ArticleCacheRevision{}).Where("article_cache_id = ?", articleCacheID).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("CountArticleCacheRevisions: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListArticleCacheRevisionsPaginator list revisions of article cache, latest first
+func (d *ArticleCacheDAO) ListArticleCacheRevisionsPaginator(articleCacheID string, page int, limit int) ([]model.ArticleCacheRevision, int, int64, error) {
+	q := d.db.Where("article_cache_id = ?", articleCacheID).Order("created_at desc")
+
+	revisions, totalPage, totalRows, err := paginator(model.ArticleCacheRevision{}, q, page, limit)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("ListArticleCacheRevisionsPaginator: %w", err)
+	}
+
+	return revisions, totalPage, totalRows, nil
+}
+
+func newArticleCacheRevision(article model.ArticleCache, action model.ArticleCacheRevisionAction, editor string) model.ArticleCacheRevision {
+	return model.ArticleCacheRevision{
+		ArticleCacheID: article.ID,
+		Action:         action,
+		Editor:         editor,
+		Title:          article.Title,
+		Content:        article.Content,
+		Status:         article.Status,
+	}
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
+	"github.com/ray31245/seo_cluster/pkg/db/model"
+
+	"gorm.io/gorm"
+)
+
+type CategoryTemplateDAO struct {
+	db *gorm.DB
+}
+
+func (d *DB) NewCategoryTemplateDAO() (*CategoryTemplateDAO, error) {
+	err := d.db.AutoMigrate(&model.CategoryTemplate{}, &model.CategoryTemplateItem{})
+	if err != nil {
+		return nil, fmt.Errorf("NewCategoryTemplateDAO: %w", err)
+	}
+
+	return &CategoryTemplateDAO{db: d.db}, nil
+}
+
+func (d *CategoryTemplateDAO) CreateCategoryTemplate(template *model.CategoryTemplate) (model.CategoryTemplate, error) {
+	setItemPosition(template)
+
+	err := d.db.Create(template).Error
+	if err != nil {
+		return model.CategoryTemplate{}, fmt.Errorf("CreateCategoryTemplate: %w", err)
+	}
+
+	return *template, nil
+}
+
+func (d *CategoryTemplateDAO) GetCategoryTemplate(id string) (*model.CategoryTemplate, error) {
+	var template model.CategoryTemplate
+
+	err := d.db.Preload("Categories", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).First(&template, "id = ?", id).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetCategoryTemplate: %w", err)
+	}
+
+	return &template, nil
+}
+
+func (d *CategoryTemplateDAO) ListCategoryTemplates() ([]model.CategoryTemplate, error) {
+	var templates []model.CategoryTemplate
+	err := d.db.Preload("Categories", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Order("name").Find(&templates).Error
+
+	return templates, err
+}
+
+// UpdateCategoryTemplate rename the template and replace all its categories in a transaction
+func (d *CategoryTemplateDAO) UpdateCategoryTemplate(template *model.CategoryTemplate) error {
+	setItemPosition(template)
+
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(template).Select("name").Updates(template)
+		if res.Error != nil {
+			return fmt.Errorf("UpdateCategoryTemplate: %w", res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("UpdateCategoryTemplate: %w", dbErr.ErrNotFound)
+		}
+
+		err := tx.Where("template_id = ?", template.ID).Delete(&model.CategoryTemplateItem{}).Error
+		if err != nil {
+			return fmt.Errorf("UpdateCategoryTemplate: %w", err)
+		}
+
+		if len(template.Categories) == 0 {
+			return nil
+		}
+
+		err = tx.Create(&template.Categories).Error
+		if err != nil {
+			return fmt.Errorf("UpdateCategoryTemplate: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (d *CategoryTemplateDAO) DeleteCategoryTemplate(id string) error {
+	tx := d.db.Delete(&model.CategoryTemplate{}, "id = ?", id)
+	if tx.Error != nil {
+		return fmt.Errorf("DeleteCategoryTemplate: %w", tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return fmt.Errorf("DeleteCategoryTemplate: %w", dbErr.ErrNotFound)
+	}
+
+	err := d.db.Where("template_id = ?", id).Delete(&model.CategoryTemplateItem{}).Error
+	if err != nil {
+		return fmt.Errorf("DeleteCategoryTemplate: %w", err)
+	}
+
+	return nil
+}
+
+// setItemPosition number the categories of template by their order and link them to template
+func setItemPosition(template *model.CategoryTemplate) {
+	for i := range template.Categories {
+		template.Categories[i].ID = uuid.Nil
+		template.Categories[i].TemplateID = template.ID
+		template.Categories[i].Position = i
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/pkg/secret"
 	"gorm.io/gorm"
 )
 
@@ -49,3 +50,35 @@ func (d *CommentUserDAO) ListCommentUsers() ([]model.CommentUser, error) {
 
 	return users, err
 }
+
+// RotateCommentUserSecrets is RotateSiteSecrets of comment users
+func (d *CommentUserDAO) RotateCommentUserSecrets() (int, error) {
+	keyring := secret.FromContext(d.db.Statement.Context)
+	if keyring == nil {
+		return 0, fmt.Errorf("RotateCommentUserSecrets: %w", secret.ErrKeyNotConfigured)
+	}
+
+	var users []model.CommentUser
+
+	err := d.db.Find(&users).Error
+	if err != nil {
+		return 0, fmt.Errorf("RotateCommentUserSecrets: %w", err)
+	}
+
+	count := 0
+
+	for _, user := range users {
+		if user.Password == "" || user.PasswordKeyID == keyring.PrimaryID() {
+			continue
+		}
+
+		err = d.db.Model(&user).Select("password", "password_key_id", "password_data_key").Updates(&user).Error
+		if err != nil {
+			return count, fmt.Errorf("RotateCommentUserSecrets: %w", err)
+		}
+
+		count++
+	}
+
+	return count, nil
+}
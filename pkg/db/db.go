@@ -1,8 +1,11 @@
 package db
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/ray31245/seo_cluster/pkg/secret"
+
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -21,6 +24,12 @@ func NewDB(dsn string) (*DB, error) {
 	return &DB{db: db}, nil
 }
 
+// UseSecretKeyring encrypt the passwords of site and comment user by keyring, and decrypt them after find.
+// It must be called before creating DAO, the passwords are stored as plaintext without keyring.
+func (d *DB) UseSecretKeyring(keyring *secret.Keyring) {
+	d.db = d.db.WithContext(secret.NewContext(context.Background(), keyring))
+}
+
 func (d *DB) Close() error {
 	db, err := d.db.DB()
 	if err != nil {
@@ -1,17 +1,34 @@
 package dbinterface
 
 import (
+	"time"
+
 	"github.com/ray31245/seo_cluster/pkg/db/model"
 )
 
 type ArticleCacheDAOInterface interface {
 	AddArticleToCache(article model.ArticleCache) error
 	ListReadyToPublishArticleCacheByLimit(limit int) ([]model.ArticleCache, error)
+	ClaimReadyToPublishArticleCache(limit int, owner string, now time.Time, until time.Time) ([]model.ArticleCache, error)
+	ReleaseArticleCacheClaim(ids []string, owner string) error
+	CountClaimedArticleCache(now time.Time) (int64, error)
 	ListPublishLaterArticleCachePaginator(titleKeyword, contentKeyword string, op model.Operator, page int, limit int) ([]model.ArticleCache, int, int64, error)
 	ListEditAbleArticleCachePaginator(titleKeyword, contentKeyword string, op model.Operator, page int, limit int) ([]model.ArticleCache, int, int64, error)
+	ListExpiredArticleCachePaginator(titleKeyword, contentKeyword string, op model.Operator, page int, limit int) ([]model.ArticleCache, int, int64, error)
+	ExpireArticleCache(now time.Time) (int64, error)
 	GetArticleCacheByID(id string) (*model.ArticleCache, error)
 	DeleteArticleCacheByIDs(ids []string) error
 	CountArticleCache() (int64, error)
+	CountReadyToPublishArticleCache() (int64, error)
 	EditArticleCache(id string, title string, content string) error
-	UpdateArticleCacheStatusByIDs(ids []string, status model.ArticleCacheStatus) error
+	UpdateArticleCacheStatusByIDs(ids []string, status model.ArticleCacheStatus, editor string) error
+	UpdateArticleCachePriorityByIDs(ids []string, priority int) error
+	UpdateArticleCacheExpiresAtByIDs(ids []string, expiresAt *time.Time) error
+	CreateArticleCacheRevision(revision *model.ArticleCacheRevision) error
+	GetArticleCacheRevision(id string) (*model.ArticleCacheRevision, error)
+	CountArticleCacheRevisions(articleCacheID string) (int64, error)
+	ListArticleCacheRevisionsPaginator(articleCacheID string, page int, limit int) ([]model.ArticleCacheRevision, int, int64, error)
+	IterateArticleCache(batchSize int, fn func([]model.ArticleCache) error) error
+	FindArticleCacheByTitle(title string) (*model.ArticleCache, error)
+	OverwriteArticleCache(article model.ArticleCache) error
 }
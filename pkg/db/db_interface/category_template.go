@@ -0,0 +1,13 @@
+package dbinterface
+
+import (
+	"github.com/ray31245/seo_cluster/pkg/db/model"
+)
+
+type CategoryTemplateDAOInterface interface {
+	CreateCategoryTemplate(template *model.CategoryTemplate) (model.CategoryTemplate, error)
+	GetCategoryTemplate(id string) (*model.CategoryTemplate, error)
+	ListCategoryTemplates() ([]model.CategoryTemplate, error)
+	UpdateCategoryTemplate(template *model.CategoryTemplate) error
+	DeleteCategoryTemplate(id string) error
+}
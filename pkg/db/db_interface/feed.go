@@ -0,0 +1,19 @@
+package dbinterface
+
+import (
+	"time"
+
+	"github.com/ray31245/seo_cluster/pkg/db/model"
+)
+
+type FeedDAOInterface interface {
+	CreateFeed(feed *model.Feed) (model.Feed, error)
+	GetFeed(id string) (*model.Feed, error)
+	ListFeeds() ([]model.Feed, error)
+	ListActiveFeeds() ([]model.Feed, error)
+	UpdateFeed(feed *model.Feed) error
+	UpdateFeedFetchState(id string, fetchedAt time.Time, newItems int, lastError string) error
+	DeleteFeed(id string) error
+	IsFeedItemExist(feedID string, key string, link string) (bool, error)
+	CreateFeedItem(item *model.FeedItem) error
+}
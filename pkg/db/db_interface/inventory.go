@@ -0,0 +1,13 @@
+package dbinterface
+
+import (
+	"time"
+
+	"github.com/ray31245/seo_cluster/pkg/db/model"
+)
+
+type InventoryDAOInterface interface {
+	CreateInventoryFlow(flow *model.InventoryFlow) error
+	SumInventoryFlowSince(direction model.InventoryFlowDirection, since time.Time) (int, error)
+	DeleteInventoryFlowBefore(before time.Time) (int64, error)
+}
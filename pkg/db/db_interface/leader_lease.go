@@ -0,0 +1,13 @@
+package dbinterface
+
+import (
+	"time"
+
+	"github.com/ray31245/seo_cluster/pkg/db/model"
+)
+
+type LeaderLeaseDAOInterface interface {
+	TryAcquireLeaderLease(name, holderID string, now time.Time, ttl time.Duration) (bool, error)
+	ReleaseLeaderLease(name, holderID string) error
+	GetLeaderLease(name string) (*model.LeaderLease, error)
+}
@@ -2,6 +2,7 @@ package dbinterface
 
 import (
 	"github.com/ray31245/seo_cluster/pkg/db/model"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
 )
 
 type SiteDAOInterface interface {
@@ -16,10 +17,11 @@ type SiteDAOInterface interface {
 	GetSite(siteID string) (*model.Site, error)
 	DeleteSite(siteID string) error
 	UpdateSite(site *model.Site) error
+	UpdateSiteHTTPOptions(siteID string, options httpclient.Options) error
 	GetCategory(categoryID string) (*model.Category, error)
 	FirstPublishedCategory() (*model.Category, error)
 	ListPublishedCategories() ([]model.Category, error)
-	LastPublishedCategoryByCMSType(cmsType model.CMSType) (*model.Category, error)
+	LastPublishedCategoryByCMSTypes(cmsTypes []model.CMSType) (*model.Category, error)
 	MarkPublished(categoryID string) error
 	IncreaseLackCount(siteID string, count int) error
 	SumLackCount() (int, error)
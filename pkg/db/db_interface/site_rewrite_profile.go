@@ -0,0 +1,9 @@
+package dbinterface
+
+import "github.com/ray31245/seo_cluster/pkg/db/model"
+
+type SiteRewriteProfileDAOInterface interface {
+	GetSiteRewriteProfile(siteID string) (*model.SiteRewriteProfile, error)
+	UpsertSiteRewriteProfile(profile *model.SiteRewriteProfile) error
+	DeleteSiteRewriteProfile(siteID string) error
+}
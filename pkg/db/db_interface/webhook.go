@@ -0,0 +1,17 @@
+package dbinterface
+
+import (
+	"github.com/ray31245/seo_cluster/pkg/db/model"
+)
+
+type WebhookDAOInterface interface {
+	CreateWebhook(webhook *model.Webhook) (model.Webhook, error)
+	GetWebhook(id string) (*model.Webhook, error)
+	ListWebhooks() ([]model.Webhook, error)
+	ListActiveWebhooks() ([]model.Webhook, error)
+	UpdateWebhook(webhook *model.Webhook) error
+	DeleteWebhook(id string) error
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	ListWebhookDeliveriesPaginator(webhookID string, page int, limit int) ([]model.WebhookDelivery, int, int64, error)
+}
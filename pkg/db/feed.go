@@ -0,0 +1,134 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
+	"github.com/ray31245/seo_cluster/pkg/db/model"
+
+	"gorm.io/gorm"
+)
+
+type FeedDAO struct {
+	db *gorm.DB
+}
+
+func (d *DB) NewFeedDAO() (*FeedDAO, error) {
+	err := d.db.AutoMigrate(&model.Feed{}, &model.FeedItem{})
+	if err != nil {
+		return nil, fmt.Errorf("NewFeedDAO: %w", err)
+	}
+
+	return &FeedDAO{db: d.db}, nil
+}
+
+func (d *FeedDAO) CreateFeed(feed *model.Feed) (model.Feed, error) {
+	err := d.db.Create(feed).Error
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("CreateFeed: %w", err)
+	}
+
+	return *feed, nil
+}
+
+func (d *FeedDAO) GetFeed(id string) (*model.Feed, error) {
+	var feed model.Feed
+
+	err := d.db.First(&feed, "id = ?", id).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetFeed: %w", err)
+	}
+
+	return &feed, nil
+}
+
+func (d *FeedDAO) ListFeeds() ([]model.Feed, error) {
+	var feeds []model.Feed
+	err := d.db.Order("created_at").Find(&feeds).Error
+
+	return feeds, err
+}
+
+func (d *FeedDAO) ListActiveFeeds() ([]model.Feed, error) {
+	var feeds []model.Feed
+	err := d.db.Where("is_active = ?", true).Order("created_at").Find(&feeds).Error
+
+	return feeds, err
+}
+
+func (d *FeedDAO) UpdateFeed(feed *model.Feed) error {
+	tx := d.db.Model(feed).Select("name", "url", "interval_minutes", "default_status", "is_rewrite", "ttl_hours", "is_active").Updates(feed)
+	if tx.Error != nil {
+		return fmt.Errorf("UpdateFeed: %w", tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return fmt.Errorf("UpdateFeed: %w", dbErr.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (d *FeedDAO) UpdateFeedFetchState(id string, fetchedAt time.Time, newItems int, lastError string) error {
+	tx := d.db.Model(&model.Feed{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_fetched_at": fetchedAt,
+		"last_new_items":  newItems,
+		"last_error":      lastError,
+	})
+	if tx.Error != nil {
+		return fmt.Errorf("UpdateFeedFetchState: %w", tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return fmt.Errorf("UpdateFeedFetchState: %w", dbErr.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (d *FeedDAO) DeleteFeed(id string) error {
+	tx := d.db.Delete(&model.Feed{}, "id = ?", id)
+	if tx.Error != nil {
+		return fmt.Errorf("DeleteFeed: %w", tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return fmt.Errorf("DeleteFeed: %w", dbErr.ErrNotFound)
+	}
+
+	err := d.db.Where("feed_id = ?", id).Delete(&model.FeedItem{}).Error
+	if err != nil {
+		return fmt.Errorf("DeleteFeed: %w", err)
+	}
+
+	return nil
+}
+
+// IsFeedItemExist check the item is fetched before by key or link
+func (d *FeedDAO) IsFeedItemExist(feedID string, key string, link string) (bool, error) {
+	var count int64
+
+	query := d.db.Model(&model.FeedItem{}).Where("feed_id = ?", feedID)
+	if link != "" {
+		query = query.Where("item_key = ? OR link = ?", key, link)
+	} else {
+		query = query.Where("item_key = ?", key)
+	}
+
+	err := query.Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("IsFeedItemExist: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (d *FeedDAO) CreateFeedItem(item *model.FeedItem) error {
+	err := d.db.Create(item).Error
+	if err != nil {
+		return fmt.Errorf("CreateFeedItem: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ray31245/seo_cluster/pkg/db/model"
+
+	"gorm.io/gorm"
+)
+
+type InventoryDAO struct {
+	db *gorm.DB
+}
+
+func (d *DB) NewInventoryDAO() (*InventoryDAO, error) {
+	err := d.db.AutoMigrate(&model.InventoryFlow{})
+	if err != nil {
+		return nil, fmt.Errorf("NewInventoryDAO: %w", err)
+	}
+
+	return &InventoryDAO{db: d.db}, nil
+}
+
+func (d *InventoryDAO) CreateInventoryFlow(flow *model.InventoryFlow) error {
+	err := d.db.Create(flow).Error
+	if err != nil {
+		return fmt.Errorf("CreateInventoryFlow: %w", err)
+	}
+
+	return nil
+}
+
+// SumInventoryFlowSince sum the count of flows in the direction created after since
+func (d *InventoryDAO) SumInventoryFlowSince(direction model.InventoryFlowDirection, since time.Time) (int, error) {
+	var sum int
+
+	err := d.db.Model(&model.InventoryFlow{}).
+		Where("direction = ? AND created_at >= ?", direction, since).
+		Select("COALESCE(SUM(count), 0)").Scan(&sum).Error
+	if err != nil {
+		return 0, fmt.Errorf("SumInventoryFlowSince: %w", err)
+	}
+
+	return sum, nil
+}
+
+// DeleteInventoryFlowBefore delete the flows created before the time, return the number of deleted rows
+func (d *InventoryDAO) DeleteInventoryFlowBefore(before time.Time) (int64, error) {
+	tx := d.db.Where("created_at < ?", before).Delete(&model.InventoryFlow{})
+	if tx.Error != nil {
+		return 0, fmt.Errorf("DeleteInventoryFlowBefore: %w", tx.Error)
+	}
+
+	return tx.RowsAffected, nil
+}
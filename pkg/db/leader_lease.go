@@ -0,0 +1,74 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ray31245/seo_cluster/pkg/db/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LeaderLeaseDAO struct {
+	db *gorm.DB
+}
+
+func (d *DB) NewLeaderLeaseDAO() (*LeaderLeaseDAO, error) {
+	err := d.db.AutoMigrate(&model.LeaderLease{})
+	if err != nil {
+		return nil, fmt.Errorf("NewLeaderLeaseDAO: %w", err)
+	}
+
+	return &LeaderLeaseDAO{db: d.db}, nil
+}
+
+// TryAcquireLeaderLease acquire or renew the lease until now+ttl,
+// return false if the lease is held by other holder and not expired
+func (d *LeaderLeaseDAO) TryAcquireLeaderLease(name, holderID string, now time.Time, ttl time.Duration) (bool, error) {
+	expiresAt := now.Add(ttl)
+
+	// renew own lease or take over the expired one in a single statement, so that only one holder wins
+	tx := d.db.Model(&model.LeaderLease{}).
+		Where("name = ? AND (holder_id = ? OR expires_at < ?)", name, holderID, now).
+		Updates(map[string]interface{}{"holder_id": holderID, "expires_at": expiresAt, "renewed_at": now})
+	if tx.Error != nil {
+		return false, fmt.Errorf("TryAcquireLeaderLease: %w", tx.Error)
+	}
+
+	if tx.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// the lease may not exist yet
+	tx = d.db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+		Create(&model.LeaderLease{Name: name, HolderID: holderID, ExpiresAt: expiresAt, RenewedAt: now})
+	if tx.Error != nil {
+		return false, fmt.Errorf("TryAcquireLeaderLease: %w", tx.Error)
+	}
+
+	return tx.RowsAffected > 0, nil
+}
+
+// ReleaseLeaderLease expire the lease immediately if it is held by the holder
+func (d *LeaderLeaseDAO) ReleaseLeaderLease(name, holderID string) error {
+	err := d.db.Model(&model.LeaderLease{}).
+		Where("name = ? AND holder_id = ?", name, holderID).
+		Update("expires_at", time.Time{}).Error
+	if err != nil {
+		return fmt.Errorf("ReleaseLeaderLease: %w", err)
+	}
+
+	return nil
+}
+
+func (d *LeaderLeaseDAO) GetLeaderLease(name string) (*model.LeaderLease, error) {
+	var lease model.LeaderLease
+
+	err := d.db.Where("name = ?", name).First(&lease).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetLeaderLease: %w", err)
+	}
+
+	return &lease, nil
+}
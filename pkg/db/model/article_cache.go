@@ -1,18 +1,28 @@
 package model
 
+import "time"
+
 type ArticleCacheStatus string
 
 const (
 	ArticleCacheStatusDefault  ArticleCacheStatus = "default"
 	ArticleCacheStatusReserved ArticleCacheStatus = "reserved"
 	ArticleCacheStatusInBuffer ArticleCacheStatus = "in_buffer"
+	ArticleCacheStatusExpired  ArticleCacheStatus = "expired"
 )
 
-var ArticleCacheStatues = []ArticleCacheStatus{ArticleCacheStatusDefault, ArticleCacheStatusReserved, ArticleCacheStatusInBuffer}
+var ArticleCacheStatues = []ArticleCacheStatus{ArticleCacheStatusDefault, ArticleCacheStatusReserved, ArticleCacheStatusInBuffer, ArticleCacheStatusExpired}
 
 type ArticleCache struct {
 	Base
-	Title   string             `json:"title"`
+	Title   string             `json:"title" gorm:"index"`
 	Content string             `json:"content"`
 	Status  ArticleCacheStatus `json:"status" gorm:"default:defualt"`
+	// Priority higher value will be published earlier
+	Priority int `json:"priority" gorm:"default:0;index"`
+	// ExpiresAt article will not be published after this time, nil means never expire
+	ExpiresAt *time.Time `json:"expires_at" gorm:"index"`
+	// ClaimedBy the id of service instance publishing the article, it is claimed until ClaimedUntil
+	ClaimedBy    string     `json:"claimed_by"`
+	ClaimedUntil *time.Time `json:"claimed_until" gorm:"index"`
 }
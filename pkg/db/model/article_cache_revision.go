@@ -0,0 +1,26 @@
+package model
+
+import "github.com/google/uuid"
+
+type ArticleCacheRevisionAction string
+
+const (
+	// ArticleCacheRevisionActionOriginal snapshot of the article before the first change
+	ArticleCacheRevisionActionOriginal ArticleCacheRevisionAction = "original"
+	ArticleCacheRevisionActionEdit     ArticleCacheRevisionAction = "edit"
+	ArticleCacheRevisionActionStatus   ArticleCacheRevisionAction = "status"
+	ArticleCacheRevisionActionRestore  ArticleCacheRevisionAction = "restore"
+	ArticleCacheRevisionActionImport   ArticleCacheRevisionAction = "import"
+)
+
+// ArticleCacheRevision snapshot of article cache after a change
+type ArticleCacheRevision struct {
+	Base
+	ArticleCacheID uuid.UUID                  `json:"article_cache_id" gorm:"index"`
+	Action         ArticleCacheRevisionAction `json:"action"`
+	// Editor user id of who made the change, empty means changed by system
+	Editor  string             `json:"editor"`
+	Title   string             `json:"title"`
+	Content string             `json:"content"`
+	Status  ArticleCacheStatus `json:"status"`
+}
@@ -8,8 +8,8 @@ import (
 
 type Category struct {
 	Base
-	ZBlogID       uint32    `json:"z_blog_id"`
-	WordpressID   uint32    `json:"wordpress_id"`
+	// CMSCategoryID is the id of category in CMS, it is interpreted by the driver of CMS type of site
+	CMSCategoryID string    `json:"cms_category_id"`
 	Name          string    `json:"name"`
 	SiteID        uuid.UUID `json:"site_id"`
 	Site          Site      `json:"site"`
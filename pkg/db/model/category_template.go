@@ -0,0 +1,21 @@
+package model
+
+import "github.com/google/uuid"
+
+// CategoryTemplate is a named category structure shared by sites
+type CategoryTemplate struct {
+	Base
+	Name       string                 `json:"name" gorm:"unique"`
+	Categories []CategoryTemplateItem `json:"categories" gorm:"foreignKey:TemplateID"`
+}
+
+// CategoryTemplateItem is a category of template, the parent is referred by its name in the same template
+type CategoryTemplateItem struct {
+	Base
+	TemplateID  uuid.UUID `json:"template_id" gorm:"index"`
+	Name        string    `json:"name"`
+	Parent      string    `json:"parent"`
+	Description string    `json:"description"`
+	// Position keep the order of categories, parent is placed before its children
+	Position int `json:"position"`
+}
@@ -1,9 +1,41 @@
 package model
 
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
 type CommentUser struct {
 	Base
-	Name         string `json:"name" gorm:"unique"`
-	Alias        string `json:"alias"`
-	Password     string `json:"password"`
-	HashPassword string `json:"hash_password"`
+	Name  string `json:"name" gorm:"unique"`
+	Alias string `json:"alias"`
+	// Password is encrypted at rest like the one of Site
+	Password        string `json:"password"`
+	PasswordKeyID   string `json:"-"`
+	PasswordDataKey string `json:"-"`
+	HashPassword    string `json:"hash_password"`
+}
+
+func (u *CommentUser) BeforeSave(tx *gorm.DB) error {
+	return sealSecret(tx, &u.Password, &u.PasswordKeyID, &u.PasswordDataKey)
+}
+
+func (u *CommentUser) AfterSave(tx *gorm.DB) error {
+	return openSecret(tx, &u.Password, u.PasswordKeyID, u.PasswordDataKey)
+}
+
+func (u *CommentUser) AfterFind(tx *gorm.DB) error {
+	return openSecret(tx, &u.Password, u.PasswordKeyID, u.PasswordDataKey)
+}
+
+// MarshalJSON redact the password and its hash, so they are not leaked by api response
+func (u CommentUser) MarshalJSON() ([]byte, error) {
+	type commentUser CommentUser
+
+	res := commentUser(u)
+	res.Password = redact(res.Password)
+	res.HashPassword = redact(res.HashPassword)
+
+	return json.Marshal(res)
 }
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Feed struct {
+	Base
+	Name            string             `json:"name"`
+	URL             string             `json:"url" gorm:"unique"`
+	IntervalMinutes int                `json:"interval_minutes"`
+	DefaultStatus   ArticleCacheStatus `json:"default_status"`
+	IsRewrite       bool               `json:"is_rewrite"`
+	// TTLHours expire time of articles from this feed, 0 means use the default ttl
+	TTLHours      int        `json:"ttl_hours"`
+	IsActive      bool       `json:"is_active"`
+	LastFetchedAt *time.Time `json:"last_fetched_at"`
+	LastError     string     `json:"last_error"`
+	LastNewItems  int        `json:"last_new_items"`
+}
+
+// IsDue report whether the feed should be fetched at now
+func (f Feed) IsDue(now time.Time) bool {
+	if f.LastFetchedAt == nil {
+		return true
+	}
+
+	return !f.LastFetchedAt.Add(time.Duration(f.IntervalMinutes) * time.Minute).After(now)
+}
+
+type FeedItem struct {
+	Base
+	FeedID  uuid.UUID `json:"feed_id" gorm:"uniqueIndex:idx_feed_item_key"`
+	ItemKey string    `json:"item_key" gorm:"uniqueIndex:idx_feed_item_key"`
+	GUID    string    `json:"guid"`
+	Link    string    `json:"link" gorm:"index"`
+	Title   string    `json:"title"`
+}
@@ -0,0 +1,15 @@
+package model
+
+type InventoryFlowDirection string
+
+const (
+	InventoryFlowIn  InventoryFlowDirection = "in"
+	InventoryFlowOut InventoryFlowDirection = "out"
+)
+
+// InventoryFlow record the articles added to (in) or consumed from (out) the article cache
+type InventoryFlow struct {
+	Base
+	Direction InventoryFlowDirection `json:"direction" gorm:"index"`
+	Count     int                    `json:"count"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// LeaderLease is held by one service instance at a time, the holder must renew it before it expires
+type LeaderLease struct {
+	Base
+	Name      string    `json:"name" gorm:"uniqueIndex"`
+	HolderID  string    `json:"holder_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RenewedAt time.Time `json:"renewed_at"`
+}
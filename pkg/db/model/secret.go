@@ -0,0 +1,59 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/ray31245/seo_cluster/pkg/secret"
+	"gorm.io/gorm"
+)
+
+// RedactedSecret replace the secrets in json of models
+const RedactedSecret = "******"
+
+// sealSecret encrypt value in place by the keyring in context of tx.
+// The value is stored as plaintext if keyring is not configured.
+func sealSecret(tx *gorm.DB, value *string, keyID *string, dataKey *string) error {
+	keyring := secret.FromContext(tx.Statement.Context)
+	if keyring == nil || *value == "" {
+		return nil
+	}
+
+	envelope, err := keyring.Seal(*value)
+	if err != nil {
+		return fmt.Errorf("sealSecret: %w", err)
+	}
+
+	*value, *keyID, *dataKey = envelope.Ciphertext, envelope.KeyID, envelope.DataKey
+
+	return nil
+}
+
+// openSecret decrypt value in place, the value without key id is plaintext stored before encryption is enabled.
+// The key id is kept, so the secret encrypted by old key can be found by it.
+func openSecret(tx *gorm.DB, value *string, keyID string, dataKey string) error {
+	if keyID == "" || *value == "" {
+		return nil
+	}
+
+	keyring := secret.FromContext(tx.Statement.Context)
+	if keyring == nil {
+		return fmt.Errorf("openSecret: %w", secret.ErrKeyNotConfigured)
+	}
+
+	plaintext, err := keyring.Open(secret.Envelope{KeyID: keyID, DataKey: dataKey, Ciphertext: *value})
+	if err != nil {
+		return fmt.Errorf("openSecret: %w", err)
+	}
+
+	*value = plaintext
+
+	return nil
+}
+
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return RedactedSecret
+}
@@ -1,18 +1,60 @@
 package model
 
+import (
+	"encoding/json"
+
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	"gorm.io/gorm"
+)
+
 type CMSType string
 
 const (
-	CMSTypeWordPress CMSType = "wordpress"
-	CMSTypeZBlog     CMSType = "zblog"
+	CMSTypeWordPress  CMSType = "wordpress"
+	CMSTypeZBlog      CMSType = "zblog"
+	CMSTypeMetaWeblog CMSType = "metaweblog"
+	CMSTypeGhost      CMSType = "ghost"
+	CMSTypeStaticSite CMSType = "static"
 )
 
 type Site struct {
 	Base
-	URL        string `json:"url" gorm:"unique"`
-	UserName   string `json:"username"`
-	Password   string `json:"password"`
-	LackCount  int    `json:"lack_count" gorm:"default:0"`
-	Categories []Category
-	CmsType    CMSType `json:"cms_type"`
+	URL      string `json:"url" gorm:"unique"`
+	UserName string `json:"username"`
+	// Password is encrypted at rest when secret key is configured, it is decrypted after find
+	Password string `json:"password"`
+	// PasswordKeyID is the id of master key encrypting PasswordDataKey, empty means Password is plaintext
+	PasswordKeyID   string `json:"-"`
+	PasswordDataKey string `json:"-"`
+	AuthMode        string `json:"auth_mode"` // only for wordpress, empty means basic auth
+	LackCount       int    `json:"lack_count" gorm:"default:0"`
+	Categories      []Category
+	CmsType         CMSType `json:"cms_type"`
+	// Language of content published to this site, BCP 47 language tag
+	Language string `json:"language" gorm:"default:zh-Hans"`
+	// HTTPOptions override the http options of cms api for this site, static site ignore them as it send no request
+	HTTPOptions httpclient.Options `json:"http_options" gorm:"serializer:json"`
+}
+
+func (s *Site) BeforeSave(tx *gorm.DB) error {
+	return sealSecret(tx, &s.Password, &s.PasswordKeyID, &s.PasswordDataKey)
+}
+
+// AfterSave decrypt the password sealed by BeforeSave, so the caller still get plaintext
+func (s *Site) AfterSave(tx *gorm.DB) error {
+	return openSecret(tx, &s.Password, s.PasswordKeyID, s.PasswordDataKey)
+}
+
+func (s *Site) AfterFind(tx *gorm.DB) error {
+	return openSecret(tx, &s.Password, s.PasswordKeyID, s.PasswordDataKey)
+}
+
+// MarshalJSON redact the password, so it is not leaked by api response
+func (s Site) MarshalJSON() ([]byte, error) {
+	type site Site
+
+	res := site(s)
+	res.Password = redact(res.Password)
+
+	return json.Marshal(res)
 }
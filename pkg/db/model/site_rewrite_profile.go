@@ -0,0 +1,22 @@
+package model
+
+import "github.com/google/uuid"
+
+// SiteRewriteProfile rewrite prompts of a site, empty prompt falls back to the default one
+type SiteRewriteProfile struct {
+	Base
+	SiteID                    uuid.UUID `json:"site_id" gorm:"uniqueIndex"`
+	SystemPrompt              string    `json:"system_prompt"`
+	Prompt                    string    `json:"prompt"`
+	ExtendSystemPrompt        string    `json:"extend_system_prompt"`
+	ExtendPrompt              string    `json:"extend_prompt"`
+	MakeTitleSystemPrompt     string    `json:"make_title_system_prompt"`
+	MakeTitlePrompt           string    `json:"make_title_prompt"`
+	MultiSectionsSystemPrompt string    `json:"multi_sections_system_prompt"`
+	// Tone writing tone of the site, e.g. "casual" or "professional"
+	Tone string `json:"tone"`
+	// TargetLength expected length of rewritten article in characters, 0 means not limited
+	TargetLength int `json:"target_length"`
+	// IsRewriteOnPublish rewrite the article again with this profile before publishing to the site
+	IsRewriteOnPublish bool `json:"is_rewrite_on_publish"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import "github.com/google/uuid"
+
+type Webhook struct {
+	Base
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Secret   string `json:"secret"`
+	Events   string `json:"events"`
+	IsActive bool   `json:"is_active"`
+}
+
+type WebhookDelivery struct {
+	Base
+	WebhookID    uuid.UUID `json:"webhook_id" gorm:"index"`
+	Event        string    `json:"event"`
+	Payload      string    `json:"payload"`
+	Attempts     int       `json:"attempts"`
+	StatusCode   int       `json:"status_code"`
+	ResponseBody string    `json:"response_body"`
+	Error        string    `json:"error"`
+	IsSuccess    bool      `json:"is_success"`
+}
@@ -6,6 +6,8 @@ import (
 
 	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
 	"github.com/ray31245/seo_cluster/pkg/db/model"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	"github.com/ray31245/seo_cluster/pkg/secret"
 
 	"gorm.io/gorm"
 )
@@ -15,7 +17,12 @@ type SiteDAO struct {
 }
 
 func (d *DB) NewSiteDAO() (*SiteDAO, error) {
-	err := d.db.AutoMigrate(&model.Site{}, &model.Category{})
+	err := d.db.AutoMigrate(&model.Site{}, &model.Category{}, &model.SiteRewriteProfile{})
+	if err != nil {
+		return nil, fmt.Errorf("NewSiteDAO: %w", err)
+	}
+
+	err = migrateCMSCategoryID(d.db)
 	if err != nil {
 		return nil, fmt.Errorf("NewSiteDAO: %w", err)
 	}
@@ -23,6 +30,49 @@ func (d *DB) NewSiteDAO() (*SiteDAO, error) {
 	return &SiteDAO{db: d.db}, nil
 }
 
+// legacyCMSCategoryIDColumns are the columns of CMS category id per CMS type,
+// they are merged into cms_category_id
+var legacyCMSCategoryIDColumns = []struct {
+	cmsType model.CMSType
+	column  string
+	// value is the expression of cms_category_id
+	value string
+}{
+	{cmsType: model.CMSTypeZBlog, column: "z_blog_id", value: "CAST(z_blog_id AS TEXT)"},
+	{cmsType: model.CMSTypeWordPress, column: "wordpress_id", value: "CAST(wordpress_id AS TEXT)"},
+	{cmsType: model.CMSTypeMetaWeblog, column: "metaweblog_id", value: "CAST(metaweblog_id AS TEXT)"},
+	// ghost_id is the hash of tag id, the categories are mirrored again with the id of tag on next sync
+	{cmsType: model.CMSTypeGhost, column: "ghost_id", value: "CAST(ghost_id AS TEXT)"},
+	// static site refer category by name
+	{cmsType: model.CMSTypeStaticSite, column: "static_site_id", value: "name"},
+}
+
+// migrateCMSCategoryID copy the CMS category id from the legacy columns and drop them
+func migrateCMSCategoryID(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, legacy := range legacyCMSCategoryIDColumns {
+			if !tx.Migrator().HasColumn(&model.Category{}, legacy.column) {
+				continue
+			}
+
+			err := tx.Model(&model.Category{}).
+				Where("cms_category_id = '' OR cms_category_id IS NULL").
+				Where("site_id IN (?)", tx.Model(&model.Site{}).Select("id").Where("cms_type = ?", legacy.cmsType)).
+				Update("cms_category_id", gorm.Expr(legacy.value)).Error
+			if err != nil {
+				return fmt.Errorf("migrateCMSCategoryID: %w", err)
+			}
+
+			err = tx.Migrator().DropColumn(&model.Category{}, legacy.column)
+			if err != nil {
+				return fmt.Errorf("migrateCMSCategoryID: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
 func (d *SiteDAO) CreateSite(site *model.Site) (model.Site, error) {
 	err := d.db.Create(site).Error
 	if err != nil {
@@ -60,6 +110,53 @@ func (d *SiteDAO) ListSitesRandom() ([]model.Site, error) {
 	return sites, err
 }
 
+// UpdateSiteHTTPOptions replace the http options of site, zero options clear them
+func (d *SiteDAO) UpdateSiteHTTPOptions(siteID string, options httpclient.Options) error {
+	tx := d.db.Model(&model.Site{}).Where("id = ?", siteID).Select("http_options").Updates(&model.Site{HTTPOptions: options})
+	if tx.Error != nil {
+		return fmt.Errorf("UpdateSiteHTTPOptions: %w", tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return fmt.Errorf("UpdateSiteHTTPOptions: %w", dbErr.ErrNotFound)
+	}
+
+	return nil
+}
+
+// RotateSiteSecrets encrypt the passwords not encrypted by primary key of the keyring of DB again, including the plaintext ones.
+// It return the number of sites rotated.
+func (d *SiteDAO) RotateSiteSecrets() (int, error) {
+	keyring := secret.FromContext(d.db.Statement.Context)
+	if keyring == nil {
+		return 0, fmt.Errorf("RotateSiteSecrets: %w", secret.ErrKeyNotConfigured)
+	}
+
+	var sites []model.Site
+
+	err := d.db.Find(&sites).Error
+	if err != nil {
+		return 0, fmt.Errorf("RotateSiteSecrets: %w", err)
+	}
+
+	count := 0
+
+	for _, site := range sites {
+		if site.Password == "" || site.PasswordKeyID == keyring.PrimaryID() {
+			continue
+		}
+
+		err = d.db.Model(&site).Select("password", "password_key_id", "password_data_key").Updates(&site).Error
+		if err != nil {
+			return count, fmt.Errorf("RotateSiteSecrets: %w", err)
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
 func (d *SiteDAO) CreateCategory(category *model.Category) error {
 	return d.db.Create(category).Error
 }
@@ -91,7 +188,7 @@ func (d *SiteDAO) DeleteSite(siteID string) error {
 		return dbErr.ErrNotFound
 	}
 
-	return nil
+	return d.DeleteSiteRewriteProfile(siteID)
 }
 
 func (d *SiteDAO) DeleteCategory(categoryID string) error {
@@ -144,10 +241,11 @@ func (d *SiteDAO) ListPublishedCategories() ([]model.Category, error) {
 	return categories, err
 }
 
-func (d *SiteDAO) LastPublishedCategoryByCMSType(cmsType model.CMSType) (*model.Category, error) {
+// LastPublishedCategoryByCMSTypes return the latest published category among the sites of CMS types
+func (d *SiteDAO) LastPublishedCategoryByCMSTypes(cmsTypes []model.CMSType) (*model.Category, error) {
 	var category model.Category
 
-	err := d.db.Where("exists (select 1 from sites where sites.id = categories.site_id and cms_type = ?)", cmsType).Preload("Site").Order("last_published desc").First(&category).Error
+	err := d.db.Where("exists (select 1 from sites where sites.id = categories.site_id and cms_type IN ?)", cmsTypes).Preload("Site").Order("last_published desc").First(&category).Error
 	if err != nil {
 		return nil, fmt.Errorf("LastPublishedCategory: %w", err)
 	}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"fmt"
+
+	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
+	"github.com/ray31245/seo_cluster/pkg/db/model"
+)
+
+func (d *SiteDAO) GetSiteRewriteProfile(siteID string) (*model.SiteRewriteProfile, error) {
+	profile := model.SiteRewriteProfile{}
+
+	err := d.db.Where("site_id = ?", siteID).First(&profile).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetSiteRewriteProfile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+// UpsertSiteRewriteProfile create the profile of site, or replace it if exists
+func (d *SiteDAO) UpsertSiteRewriteProfile(profile *model.SiteRewriteProfile) error {
+	existing, err := d.GetSiteRewriteProfile(profile.SiteID.String())
+	if err != nil && !dbErr.IsNotfoundErr(err) {
+		return fmt.Errorf("UpsertSiteRewriteProfile: %w", err)
+	} else if err == nil {
+		profile.ID = existing.ID
+		profile.CreatedAt = existing.CreatedAt
+	}
+
+	err = d.db.Save(profile).Error
+	if err != nil {
+		return fmt.Errorf("UpsertSiteRewriteProfile: %w", err)
+	}
+
+	return nil
+}
+
+func (d *SiteDAO) DeleteSiteRewriteProfile(siteID string) error {
+	err := d.db.Where("site_id = ?", siteID).Delete(&model.SiteRewriteProfile{}).Error
+	if err != nil {
+		return fmt.Errorf("DeleteSiteRewriteProfile: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"fmt"
+
+	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
+	"github.com/ray31245/seo_cluster/pkg/db/model"
+
+	"gorm.io/gorm"
+)
+
+type WebhookDAO struct {
+	db *gorm.DB
+}
+
+func (d *DB) NewWebhookDAO() (*WebhookDAO, error) {
+	err := d.db.AutoMigrate(&model.Webhook{}, &model.WebhookDelivery{})
+	if err != nil {
+		return nil, fmt.Errorf("NewWebhookDAO: %w", err)
+	}
+
+	return &WebhookDAO{db: d.db}, nil
+}
+
+func (d *WebhookDAO) CreateWebhook(webhook *model.Webhook) (model.Webhook, error) {
+	err := d.db.Create(webhook).Error
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("CreateWebhook: %w", err)
+	}
+
+	return *webhook, nil
+}
+
+func (d *WebhookDAO) GetWebhook(id string) (*model.Webhook, error) {
+	var webhook model.Webhook
+
+	err := d.db.First(&webhook, "id = ?", id).Error
+	if err != nil {
+		return nil, fmt.Errorf("GetWebhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+func (d *WebhookDAO) ListWebhooks() ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+	err := d.db.Order("created_at").Find(&webhooks).Error
+
+	return webhooks, err
+}
+
+func (d *WebhookDAO) ListActiveWebhooks() ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+	err := d.db.Where("is_active = ?", true).Find(&webhooks).Error
+
+	return webhooks, err
+}
+
+func (d *WebhookDAO) UpdateWebhook(webhook *model.Webhook) error {
+	tx := d.db.Model(webhook).Select("name", "url", "secret", "events", "is_active").Updates(webhook)
+	if tx.Error != nil {
+		return fmt.Errorf("UpdateWebhook: %w", tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return fmt.Errorf("UpdateWebhook: %w", dbErr.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (d *WebhookDAO) DeleteWebhook(id string) error {
+	tx := d.db.Delete(&model.Webhook{}, "id = ?", id)
+	if tx.Error != nil {
+		return fmt.Errorf("DeleteWebhook: %w", tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return fmt.Errorf("DeleteWebhook: %w", dbErr.ErrNotFound)
+	}
+
+	err := d.db.Where("webhook_id = ?", id).Delete(&model.WebhookDelivery{}).Error
+	if err != nil {
+		return fmt.Errorf("DeleteWebhook: %w", err)
+	}
+
+	return nil
+}
+
+func (d *WebhookDAO) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return d.db.Create(delivery).Error
+}
+
+func (d *WebhookDAO) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return d.db.Save(delivery).Error
+}
+
+func (d *WebhookDAO) ListWebhookDeliveriesPaginator(webhookID string, page int, limit int) ([]model.WebhookDelivery, int, int64, error) {
+	q := d.db.Where("webhook_id = ?", webhookID).Order("created_at desc")
+
+	deliveries, totalPage, totalRows, err := paginator(model.WebhookDelivery{}, q, page, limit)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("ListWebhookDeliveriesPaginator: %w", err)
+	}
+
+	return deliveries, totalPage, totalRows, nil
+}
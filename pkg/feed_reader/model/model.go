@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+type Feed struct {
+	Title string
+	Link  string
+	Items []Item
+}
+
+type Item struct {
+	GUID        string
+	Link        string
+	Title       string
+	Content     string
+	PublishedAt time.Time
+}
+
+// Key is the identity of the item, GUID is preferred, fallback to link
+func (i Item) Key() string {
+	if i.GUID != "" {
+		return i.GUID
+	}
+
+	return i.Link
+}
@@ -0,0 +1,210 @@
+package feedreader
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ray31245/seo_cluster/pkg/feed_reader/model"
+)
+
+var ErrUnknownFeedFormat = errors.New("unknown feed format")
+
+type rss struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Link  string    `xml:"link"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	GUID           string `xml:"guid"`
+	Link           string `xml:"link"`
+	Title          string `xml:"title"`
+	Description    string `xml:"description"`
+	ContentEncoded string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate        string `xml:"pubDate"`
+}
+
+type atom struct {
+	Title   string      `xml:"title"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+// String return the html of text construct, xhtml content is kept as markup
+func (a atomText) String() string {
+	if a.Type == "xhtml" {
+		return a.Inner
+	}
+
+	return a.Text
+}
+
+type atomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Links     []atomLink `xml:"link"`
+	Content   atomText   `xml:"content"`
+	Summary   atomText   `xml:"summary"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+}
+
+// Parse parse RSS 2.0 or Atom 1.0 document
+func Parse(data []byte) (model.Feed, error) {
+	root, err := rootName(data)
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("Parse: %w", err)
+	}
+
+	switch root {
+	case "rss":
+		return parseRSS(data)
+	case "feed":
+		return parseAtom(data)
+	default:
+		return model.Feed{}, fmt.Errorf("Parse: %w: %s", ErrUnknownFeedFormat, root)
+	}
+}
+
+func rootName(data []byte) (string, error) {
+	decoder := newDecoder(data)
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("rootName: %w", err)
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func newDecoder(data []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	// most feeds are utf-8, pass through other declared charsets as is
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	return decoder
+}
+
+func parseRSS(data []byte) (model.Feed, error) {
+	var doc rss
+
+	err := newDecoder(data).Decode(&doc)
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("parseRSS: %w", err)
+	}
+
+	feed := model.Feed{
+		Title: strings.TrimSpace(doc.Channel.Title),
+		Link:  strings.TrimSpace(doc.Channel.Link),
+	}
+
+	for _, item := range doc.Channel.Items {
+		content := item.ContentEncoded
+		if strings.TrimSpace(content) == "" {
+			content = item.Description
+		}
+
+		feed.Items = append(feed.Items, model.Item{
+			GUID:        strings.TrimSpace(item.GUID),
+			Link:        strings.TrimSpace(item.Link),
+			Title:       strings.TrimSpace(item.Title),
+			Content:     strings.TrimSpace(content),
+			PublishedAt: parseTime(item.PubDate),
+		})
+	}
+
+	return feed, nil
+}
+
+func parseAtom(data []byte) (model.Feed, error) {
+	var doc atom
+
+	err := newDecoder(data).Decode(&doc)
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("parseAtom: %w", err)
+	}
+
+	feed := model.Feed{
+		Title: strings.TrimSpace(doc.Title),
+		Link:  atomAlternateLink(doc.Links),
+	}
+
+	for _, entry := range doc.Entries {
+		content := entry.Content.String()
+		if strings.TrimSpace(content) == "" {
+			content = entry.Summary.String()
+		}
+
+		published := entry.Published
+		if published == "" {
+			published = entry.Updated
+		}
+
+		feed.Items = append(feed.Items, model.Item{
+			GUID:        strings.TrimSpace(entry.ID),
+			Link:        atomAlternateLink(entry.Links),
+			Title:       strings.TrimSpace(entry.Title),
+			Content:     strings.TrimSpace(content),
+			PublishedAt: parseTime(published),
+		})
+	}
+
+	return feed, nil
+}
+
+func atomAlternateLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+
+	return ""
+}
+
+var timeLayouts = []string{ //nolint:gochecknoglobals // layouts of feed time
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	time.RFC822Z,
+	time.RFC822,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+}
+
+func parseTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
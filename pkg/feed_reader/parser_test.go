@@ -0,0 +1,139 @@
+package feedreader_test
+
+import (
+	"testing"
+	"time"
+
+	feedreader "github.com/ray31245/seo_cluster/pkg/feed_reader"
+	"github.com/ray31245/seo_cluster/pkg/feed_reader/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rssDoc = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel>
+	<title>RSS Feed</title>
+	<link>https://example.com</link>
+	<item>
+		<guid>rss-1</guid>
+		<link>https://example.com/1</link>
+		<title>first</title>
+		<description>short</description>
+		<content:encoded><![CDATA[<p>full content</p>]]></content:encoded>
+		<pubDate>Mon, 02 Jan 2006 15:04:05 +0000</pubDate>
+	</item>
+	<item>
+		<link>https://example.com/2</link>
+		<title>second</title>
+		<description>&lt;p&gt;description only&lt;/p&gt;</description>
+	</item>
+</channel>
+</rss>`
+
+const atomDoc = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Atom Feed</title>
+	<link href="https://example.com/atom" rel="self"/>
+	<link href="https://example.com"/>
+	<entry>
+		<id>atom-1</id>
+		<title>first</title>
+		<link href="https://example.com/a1" rel="alternate"/>
+		<content type="html">&lt;p&gt;html content&lt;/p&gt;</content>
+		<updated>2006-01-02T15:04:05Z</updated>
+	</entry>
+	<entry>
+		<id>atom-2</id>
+		<title>second</title>
+		<summary>summary only</summary>
+	</entry>
+</feed>`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		want    model.Feed
+		wantErr bool
+	}{
+		{
+			name: "rss",
+			data: rssDoc,
+			want: model.Feed{
+				Title: "RSS Feed",
+				Link:  "https://example.com",
+				Items: []model.Item{
+					{
+						GUID:        "rss-1",
+						Link:        "https://example.com/1",
+						Title:       "first",
+						Content:     "<p>full content</p>",
+						PublishedAt: time.Date(2006, 1, 2, 15, 4, 5, 0, time.FixedZone("", 0)),
+					},
+					{
+						Link:    "https://example.com/2",
+						Title:   "second",
+						Content: "<p>description only</p>",
+					},
+				},
+			},
+		},
+		{
+			name: "atom",
+			data: atomDoc,
+			want: model.Feed{
+				Title: "Atom Feed",
+				Link:  "https://example.com",
+				Items: []model.Item{
+					{
+						GUID:        "atom-1",
+						Link:        "https://example.com/a1",
+						Title:       "first",
+						Content:     "<p>html content</p>",
+						PublishedAt: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+					},
+					{
+						GUID:    "atom-2",
+						Title:   "second",
+						Content: "summary only",
+					},
+				},
+			},
+		},
+		{
+			name:    "unknown format",
+			data:    `<html><body></body></html>`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+			require := require.New(t)
+
+			got, err := feedreader.Parse([]byte(tt.data))
+			if tt.wantErr {
+				require.Error(err)
+
+				return
+			}
+
+			require.NoError(err)
+			assert.Equal(tt.want.Title, got.Title)
+			assert.Equal(tt.want.Link, got.Link)
+			require.Len(got.Items, len(tt.want.Items))
+
+			for i, item := range tt.want.Items {
+				assert.Equal(item.GUID, got.Items[i].GUID)
+				assert.Equal(item.Link, got.Items[i].Link)
+				assert.Equal(item.Title, got.Items[i].Title)
+				assert.Equal(item.Content, got.Items[i].Content)
+				assert.True(item.PublishedAt.Equal(got.Items[i].PublishedAt))
+			}
+		})
+	}
+}
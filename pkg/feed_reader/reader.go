@@ -0,0 +1,58 @@
+package feedreader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ray31245/seo_cluster/pkg/feed_reader/model"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+	maxFeedSize    = 10 << 20
+)
+
+type FeedReader struct {
+	httpClient *http.Client
+}
+
+func NewFeedReader() *FeedReader {
+	return &FeedReader{
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// Fetch download and parse the feed of url
+func (f *FeedReader) Fetch(ctx context.Context, url string) (model.Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("Fetch: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml, text/xml")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("Fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return model.Feed{}, fmt.Errorf("Fetch: unexpected status code %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("Fetch: %w", err)
+	}
+
+	feed, err := Parse(data)
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("Fetch: %w", err)
+	}
+
+	return feed, nil
+}
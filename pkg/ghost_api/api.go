@@ -0,0 +1,79 @@
+package ghostapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	clientpool "github.com/ray31245/seo_cluster/pkg/client_pool"
+	ghostInterface "github.com/ray31245/seo_cluster/pkg/ghost_api/ghost_interface"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+)
+
+// GhostAPI is a struct to implement GhostAPI interface
+var _ ghostInterface.GhostAPI = &GhostAPI{}
+
+// GhostAPI keep the clients of ghost sites,
+// the admin api key of site is used as password and user name is not used
+type GhostAPI struct {
+	// key is site id or user id
+	clientPool  *clientpool.Pool[*Client]
+	httpClients *httpclient.Factory
+}
+
+// NewGhostAPI create the api, the http clients of sites are built by httpClients with the options of site
+func NewGhostAPI(poolOptions clientpool.Options, httpClients *httpclient.Factory) *GhostAPI {
+	return &GhostAPI{
+		clientPool:  clientpool.NewPool[*Client](poolOptions),
+		httpClients: httpClients,
+	}
+}
+
+func (t *GhostAPI) GetClient(ctx context.Context, ID uuid.UUID, urlStr string, _ string, password string, httpOptions httpclient.Options) (ghostInterface.GhostClient, error) {
+	client, err := t.clientPool.Get(ctx, ID, func(ctx context.Context) (*Client, error) {
+		return t.newClient(ctx, urlStr, password, httpOptions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (t *GhostAPI) UpdateClient(ctx context.Context, ID uuid.UUID, urlStr string, _ string, password string, httpOptions httpclient.Options) (ghostInterface.GhostClient, error) {
+	client, err := t.newClient(ctx, urlStr, password, httpOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	t.clientPool.Put(ID, client)
+
+	return client, nil
+}
+
+func (t *GhostAPI) DeleteClient(ID uuid.UUID) {
+	t.clientPool.Delete(ID)
+}
+
+// PoolStats return the stats of client pool
+func (t *GhostAPI) PoolStats() clientpool.Stats {
+	return t.clientPool.Stats()
+}
+
+func (t *GhostAPI) NewClient(ctx context.Context, urlStr string, _ string, password string, httpOptions httpclient.Options) (ghostInterface.GhostClient, error) {
+	return t.newClient(ctx, urlStr, password, httpOptions)
+}
+
+func (t *GhostAPI) NewAnonymousClient(ctx context.Context, urlStr string) ghostInterface.GhostClient {
+	return NewAnonymousClientWithHTTPClient(ctx, t.httpClients.DefaultClient(), urlStr)
+}
+
+func (t *GhostAPI) newClient(ctx context.Context, urlStr string, adminKey string, httpOptions httpclient.Options) (*Client, error) {
+	httpClient, err := t.httpClients.Client(httpOptions)
+	if err != nil {
+		return nil, fmt.Errorf("newClient: %w", err)
+	}
+
+	return NewClientWithHTTPClient(ctx, httpClient, urlStr, adminKey)
+}
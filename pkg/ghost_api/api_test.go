@@ -0,0 +1,59 @@
+package ghostapi_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	clientpool "github.com/ray31245/seo_cluster/pkg/client_pool"
+	ghostApi "github.com/ray31245/seo_cluster/pkg/ghost_api"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGhostAPI_ClientPoolConcurrent should be run with -race
+func TestGhostAPI_ClientPoolConcurrent(t *testing.T) {
+	t.Parallel()
+
+	srv := newFakeAdminAPI()
+	t.Cleanup(srv.Close)
+
+	api := ghostApi.NewGhostAPI(clientpool.Options{IdleTTL: time.Millisecond, MaxSize: 2}, httpclient.NewDefaultFactory())
+	IDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+
+	for i := range 30 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ID := IDs[i%len(IDs)]
+
+			for j := range 10 {
+				switch (i + j) % 3 {
+				case 0:
+					client, err := api.GetClient(ctx, ID, srv.URL, "", TestAdminKey, httpclient.Options{})
+					if assert.NoError(t, err) {
+						assert.NotNil(t, client)
+					}
+				case 1:
+					_, err := api.UpdateClient(ctx, ID, srv.URL, "", TestAdminKey, httpclient.Options{})
+					assert.NoError(t, err)
+				default:
+					api.DeleteClient(ID)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	stats := api.PoolStats()
+	assert.LessOrEqual(t, stats.Size, 2)
+	assert.Positive(t, stats.Hits+stats.Misses)
+}
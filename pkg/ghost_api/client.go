@@ -0,0 +1,150 @@
+package ghostapi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/ray31245/seo_cluster/pkg/ghost_api/model"
+	"github.com/ray31245/seo_cluster/pkg/ghost_api/origin"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+)
+
+type Client struct {
+	// baseURL is the url of ghost site, e.g. https://example.com
+	baseURL    string
+	httpClient *http.Client
+	adminKey   model.AdminKey
+}
+
+// NewClient check the admin api key by listing one tag
+func NewClient(ctx context.Context, urlStr string, adminKey string) (*Client, error) {
+	return NewClientWithHTTPClient(ctx, httpclient.NewDefaultClient(), urlStr, adminKey)
+}
+
+// NewClientWithHTTPClient is NewClient sending the requests by httpClient
+func NewClientWithHTTPClient(ctx context.Context, httpClient *http.Client, urlStr string, adminKey string) (*Client, error) {
+	slog.InfoContext(ctx, "login ghost", slog.String("url", urlStr))
+
+	key, err := origin.ParseAdminKey(adminKey)
+	if err != nil {
+		return nil, fmt.Errorf("NewClient: %w", err)
+	}
+
+	res := &Client{
+		baseURL:    urlStr,
+		httpClient: httpClient,
+		adminKey:   key,
+	}
+
+	_, err = res.ListTag(ctx, model.ListTagArgs{Limit: "1"})
+	if err != nil {
+		return nil, fmt.Errorf("NewClient: %w", err)
+	}
+
+	return res, nil
+}
+
+// NewAnonymousClient return a client without admin api key,
+// every admin api require the key and will return error
+func NewAnonymousClient(ctx context.Context, urlStr string) *Client {
+	return NewAnonymousClientWithHTTPClient(ctx, httpclient.NewDefaultClient(), urlStr)
+}
+
+// NewAnonymousClientWithHTTPClient is NewAnonymousClient sending the requests by httpClient
+func NewAnonymousClientWithHTTPClient(_ context.Context, httpClient *http.Client, urlStr string) *Client {
+	return &Client{
+		baseURL:    urlStr,
+		httpClient: httpClient,
+		adminKey: model.AdminKey{
+			IsAnonymous: true,
+		},
+	}
+}
+
+func (c *Client) ListTag(ctx context.Context, args model.ListTagArgs) (model.ListTagResponse, error) {
+	res, err := origin.ListTag(ctx, c.httpClient, c.baseURL, c.adminKey, args)
+	if err != nil {
+		return model.ListTagResponse{}, fmt.Errorf("list tag error: %w", err)
+	}
+
+	return res, nil
+}
+
+// ListTagAll list all public tags with count of posts
+func (c *Client) ListTagAll(ctx context.Context) ([]model.Tag, error) {
+	res, err := origin.ListTag(ctx, c.httpClient, c.baseURL, c.adminKey, model.ListTagArgs{
+		Limit:   model.LimitAll,
+		Filter:  fmt.Sprintf("visibility:%s", model.VisibilityPublic),
+		Include: "count.posts",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list tag all error: %w", err)
+	}
+
+	return res.Tags, nil
+}
+
+func (c *Client) CreateTag(ctx context.Context, args model.CreateTagArgs) (model.CreateTagResponse, error) {
+	res, err := origin.CreateTag(ctx, c.httpClient, c.baseURL, c.adminKey, args)
+	if err != nil {
+		return model.CreateTagResponse{}, fmt.Errorf("create tag error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) ListPost(ctx context.Context, args model.ListPostArgs) (model.ListPostResponse, error) {
+	res, err := origin.ListPost(ctx, c.httpClient, c.baseURL, c.adminKey, args)
+	if err != nil {
+		return model.ListPostResponse{}, fmt.Errorf("list post error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) CreatePost(ctx context.Context, args model.CreatePostArgs) (model.CreatePostResponse, error) {
+	res, err := origin.CreatePost(ctx, c.httpClient, c.baseURL, c.adminKey, args)
+	if err != nil {
+		return model.CreatePostResponse{}, fmt.Errorf("create post error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) RetrievePost(ctx context.Context, args model.RetrievePostArgs) (model.RetrievePostResponse, error) {
+	res, err := origin.RetrievePost(ctx, c.httpClient, c.baseURL, c.adminKey, args)
+	if err != nil {
+		return model.RetrievePostResponse{}, fmt.Errorf("retrieve post error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) UpdatePost(ctx context.Context, args model.UpdatePostArgs) (model.UpdatePostResponse, error) {
+	res, err := origin.UpdatePost(ctx, c.httpClient, c.baseURL, c.adminKey, args)
+	if err != nil {
+		return model.UpdatePostResponse{}, fmt.Errorf("update post error: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) DeletePost(ctx context.Context, ID string) error {
+	err := origin.DeletePost(ctx, c.httpClient, c.baseURL, c.adminKey, ID)
+	if err != nil {
+		return fmt.Errorf("delete post error: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) UploadImage(ctx context.Context, args model.UploadImageArgs) (model.UploadImageResponse, error) {
+	res, err := origin.UploadImage(ctx, c.httpClient, c.baseURL, c.adminKey, args)
+	if err != nil {
+		return model.UploadImageResponse{}, fmt.Errorf("upload image error: %w", err)
+	}
+
+	return res, nil
+}
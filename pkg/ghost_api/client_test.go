@@ -0,0 +1,140 @@
+package ghostapi_test
+
+import (
+	"context"
+	"testing"
+
+	ghostApi "github.com/ray31245/seo_cluster/pkg/ghost_api"
+	ghostError "github.com/ray31245/seo_cluster/pkg/ghost_api/error"
+	"github.com/ray31245/seo_cluster/pkg/ghost_api/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	server := newFakeAdminAPI()
+	defer server.Close()
+
+	t.Run("valid admin key", func(t *testing.T) {
+		_, err := ghostApi.NewClient(context.Background(), server.URL, TestAdminKey)
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid format of admin key", func(t *testing.T) {
+		_, err := ghostApi.NewClient(context.Background(), server.URL, "no-secret")
+		require.ErrorIs(t, err, ghostError.ErrInvalidAdminKey)
+
+		_, err = ghostApi.NewClient(context.Background(), server.URL, TestKeyID+":not-hex")
+		require.ErrorIs(t, err, ghostError.ErrInvalidAdminKey)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		_, err := ghostApi.NewClient(context.Background(), server.URL, TestKeyID+":00ff")
+		require.ErrorIs(t, err, ghostError.ErrHTTPUnauthorized)
+	})
+
+	t.Run("anonymous client", func(t *testing.T) {
+		client := ghostApi.NewAnonymousClient(context.Background(), server.URL)
+
+		_, err := client.ListTagAll(context.Background())
+		require.ErrorIs(t, err, ghostError.ErrAnonymous)
+	})
+}
+
+func TestClient_Tag(t *testing.T) {
+	server := newFakeAdminAPI()
+	defer server.Close()
+
+	ctx := context.Background()
+
+	client, err := ghostApi.NewClient(ctx, server.URL, TestAdminKey)
+	require.NoError(t, err)
+
+	tags, err := client.ListTagAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	assert.Equal(t, "News", tags[0].Name)
+	assert.Equal(t, "Tech", tags[1].Name)
+	require.NotNil(t, tags[0].Count)
+
+	tag, err := client.CreateTag(ctx, model.CreateTagArgs{Name: "Life"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, tag.ID)
+	assert.Equal(t, "Life", tag.Name)
+
+	tags, err = client.ListTagAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tags, 3)
+}
+
+func TestClient_Post(t *testing.T) {
+	server := newFakeAdminAPI()
+	defer server.Close()
+
+	ctx := context.Background()
+
+	client, err := ghostApi.NewClient(ctx, server.URL, TestAdminKey)
+	require.NoError(t, err)
+
+	content := `<p>Tom & Jerry <a href="https://example.com">link</a></p>`
+
+	post, err := client.CreatePost(ctx, model.CreatePostArgs{
+		Title:  "title",
+		HTML:   content,
+		Status: model.StatusPublished,
+		Tags:   []model.PostTag{{ID: server.tags[1].ID}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, content, post.HTML)
+	require.NotNil(t, post.PrimaryTag)
+	assert.Equal(t, "Tech", post.PrimaryTag.Name)
+
+	retrieved, err := client.RetrievePost(ctx, model.RetrievePostArgs{ID: post.ID, Include: "tags"})
+	require.NoError(t, err)
+	assert.Equal(t, post.ID, retrieved.ID)
+
+	updated, err := client.UpdatePost(ctx, model.UpdatePostArgs{
+		ID:        post.ID,
+		Tags:      []model.PostTag{{ID: server.tags[1].ID}, {Name: "golang"}},
+		UpdatedAt: retrieved.UpdatedAt,
+	})
+	require.NoError(t, err)
+	require.Len(t, updated.Tags, 2)
+	assert.Equal(t, "golang", updated.Tags[1].Name)
+
+	// stale updated_at is rejected
+	_, err = client.UpdatePost(ctx, model.UpdatePostArgs{
+		ID:        post.ID,
+		Title:     "new title",
+		UpdatedAt: retrieved.UpdatedAt,
+	})
+	require.ErrorIs(t, err, ghostError.ErrHTTPStatusCodeError)
+
+	posts, err := client.ListPost(ctx, model.ListPostArgs{Limit: "20"})
+	require.NoError(t, err)
+	assert.Len(t, posts.Posts, 1)
+
+	err = client.DeletePost(ctx, post.ID)
+	require.NoError(t, err)
+
+	_, err = client.RetrievePost(ctx, model.RetrievePostArgs{ID: post.ID})
+	require.ErrorIs(t, err, ghostError.ErrHTTPNotFound)
+}
+
+func TestClient_UploadImage(t *testing.T) {
+	server := newFakeAdminAPI()
+	defer server.Close()
+
+	ctx := context.Background()
+
+	client, err := ghostApi.NewClient(ctx, server.URL, TestAdminKey)
+	require.NoError(t, err)
+
+	data := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+
+	image, err := client.UploadImage(ctx, model.UploadImageArgs{FileName: "cover.png", Data: data, Ref: "cover"})
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/content/images/cover.png", image.URL)
+	assert.Equal(t, "cover", image.Ref)
+	assert.Equal(t, data, server.images["cover.png"])
+}
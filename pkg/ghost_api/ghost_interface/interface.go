@@ -0,0 +1,29 @@
+package ghostinterface
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ray31245/seo_cluster/pkg/ghost_api/model"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+)
+
+type GhostAPI interface {
+	GetClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string, httpOptions httpclient.Options) (GhostClient, error)
+	UpdateClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string, httpOptions httpclient.Options) (GhostClient, error)
+	DeleteClient(ID uuid.UUID)
+	NewClient(ctx context.Context, urlStr string, userName string, password string, httpOptions httpclient.Options) (GhostClient, error)
+	NewAnonymousClient(ctx context.Context, urlStr string) GhostClient
+}
+
+type GhostClient interface {
+	ListTag(ctx context.Context, args model.ListTagArgs) (model.ListTagResponse, error)
+	ListTagAll(ctx context.Context) ([]model.Tag, error)
+	CreateTag(ctx context.Context, args model.CreateTagArgs) (model.CreateTagResponse, error)
+	ListPost(ctx context.Context, args model.ListPostArgs) (model.ListPostResponse, error)
+	CreatePost(ctx context.Context, args model.CreatePostArgs) (model.CreatePostResponse, error)
+	RetrievePost(ctx context.Context, args model.RetrievePostArgs) (model.RetrievePostResponse, error)
+	UpdatePost(ctx context.Context, args model.UpdatePostArgs) (model.UpdatePostResponse, error)
+	DeletePost(ctx context.Context, ID string) error
+	UploadImage(ctx context.Context, args model.UploadImageArgs) (model.UploadImageResponse, error)
+}
@@ -0,0 +1,358 @@
+package ghostapi_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/ray31245/seo_cluster/pkg/ghost_api/model"
+	"github.com/ray31245/seo_cluster/pkg/ghost_api/origin"
+)
+
+const (
+	// TestKeyID is the id of test admin api key
+	TestKeyID = "6489a2d5c0f1b2001c3e4a5b"
+	// TestKeySecret is the hex secret of test admin api key
+	TestKeySecret = "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+	// TestAdminKey is the test admin api key
+	TestAdminKey = TestKeyID + ":" + TestKeySecret
+
+	apiPrefix = "/" + origin.APIPath + "/"
+)
+
+// fakeAdminAPI is a fake ghost admin api which keep tags and posts in memory
+type fakeAdminAPI struct {
+	*httptest.Server
+
+	lock   sync.Mutex
+	nextID int
+	tags   []model.Tag
+	posts  map[string]model.Post
+	images map[string][]byte
+}
+
+func newFakeAdminAPI() *fakeAdminAPI {
+	f := &fakeAdminAPI{
+		tags: []model.Tag{
+			{ID: "64f0000000000000000000a1", Name: "News", Slug: "news", Visibility: model.VisibilityPublic},
+			{ID: "64f0000000000000000000a2", Name: "Tech", Slug: "tech", Visibility: model.VisibilityPublic},
+			{ID: "64f0000000000000000000a3", Name: "#hidden", Slug: "hash-hidden", Visibility: model.VisibilityInternal},
+		},
+		posts:  map[string]model.Post{},
+		images: map[string][]byte{},
+	}
+
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+
+	return f
+}
+
+func (f *fakeAdminAPI) newID() string {
+	f.nextID++
+
+	return fmt.Sprintf("65%022x", f.nextID)
+}
+
+func (f *fakeAdminAPI) handle(w http.ResponseWriter, r *http.Request) {
+	if err := checkToken(r); err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
+
+		return
+	}
+
+	if r.Header.Get("Accept-Version") == "" {
+		writeError(w, http.StatusBadRequest, "missing Accept-Version")
+
+		return
+	}
+
+	route, ok := strings.CutPrefix(r.URL.Path, apiPrefix)
+	if !ok || !strings.HasSuffix(route, "/") {
+		writeError(w, http.StatusNotFound, "resource not found")
+
+		return
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	segments := strings.Split(strings.TrimSuffix(route, "/"), "/")
+
+	switch {
+	case segments[0] == "tags" && len(segments) == 1:
+		f.handleTags(w, r)
+	case segments[0] == "posts" && len(segments) == 1:
+		f.handlePosts(w, r)
+	case segments[0] == "posts" && len(segments) == 2: //nolint:mnd
+		f.handlePost(w, r, segments[1])
+	case route == "images/upload/" && r.Method == http.MethodPost:
+		f.handleUploadImage(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "resource not found")
+	}
+}
+
+func (f *fakeAdminAPI) handleTags(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		tags := []model.Tag{}
+
+		for _, tag := range f.tags {
+			if r.URL.Query().Get("filter") == "visibility:public" && tag.Visibility != model.VisibilityPublic {
+				continue
+			}
+
+			if r.URL.Query().Get("include") == "count.posts" {
+				tag.Count = &struct {
+					Posts int `json:"posts"`
+				}{Posts: f.countPosts(tag.ID)}
+			}
+
+			tags = append(tags, tag)
+		}
+
+		if r.URL.Query().Get("limit") == "1" && len(tags) > 1 {
+			tags = tags[:1]
+		}
+
+		writeJSON(w, http.StatusOK, model.ListTagResponse{Tags: tags})
+	case http.MethodPost:
+		req := struct {
+			Tags []model.CreateTagArgs `json:"tags"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Tags) == 0 {
+			writeError(w, http.StatusBadRequest, "invalid body")
+
+			return
+		}
+
+		tag := f.createTag(req.Tags[0].Name)
+		writeJSON(w, http.StatusCreated, model.ListTagResponse{Tags: []model.Tag{tag}})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (f *fakeAdminAPI) handlePosts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		posts := []model.Post{}
+		for _, post := range f.posts {
+			posts = append(posts, post)
+		}
+
+		writeJSON(w, http.StatusOK, model.ListPostResponse{Posts: posts})
+	case http.MethodPost:
+		if r.URL.Query().Get("source") != "html" {
+			writeError(w, http.StatusUnprocessableEntity, "html is ignored without source=html")
+
+			return
+		}
+
+		req := struct {
+			Posts []model.CreatePostArgs `json:"posts"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Posts) == 0 {
+			writeError(w, http.StatusBadRequest, "invalid body")
+
+			return
+		}
+
+		args := req.Posts[0]
+		now := time.Now().UTC().Truncate(time.Second)
+		post := model.Post{
+			ID:          f.newID(),
+			Title:       args.Title,
+			HTML:        args.HTML,
+			Status:      args.Status,
+			Featured:    args.Featured,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			PublishedAt: &now,
+		}
+
+		post.Tags = f.resolveTags(args.Tags)
+		if len(post.Tags) > 0 {
+			post.PrimaryTag = &post.Tags[0]
+		}
+
+		f.posts[post.ID] = post
+		writeJSON(w, http.StatusCreated, model.ListPostResponse{Posts: []model.Post{post}})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (f *fakeAdminAPI) handlePost(w http.ResponseWriter, r *http.Request, ID string) {
+	post, ok := f.posts[ID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Post not found.")
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, model.ListPostResponse{Posts: []model.Post{post}})
+	case http.MethodPut:
+		req := struct {
+			Posts []model.UpdatePostArgs `json:"posts"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Posts) == 0 {
+			writeError(w, http.StatusBadRequest, "invalid body")
+
+			return
+		}
+
+		args := req.Posts[0]
+		if !args.UpdatedAt.Equal(post.UpdatedAt) {
+			writeError(w, http.StatusConflict, "Saving failed! Someone else is editing this post.")
+
+			return
+		}
+
+		if args.Title != "" {
+			post.Title = args.Title
+		}
+
+		if args.Tags != nil {
+			post.Tags = f.resolveTags(args.Tags)
+			post.PrimaryTag = nil
+
+			if len(post.Tags) > 0 {
+				post.PrimaryTag = &post.Tags[0]
+			}
+		}
+
+		post.UpdatedAt = post.UpdatedAt.Add(time.Second)
+		f.posts[ID] = post
+		writeJSON(w, http.StatusOK, model.ListPostResponse{Posts: []model.Post{post}})
+	case http.MethodDelete:
+		delete(f.posts, ID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (f *fakeAdminAPI) handleUploadImage(w http.ResponseWriter, r *http.Request) {
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	f.images[header.Filename] = data
+
+	writeJSON(w, http.StatusCreated, map[string][]model.Image{
+		"images": {{URL: f.URL + "/content/images/" + header.Filename, Ref: r.FormValue("ref")}},
+	})
+}
+
+// resolveTags find tags by id or name, and create the tags of name not exist
+func (f *fakeAdminAPI) resolveTags(postTags []model.PostTag) []model.Tag {
+	res := []model.Tag{}
+
+	for _, postTag := range postTags {
+		found := false
+
+		for _, tag := range f.tags {
+			if (postTag.ID != "" && tag.ID == postTag.ID) || (postTag.ID == "" && tag.Name == postTag.Name) {
+				res = append(res, tag)
+				found = true
+
+				break
+			}
+		}
+
+		if !found && postTag.ID == "" {
+			res = append(res, f.createTag(postTag.Name))
+		}
+	}
+
+	return res
+}
+
+func (f *fakeAdminAPI) createTag(name string) model.Tag {
+	tag := model.Tag{ID: f.newID(), Name: name, Slug: strings.ToLower(name), Visibility: model.VisibilityPublic}
+	f.tags = append(f.tags, tag)
+
+	return tag
+}
+
+func (f *fakeAdminAPI) countPosts(tagID string) int {
+	count := 0
+
+	for _, post := range f.posts {
+		for _, tag := range post.Tags {
+			if tag.ID == tagID {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+// checkToken verify the admin api token like ghost does
+func checkToken(r *http.Request) error {
+	tokenStr, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Ghost ")
+	if !ok {
+		return fmt.Errorf("authorization scheme is not Ghost")
+	}
+
+	claims := jwt.RegisteredClaims{}
+
+	token, err := jwt.ParseWithClaims(tokenStr, &claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+
+		if token.Header["kid"] != TestKeyID {
+			return nil, fmt.Errorf("unknown kid %v", token.Header["kid"])
+		}
+
+		return hex.DecodeString(TestKeySecret)
+	})
+	if err != nil || !token.Valid {
+		return fmt.Errorf("invalid token: %w", err)
+	}
+
+	if !claims.VerifyAudience(origin.TokenAudience, true) {
+		return fmt.Errorf("invalid audience %v", claims.Audience)
+	}
+
+	if claims.ExpiresAt.Sub(claims.IssuedAt.Time) > 5*time.Minute {
+		return fmt.Errorf("token expire too late")
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"errors": []map[string]string{{"message": message, "type": http.StatusText(statusCode)}},
+	})
+}
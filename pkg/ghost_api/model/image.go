@@ -0,0 +1,16 @@
+package model
+
+type UploadImageArgs struct {
+	// FileName with extension, e.g. cover.png
+	FileName string
+	Data     []byte
+	// Ref is returned as it is, used to identify the image
+	Ref string
+}
+
+type Image struct {
+	URL string `json:"url"`
+	Ref string `json:"ref"`
+}
+
+type UploadImageResponse Image
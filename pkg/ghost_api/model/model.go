@@ -0,0 +1,45 @@
+package model
+
+const (
+	// status of post
+	StatusPublished PostStatus = "published"
+	StatusDraft     PostStatus = "draft"
+
+	// visibility of tag, internal tags are named with prefix #
+	VisibilityPublic   TagVisibility = "public"
+	VisibilityInternal TagVisibility = "internal"
+
+	// LimitAll list all resources in one page
+	LimitAll = "all"
+)
+
+type (
+	PostStatus    string
+	TagVisibility string
+)
+
+// AdminKey is the admin api key of custom integration, in format of {id}:{secret}
+type AdminKey struct {
+	ID string
+	// Secret is hex encoded
+	Secret      string
+	IsAnonymous bool
+}
+
+type ErrorResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+		Context string `json:"context"`
+		Type    string `json:"type"`
+	} `json:"errors"`
+}
+
+type Meta struct {
+	Pagination struct {
+		Page  int         `json:"page"`
+		Limit interface{} `json:"limit"`
+		Pages int         `json:"pages"`
+		Total int         `json:"total"`
+		Next  *int        `json:"next"`
+	} `json:"pagination"`
+}
@@ -0,0 +1,74 @@
+package model
+
+import "time"
+
+type Post struct {
+	ID           string     `json:"id"`
+	UUID         string     `json:"uuid"`
+	Title        string     `json:"title"`
+	Slug         string     `json:"slug"`
+	HTML         string     `json:"html"`
+	Status       PostStatus `json:"status"`
+	Featured     bool       `json:"featured"`
+	FeatureImage string     `json:"feature_image"`
+	Tags         []Tag      `json:"tags"`
+	PrimaryTag   *Tag       `json:"primary_tag"`
+	URL          string     `json:"url"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	PublishedAt  *time.Time `json:"published_at"`
+}
+
+// PostTag refer tag by id, or by name which is created if not exists
+type PostTag struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type ListPostArgs struct {
+	Limit  string `json:"limit,omitempty"`
+	Page   int    `json:"page,omitempty"`
+	Filter string `json:"filter,omitempty"`
+	// Order e.g. "published_at desc"
+	Order   string `json:"order,omitempty"`
+	Include string `json:"include,omitempty"`
+	Formats string `json:"formats,omitempty"`
+}
+
+type ListPostResponse struct {
+	Posts []Post `json:"posts"`
+	Meta  Meta   `json:"meta"`
+}
+
+type CreatePostArgs struct {
+	Title        string     `json:"title"`
+	HTML         string     `json:"html,omitempty"`
+	Status       PostStatus `json:"status,omitempty"`
+	Featured     bool       `json:"featured,omitempty"`
+	FeatureImage string     `json:"feature_image,omitempty"`
+	// the first tag is the primary tag
+	Tags        []PostTag  `json:"tags,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+type CreatePostResponse Post
+
+type RetrievePostArgs struct {
+	ID      string `json:"-"`
+	Include string `json:"include,omitempty"`
+	Formats string `json:"formats,omitempty"`
+}
+
+type RetrievePostResponse Post
+
+// UpdatePostArgs require UpdatedAt of the latest post, ghost reject the update of stale post
+type UpdatePostArgs struct {
+	ID        string     `json:"-"`
+	Title     string     `json:"title,omitempty"`
+	HTML      string     `json:"html,omitempty"`
+	Status    PostStatus `json:"status,omitempty"`
+	Tags      []PostTag  `json:"tags,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type UpdatePostResponse Post
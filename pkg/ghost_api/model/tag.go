@@ -0,0 +1,35 @@
+package model
+
+type Tag struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Slug        string        `json:"slug"`
+	Description string        `json:"description"`
+	Visibility  TagVisibility `json:"visibility"`
+	URL         string        `json:"url"`
+	Count       *struct {
+		Posts int `json:"posts"`
+	} `json:"count,omitempty"`
+}
+
+type ListTagArgs struct {
+	// Limit is number or LimitAll
+	Limit   string `json:"limit,omitempty"`
+	Page    int    `json:"page,omitempty"`
+	Filter  string `json:"filter,omitempty"`
+	Include string `json:"include,omitempty"`
+}
+
+type ListTagResponse struct {
+	Tags []Tag `json:"tags"`
+	Meta Meta  `json:"meta"`
+}
+
+type CreateTagArgs struct {
+	Name string `json:"name"`
+	// Slug is generated from name by ghost if it is empty
+	Slug        string `json:"slug,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type CreateTagResponse Tag
@@ -0,0 +1,101 @@
+package origin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	ghostError "github.com/ray31245/seo_cluster/pkg/ghost_api/error"
+	"github.com/ray31245/seo_cluster/pkg/ghost_api/model"
+)
+
+const (
+	APIPath = "ghost/api/admin"
+	// AcceptVersion is the version of admin api
+	AcceptVersion = "v5.0"
+)
+
+// doRequest call the admin api of site by httpClient with the token signed by admin api key
+func doRequest(ctx context.Context, httpClient *http.Client, baseURL string, method string, route string, adminKey model.AdminKey, parameter map[string]interface{}, contentType string, body []byte) ([]byte, error) {
+	if adminKey.IsAnonymous {
+		return nil, fmt.Errorf("request error: %w", ghostError.ErrAnonymous)
+	}
+
+	reqURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse url error: %w", err)
+	}
+
+	// ghost redirect the route without trailing slash
+	reqURL = reqURL.JoinPath(APIPath, route, "/")
+
+	values := reqURL.Query()
+	for k, v := range parameter {
+		values.Add(k, fmt.Sprintf("%v", v))
+	}
+
+	reqURL.RawQuery = values.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("new request error: %w", err)
+	}
+
+	token, err := NewToken(adminKey, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("new token error: %w", err)
+	}
+
+	req.Header.Add("Authorization", "Ghost "+token)
+	req.Header.Add("Accept-Version", AcceptVersion)
+
+	if body != nil {
+		req.Header.Add("Content-Type", contentType)
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request error: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response error: %w", err)
+	}
+
+	statusCodeErr := ghostError.NewHTTPStatusCodeError(res.StatusCode)
+	if statusCodeErr != nil {
+		errRes := model.ErrorResponse{}
+		if err := json.Unmarshal(resBody, &errRes); err != nil || len(errRes.Errors) == 0 {
+			return nil, fmt.Errorf("request error: %w with message: %s", statusCodeErr, resBody)
+		}
+
+		return nil, fmt.Errorf("request error: %w with message: %s %s", statusCodeErr, errRes.Errors[0].Message, errRes.Errors[0].Context)
+	}
+
+	return resBody, nil
+}
+
+// toParameter convert the args to query parameter by json tags of args
+func toParameter(args interface{}) (map[string]interface{}, error) {
+	param, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("marshal error: %w", err)
+	}
+
+	paramsMap := map[string]interface{}{}
+
+	err = json.Unmarshal(param, &paramsMap)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	return paramsMap, nil
+}
@@ -0,0 +1,54 @@
+package origin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/ray31245/seo_cluster/pkg/ghost_api/model"
+)
+
+func UploadImage(ctx context.Context, httpClient *http.Client, baseURL string, adminKey model.AdminKey, args model.UploadImageArgs) (model.UploadImageResponse, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", args.FileName)
+	if err != nil {
+		return model.UploadImageResponse{}, fmt.Errorf("create form file error: %w", err)
+	}
+
+	if _, err := part.Write(args.Data); err != nil {
+		return model.UploadImageResponse{}, fmt.Errorf("write form file error: %w", err)
+	}
+
+	if args.Ref != "" {
+		if err := writer.WriteField("ref", args.Ref); err != nil {
+			return model.UploadImageResponse{}, fmt.Errorf("write form field error: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return model.UploadImageResponse{}, fmt.Errorf("close multipart writer error: %w", err)
+	}
+
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodPost, "images/upload", adminKey, nil, writer.FormDataContentType(), body.Bytes())
+	if err != nil {
+		return model.UploadImageResponse{}, fmt.Errorf("upload image error: %w", err)
+	}
+
+	resData := struct {
+		Images []model.Image `json:"images"`
+	}{}
+	if err := json.Unmarshal(resBody, &resData); err != nil {
+		return model.UploadImageResponse{}, fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	if len(resData.Images) == 0 {
+		return model.UploadImageResponse{}, fmt.Errorf("upload image error: empty response: %s", resBody)
+	}
+
+	return model.UploadImageResponse(resData.Images[0]), nil
+}
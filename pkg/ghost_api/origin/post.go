@@ -0,0 +1,126 @@
+package origin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ray31245/seo_cluster/pkg/ghost_api/model"
+	"github.com/ray31245/seo_cluster/pkg/util"
+)
+
+// sourceHTML let ghost convert the html of post to its editor format
+const sourceHTML = "html"
+
+func ListPost(ctx context.Context, httpClient *http.Client, baseURL string, adminKey model.AdminKey, args model.ListPostArgs) (model.ListPostResponse, error) {
+	paramsMap, err := toParameter(args)
+	if err != nil {
+		return model.ListPostResponse{}, fmt.Errorf("list post error: %w", err)
+	}
+
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, "posts", adminKey, paramsMap, "", nil)
+	if err != nil {
+		return model.ListPostResponse{}, fmt.Errorf("list post error: %w", err)
+	}
+
+	resData := model.ListPostResponse{}
+	if err := json.Unmarshal(resBody, &resData); err != nil {
+		return model.ListPostResponse{}, fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	return resData, nil
+}
+
+func CreatePost(ctx context.Context, httpClient *http.Client, baseURL string, adminKey model.AdminKey, args model.CreatePostArgs) (model.CreatePostResponse, error) {
+	bytesData, err := util.EscapeHTMLMarshal(map[string][]model.CreatePostArgs{"posts": {args}})
+	if err != nil {
+		return model.CreatePostResponse{}, fmt.Errorf("marshal error: %w", err)
+	}
+
+	paramsMap := map[string]interface{}{"source": sourceHTML}
+
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodPost, "posts", adminKey, paramsMap, "application/json", bytesData)
+	if err != nil {
+		return model.CreatePostResponse{}, fmt.Errorf("create post error: %w", err)
+	}
+
+	post, err := firstPost(resBody)
+	if err != nil {
+		return model.CreatePostResponse{}, fmt.Errorf("create post error: %w", err)
+	}
+
+	return model.CreatePostResponse(post), nil
+}
+
+func RetrievePost(ctx context.Context, httpClient *http.Client, baseURL string, adminKey model.AdminKey, args model.RetrievePostArgs) (model.RetrievePostResponse, error) {
+	paramsMap, err := toParameter(args)
+	if err != nil {
+		return model.RetrievePostResponse{}, fmt.Errorf("retrieve post error: %w", err)
+	}
+
+	route := fmt.Sprintf("posts/%s", args.ID)
+
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, route, adminKey, paramsMap, "", nil)
+	if err != nil {
+		return model.RetrievePostResponse{}, fmt.Errorf("retrieve post error: %w", err)
+	}
+
+	post, err := firstPost(resBody)
+	if err != nil {
+		return model.RetrievePostResponse{}, fmt.Errorf("retrieve post error: %w", err)
+	}
+
+	return model.RetrievePostResponse(post), nil
+}
+
+func UpdatePost(ctx context.Context, httpClient *http.Client, baseURL string, adminKey model.AdminKey, args model.UpdatePostArgs) (model.UpdatePostResponse, error) {
+	bytesData, err := util.EscapeHTMLMarshal(map[string][]model.UpdatePostArgs{"posts": {args}})
+	if err != nil {
+		return model.UpdatePostResponse{}, fmt.Errorf("marshal error: %w", err)
+	}
+
+	var paramsMap map[string]interface{}
+	if args.HTML != "" {
+		paramsMap = map[string]interface{}{"source": sourceHTML}
+	}
+
+	route := fmt.Sprintf("posts/%s", args.ID)
+
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodPut, route, adminKey, paramsMap, "application/json", bytesData)
+	if err != nil {
+		return model.UpdatePostResponse{}, fmt.Errorf("update post error: %w", err)
+	}
+
+	post, err := firstPost(resBody)
+	if err != nil {
+		return model.UpdatePostResponse{}, fmt.Errorf("update post error: %w", err)
+	}
+
+	return model.UpdatePostResponse(post), nil
+}
+
+func DeletePost(ctx context.Context, httpClient *http.Client, baseURL string, adminKey model.AdminKey, ID string) error {
+	route := fmt.Sprintf("posts/%s", ID)
+
+	_, err := doRequest(ctx, httpClient, baseURL, http.MethodDelete, route, adminKey, nil, "", nil)
+	if err != nil {
+		return fmt.Errorf("delete post error: %w", err)
+	}
+
+	return nil
+}
+
+// firstPost return the only post of the response which wrap post in list
+func firstPost(resBody []byte) (model.Post, error) {
+	resData := model.ListPostResponse{}
+	if err := json.Unmarshal(resBody, &resData); err != nil {
+		return model.Post{}, fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	if len(resData.Posts) == 0 {
+		return model.Post{}, fmt.Errorf("empty response: %s", resBody)
+	}
+
+	return resData.Posts[0], nil
+}
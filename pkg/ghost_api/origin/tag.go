@@ -0,0 +1,53 @@
+package origin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ray31245/seo_cluster/pkg/ghost_api/model"
+	"github.com/ray31245/seo_cluster/pkg/util"
+)
+
+func ListTag(ctx context.Context, httpClient *http.Client, baseURL string, adminKey model.AdminKey, args model.ListTagArgs) (model.ListTagResponse, error) {
+	paramsMap, err := toParameter(args)
+	if err != nil {
+		return model.ListTagResponse{}, fmt.Errorf("list tag error: %w", err)
+	}
+
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, "tags", adminKey, paramsMap, "", nil)
+	if err != nil {
+		return model.ListTagResponse{}, fmt.Errorf("list tag error: %w", err)
+	}
+
+	resData := model.ListTagResponse{}
+	if err := json.Unmarshal(resBody, &resData); err != nil {
+		return model.ListTagResponse{}, fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	return resData, nil
+}
+
+func CreateTag(ctx context.Context, httpClient *http.Client, baseURL string, adminKey model.AdminKey, args model.CreateTagArgs) (model.CreateTagResponse, error) {
+	bytesData, err := util.EscapeHTMLMarshal(map[string][]model.CreateTagArgs{"tags": {args}})
+	if err != nil {
+		return model.CreateTagResponse{}, fmt.Errorf("marshal error: %w", err)
+	}
+
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodPost, "tags", adminKey, nil, "application/json", bytesData)
+	if err != nil {
+		return model.CreateTagResponse{}, fmt.Errorf("create tag error: %w", err)
+	}
+
+	resData := model.ListTagResponse{}
+	if err := json.Unmarshal(resBody, &resData); err != nil {
+		return model.CreateTagResponse{}, fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	if len(resData.Tags) == 0 {
+		return model.CreateTagResponse{}, fmt.Errorf("create tag error: empty response: %s", resBody)
+	}
+
+	return model.CreateTagResponse(resData.Tags[0]), nil
+}
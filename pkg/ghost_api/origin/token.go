@@ -0,0 +1,55 @@
+package origin
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	ghostError "github.com/ray31245/seo_cluster/pkg/ghost_api/error"
+	"github.com/ray31245/seo_cluster/pkg/ghost_api/model"
+)
+
+const (
+	// TokenAudience is the audience of admin api token
+	TokenAudience = "/admin/"
+	// ghost reject the token which expire more than 5 minutes later
+	tokenTTL = 5 * time.Minute
+)
+
+// ParseAdminKey split the admin api key in format of {id}:{secret}
+func ParseAdminKey(key string) (model.AdminKey, error) {
+	ID, secret, ok := strings.Cut(key, ":")
+	if !ok || ID == "" || secret == "" {
+		return model.AdminKey{}, fmt.Errorf("ParseAdminKey: %w", ghostError.ErrInvalidAdminKey)
+	}
+
+	if _, err := hex.DecodeString(secret); err != nil {
+		return model.AdminKey{}, fmt.Errorf("ParseAdminKey: %w: secret is not hex: %w", ghostError.ErrInvalidAdminKey, err)
+	}
+
+	return model.AdminKey{ID: ID, Secret: secret}, nil
+}
+
+// NewToken sign the short-lived jwt of admin api key
+func NewToken(key model.AdminKey, now time.Time) (string, error) {
+	secret, err := hex.DecodeString(key.Secret)
+	if err != nil {
+		return "", fmt.Errorf("NewToken: %w: %w", ghostError.ErrInvalidAdminKey, err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Audience:  jwt.ClaimStrings{TokenAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(tokenTTL)),
+	})
+	token.Header["kid"] = key.ID
+
+	res, err := token.SignedString(secret)
+	if err != nil {
+		return "", fmt.Errorf("NewToken: %w", err)
+	}
+
+	return res, nil
+}
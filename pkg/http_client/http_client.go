@@ -0,0 +1,214 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout limit the whole request when timeout is not set, so one slow site can not hang the caller forever
+	DefaultTimeout             = 60 * time.Second
+	defaultMaxIdleConnsPerHost = 8
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+)
+
+var ErrInvalidProxy = errors.New("invalid proxy")
+
+// Options of outbound http request to sites, zero value means the default
+type Options struct {
+	// TimeoutSeconds limit the whole request including reading body, 0 means DefaultTimeout
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	// Proxy is the url of outbound proxy, e.g. "http://127.0.0.1:8080", empty means the proxy from environment
+	Proxy     string `json:"proxy,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	// Headers are added to every request, the ones set by client itself are not replaced
+	Headers map[string]string `json:"headers,omitempty"`
+	// InsecureSkipVerify skip verifying the certificate of site, only for the sites with self-signed certificate
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+}
+
+// Override return the options replaced by the non-zero fields of override.
+// Headers are merged and InsecureSkipVerify is enabled if either of them enable it.
+func (o Options) Override(override Options) Options {
+	res := o
+
+	if override.TimeoutSeconds != 0 {
+		res.TimeoutSeconds = override.TimeoutSeconds
+	}
+
+	if override.Proxy != "" {
+		res.Proxy = override.Proxy
+	}
+
+	if override.UserAgent != "" {
+		res.UserAgent = override.UserAgent
+	}
+
+	if len(override.Headers) != 0 {
+		res.Headers = maps.Clone(o.Headers)
+		if res.Headers == nil {
+			res.Headers = make(map[string]string, len(override.Headers))
+		}
+
+		maps.Copy(res.Headers, override.Headers)
+	}
+
+	res.InsecureSkipVerify = o.InsecureSkipVerify || override.InsecureSkipVerify
+
+	return res
+}
+
+func (o Options) timeout() time.Duration {
+	if o.TimeoutSeconds <= 0 {
+		return DefaultTimeout
+	}
+
+	return time.Duration(o.TimeoutSeconds) * time.Second
+}
+
+// transportKey is the options which need their own transport
+type transportKey struct {
+	proxy              string
+	insecureSkipVerify bool
+}
+
+// Factory build http clients from its base options and per-site overrides.
+// The clients having the same proxy and tls settings share one transport to reuse the connections.
+type Factory struct {
+	lock       sync.Mutex
+	base       Options
+	transports map[transportKey]*http.Transport
+}
+
+func NewFactory(base Options) (*Factory, error) {
+	err := Validate(base)
+	if err != nil {
+		return nil, fmt.Errorf("NewFactory: %w", err)
+	}
+
+	return &Factory{
+		base:       base,
+		transports: make(map[transportKey]*http.Transport),
+	}, nil
+}
+
+// NewDefaultFactory return the factory of default options
+func NewDefaultFactory() *Factory {
+	return &Factory{transports: make(map[transportKey]*http.Transport)}
+}
+
+// NewDefaultClient return the client using the shared default transport with DefaultTimeout,
+// it is used by the clients created without factory
+func NewDefaultClient() *http.Client {
+	return &http.Client{Timeout: DefaultTimeout}
+}
+
+// Client return the http client of base options overridden by override
+func (f *Factory) Client(override Options) (*http.Client, error) {
+	options := f.base.Override(override)
+
+	transport, err := f.transport(transportKey{proxy: options.Proxy, insecureSkipVerify: options.InsecureSkipVerify})
+	if err != nil {
+		return nil, fmt.Errorf("Client: %w", err)
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if options.UserAgent != "" || len(options.Headers) != 0 {
+		roundTripper = &headerTransport{base: transport, userAgent: options.UserAgent, headers: maps.Clone(options.Headers)}
+	}
+
+	return &http.Client{Transport: roundTripper, Timeout: options.timeout()}, nil
+}
+
+// DefaultClient return the http client of base options
+func (f *Factory) DefaultClient() *http.Client {
+	// base options are validated by NewFactory
+	client, _ := f.Client(Options{})
+
+	return client
+}
+
+// Validate check the options can build a client
+func Validate(options Options) error {
+	_, err := proxyFunc(options.Proxy)
+	if err != nil {
+		return fmt.Errorf("Validate: %w", err)
+	}
+
+	return nil
+}
+
+func (f *Factory) transport(key transportKey) (*http.Transport, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if transport, ok := f.transports[key]; ok {
+		return transport, nil
+	}
+
+	proxy, err := proxyFunc(key.proxy)
+	if err != nil {
+		return nil, fmt.Errorf("transport: %w", err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // DefaultTransport is always *http.Transport
+	transport.Proxy = proxy
+	transport.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	transport.IdleConnTimeout = defaultIdleConnTimeout
+	transport.TLSHandshakeTimeout = defaultTLSHandshakeTimeout
+
+	if key.insecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // enabled by site explicitly
+	}
+
+	f.transports[key] = transport
+
+	return transport, nil
+}
+
+func proxyFunc(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	if proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("proxyFunc: %w: %w", ErrInvalidProxy, err)
+	}
+
+	if proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return nil, fmt.Errorf("proxyFunc: %w: %q", ErrInvalidProxy, proxy)
+	}
+
+	return http.ProxyURL(proxyURL), nil
+}
+
+// headerTransport add user agent and headers to request
+type headerTransport struct {
+	base      http.RoundTripper
+	userAgent string
+	headers   map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	if t.userAgent != "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+
+	for k, v := range t.headers {
+		if req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,113 @@
+package httpclient_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_Override(t *testing.T) {
+	base := httpclient.Options{TimeoutSeconds: 30, UserAgent: "base", Headers: map[string]string{"A": "1", "B": "1"}}
+
+	res := base.Override(httpclient.Options{UserAgent: "site", Headers: map[string]string{"B": "2"}, InsecureSkipVerify: true})
+	assert.Equal(t, httpclient.Options{
+		TimeoutSeconds:     30,
+		UserAgent:          "site",
+		Headers:            map[string]string{"A": "1", "B": "2"},
+		InsecureSkipVerify: true,
+	}, res)
+
+	// base is not changed by override
+	assert.Equal(t, map[string]string{"A": "1", "B": "1"}, base.Headers)
+	assert.Equal(t, base, base.Override(httpclient.Options{}))
+}
+
+func TestFactory_Client(t *testing.T) {
+	var header http.Header
+
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+	}))
+	t.Cleanup(srv.Close)
+
+	factory, err := httpclient.NewFactory(httpclient.Options{UserAgent: "seo-cluster", Headers: map[string]string{"X-Base": "base"}})
+	require.NoError(t, err)
+
+	client, err := factory.Client(httpclient.Options{Headers: map[string]string{"X-Site": "site"}})
+	require.NoError(t, err)
+	assert.Equal(t, httpclient.DefaultTimeout, client.Timeout)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Base", "request")
+
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	assert.Equal(t, "seo-cluster", header.Get("User-Agent"))
+	assert.Equal(t, "site", header.Get("X-Site"))
+	// the header set by request is kept
+	assert.Equal(t, "request", header.Get("X-Base"))
+}
+
+func TestFactory_SharedTransport(t *testing.T) {
+	factory := httpclient.NewDefaultFactory()
+
+	a, err := factory.Client(httpclient.Options{TimeoutSeconds: 5})
+	require.NoError(t, err)
+
+	b, err := factory.Client(httpclient.Options{TimeoutSeconds: 10})
+	require.NoError(t, err)
+
+	insecure, err := factory.Client(httpclient.Options{InsecureSkipVerify: true})
+	require.NoError(t, err)
+
+	assert.Same(t, a.Transport, b.Transport)
+	assert.NotSame(t, a.Transport, insecure.Transport)
+	assert.Equal(t, 10*time.Second, b.Timeout)
+}
+
+func TestFactory_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		<-release
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	client, err := httpclient.NewDefaultFactory().Client(httpclient.Options{TimeoutSeconds: 1})
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	_, err = client.Get(srv.URL) //nolint:noctx // timeout of client is tested
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestFactory_Proxy(t *testing.T) {
+	var proxiedHost string
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+	}))
+	t.Cleanup(proxy.Close)
+
+	client, err := httpclient.NewDefaultFactory().Client(httpclient.Options{Proxy: proxy.URL})
+	require.NoError(t, err)
+
+	res, err := client.Get("http://site.test/") //nolint:noctx // request is sent to proxy
+	require.NoError(t, err)
+	res.Body.Close()
+
+	assert.Equal(t, "site.test", proxiedHost)
+
+	_, err = httpclient.NewFactory(httpclient.Options{Proxy: "127.0.0.1"})
+	require.ErrorIs(t, err, httpclient.ErrInvalidProxy)
+}
@@ -1,12 +1,13 @@
 package jwtkit
 
 import (
-	"log"
+	"log/slog"
 	"time"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
 	jwt_kit_interface "github.com/ray31245/seo_cluster/pkg/jwt_kit/jwt_kit_interface"
+	"github.com/ray31245/seo_cluster/pkg/logger"
 )
 
 // assert the JWTKit struct implements the SetJWTKit interface
@@ -83,7 +84,7 @@ func (j *JWTKit) InitMiddleWare() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		errInit := j.middleware.MiddlewareInit()
 		if errInit != nil {
-			log.Printf("JWTKit InitMiddleWare: %v", errInit)
+			slog.ErrorContext(ctx, "JWTKit InitMiddleWare", logger.Err(errInit))
 			ctx.Abort()
 
 			return
@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// keys of the fields shared by services
+const (
+	KeyRequestID = "request_id"
+	KeySiteID    = "site_id"
+	KeyArticleID = "article_id"
+	KeyError     = "error"
+)
+
+// Redacted replace the value of secret fields
+const Redacted = "******"
+
+var ErrInvalidFormat = errors.New("invalid log format")
+
+// Format of log output
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// Options of logger, zero value is info level text log to stderr
+type Options struct {
+	Level  slog.Level
+	Format Format
+	Output io.Writer
+}
+
+// ParseFormat return FormatText for empty string
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("ParseFormat: %w: %q", ErrInvalidFormat, s)
+	}
+}
+
+// ParseLevel parse debug, info, warn or error, empty string is info
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s == "" {
+		return level, nil
+	}
+
+	err := level.UnmarshalText([]byte(s))
+	if err != nil {
+		return level, fmt.Errorf("ParseLevel: %w", err)
+	}
+
+	return level, nil
+}
+
+// LoadOptions read LOG_LEVEL (debug, info, warn or error) and LOG_FORMAT (text or json) from environment
+func LoadOptions() (Options, error) {
+	level, err := ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		return Options{}, fmt.Errorf("LoadOptions: %w", err)
+	}
+
+	format, err := ParseFormat(os.Getenv("LOG_FORMAT"))
+	if err != nil {
+		return Options{}, fmt.Errorf("LoadOptions: %w", err)
+	}
+
+	return Options{Level: level, Format: format}, nil
+}
+
+// New return the logger redacting secret fields and adding the fields carried by context
+func New(options Options) *slog.Logger {
+	output := options.Output
+	if output == nil {
+		output = os.Stderr
+	}
+
+	handlerOptions := &slog.HandlerOptions{Level: options.Level, ReplaceAttr: redact}
+
+	var handler slog.Handler
+	if options.Format == FormatJSON {
+		handler = slog.NewJSONHandler(output, handlerOptions)
+	} else {
+		handler = slog.NewTextHandler(output, handlerOptions)
+	}
+
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// Init set the logger of options as the shared default logger, the output of package log is sent to it too
+func Init(options Options) *slog.Logger {
+	res := New(options)
+	slog.SetDefault(res)
+
+	return res
+}
+
+// WithAttrs return the context carrying attrs, they are added to the logs written with the context
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+
+	prev, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+
+	res := make([]slog.Attr, 0, len(prev)+len(attrs))
+	res = append(res, prev...)
+	res = append(res, attrs...)
+
+	return context.WithValue(ctx, attrsKey{}, res)
+}
+
+func SiteID(ID any) slog.Attr {
+	return slog.Any(KeySiteID, ID)
+}
+
+func ArticleID(ID any) slog.Attr {
+	return slog.Any(KeyArticleID, ID)
+}
+
+func Err(err error) slog.Attr {
+	return slog.Any(KeyError, err)
+}
+
+// IsSecretKey report the field of key is a secret, e.g. password, access_token, api_key or Authorization
+func IsSecretKey(key string) bool {
+	key = strings.NewReplacer("_", "", "-", "", ".", "").Replace(strings.ToLower(key))
+
+	for _, suffix := range []string{"password", "passwd", "token", "secret", "apikey", "authorization", "cookie"} {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func redact(_ []string, attr slog.Attr) slog.Attr {
+	if IsSecretKey(attr.Key) && attr.Value.Kind() != slog.KindGroup {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	return attr
+}
+
+type attrsKey struct{}
+
+// contextHandler add the attrs carried by context to record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+			record.AddAttrs(attrs...)
+		}
+	}
+
+	return h.Handler.Handle(ctx, record) //nolint:wrapcheck // the error of inner handler is returned as it is
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
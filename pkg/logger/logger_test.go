@@ -0,0 +1,85 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger_JSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := logger.New(logger.Options{Level: slog.LevelInfo, Format: logger.FormatJSON, Output: buf})
+
+	ctx := logger.WithAttrs(context.Background(), slog.String(logger.KeyRequestID, "req-1"))
+	ctx = logger.WithAttrs(ctx, logger.SiteID("site-1"))
+
+	log.InfoContext(ctx, "publish",
+		logger.ArticleID("article-1"),
+		logger.Err(errors.New("failed")),
+		slog.String("password", "p@ss"),
+		slog.Group("client", slog.String("access_token", "t0ken"), slog.String("url", "http://a.test")),
+	)
+	log.DebugContext(ctx, "debug is below level")
+
+	record := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+
+	assert.Equal(t, "publish", record["msg"])
+	assert.Equal(t, "req-1", record[logger.KeyRequestID])
+	assert.Equal(t, "site-1", record[logger.KeySiteID])
+	assert.Equal(t, "article-1", record[logger.KeyArticleID])
+	assert.Equal(t, "failed", record[logger.KeyError])
+	assert.Equal(t, logger.Redacted, record["password"])
+	assert.Equal(t, map[string]any{"access_token": logger.Redacted, "url": "http://a.test"}, record["client"])
+	assert.NotContains(t, buf.String(), "p@ss")
+	assert.NotContains(t, buf.String(), "debug is below level")
+}
+
+func TestLogger_Text(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := logger.New(logger.Options{Level: slog.LevelDebug, Output: buf}).With(slog.String("api_key", "k3y"))
+
+	log.DebugContext(context.Background(), "login", slog.String("username", "admin"))
+
+	assert.Contains(t, buf.String(), "level=DEBUG")
+	assert.Contains(t, buf.String(), "username=admin")
+	assert.Contains(t, buf.String(), "api_key="+logger.Redacted)
+	assert.NotContains(t, buf.String(), "k3y")
+}
+
+func TestIsSecretKey(t *testing.T) {
+	for _, key := range []string{"password", "Password", "user_password", "token", "access-token", "API_KEY", "apiKey", "client_secret", "Authorization", "cookie"} {
+		assert.True(t, logger.IsSecretKey(key), key)
+	}
+
+	for _, key := range []string{"username", "url", "token_expire_at", "site_id", "key"} {
+		assert.False(t, logger.IsSecretKey(key), key)
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	level, err := logger.ParseLevel("warn")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelWarn, level)
+
+	level, err = logger.ParseLevel("")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelInfo, level)
+
+	_, err = logger.ParseLevel("verbose")
+	require.Error(t, err)
+
+	format, err := logger.ParseFormat("JSON")
+	require.NoError(t, err)
+	assert.Equal(t, logger.FormatJSON, format)
+
+	_, err = logger.ParseFormat("xml")
+	require.ErrorIs(t, err, logger.ErrInvalidFormat)
+}
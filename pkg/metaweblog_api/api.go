@@ -0,0 +1,77 @@
+package metaweblogapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	clientpool "github.com/ray31245/seo_cluster/pkg/client_pool"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	metaWeblogInterface "github.com/ray31245/seo_cluster/pkg/metaweblog_api/metaweblog_interface"
+)
+
+// MetaWeblogAPI is a struct to implement MetaWeblogAPI interface
+var _ metaWeblogInterface.MetaWeblogAPI = &MetaWeblogAPI{}
+
+type MetaWeblogAPI struct {
+	// key is site id or user id
+	clientPool  *clientpool.Pool[*Client]
+	httpClients *httpclient.Factory
+}
+
+// NewMetaWeblogAPI create the api, the http clients of sites are built by httpClients with the options of site
+func NewMetaWeblogAPI(poolOptions clientpool.Options, httpClients *httpclient.Factory) *MetaWeblogAPI {
+	return &MetaWeblogAPI{
+		clientPool:  clientpool.NewPool[*Client](poolOptions),
+		httpClients: httpClients,
+	}
+}
+
+func (t *MetaWeblogAPI) GetClient(ctx context.Context, ID uuid.UUID, endpoint string, userName string, password string, httpOptions httpclient.Options) (metaWeblogInterface.MetaWeblogClient, error) {
+	client, err := t.clientPool.Get(ctx, ID, func(ctx context.Context) (*Client, error) {
+		return t.newClient(ctx, endpoint, userName, password, httpOptions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (t *MetaWeblogAPI) UpdateClient(ctx context.Context, ID uuid.UUID, endpoint string, userName string, password string, httpOptions httpclient.Options) (metaWeblogInterface.MetaWeblogClient, error) {
+	client, err := t.newClient(ctx, endpoint, userName, password, httpOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	t.clientPool.Put(ID, client)
+
+	return client, nil
+}
+
+func (t *MetaWeblogAPI) DeleteClient(ID uuid.UUID) {
+	t.clientPool.Delete(ID)
+}
+
+// PoolStats return the stats of client pool
+func (t *MetaWeblogAPI) PoolStats() clientpool.Stats {
+	return t.clientPool.Stats()
+}
+
+func (t *MetaWeblogAPI) NewClient(ctx context.Context, endpoint string, userName string, password string, httpOptions httpclient.Options) (metaWeblogInterface.MetaWeblogClient, error) {
+	return t.newClient(ctx, endpoint, userName, password, httpOptions)
+}
+
+func (t *MetaWeblogAPI) NewAnonymousClient(ctx context.Context, endpoint string) metaWeblogInterface.MetaWeblogClient {
+	return NewAnonymousClientWithHTTPClient(ctx, t.httpClients.DefaultClient(), endpoint)
+}
+
+func (t *MetaWeblogAPI) newClient(ctx context.Context, endpoint string, userName string, password string, httpOptions httpclient.Options) (*Client, error) {
+	httpClient, err := t.httpClients.Client(httpOptions)
+	if err != nil {
+		return nil, fmt.Errorf("newClient: %w", err)
+	}
+
+	return NewClientWithHTTPClient(ctx, httpClient, endpoint, userName, password)
+}
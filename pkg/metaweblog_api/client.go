@@ -0,0 +1,154 @@
+package metaweblogapi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	"github.com/ray31245/seo_cluster/pkg/metaweblog_api/model"
+	"github.com/ray31245/seo_cluster/pkg/metaweblog_api/origin"
+)
+
+// defaultBlogID is used when the server does not list any blog of user
+const defaultBlogID = "1"
+
+type Client struct {
+	// endpoint is the url of xml-rpc server, e.g. https://example.com/xmlrpc.php
+	endpoint   string
+	httpClient *http.Client
+	auth       model.Authentication
+	blogID     string
+}
+
+// NewClient login to the xml-rpc server and use the first blog of user
+func NewClient(ctx context.Context, endpoint string, userName string, password string) (*Client, error) {
+	return NewClientWithHTTPClient(ctx, httpclient.NewDefaultClient(), endpoint, userName, password)
+}
+
+// NewClientWithHTTPClient is NewClient sending the requests by httpClient
+func NewClientWithHTTPClient(ctx context.Context, httpClient *http.Client, endpoint string, userName string, password string) (*Client, error) {
+	slog.InfoContext(ctx, "login metaweblog", slog.String("url", endpoint))
+
+	res := &Client{
+		endpoint:   endpoint,
+		httpClient: httpClient,
+		auth: model.Authentication{
+			UserName: userName,
+			Password: password,
+		},
+		blogID: defaultBlogID,
+	}
+
+	blogs, err := res.GetUsersBlogs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("NewClient: %w", err)
+	}
+
+	if len(blogs) > 0 && blogs[0].BlogID != "" {
+		res.blogID = string(blogs[0].BlogID)
+	}
+
+	return res, nil
+}
+
+// NewAnonymousClient return a client without credential,
+// most of the metaweblog methods require credential and will return fault
+func NewAnonymousClient(ctx context.Context, endpoint string) *Client {
+	return NewAnonymousClientWithHTTPClient(ctx, httpclient.NewDefaultClient(), endpoint)
+}
+
+// NewAnonymousClientWithHTTPClient is NewAnonymousClient sending the requests by httpClient
+func NewAnonymousClientWithHTTPClient(_ context.Context, httpClient *http.Client, endpoint string) *Client {
+	return &Client{
+		endpoint:   endpoint,
+		httpClient: httpClient,
+		blogID:     defaultBlogID,
+	}
+}
+
+func (c *Client) BlogID() string {
+	return c.blogID
+}
+
+func (c *Client) GetUsersBlogs(ctx context.Context) ([]model.Blog, error) {
+	res, err := origin.GetUsersBlogs(ctx, c.httpClient, c.endpoint, c.auth)
+	if err != nil {
+		return nil, fmt.Errorf("GetUsersBlogs: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) GetCategories(ctx context.Context) ([]model.Category, error) {
+	res, err := origin.GetCategories(ctx, c.httpClient, c.endpoint, c.auth, c.blogID)
+	if err != nil {
+		return nil, fmt.Errorf("GetCategories: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) NewCategory(ctx context.Context, req model.NewCategoryRequest) (string, error) {
+	res, err := origin.NewCategory(ctx, c.httpClient, c.endpoint, c.auth, c.blogID, req)
+	if err != nil {
+		return "", fmt.Errorf("NewCategory: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) NewPost(ctx context.Context, req model.NewPostRequest) (string, error) {
+	res, err := origin.NewPost(ctx, c.httpClient, c.endpoint, c.auth, c.blogID, req)
+	if err != nil {
+		return "", fmt.Errorf("NewPost: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) EditPost(ctx context.Context, req model.EditPostRequest) error {
+	err := origin.EditPost(ctx, c.httpClient, c.endpoint, c.auth, req)
+	if err != nil {
+		return fmt.Errorf("EditPost: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) GetPost(ctx context.Context, postID string) (model.Post, error) {
+	res, err := origin.GetPost(ctx, c.httpClient, c.endpoint, c.auth, postID)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("GetPost: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) GetRecentPosts(ctx context.Context, numberOfPosts int) ([]model.Post, error) {
+	res, err := origin.GetRecentPosts(ctx, c.httpClient, c.endpoint, c.auth, c.blogID, numberOfPosts)
+	if err != nil {
+		return nil, fmt.Errorf("GetRecentPosts: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) DeletePost(ctx context.Context, postID string) error {
+	err := origin.DeletePost(ctx, c.httpClient, c.endpoint, c.auth, postID)
+	if err != nil {
+		return fmt.Errorf("DeletePost: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) NewMediaObject(ctx context.Context, req model.NewMediaObjectRequest) (model.MediaObjectResponse, error) {
+	res, err := origin.NewMediaObject(ctx, c.httpClient, c.endpoint, c.auth, c.blogID, req)
+	if err != nil {
+		return model.MediaObjectResponse{}, fmt.Errorf("NewMediaObject: %w", err)
+	}
+
+	return res, nil
+}
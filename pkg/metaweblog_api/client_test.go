@@ -0,0 +1,148 @@
+package metaweblogapi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	metaweblogApi "github.com/ray31245/seo_cluster/pkg/metaweblog_api"
+	metaWeblogErr "github.com/ray31245/seo_cluster/pkg/metaweblog_api/error"
+	"github.com/ray31245/seo_cluster/pkg/metaweblog_api/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	server := newStandInServer()
+	defer server.Close()
+
+	t.Run("login success", func(t *testing.T) {
+		client, err := metaweblogApi.NewClient(context.Background(), server.URL, TestUserName, TestPassword)
+		require.NoError(t, err)
+		assert.Equal(t, "1", client.BlogID())
+	})
+
+	t.Run("login failed", func(t *testing.T) {
+		_, err := metaweblogApi.NewClient(context.Background(), server.URL, TestUserName, "wrong password")
+		require.ErrorIs(t, err, metaWeblogErr.ErrFault)
+
+		faultErr := metaWeblogErr.FaultError{}
+		require.True(t, errors.As(err, &faultErr))
+		assert.Equal(t, faultCodeLogin, faultErr.Code)
+	})
+
+	t.Run("anonymous client", func(t *testing.T) {
+		client := metaweblogApi.NewAnonymousClient(context.Background(), server.URL)
+
+		_, err := client.GetCategories(context.Background())
+		require.ErrorIs(t, err, metaWeblogErr.ErrFault)
+	})
+}
+
+func TestClient_Categories(t *testing.T) {
+	server := newStandInServer()
+	defer server.Close()
+
+	ctx := context.Background()
+
+	client, err := metaweblogApi.NewClient(ctx, server.URL, TestUserName, TestPassword)
+	require.NoError(t, err)
+
+	categories, err := client.GetCategories(ctx)
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	assert.Equal(t, "1", string(categories[0].CategoryID))
+	assert.Equal(t, "news", categories[0].Name())
+	assert.Equal(t, "2", string(categories[1].CategoryID))
+	assert.Equal(t, "tech", categories[1].Name())
+
+	cateID, err := client.NewCategory(ctx, model.NewCategoryRequest{Name: "life"})
+	require.NoError(t, err)
+	assert.Equal(t, "3", cateID)
+}
+
+func TestClient_Post(t *testing.T) {
+	server := newStandInServer()
+	defer server.Close()
+
+	ctx := context.Background()
+
+	client, err := metaweblogApi.NewClient(ctx, server.URL, TestUserName, TestPassword)
+	require.NoError(t, err)
+
+	// content need to be escaped in xml
+	content := `<p class="a">Tom & Jerry</p>`
+
+	postID, err := client.NewPost(ctx, model.NewPostRequest{
+		Title:       "title <1>",
+		Description: content,
+		Categories:  []string{"news"},
+		Keywords:    "go,rust",
+		Publish:     true,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, postID)
+
+	post, err := client.GetPost(ctx, postID)
+	require.NoError(t, err)
+	assert.Equal(t, postID, string(post.PostID))
+	assert.Equal(t, "title <1>", post.Title)
+	assert.Equal(t, content, post.Description)
+	assert.Equal(t, []string{"news"}, post.Categories)
+	assert.Equal(t, "go,rust", post.Keywords)
+	assert.True(t, post.DateCreated.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+	err = client.EditPost(ctx, model.EditPostRequest{
+		PostID: postID,
+		NewPostRequest: model.NewPostRequest{
+			Title:       post.Title,
+			Description: post.Description,
+			Categories:  []string{"tech"},
+			Publish:     true,
+		},
+	})
+	require.NoError(t, err)
+
+	post, err = client.GetPost(ctx, postID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tech"}, post.Categories)
+	assert.Empty(t, post.Keywords)
+
+	secondID, err := client.NewPost(ctx, model.NewPostRequest{Title: "second", Publish: true})
+	require.NoError(t, err)
+
+	posts, err := client.GetRecentPosts(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, secondID, string(posts[0].PostID))
+
+	err = client.DeletePost(ctx, postID)
+	require.NoError(t, err)
+
+	_, err = client.GetPost(ctx, postID)
+	require.ErrorIs(t, err, metaWeblogErr.ErrFault)
+
+	err = client.DeletePost(ctx, postID)
+	require.ErrorIs(t, err, metaWeblogErr.ErrFault)
+}
+
+func TestClient_NewMediaObject(t *testing.T) {
+	server := newStandInServer()
+	defer server.Close()
+
+	ctx := context.Background()
+
+	client, err := metaweblogApi.NewClient(ctx, server.URL, TestUserName, TestPassword)
+	require.NoError(t, err)
+
+	bits := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+
+	res, err := client.NewMediaObject(ctx, model.NewMediaObjectRequest{Name: "a.png", Type: "image/png", Bits: bits})
+	require.NoError(t, err)
+	assert.Equal(t, "7", string(res.ID))
+	assert.Equal(t, "a.png", res.File)
+	assert.Equal(t, server.URL+"/usr/uploads/a.png", res.URL)
+	assert.Equal(t, "image/png", res.Type)
+	assert.Equal(t, bits, server.media["a.png"])
+}
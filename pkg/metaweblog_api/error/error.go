@@ -0,0 +1,35 @@
+package metaweblogerror
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrHTTPStatusCodeError = errors.New("http status code error")
+	// ErrFault is the fault returned by xml-rpc server, e.g. login failed or post not found
+	ErrFault = errors.New("xml-rpc fault")
+)
+
+// FaultError is the fault response of xml-rpc method call
+type FaultError struct {
+	Code    int
+	Message string
+}
+
+func (e FaultError) Error() string {
+	return fmt.Sprintf("%v: code %d, %s", ErrFault, e.Code, e.Message)
+}
+
+func (e FaultError) Unwrap() error {
+	return ErrFault
+}
+
+func NewHTTPStatusCodeError(statusCode int) error {
+	// check if status code is 2xx
+	if statusCode/100 == 2 { //nolint:mnd
+		return nil
+	}
+
+	return fmt.Errorf("%w: %d", ErrHTTPStatusCodeError, statusCode)
+}
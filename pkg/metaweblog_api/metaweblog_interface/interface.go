@@ -0,0 +1,29 @@
+package metawebloginterface
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	"github.com/ray31245/seo_cluster/pkg/metaweblog_api/model"
+)
+
+type MetaWeblogAPI interface {
+	GetClient(ctx context.Context, ID uuid.UUID, endpoint string, userName string, password string, httpOptions httpclient.Options) (MetaWeblogClient, error)
+	UpdateClient(ctx context.Context, ID uuid.UUID, endpoint string, userName string, password string, httpOptions httpclient.Options) (MetaWeblogClient, error)
+	DeleteClient(ID uuid.UUID)
+	NewClient(ctx context.Context, endpoint string, userName string, password string, httpOptions httpclient.Options) (MetaWeblogClient, error)
+	NewAnonymousClient(ctx context.Context, endpoint string) MetaWeblogClient
+}
+
+type MetaWeblogClient interface {
+	GetUsersBlogs(ctx context.Context) ([]model.Blog, error)
+	GetCategories(ctx context.Context) ([]model.Category, error)
+	NewCategory(ctx context.Context, req model.NewCategoryRequest) (string, error)
+	NewPost(ctx context.Context, req model.NewPostRequest) (string, error)
+	EditPost(ctx context.Context, req model.EditPostRequest) error
+	GetPost(ctx context.Context, postID string) (model.Post, error)
+	GetRecentPosts(ctx context.Context, numberOfPosts int) ([]model.Post, error)
+	DeletePost(ctx context.Context, postID string) error
+	NewMediaObject(ctx context.Context, req model.NewMediaObjectRequest) (model.MediaObjectResponse, error)
+}
@@ -0,0 +1,183 @@
+package metaweblogapi_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ray31245/seo_cluster/pkg/metaweblog_api/origin"
+)
+
+const (
+	// TestUserName is the test username
+	TestUserName = "admin"
+	// TestPassword is the test password
+	TestPassword = "admin"
+
+	faultCodeLogin    = 403
+	faultCodeNotFound = 404
+)
+
+// standInServer is a local xml-rpc server which keep posts in memory
+type standInServer struct {
+	*httptest.Server
+
+	lock   sync.Mutex
+	nextID int
+	posts  map[string]map[string]interface{}
+	// order of post ids, latest last
+	postIDs []string
+	media   map[string][]byte
+}
+
+func newStandInServer() *standInServer {
+	s := &standInServer{
+		nextID: 100,
+		posts:  map[string]map[string]interface{}{},
+		media:  map[string][]byte{},
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+func (s *standInServer) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	method, params, err := origin.DecodeMethodCall(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	s.lock.Lock()
+	res, faultCode, faultString := s.call(method, params)
+	s.lock.Unlock()
+
+	var resBody []byte
+	if faultCode != 0 {
+		resBody, err = origin.EncodeFaultResponse(faultCode, faultString)
+	} else {
+		resBody, err = origin.EncodeMethodResponse(res)
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	_, _ = w.Write(resBody)
+}
+
+func (s *standInServer) call(method string, params []interface{}) (interface{}, int, string) {
+	// position of user name in params, password follows it
+	userIdx := 1
+	if method == origin.MethodDeletePost {
+		userIdx = 2
+	}
+
+	if len(params) < userIdx+2 || params[userIdx] != TestUserName || params[userIdx+1] != TestPassword {
+		return nil, faultCodeLogin, "Incorrect username or password."
+	}
+
+	switch method {
+	case origin.MethodGetUsersBlogs:
+		return []interface{}{
+			map[string]interface{}{"blogid": "1", "blogName": "stand-in", "url": s.URL, "isAdmin": true},
+		}, 0, ""
+	case origin.MethodGetCategories:
+		return []interface{}{
+			map[string]interface{}{"categoryId": "1", "categoryName": "news", "htmlUrl": s.URL + "/category/news"},
+			// some servers return int id and title only
+			map[string]interface{}{"categoryId": 2, "title": "tech"},
+		}, 0, ""
+	case origin.MethodNewCategory:
+		return 3, 0, ""
+	case origin.MethodNewPost:
+		post, _ := params[3].(map[string]interface{})
+		post["dateCreated"] = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		s.nextID++
+		postID := strconv.Itoa(s.nextID)
+		post["postid"] = postID
+		s.posts[postID] = post
+		s.postIDs = append(s.postIDs, postID)
+
+		return postID, 0, ""
+	case origin.MethodEditPost:
+		postID, _ := params[0].(string)
+
+		old, ok := s.posts[postID]
+		if !ok {
+			return nil, faultCodeNotFound, "Invalid post ID."
+		}
+
+		post, _ := params[3].(map[string]interface{})
+		post["postid"] = postID
+		post["dateCreated"] = old["dateCreated"]
+		s.posts[postID] = post
+
+		return true, 0, ""
+	case origin.MethodGetPost:
+		postID, _ := params[0].(string)
+
+		post, ok := s.posts[postID]
+		if !ok {
+			return nil, faultCodeNotFound, "Invalid post ID."
+		}
+
+		return post, 0, ""
+	case origin.MethodGetRecentPosts:
+		res := []interface{}{}
+		for i := len(s.postIDs) - 1; i >= 0; i-- {
+			res = append(res, s.posts[s.postIDs[i]])
+		}
+
+		return res, 0, ""
+	case origin.MethodDeletePost:
+		postID, _ := params[1].(string)
+
+		if _, ok := s.posts[postID]; !ok {
+			return nil, faultCodeNotFound, "Invalid post ID."
+		}
+
+		delete(s.posts, postID)
+
+		for i, ID := range s.postIDs {
+			if ID == postID {
+				s.postIDs = append(s.postIDs[:i], s.postIDs[i+1:]...)
+
+				break
+			}
+		}
+
+		return true, 0, ""
+	case origin.MethodNewMediaObject:
+		media, _ := params[3].(map[string]interface{})
+		name, _ := media["name"].(string)
+		bits, _ := media["bits"].([]byte)
+		s.media[name] = bits
+
+		return map[string]interface{}{"id": 7, "file": name, "url": s.URL + "/usr/uploads/" + name, "type": media["type"]}, 0, ""
+	default:
+		return nil, -32601, "server error. requested method " + method + " does not exist."
+	}
+}
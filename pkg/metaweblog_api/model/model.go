@@ -0,0 +1,94 @@
+package model
+
+import (
+	"time"
+
+	"github.com/ray31245/seo_cluster/pkg/util"
+)
+
+// Authentication is the credential passed to every xml-rpc method
+type Authentication struct {
+	UserName string
+	Password string
+}
+
+// ----response----
+
+type Blog struct {
+	BlogID   util.NumberString `json:"blogid"`
+	BlogName string            `json:"blogName"`
+	URL      string            `json:"url"`
+	IsAdmin  bool              `json:"isAdmin"`
+}
+
+type Category struct {
+	CategoryID util.NumberString `json:"categoryId"`
+	// CategoryName is returned by wordpress and typecho
+	CategoryName string `json:"categoryName"`
+	// Title is the name of category by metaweblog spec
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	HTMLURL     string `json:"htmlUrl"`
+	RSSURL      string `json:"rssUrl"`
+}
+
+// Name return the name of category, CategoryName first
+func (c Category) Name() string {
+	if c.CategoryName != "" {
+		return c.CategoryName
+	}
+
+	return c.Title
+}
+
+type Post struct {
+	PostID      util.NumberString `json:"postid"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Link        string            `json:"link"`
+	DateCreated time.Time         `json:"dateCreated"`
+	// Categories are the names of categories
+	Categories []string `json:"categories"`
+	// Keywords are the tags separated by comma
+	Keywords string `json:"mt_keywords"`
+}
+
+type MediaObjectResponse struct {
+	ID   util.NumberString `json:"id"`
+	File string            `json:"file"`
+	URL  string            `json:"url"`
+	Type string            `json:"type"`
+}
+
+// ----request----
+
+type NewPostRequest struct {
+	Title       string
+	Description string
+	// Categories are the names of categories
+	Categories []string
+	// Keywords are the tags separated by comma
+	Keywords    string
+	DateCreated *time.Time
+	Publish     bool
+}
+
+type EditPostRequest struct {
+	PostID string
+	NewPostRequest
+}
+
+type NewMediaObjectRequest struct {
+	Name string
+	// Type is the mime type of media
+	Type string
+	Bits []byte
+	// Overwrite the file with the same name
+	Overwrite bool
+}
+
+type NewCategoryRequest struct {
+	Name        string
+	Description string
+	ParentID    int
+}
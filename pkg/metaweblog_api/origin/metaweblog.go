@@ -0,0 +1,177 @@
+package origin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ray31245/seo_cluster/pkg/metaweblog_api/model"
+	"github.com/ray31245/seo_cluster/pkg/util"
+)
+
+const (
+	MethodGetUsersBlogs  = "blogger.getUsersBlogs"
+	MethodDeletePost     = "blogger.deletePost"
+	MethodGetCategories  = "metaWeblog.getCategories"
+	MethodNewPost        = "metaWeblog.newPost"
+	MethodEditPost       = "metaWeblog.editPost"
+	MethodGetPost        = "metaWeblog.getPost"
+	MethodGetRecentPosts = "metaWeblog.getRecentPosts"
+	MethodNewMediaObject = "metaWeblog.newMediaObject"
+	MethodNewCategory    = "wp.newCategory"
+
+	// appKey is ignored by most of servers, but blogger methods require it
+	appKey = ""
+)
+
+func GetUsersBlogs(ctx context.Context, httpClient *http.Client, endpoint string, auth model.Authentication) ([]model.Blog, error) {
+	res, err := doRequest(ctx, httpClient, endpoint, MethodGetUsersBlogs, appKey, auth.UserName, auth.Password)
+	if err != nil {
+		return nil, fmt.Errorf("get users blogs error: %w", err)
+	}
+
+	resData := []model.Blog{}
+	if err := decodeValue(res, &resData); err != nil {
+		return nil, fmt.Errorf("get users blogs error: %w", err)
+	}
+
+	return resData, nil
+}
+
+func GetCategories(ctx context.Context, httpClient *http.Client, endpoint string, auth model.Authentication, blogID string) ([]model.Category, error) {
+	res, err := doRequest(ctx, httpClient, endpoint, MethodGetCategories, blogID, auth.UserName, auth.Password)
+	if err != nil {
+		return nil, fmt.Errorf("get categories error: %w", err)
+	}
+
+	resData := []model.Category{}
+	if err := decodeValue(res, &resData); err != nil {
+		return nil, fmt.Errorf("get categories error: %w", err)
+	}
+
+	return resData, nil
+}
+
+// NewCategory create category by wordpress extension, which is also supported by typecho
+func NewCategory(ctx context.Context, httpClient *http.Client, endpoint string, auth model.Authentication, blogID string, req model.NewCategoryRequest) (string, error) {
+	category := map[string]interface{}{
+		"name":        req.Name,
+		"description": req.Description,
+		"parent_id":   req.ParentID,
+	}
+
+	res, err := doRequest(ctx, httpClient, endpoint, MethodNewCategory, blogID, auth.UserName, auth.Password, category)
+	if err != nil {
+		return "", fmt.Errorf("new category error: %w", err)
+	}
+
+	var categoryID util.NumberString
+	if err := decodeValue(res, &categoryID); err != nil {
+		return "", fmt.Errorf("new category error: %w", err)
+	}
+
+	return string(categoryID), nil
+}
+
+func NewPost(ctx context.Context, httpClient *http.Client, endpoint string, auth model.Authentication, blogID string, req model.NewPostRequest) (string, error) {
+	res, err := doRequest(ctx, httpClient, endpoint, MethodNewPost, blogID, auth.UserName, auth.Password, postStruct(req), req.Publish)
+	if err != nil {
+		return "", fmt.Errorf("new post error: %w", err)
+	}
+
+	var postID util.NumberString
+	if err := decodeValue(res, &postID); err != nil {
+		return "", fmt.Errorf("new post error: %w", err)
+	}
+
+	return string(postID), nil
+}
+
+func EditPost(ctx context.Context, httpClient *http.Client, endpoint string, auth model.Authentication, req model.EditPostRequest) error {
+	_, err := doRequest(ctx, httpClient, endpoint, MethodEditPost, req.PostID, auth.UserName, auth.Password, postStruct(req.NewPostRequest), req.Publish)
+	if err != nil {
+		return fmt.Errorf("edit post error: %w", err)
+	}
+
+	return nil
+}
+
+func GetPost(ctx context.Context, httpClient *http.Client, endpoint string, auth model.Authentication, postID string) (model.Post, error) {
+	res, err := doRequest(ctx, httpClient, endpoint, MethodGetPost, postID, auth.UserName, auth.Password)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("get post error: %w", err)
+	}
+
+	resData := model.Post{}
+	if err := decodeValue(res, &resData); err != nil {
+		return model.Post{}, fmt.Errorf("get post error: %w", err)
+	}
+
+	return resData, nil
+}
+
+func GetRecentPosts(ctx context.Context, httpClient *http.Client, endpoint string, auth model.Authentication, blogID string, numberOfPosts int) ([]model.Post, error) {
+	res, err := doRequest(ctx, httpClient, endpoint, MethodGetRecentPosts, blogID, auth.UserName, auth.Password, numberOfPosts)
+	if err != nil {
+		return nil, fmt.Errorf("get recent posts error: %w", err)
+	}
+
+	resData := []model.Post{}
+	if err := decodeValue(res, &resData); err != nil {
+		return nil, fmt.Errorf("get recent posts error: %w", err)
+	}
+
+	return resData, nil
+}
+
+func DeletePost(ctx context.Context, httpClient *http.Client, endpoint string, auth model.Authentication, postID string) error {
+	_, err := doRequest(ctx, httpClient, endpoint, MethodDeletePost, appKey, postID, auth.UserName, auth.Password, true)
+	if err != nil {
+		return fmt.Errorf("delete post error: %w", err)
+	}
+
+	return nil
+}
+
+func NewMediaObject(ctx context.Context, httpClient *http.Client, endpoint string, auth model.Authentication, blogID string, req model.NewMediaObjectRequest) (model.MediaObjectResponse, error) {
+	media := map[string]interface{}{
+		"name":      req.Name,
+		"type":      req.Type,
+		"bits":      Base64(req.Bits),
+		"overwrite": req.Overwrite,
+	}
+
+	res, err := doRequest(ctx, httpClient, endpoint, MethodNewMediaObject, blogID, auth.UserName, auth.Password, media)
+	if err != nil {
+		return model.MediaObjectResponse{}, fmt.Errorf("new media object error: %w", err)
+	}
+
+	resData := model.MediaObjectResponse{}
+	if err := decodeValue(res, &resData); err != nil {
+		return model.MediaObjectResponse{}, fmt.Errorf("new media object error: %w", err)
+	}
+
+	return resData, nil
+}
+
+// postStruct convert post request to the struct param of newPost and editPost
+func postStruct(req model.NewPostRequest) map[string]interface{} {
+	res := map[string]interface{}{
+		"title":       req.Title,
+		"description": req.Description,
+	}
+
+	if len(req.Categories) > 0 {
+		res["categories"] = req.Categories
+	}
+
+	if req.Keywords != "" {
+		res["mt_keywords"] = req.Keywords
+	}
+
+	if req.DateCreated != nil {
+		res["dateCreated"] = *req.DateCreated
+	}
+
+	return res
+}
@@ -0,0 +1,405 @@
+package origin
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metaWeblogErr "github.com/ray31245/seo_cluster/pkg/metaweblog_api/error"
+	"github.com/ray31245/seo_cluster/pkg/util"
+)
+
+// dateTimeLayouts are the layouts of dateTime.iso8601 seen in the wild
+var dateTimeLayouts = []string{
+	"20060102T15:04:05",
+	"20060102T15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+	"20060102T150405",
+	"20060102T150405Z07:00",
+}
+
+// Base64 is the binary data encoded as base64 in xml-rpc
+type Base64 []byte
+
+// doRequest call the xml-rpc method of endpoint by httpClient and return the decoded value of first param
+func doRequest(ctx context.Context, httpClient *http.Client, endpoint string, method string, params ...interface{}) (interface{}, error) {
+	body, err := encodeMethodCall(method, params...)
+	if err != nil {
+		return nil, fmt.Errorf("encode method call error: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("new request error: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "text/xml; charset=utf-8")
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request error: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response error: %w", err)
+	}
+
+	statusCodeErr := metaWeblogErr.NewHTTPStatusCodeError(res.StatusCode)
+	if statusCodeErr != nil {
+		return nil, fmt.Errorf("request error: %w with message: %s", statusCodeErr, resBody)
+	}
+
+	resData, err := decodeMethodResponse(resBody)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w", method, err)
+	}
+
+	return resData, nil
+}
+
+// decodeValue convert the decoded xml-rpc value to out by json tags of out
+func decodeValue(value interface{}, out interface{}) error {
+	bytesData, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal error: %w", err)
+	}
+
+	if err := json.Unmarshal(bytesData, out); err != nil {
+		return fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	return nil
+}
+
+func encodeMethodCall(method string, params ...interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	buf.WriteString(xml.Header)
+	buf.WriteString("<methodCall><methodName>")
+
+	if err := xml.EscapeText(buf, []byte(method)); err != nil {
+		return nil, fmt.Errorf("escape method name error: %w", err)
+	}
+
+	buf.WriteString("</methodName><params>")
+
+	for _, param := range params {
+		buf.WriteString("<param>")
+
+		if err := encodeValue(buf, param); err != nil {
+			return nil, err
+		}
+
+		buf.WriteString("</param>")
+	}
+
+	buf.WriteString("</params></methodCall>")
+
+	return buf.Bytes(), nil
+}
+
+func encodeValue(buf *bytes.Buffer, value interface{}) error {
+	buf.WriteString("<value>")
+
+	switch v := value.(type) {
+	case string:
+		buf.WriteString("<string>")
+
+		if err := xml.EscapeText(buf, []byte(v)); err != nil {
+			return fmt.Errorf("escape string error: %w", err)
+		}
+
+		buf.WriteString("</string>")
+	case int:
+		buf.WriteString("<int>" + strconv.Itoa(v) + "</int>")
+	case bool:
+		boolean := "0"
+		if v {
+			boolean = "1"
+		}
+
+		buf.WriteString("<boolean>" + boolean + "</boolean>")
+	case float64:
+		buf.WriteString("<double>" + strconv.FormatFloat(v, 'f', -1, 64) + "</double>")
+	case time.Time:
+		buf.WriteString("<dateTime.iso8601>" + v.UTC().Format(dateTimeLayouts[0]) + "</dateTime.iso8601>")
+	case Base64:
+		buf.WriteString("<base64>" + base64.StdEncoding.EncodeToString(v) + "</base64>")
+	case []string:
+		buf.WriteString("<array><data>")
+
+		for _, item := range v {
+			if err := encodeValue(buf, item); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteString("</data></array>")
+	case []interface{}:
+		buf.WriteString("<array><data>")
+
+		for _, item := range v {
+			if err := encodeValue(buf, item); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteString("</data></array>")
+	case map[string]interface{}:
+		// sort the members to keep the request stable
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		buf.WriteString("<struct>")
+
+		for _, name := range names {
+			buf.WriteString("<member><name>")
+
+			if err := xml.EscapeText(buf, []byte(name)); err != nil {
+				return fmt.Errorf("escape member name error: %w", err)
+			}
+
+			buf.WriteString("</name>")
+
+			if err := encodeValue(buf, v[name]); err != nil {
+				return err
+			}
+
+			buf.WriteString("</member>")
+		}
+
+		buf.WriteString("</struct>")
+	default:
+		return fmt.Errorf("encodeValue: unsupported type %T", value)
+	}
+
+	buf.WriteString("</value>")
+
+	return nil
+}
+
+type xmlValue struct {
+	String   *string    `xml:"string"`
+	Int      *string    `xml:"int"`
+	I4       *string    `xml:"i4"`
+	I8       *string    `xml:"i8"`
+	Boolean  *string    `xml:"boolean"`
+	Double   *string    `xml:"double"`
+	DateTime *string    `xml:"dateTime.iso8601"`
+	Base64   *string    `xml:"base64"`
+	Nil      *struct{}  `xml:"nil"`
+	Struct   *xmlStruct `xml:"struct"`
+	Array    *xmlArray  `xml:"array"`
+	// Text is the value without type, which is string by spec
+	Text string `xml:",chardata"`
+}
+
+type xmlStruct struct {
+	Members []xmlMember `xml:"member"`
+}
+
+type xmlMember struct {
+	Name  string   `xml:"name"`
+	Value xmlValue `xml:"value"`
+}
+
+type xmlArray struct {
+	Values []xmlValue `xml:"data>value"`
+}
+
+type xmlMethodResponse struct {
+	Params []xmlValue `xml:"params>param>value"`
+	Fault  *xmlValue  `xml:"fault>value"`
+}
+
+type xmlMethodCall struct {
+	MethodName string     `xml:"methodName"`
+	Params     []xmlValue `xml:"params>param>value"`
+}
+
+func decodeMethodResponse(data []byte) (interface{}, error) {
+	res := xmlMethodResponse{}
+	if err := xml.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("unmarshal xml error: %w", err)
+	}
+
+	if res.Fault != nil {
+		fault, err := res.Fault.decode()
+		if err != nil {
+			return nil, fmt.Errorf("decode fault error: %w", err)
+		}
+
+		faultData := struct {
+			Code    util.StringNumber `json:"faultCode"`
+			Message string            `json:"faultString"`
+		}{}
+		if err := decodeValue(fault, &faultData); err != nil {
+			return nil, fmt.Errorf("decode fault error: %w", err)
+		}
+
+		return nil, metaWeblogErr.FaultError{Code: int(faultData.Code), Message: faultData.Message}
+	}
+
+	if len(res.Params) == 0 {
+		return nil, nil
+	}
+
+	return res.Params[0].decode()
+}
+
+// DecodeMethodCall decode the method name and params of xml-rpc request,
+// it is used by the stand-in server in tests
+func DecodeMethodCall(data []byte) (string, []interface{}, error) {
+	call := xmlMethodCall{}
+	if err := xml.Unmarshal(data, &call); err != nil {
+		return "", nil, fmt.Errorf("DecodeMethodCall: %w", err)
+	}
+
+	params := make([]interface{}, 0, len(call.Params))
+
+	for _, param := range call.Params {
+		value, err := param.decode()
+		if err != nil {
+			return "", nil, fmt.Errorf("DecodeMethodCall: %w", err)
+		}
+
+		params = append(params, value)
+	}
+
+	return call.MethodName, params, nil
+}
+
+// EncodeMethodResponse encode the value as xml-rpc response,
+// it is used by the stand-in server in tests
+func EncodeMethodResponse(value interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	buf.WriteString(xml.Header)
+	buf.WriteString("<methodResponse><params><param>")
+
+	if err := encodeValue(buf, value); err != nil {
+		return nil, fmt.Errorf("EncodeMethodResponse: %w", err)
+	}
+
+	buf.WriteString("</param></params></methodResponse>")
+
+	return buf.Bytes(), nil
+}
+
+// EncodeFaultResponse encode the fault as xml-rpc response,
+// it is used by the stand-in server in tests
+func EncodeFaultResponse(code int, message string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	buf.WriteString(xml.Header)
+	buf.WriteString("<methodResponse><fault>")
+
+	if err := encodeValue(buf, map[string]interface{}{"faultCode": code, "faultString": message}); err != nil {
+		return nil, fmt.Errorf("EncodeFaultResponse: %w", err)
+	}
+
+	buf.WriteString("</fault></methodResponse>")
+
+	return buf.Bytes(), nil
+}
+
+func (v xmlValue) decode() (interface{}, error) {
+	switch {
+	case v.String != nil:
+		return *v.String, nil
+	case v.Int != nil, v.I4 != nil, v.I8 != nil:
+		raw := firstNotNil(v.Int, v.I4, v.I8)
+
+		res, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("decode int error: %w", err)
+		}
+
+		return res, nil
+	case v.Boolean != nil:
+		return strings.TrimSpace(*v.Boolean) == "1", nil
+	case v.Double != nil:
+		res, err := strconv.ParseFloat(strings.TrimSpace(*v.Double), 64)
+		if err != nil {
+			return nil, fmt.Errorf("decode double error: %w", err)
+		}
+
+		return res, nil
+	case v.DateTime != nil:
+		return parseDateTime(strings.TrimSpace(*v.DateTime))
+	case v.Base64 != nil:
+		res, err := base64.StdEncoding.DecodeString(strings.TrimSpace(*v.Base64))
+		if err != nil {
+			return nil, fmt.Errorf("decode base64 error: %w", err)
+		}
+
+		return res, nil
+	case v.Nil != nil:
+		return nil, nil
+	case v.Struct != nil:
+		res := make(map[string]interface{}, len(v.Struct.Members))
+
+		for _, member := range v.Struct.Members {
+			value, err := member.Value.decode()
+			if err != nil {
+				return nil, fmt.Errorf("decode member %s error: %w", member.Name, err)
+			}
+
+			res[member.Name] = value
+		}
+
+		return res, nil
+	case v.Array != nil:
+		res := make([]interface{}, 0, len(v.Array.Values))
+
+		for _, item := range v.Array.Values {
+			value, err := item.decode()
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, value)
+		}
+
+		return res, nil
+	default:
+		return v.Text, nil
+	}
+}
+
+func firstNotNil(values ...*string) string {
+	for _, v := range values {
+		if v != nil {
+			return *v
+		}
+	}
+
+	return ""
+}
+
+func parseDateTime(str string) (time.Time, error) {
+	for _, layout := range dateTimeLayouts {
+		if res, err := time.Parse(layout, str); err == nil {
+			return res, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("parse dateTime.iso8601 error: %s", str)
+}
@@ -0,0 +1,299 @@
+package secret
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeySize is the size of master key and data key, AES-256 is used
+const KeySize = 32
+
+// DefaultKeyID is the id of master key from SECRET_KEY when SECRET_KEY_ID is not set
+const DefaultKeyID = "default"
+
+var (
+	ErrInvalidKey       = errors.New("invalid secret key")
+	ErrKeyNotFound      = errors.New("secret key not found")
+	ErrInvalidEnvelope  = errors.New("invalid secret envelope")
+	ErrKeyNotConfigured = errors.New("secret key is not configured")
+)
+
+// Envelope is a secret encrypted by its own data key, the data key is encrypted by the master key of KeyID.
+// All fields are base64 encoded.
+type Envelope struct {
+	KeyID      string
+	DataKey    string
+	Ciphertext string
+}
+
+// Keyring keep the master keys by key id, new secrets are encrypted by the primary key
+// and the others are kept to decrypt the secrets encrypted before rotation.
+type Keyring struct {
+	primaryID string
+	keys      map[string]cipher.AEAD
+}
+
+func NewKeyring(primaryID string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[primaryID]; !ok {
+		return nil, fmt.Errorf("NewKeyring: %w: %q", ErrKeyNotFound, primaryID)
+	}
+
+	res := &Keyring{primaryID: primaryID, keys: make(map[string]cipher.AEAD, len(keys))}
+
+	for ID, key := range keys {
+		if ID == "" {
+			return nil, fmt.Errorf("NewKeyring: %w: empty key id", ErrInvalidKey)
+		}
+
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("NewKeyring: key %q: %w", ID, err)
+		}
+
+		res.keys[ID] = aead
+	}
+
+	return res, nil
+}
+
+// LoadKeyring load the master keys from environment, it return nil keyring if no key is configured.
+//
+// SECRET_KEY_FILE is the file of keys, one "<key id>=<base64 key>" per line, lines starting with # are ignored.
+// Otherwise SECRET_KEY is the base64 key with id DefaultKeyID.
+// SECRET_KEY_ID choose the primary key, default is the last key in file.
+func LoadKeyring() (*Keyring, error) {
+	primaryID := os.Getenv("SECRET_KEY_ID")
+
+	if path, ok := os.LookupEnv("SECRET_KEY_FILE"); ok {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("LoadKeyring: %w", err)
+		}
+
+		keys, lastID, err := ParseKeyFile(content)
+		if err != nil {
+			return nil, fmt.Errorf("LoadKeyring: %w", err)
+		}
+
+		if primaryID == "" {
+			primaryID = lastID
+		}
+
+		keyring, err := NewKeyring(primaryID, keys)
+		if err != nil {
+			return nil, fmt.Errorf("LoadKeyring: %w", err)
+		}
+
+		return keyring, nil
+	}
+
+	encoded, ok := os.LookupEnv("SECRET_KEY")
+	if !ok {
+		return nil, nil //nolint:nilnil // nil keyring means encryption is disabled
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("LoadKeyring: %w: %w", ErrInvalidKey, err)
+	}
+
+	if primaryID == "" {
+		primaryID = DefaultKeyID
+	}
+
+	keyring, err := NewKeyring(primaryID, map[string][]byte{primaryID: key})
+	if err != nil {
+		return nil, fmt.Errorf("LoadKeyring: %w", err)
+	}
+
+	return keyring, nil
+}
+
+// ParseKeyFile parse the content of key file, it return the keys and the id of last key
+func ParseKeyFile(content []byte) (map[string][]byte, string, error) {
+	keys := make(map[string][]byte)
+	lastID := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		ID, encoded, ok := strings.Cut(line, "=")
+		ID = strings.TrimSpace(ID)
+
+		if !ok || ID == "" {
+			// the line is not in error, it may contain the key
+			return nil, "", fmt.Errorf("ParseKeyFile: %w: line without key id", ErrInvalidKey)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, "", fmt.Errorf("ParseKeyFile: %w: key %q: %w", ErrInvalidKey, ID, err)
+		}
+
+		keys[ID] = key
+		lastID = ID
+	}
+
+	if lastID == "" {
+		return nil, "", fmt.Errorf("ParseKeyFile: %w: no key", ErrInvalidKey)
+	}
+
+	return keys, lastID, nil
+}
+
+// GenerateKey return a random base64 master key
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+
+	_, err := rand.Read(key)
+	if err != nil {
+		return "", fmt.Errorf("GenerateKey: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func (k *Keyring) PrimaryID() string {
+	return k.primaryID
+}
+
+// Seal encrypt plaintext by a new data key, and the data key by the primary key
+func (k *Keyring) Seal(plaintext string) (Envelope, error) {
+	dataKey := make([]byte, KeySize)
+
+	_, err := rand.Read(dataKey)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("Seal: %w", err)
+	}
+
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("Seal: %w", err)
+	}
+
+	ciphertext, err := seal(dataAEAD, []byte(plaintext), nil)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("Seal: %w", err)
+	}
+
+	// key id is authenticated, so the data key can not be moved to another key id
+	wrappedKey, err := seal(k.keys[k.primaryID], dataKey, []byte(k.primaryID))
+	if err != nil {
+		return Envelope{}, fmt.Errorf("Seal: %w", err)
+	}
+
+	return Envelope{
+		KeyID:      k.primaryID,
+		DataKey:    base64.StdEncoding.EncodeToString(wrappedKey),
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+	}, nil
+}
+
+// Open decrypt the envelope sealed by any key of keyring
+func (k *Keyring) Open(envelope Envelope) (string, error) {
+	masterAEAD, ok := k.keys[envelope.KeyID]
+	if !ok {
+		return "", fmt.Errorf("Open: %w: %q", ErrKeyNotFound, envelope.KeyID)
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(envelope.DataKey)
+	if err != nil {
+		return "", fmt.Errorf("Open: %w: %w", ErrInvalidEnvelope, err)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(envelope.Ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("Open: %w: %w", ErrInvalidEnvelope, err)
+	}
+
+	dataKey, err := open(masterAEAD, wrappedKey, []byte(envelope.KeyID))
+	if err != nil {
+		return "", fmt.Errorf("Open: %w", err)
+	}
+
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", fmt.Errorf("Open: %w: %w", ErrInvalidEnvelope, err)
+	}
+
+	plaintext, err := open(dataAEAD, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("Open: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+type contextKey struct{}
+
+// NewContext return the context carrying keyring, it is used by db models to encrypt and decrypt secrets
+func NewContext(ctx context.Context, keyring *Keyring) context.Context {
+	return context.WithValue(ctx, contextKey{}, keyring)
+}
+
+// FromContext return the keyring in ctx, or nil if there is none
+func FromContext(ctx context.Context) *Keyring {
+	if ctx == nil {
+		return nil
+	}
+
+	keyring, _ := ctx.Value(contextKey{}).(*Keyring)
+
+	return keyring
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("%w: key size is %d, expect %d", ErrInvalidKey, len(key), KeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	return aead, nil
+}
+
+// seal return the random nonce followed by ciphertext
+func seal(aead cipher.AEAD, plaintext []byte, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("seal: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, data []byte, additionalData []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("open: %w: too short", ErrInvalidEnvelope)
+	}
+
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w: %w", ErrInvalidEnvelope, err)
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,150 @@
+package secret_test
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ray31245/seo_cluster/pkg/secret"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKey(t *testing.T) []byte {
+	t.Helper()
+
+	encoded, err := secret.GenerateKey()
+	require.NoError(t, err)
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	require.NoError(t, err)
+
+	return key
+}
+
+func TestKeyring_SealOpen(t *testing.T) {
+	oldKey, newKeyBytes := newKey(t), newKey(t)
+
+	oldKeyring, err := secret.NewKeyring("old", map[string][]byte{"old": oldKey})
+	require.NoError(t, err)
+
+	envelope, err := oldKeyring.Seal("password")
+	require.NoError(t, err)
+	assert.Equal(t, "old", envelope.KeyID)
+	assert.NotContains(t, envelope.Ciphertext, "password")
+
+	plaintext, err := oldKeyring.Open(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, "password", plaintext)
+
+	t.Run("old key is kept after rotation", func(t *testing.T) {
+		keyring, err := secret.NewKeyring("new", map[string][]byte{"old": oldKey, "new": newKeyBytes})
+		require.NoError(t, err)
+
+		plaintext, err := keyring.Open(envelope)
+		require.NoError(t, err)
+		assert.Equal(t, "password", plaintext)
+
+		rotated, err := keyring.Seal(plaintext)
+		require.NoError(t, err)
+		assert.Equal(t, "new", rotated.KeyID)
+	})
+
+	t.Run("key is removed", func(t *testing.T) {
+		keyring, err := secret.NewKeyring("new", map[string][]byte{"new": newKeyBytes})
+		require.NoError(t, err)
+
+		_, err = keyring.Open(envelope)
+		require.ErrorIs(t, err, secret.ErrKeyNotFound)
+	})
+
+	t.Run("data key is moved to another key id", func(t *testing.T) {
+		keyring, err := secret.NewKeyring("new", map[string][]byte{"new": oldKey})
+		require.NoError(t, err)
+
+		moved := envelope
+		moved.KeyID = "new"
+
+		_, err = keyring.Open(moved)
+		require.ErrorIs(t, err, secret.ErrInvalidEnvelope)
+	})
+
+	t.Run("ciphertext is modified", func(t *testing.T) {
+		other, err := oldKeyring.Seal("another")
+		require.NoError(t, err)
+
+		modified := envelope
+		modified.Ciphertext = other.Ciphertext
+
+		_, err = oldKeyring.Open(modified)
+		require.ErrorIs(t, err, secret.ErrInvalidEnvelope)
+	})
+}
+
+func TestNewKeyring(t *testing.T) {
+	_, err := secret.NewKeyring("a", map[string][]byte{"b": newKey(t)})
+	require.ErrorIs(t, err, secret.ErrKeyNotFound)
+
+	_, err = secret.NewKeyring("a", map[string][]byte{"a": []byte("short")})
+	require.ErrorIs(t, err, secret.ErrInvalidKey)
+}
+
+func TestLoadKeyring(t *testing.T) {
+	oldKey, newKeyBytes := newKey(t), newKey(t)
+
+	t.Run("not configured", func(t *testing.T) {
+		for _, key := range []string{"SECRET_KEY", "SECRET_KEY_FILE"} {
+			// Setenv restore the variable after test
+			t.Setenv(key, "")
+			os.Unsetenv(key)
+		}
+
+		keyring, err := secret.LoadKeyring()
+		require.NoError(t, err)
+		assert.Nil(t, keyring)
+	})
+
+	t.Run("key from env", func(t *testing.T) {
+		t.Setenv("SECRET_KEY", base64.StdEncoding.EncodeToString(oldKey))
+
+		keyring, err := secret.LoadKeyring()
+		require.NoError(t, err)
+		assert.Equal(t, secret.DefaultKeyID, keyring.PrimaryID())
+	})
+
+	t.Run("keys from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keys")
+		content := "# keys of seo cluster\nold=" + base64.StdEncoding.EncodeToString(oldKey) + "\n\nnew=" + base64.StdEncoding.EncodeToString(newKeyBytes) + "\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		t.Setenv("SECRET_KEY_FILE", path)
+
+		keyring, err := secret.LoadKeyring()
+		require.NoError(t, err)
+		assert.Equal(t, "new", keyring.PrimaryID())
+
+		t.Setenv("SECRET_KEY_ID", "old")
+
+		keyring, err = secret.LoadKeyring()
+		require.NoError(t, err)
+		assert.Equal(t, "old", keyring.PrimaryID())
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keys")
+		require.NoError(t, os.WriteFile(path, []byte("no key id\n"), 0o600))
+		t.Setenv("SECRET_KEY_FILE", path)
+
+		_, err := secret.LoadKeyring()
+		require.ErrorIs(t, err, secret.ErrInvalidKey)
+	})
+}
+
+func TestContext(t *testing.T) {
+	keyring, err := secret.NewKeyring("a", map[string][]byte{"a": newKey(t)})
+	require.NoError(t, err)
+
+	assert.Nil(t, secret.FromContext(context.Background()))
+	assert.Same(t, keyring, secret.FromContext(secret.NewContext(context.Background(), keyring)))
+}
@@ -0,0 +1,70 @@
+package staticsite
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	staticSiteInterface "github.com/ray31245/seo_cluster/pkg/static_site/static_site_interface"
+)
+
+// StaticSiteAPI is a struct to implement StaticSiteAPI interface
+var _ staticSiteInterface.StaticSiteAPI = &StaticSiteAPI{}
+
+// StaticSiteAPI keep the clients of static sites,
+// the url of site is the local directory and user name is the git author, password is not used
+type StaticSiteAPI struct {
+	lock sync.Mutex
+	// key is site id
+	clientPool map[uuid.UUID]*Client
+	// baseDir contain the roots of sites, the root outside it is rejected
+	baseDir string
+}
+
+func NewStaticSiteAPI(baseDir string) *StaticSiteAPI {
+	return &StaticSiteAPI{
+		clientPool: make(map[uuid.UUID]*Client),
+		baseDir:    baseDir,
+	}
+}
+
+func (t *StaticSiteAPI) GetClient(ctx context.Context, ID uuid.UUID, root string, userName string, password string) (staticSiteInterface.StaticSiteClient, error) {
+	t.lock.Lock()
+	client, ok := t.clientPool[ID]
+	t.lock.Unlock()
+
+	if ok {
+		return client, nil
+	}
+
+	return t.UpdateClient(ctx, ID, root, userName, password)
+}
+
+func (t *StaticSiteAPI) UpdateClient(ctx context.Context, ID uuid.UUID, root string, userName string, _ string) (staticSiteInterface.StaticSiteClient, error) {
+	client, err := NewClient(ctx, t.baseDir, root, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	t.lock.Lock()
+	t.clientPool[ID] = client
+	t.lock.Unlock()
+
+	return client, nil
+}
+
+func (t *StaticSiteAPI) DeleteClient(ID uuid.UUID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.clientPool, ID)
+}
+
+func (t *StaticSiteAPI) NewClient(ctx context.Context, root string, userName string, _ string) (staticSiteInterface.StaticSiteClient, error) {
+	return NewClient(ctx, t.baseDir, root, userName)
+}
+
+func (t *StaticSiteAPI) NewAnonymousClient(ctx context.Context, root string) staticSiteInterface.StaticSiteClient {
+	return NewAnonymousClient(ctx, t.baseDir, root)
+}
@@ -0,0 +1,559 @@
+package staticsite
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	"github.com/ray31245/seo_cluster/pkg/static_site/model"
+)
+
+const (
+	// ManifestFile keep the categories at the root of site
+	ManifestFile = ".seo_cluster.json"
+	postExt      = ".md"
+	// hexo is detected by its config and source directory
+	hexoConfigFile = "_config.yml"
+	hexoSourceDir  = "source"
+)
+
+var (
+	ErrNotDirectory = errors.New("site root is not a directory")
+	// ErrBaseDirNotSet is returned when no base directory is set to place the static sites
+	ErrBaseDirNotSet = errors.New("base directory of static sites is not set")
+	// ErrRootOutsideBaseDir is returned when the root of site does not resolve under the base directory
+	ErrRootOutsideBaseDir = errors.New("site root is outside base directory")
+	ErrPostNotFound       = errors.New("post not found")
+	// ErrInvalidPostID is returned when the id is not a file name in post directory
+	ErrInvalidPostID = errors.New("invalid post id")
+)
+
+// defaultAuthor commit the posts when the author of site is not set
+var defaultAuthor = model.Author{Name: "seo_cluster", Email: "seo_cluster@localhost"}
+
+// rootLocks avoid concurrent write of the same root, so the clients of sites sharing the root
+// or the replaced client in pool do not use the same git index at once
+var rootLocks = struct {
+	sync.Mutex
+	// key is the resolved root
+	locks map[string]*sync.Mutex
+}{locks: map[string]*sync.Mutex{}}
+
+func rootLock(root string) *sync.Mutex {
+	rootLocks.Lock()
+	defer rootLocks.Unlock()
+
+	lock, ok := rootLocks.locks[root]
+	if !ok {
+		lock = &sync.Mutex{}
+		rootLocks.locks[root] = lock
+	}
+
+	return lock
+}
+
+// Client write posts of the static site into the local directory,
+// the changes are committed if the directory is a git working tree
+type Client struct {
+	root   string
+	layout model.Layout
+	author model.Author
+	isGit  bool
+	// lock of root shared by clients
+	lock *sync.Mutex
+	// err is the error of resolving root of anonymous client, it is returned by every method
+	err error
+}
+
+// NewClient open the static site at root which must resolve under baseDir,
+// author is in format of "Name <email>" or just name
+func NewClient(ctx context.Context, baseDir string, root string, author string) (*Client, error) {
+	slog.InfoContext(ctx, "open static site", slog.String("root", root))
+
+	root, err := resolveRoot(baseDir, root)
+	if err != nil {
+		return nil, fmt.Errorf("NewClient: %w", err)
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("NewClient: %w", err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("NewClient: %w: %s", ErrNotDirectory, root)
+	}
+
+	res := &Client{
+		root:   root,
+		layout: detectLayout(root),
+		author: parseAuthor(author),
+		isGit:  exists(filepath.Join(root, ".git")),
+		lock:   rootLock(root),
+	}
+
+	if err := os.MkdirAll(res.postDir(), 0o755); err != nil { //nolint:mnd
+		return nil, fmt.Errorf("NewClient: %w", err)
+	}
+
+	return res, nil
+}
+
+// NewAnonymousClient return a client without checking the directory, it is used to read posts only.
+// The root must resolve under baseDir too, otherwise every method return the error
+func NewAnonymousClient(ctx context.Context, baseDir string, root string) *Client {
+	root, err := resolveRoot(baseDir, root)
+	if err != nil {
+		return &Client{err: fmt.Errorf("NewAnonymousClient: %w", err)}
+	}
+
+	return &Client{
+		root:   root,
+		layout: detectLayout(root),
+		author: defaultAuthor,
+		isGit:  exists(filepath.Join(root, ".git")),
+		lock:   rootLock(root),
+	}
+}
+
+func (c *Client) Layout() model.Layout {
+	return c.layout
+}
+
+func (c *Client) IsGit() bool {
+	return c.isGit
+}
+
+// ListCategory return the categories of manifest and the categories used by posts
+func (c *Client) ListCategory(ctx context.Context) ([]string, error) {
+	unlock, err := c.lockRoot()
+	if err != nil {
+		return nil, fmt.Errorf("ListCategory: %w", err)
+	}
+	defer unlock()
+
+	manifest, err := c.readManifest()
+	if err != nil {
+		return nil, fmt.Errorf("ListCategory: %w", err)
+	}
+
+	posts, err := c.listPost()
+	if err != nil {
+		return nil, fmt.Errorf("ListCategory: %w", err)
+	}
+
+	res := slices.Clone(manifest.Categories)
+
+	used := []string{}
+	for _, post := range posts {
+		used = append(used, post.Categories...)
+	}
+
+	slices.Sort(used)
+
+	for _, name := range slices.Compact(used) {
+		if !slices.Contains(res, name) {
+			res = append(res, name)
+		}
+	}
+
+	return res, nil
+}
+
+// CreateCategory add category to manifest, so it is listed before any post use it
+func (c *Client) CreateCategory(ctx context.Context, name string) error {
+	unlock, err := c.lockRoot()
+	if err != nil {
+		return fmt.Errorf("CreateCategory: %w", err)
+	}
+	defer unlock()
+
+	manifest, err := c.readManifest()
+	if err != nil {
+		return fmt.Errorf("CreateCategory: %w", err)
+	}
+
+	if slices.Contains(manifest.Categories, name) {
+		return nil
+	}
+
+	manifest.Categories = append(manifest.Categories, name)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("CreateCategory: %w", err)
+	}
+
+	err = c.writeAndCommit(ctx, ManifestFile, data, fmt.Sprintf("add category %s", name))
+	if err != nil {
+		return fmt.Errorf("CreateCategory: %w", err)
+	}
+
+	return nil
+}
+
+// ListTag return the tags used by posts with count of posts
+func (c *Client) ListTag(ctx context.Context) ([]model.Tag, error) {
+	unlock, err := c.lockRoot()
+	if err != nil {
+		return nil, fmt.Errorf("ListTag: %w", err)
+	}
+	defer unlock()
+
+	posts, err := c.listPost()
+	if err != nil {
+		return nil, fmt.Errorf("ListTag: %w", err)
+	}
+
+	counts := map[string]int{}
+	for _, post := range posts {
+		for _, tag := range post.Tags {
+			counts[tag]++
+		}
+	}
+
+	res := make([]model.Tag, 0, len(counts))
+	for name, count := range counts {
+		res = append(res, model.Tag{Name: name, Count: count})
+	}
+
+	slices.SortFunc(res, func(a, b model.Tag) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return res, nil
+}
+
+// ListPost return posts order by date desc
+func (c *Client) ListPost(ctx context.Context) ([]model.Post, error) {
+	unlock, err := c.lockRoot()
+	if err != nil {
+		return nil, fmt.Errorf("ListPost: %w", err)
+	}
+	defer unlock()
+
+	res, err := c.listPost()
+	if err != nil {
+		return nil, fmt.Errorf("ListPost: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) GetPost(ctx context.Context, ID string) (model.Post, error) {
+	unlock, err := c.lockRoot()
+	if err != nil {
+		return model.Post{}, fmt.Errorf("GetPost: %w", err)
+	}
+	defer unlock()
+
+	res, err := c.readPost(ID)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("GetPost: %w", err)
+	}
+
+	return res, nil
+}
+
+// CreatePost write the post to a new file and return the post with ID
+func (c *Client) CreatePost(ctx context.Context, post model.Post) (model.Post, error) {
+	unlock, err := c.lockRoot()
+	if err != nil {
+		return model.Post{}, fmt.Errorf("CreatePost: %w", err)
+	}
+	defer unlock()
+
+	if post.Date.IsZero() {
+		post.Date = time.Now()
+	}
+
+	ID, err := newPostID(post.Date)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("CreatePost: %w", err)
+	}
+
+	post.ID = ID
+
+	err = c.writePost(ctx, post, fmt.Sprintf("publish %s", post.Title))
+	if err != nil {
+		return model.Post{}, fmt.Errorf("CreatePost: %w", err)
+	}
+
+	return post, nil
+}
+
+// UpdatePost overwrite the file of existing post
+func (c *Client) UpdatePost(ctx context.Context, post model.Post) error {
+	unlock, err := c.lockRoot()
+	if err != nil {
+		return fmt.Errorf("UpdatePost: %w", err)
+	}
+	defer unlock()
+
+	if err = checkPostID(post.ID); err != nil {
+		return fmt.Errorf("UpdatePost: %w", err)
+	}
+
+	if !exists(c.postPath(post.ID)) {
+		return fmt.Errorf("UpdatePost: %w: %s", ErrPostNotFound, post.ID)
+	}
+
+	err = c.writePost(ctx, post, fmt.Sprintf("update %s", post.Title))
+	if err != nil {
+		return fmt.Errorf("UpdatePost: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) DeletePost(ctx context.Context, ID string) error {
+	unlock, err := c.lockRoot()
+	if err != nil {
+		return fmt.Errorf("DeletePost: %w", err)
+	}
+	defer unlock()
+
+	if err = checkPostID(ID); err != nil {
+		return fmt.Errorf("DeletePost: %w", err)
+	}
+
+	err = os.Remove(c.postPath(ID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("DeletePost: %w: %s", ErrPostNotFound, ID)
+	} else if err != nil {
+		return fmt.Errorf("DeletePost: %w", err)
+	}
+
+	if c.isGit {
+		err = commit(ctx, c.root, c.author, fmt.Sprintf("delete %s", ID), c.relPostPath(ID))
+		if err != nil {
+			return fmt.Errorf("DeletePost: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// lockRoot lock the root of site, it return the error of anonymous client which root is not resolved
+func (c *Client) lockRoot() (func(), error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	c.lock.Lock()
+
+	return c.lock.Unlock, nil
+}
+
+// resolveRoot return the absolute root with symlinks followed, it must be baseDir or under it
+func resolveRoot(baseDir string, root string) (string, error) {
+	if baseDir == "" {
+		return "", fmt.Errorf("resolveRoot: %w", ErrBaseDirNotSet)
+	}
+
+	base, err := evalAbs(baseDir)
+	if err != nil {
+		return "", fmt.Errorf("resolveRoot: %w", err)
+	}
+
+	res, err := evalAbs(root)
+	if err != nil {
+		return "", fmt.Errorf("resolveRoot: %w", err)
+	}
+
+	rel, err := filepath.Rel(base, res)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("resolveRoot: %w: %s", ErrRootOutsideBaseDir, root)
+	}
+
+	return res, nil
+}
+
+func evalAbs(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("evalAbs: %w", err)
+	}
+
+	res, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", fmt.Errorf("evalAbs: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) postDir() string {
+	return filepath.Join(c.root, filepath.FromSlash(c.layout.PostDir()))
+}
+
+func (c *Client) relPostPath(ID string) string {
+	return filepath.Join(filepath.FromSlash(c.layout.PostDir()), ID+postExt)
+}
+
+func (c *Client) postPath(ID string) string {
+	return filepath.Join(c.root, c.relPostPath(ID))
+}
+
+func (c *Client) listPost() ([]model.Post, error) {
+	entries, err := os.ReadDir(c.postDir())
+	if err != nil {
+		return nil, fmt.Errorf("listPost: %w", err)
+	}
+
+	res := []model.Post{}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != postExt {
+			continue
+		}
+
+		post, err := c.readPost(strings.TrimSuffix(entry.Name(), postExt))
+		if err != nil {
+			// skip the post not written by us, e.g. front matter in toml
+			slog.Warn("listPost: skip post", slog.String("file", entry.Name()), logger.Err(err))
+
+			continue
+		}
+
+		res = append(res, post)
+	}
+
+	slices.SortFunc(res, func(a, b model.Post) int {
+		return b.Date.Compare(a.Date)
+	})
+
+	return res, nil
+}
+
+func (c *Client) readPost(ID string) (model.Post, error) {
+	if err := checkPostID(ID); err != nil {
+		return model.Post{}, fmt.Errorf("readPost: %w", err)
+	}
+
+	data, err := os.ReadFile(c.postPath(ID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return model.Post{}, fmt.Errorf("readPost: %w: %s", ErrPostNotFound, ID)
+	} else if err != nil {
+		return model.Post{}, fmt.Errorf("readPost: %w", err)
+	}
+
+	res, err := decodePost(ID, data)
+	if err != nil {
+		return model.Post{}, fmt.Errorf("readPost: %w", err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) writePost(ctx context.Context, post model.Post, message string) error {
+	data, err := encodePost(post)
+	if err != nil {
+		return fmt.Errorf("writePost: %w", err)
+	}
+
+	err = c.writeAndCommit(ctx, c.relPostPath(post.ID), data, message)
+	if err != nil {
+		return fmt.Errorf("writePost: %w", err)
+	}
+
+	return nil
+}
+
+// writeAndCommit write the file relative to root and commit it if the site is git working tree
+func (c *Client) writeAndCommit(ctx context.Context, relPath string, data []byte, message string) error {
+	err := os.WriteFile(filepath.Join(c.root, relPath), data, 0o644) //nolint:mnd
+	if err != nil {
+		return fmt.Errorf("writeAndCommit: %w", err)
+	}
+
+	if !c.isGit {
+		return nil
+	}
+
+	err = commit(ctx, c.root, c.author, message, relPath)
+	if err != nil {
+		return fmt.Errorf("writeAndCommit: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) readManifest() (model.Manifest, error) {
+	res := model.Manifest{}
+
+	data, err := os.ReadFile(filepath.Join(c.root, ManifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return res, nil
+	} else if err != nil {
+		return res, fmt.Errorf("readManifest: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &res); err != nil {
+		return res, fmt.Errorf("readManifest: %w", err)
+	}
+
+	return res, nil
+}
+
+func detectLayout(root string) model.Layout {
+	if exists(filepath.Join(root, hexoConfigFile)) && exists(filepath.Join(root, hexoSourceDir)) {
+		return model.LayoutHexo
+	}
+
+	return model.LayoutHugo
+}
+
+func parseAuthor(author string) model.Author {
+	if author == "" {
+		return defaultAuthor
+	}
+
+	if address, err := mail.ParseAddress(author); err == nil {
+		if address.Name == "" {
+			address.Name = defaultAuthor.Name
+		}
+
+		return model.Author{Name: address.Name, Email: address.Address}
+	}
+
+	return model.Author{Name: author, Email: defaultAuthor.Email}
+}
+
+// newPostID return the id in format of {date}-{random hex}, which is used as file name
+func newPostID(date time.Time) (string, error) {
+	suffix := make([]byte, 4) //nolint:mnd
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("newPostID: %w", err)
+	}
+
+	return date.Format("2006-01-02") + "-" + hex.EncodeToString(suffix), nil
+}
+
+// checkPostID avoid the id escape from post directory
+func checkPostID(ID string) error {
+	if ID == "" || ID != filepath.Base(ID) || strings.HasPrefix(ID, ".") {
+		return fmt.Errorf("%w: %q", ErrInvalidPostID, ID)
+	}
+
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+
+	return err == nil
+}
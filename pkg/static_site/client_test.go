@@ -0,0 +1,210 @@
+package staticsite_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	staticSite "github.com/ray31245/seo_cluster/pkg/static_site"
+	"github.com/ray31245/seo_cluster/pkg/static_site/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initGitRepo(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+
+	out, err := exec.Command("git", "-C", root, "init", "-q").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	return root
+}
+
+func gitLog(t *testing.T, root string) []string {
+	t.Helper()
+
+	out, err := exec.Command("git", "-C", root, "log", "--format=%an <%ae>|%s").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	return strings.Split(strings.TrimSpace(string(out)), "\n")
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("hugo layout", func(t *testing.T) {
+		root := t.TempDir()
+
+		client, err := staticSite.NewClient(context.Background(), filepath.Dir(root), root, "")
+		require.NoError(t, err)
+		assert.Equal(t, model.LayoutHugo, client.Layout())
+		assert.False(t, client.IsGit())
+		assert.DirExists(t, filepath.Join(root, "content", "posts"))
+	})
+
+	t.Run("hexo layout", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, "_config.yml"), []byte("title: test\n"), 0o644))
+		require.NoError(t, os.Mkdir(filepath.Join(root, "source"), 0o755))
+
+		client, err := staticSite.NewClient(context.Background(), filepath.Dir(root), root, "")
+		require.NoError(t, err)
+		assert.Equal(t, model.LayoutHexo, client.Layout())
+		assert.DirExists(t, filepath.Join(root, "source", "_posts"))
+	})
+
+	t.Run("root is not directory", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(file, nil, 0o644))
+
+		_, err := staticSite.NewClient(context.Background(), filepath.Dir(file), file, "")
+		require.ErrorIs(t, err, staticSite.ErrNotDirectory)
+	})
+
+	t.Run("root outside base directory", func(t *testing.T) {
+		base := t.TempDir()
+		outside := t.TempDir()
+
+		_, err := staticSite.NewClient(context.Background(), base, outside, "")
+		require.ErrorIs(t, err, staticSite.ErrRootOutsideBaseDir)
+
+		_, err = staticSite.NewClient(context.Background(), base, filepath.Join(base, ".."), "")
+		require.ErrorIs(t, err, staticSite.ErrRootOutsideBaseDir)
+
+		// symlink under base is followed
+		link := filepath.Join(base, "link")
+		require.NoError(t, os.Symlink(outside, link))
+
+		_, err = staticSite.NewClient(context.Background(), base, link, "")
+		require.ErrorIs(t, err, staticSite.ErrRootOutsideBaseDir)
+
+		_, err = staticSite.NewAnonymousClient(context.Background(), base, link).ListPost(context.Background())
+		require.ErrorIs(t, err, staticSite.ErrRootOutsideBaseDir)
+
+		_, err = staticSite.NewClient(context.Background(), "", outside, "")
+		require.ErrorIs(t, err, staticSite.ErrBaseDirNotSet)
+	})
+}
+
+func TestClient_Post(t *testing.T) {
+	root := initGitRepo(t)
+	ctx := context.Background()
+
+	client, err := staticSite.NewClient(ctx, filepath.Dir(root), root, "Bot <bot@example.com>")
+	require.NoError(t, err)
+	require.True(t, client.IsGit())
+
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	post, err := client.CreatePost(ctx, model.Post{
+		Title:      `title: with "quote"`,
+		Date:       date,
+		Categories: []string{"news"},
+		Content:    "# heading\n\ncontent",
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(post.ID, "2024-01-02-"))
+
+	data, err := os.ReadFile(filepath.Join(root, "content", "posts", post.ID+".md"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "---\n"))
+	assert.Contains(t, string(data), "\n---\n\n# heading\n\ncontent\n")
+
+	got, err := client.GetPost(ctx, post.ID)
+	require.NoError(t, err)
+	assert.Equal(t, `title: with "quote"`, got.Title)
+	assert.True(t, got.Date.Equal(date))
+	assert.Equal(t, []string{"news"}, got.Categories)
+	assert.Equal(t, "# heading\n\ncontent", got.Content)
+
+	got.Tags = []string{"go", "rust"}
+	require.NoError(t, client.UpdatePost(ctx, got))
+
+	tags, err := client.ListTag(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Tag{{Name: "go", Count: 1}, {Name: "rust", Count: 1}}, tags)
+
+	require.NoError(t, client.DeletePost(ctx, post.ID))
+	require.ErrorIs(t, client.DeletePost(ctx, post.ID), staticSite.ErrPostNotFound)
+
+	assert.Equal(t, []string{
+		"Bot <bot@example.com>|delete " + post.ID,
+		`Bot <bot@example.com>|update title: with "quote"`,
+		`Bot <bot@example.com>|publish title: with "quote"`,
+	}, gitLog(t, root))
+
+	_, err = client.GetPost(ctx, "../../etc/passwd")
+	require.ErrorIs(t, err, staticSite.ErrInvalidPostID)
+}
+
+func TestClient_SharedRoot(t *testing.T) {
+	root := initGitRepo(t)
+	ctx := context.Background()
+
+	// clients of the same root, e.g. the replaced client in pool, commit one by one
+	clients := make([]*staticSite.Client, 2)
+	for i := range clients {
+		client, err := staticSite.NewClient(ctx, filepath.Dir(root), root, "")
+		require.NoError(t, err)
+
+		clients[i] = client
+	}
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, 10)
+
+	for i := range 10 {
+		wg.Add(1)
+
+		go func(client *staticSite.Client) {
+			defer wg.Done()
+
+			_, err := client.CreatePost(ctx, model.Post{Title: "title"})
+			errs <- err
+		}(clients[i%len(clients)])
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, gitLog(t, root), 10)
+}
+
+func TestClient_Category(t *testing.T) {
+	root := initGitRepo(t)
+	ctx := context.Background()
+
+	client, err := staticSite.NewClient(ctx, filepath.Dir(root), root, "")
+	require.NoError(t, err)
+
+	// post written by hand without our manifest
+	handWritten := "---\ntitle: old\ndate: 2023-05-06 07:08:09\ncategories:\n  - tech\n---\nold post\n"
+	require.NoError(t, os.WriteFile(filepath.Join(root, "content", "posts", "old.md"), []byte(handWritten), 0o644))
+	// post with toml front matter is skipped
+	require.NoError(t, os.WriteFile(filepath.Join(root, "content", "posts", "toml.md"), []byte("+++\ntitle = 'toml'\n+++\n"), 0o644))
+
+	require.NoError(t, client.CreateCategory(ctx, "news"))
+	require.NoError(t, client.CreateCategory(ctx, "news"))
+
+	categories, err := client.ListCategory(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"news", "tech"}, categories)
+	assert.FileExists(t, filepath.Join(root, staticSite.ManifestFile))
+
+	posts, err := client.ListPost(ctx)
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, "old", posts[0].ID)
+
+	assert.Equal(t, []string{"seo_cluster <seo_cluster@localhost>|add category news"}, gitLog(t, root))
+}
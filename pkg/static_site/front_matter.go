@@ -0,0 +1,61 @@
+package staticsite
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ray31245/seo_cluster/pkg/static_site/model"
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterDelimiter = "---"
+
+var ErrNoFrontMatter = errors.New("no front matter")
+
+// encodePost render the post as markdown with yaml front matter
+func encodePost(post model.Post) ([]byte, error) {
+	frontMatter, err := yaml.Marshal(post)
+	if err != nil {
+		return nil, fmt.Errorf("encodePost: %w", err)
+	}
+
+	buf := bytes.Buffer{}
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.Write(frontMatter)
+	buf.WriteString(frontMatterDelimiter + "\n\n")
+	buf.WriteString(strings.TrimSpace(post.Content))
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+// decodePost parse the markdown with yaml front matter
+func decodePost(ID string, data []byte) (model.Post, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	rest, ok := strings.CutPrefix(text, frontMatterDelimiter+"\n")
+	if !ok {
+		return model.Post{}, fmt.Errorf("decodePost: %w", ErrNoFrontMatter)
+	}
+
+	frontMatter, content, ok := strings.Cut(rest, "\n"+frontMatterDelimiter+"\n")
+	if !ok {
+		// the file end with front matter
+		frontMatter, ok = strings.CutSuffix(rest, "\n"+frontMatterDelimiter)
+		if !ok {
+			return model.Post{}, fmt.Errorf("decodePost: %w", ErrNoFrontMatter)
+		}
+	}
+
+	res := model.Post{}
+	if err := yaml.Unmarshal([]byte(frontMatter), &res); err != nil {
+		return model.Post{}, fmt.Errorf("decodePost: %w", err)
+	}
+
+	res.ID = ID
+	res.Content = strings.TrimSpace(content)
+
+	return res, nil
+}
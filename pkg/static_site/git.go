@@ -0,0 +1,63 @@
+package staticsite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/ray31245/seo_cluster/pkg/static_site/model"
+)
+
+// runGit run git command in the working tree with extra environment variables
+func runGit(ctx context.Context, root string, env []string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", root}, args...)...)
+	cmd.Env = append(os.Environ(), env...)
+
+	stderr := bytes.Buffer{}
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s error: %w: %s", args[0], err, stderr.String())
+	}
+
+	return nil
+}
+
+// commit stage the paths and commit them, nothing is committed if the paths are not changed
+func commit(ctx context.Context, root string, author model.Author, message string, paths ...string) error {
+	addArgs := append([]string{"add", "-A", "--"}, paths...)
+	if err := runGit(ctx, root, nil, addArgs...); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	diffArgs := append([]string{"diff", "--cached", "--quiet", "--"}, paths...)
+
+	err := runGit(ctx, root, nil, diffArgs...)
+	if err == nil {
+		return nil
+	}
+
+	exitErr := &exec.ExitError{}
+	// exit code 1 means there are staged changes
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	// the site may be on the host without git identity
+	env := []string{
+		"GIT_AUTHOR_NAME=" + author.Name,
+		"GIT_AUTHOR_EMAIL=" + author.Email,
+		"GIT_COMMITTER_NAME=" + author.Name,
+		"GIT_COMMITTER_EMAIL=" + author.Email,
+	}
+
+	commitArgs := append([]string{"commit", "-m", message, "--"}, paths...)
+	if err := runGit(ctx, root, env, commitArgs...); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
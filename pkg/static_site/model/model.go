@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+const (
+	// LayoutHugo keep posts in content/posts
+	LayoutHugo Layout = "hugo"
+	// LayoutHexo keep posts in source/_posts
+	LayoutHexo Layout = "hexo"
+)
+
+type Layout string
+
+// PostDir return the directory of posts relative to the root of site
+func (l Layout) PostDir() string {
+	switch l {
+	case LayoutHexo:
+		return "source/_posts"
+	default:
+		return "content/posts"
+	}
+}
+
+// Post is the markdown file with yaml front matter, ID is the file name without extension
+type Post struct {
+	ID         string    `yaml:"-"`
+	Title      string    `yaml:"title"`
+	Date       time.Time `yaml:"date"`
+	Categories []string  `yaml:"categories,omitempty"`
+	Tags       []string  `yaml:"tags,omitempty"`
+	// Content is the markdown after front matter
+	Content string `yaml:"-"`
+}
+
+// Manifest keep the categories which have no post yet
+type Manifest struct {
+	Categories []string `json:"categories"`
+}
+
+type Tag struct {
+	Name  string
+	Count int
+}
+
+// Author is the author of git commit
+type Author struct {
+	Name  string
+	Email string
+}
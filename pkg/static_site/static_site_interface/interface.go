@@ -0,0 +1,27 @@
+package staticsiteinterface
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ray31245/seo_cluster/pkg/static_site/model"
+)
+
+type StaticSiteAPI interface {
+	GetClient(ctx context.Context, ID uuid.UUID, root string, userName string, password string) (StaticSiteClient, error)
+	UpdateClient(ctx context.Context, ID uuid.UUID, root string, userName string, password string) (StaticSiteClient, error)
+	DeleteClient(ID uuid.UUID)
+	NewClient(ctx context.Context, root string, userName string, password string) (StaticSiteClient, error)
+	NewAnonymousClient(ctx context.Context, root string) StaticSiteClient
+}
+
+type StaticSiteClient interface {
+	ListCategory(ctx context.Context) ([]string, error)
+	CreateCategory(ctx context.Context, name string) error
+	ListTag(ctx context.Context) ([]model.Tag, error)
+	ListPost(ctx context.Context) ([]model.Post, error)
+	GetPost(ctx context.Context, ID string) (model.Post, error)
+	CreatePost(ctx context.Context, post model.Post) (model.Post, error)
+	UpdatePost(ctx context.Context, post model.Post) error
+	DeletePost(ctx context.Context, ID string) error
+}
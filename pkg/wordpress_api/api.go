@@ -2,9 +2,12 @@ package wordpressapi
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 
+	clientpool "github.com/ray31245/seo_cluster/pkg/client_pool"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
 	"github.com/ray31245/seo_cluster/pkg/wordpress_api/model"
 	wordpressinterface "github.com/ray31245/seo_cluster/pkg/wordpress_api/wordpress_interface"
 )
@@ -13,61 +16,63 @@ import (
 var _ wordpressinterface.WordpressAPI = &WordpressApi{}
 
 type WordpressApi struct {
-	// TODO: clientPool should be sync.Map
 	// key is site id or user id
-	clientPool map[uuid.UUID]*Client
+	clientPool  *clientpool.Pool[*Client]
+	httpClients *httpclient.Factory
 }
 
-func NewWordpressApi() *WordpressApi {
+// NewWordpressApi create the api, the http clients of sites are built by httpClients with the options of site
+func NewWordpressApi(poolOptions clientpool.Options, httpClients *httpclient.Factory) *WordpressApi {
 	return &WordpressApi{
-		clientPool: make(map[uuid.UUID]*Client),
+		clientPool:  clientpool.NewPool[*Client](poolOptions),
+		httpClients: httpClients,
 	}
 }
 
-func (t *WordpressApi) GetClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string) (wordpressinterface.WordpressClient, error) {
-	client, ok := t.clientPool[ID]
-
-	var err error
-	if !ok {
-		client, err = NewClient(ctx, urlStr, model.BasicAuthentication{
-			Username: userName,
-			Password: password,
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		t.clientPool[ID] = client
+func (t *WordpressApi) GetClient(ctx context.Context, ID uuid.UUID, urlStr string, auth model.Authentication, httpOptions httpclient.Options) (wordpressinterface.WordpressClient, error) {
+	client, err := t.clientPool.Get(ctx, ID, func(ctx context.Context) (*Client, error) {
+		return t.newClient(ctx, urlStr, auth, httpOptions)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return client, nil
 }
 
-func (t *WordpressApi) UpdateClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string) (wordpressinterface.WordpressClient, error) {
-	client, err := NewClient(ctx, urlStr, model.BasicAuthentication{
-		Username: userName,
-		Password: password,
-	})
+func (t *WordpressApi) UpdateClient(ctx context.Context, ID uuid.UUID, urlStr string, auth model.Authentication, httpOptions httpclient.Options) (wordpressinterface.WordpressClient, error) {
+	client, err := t.newClient(ctx, urlStr, auth, httpOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	t.clientPool[ID] = client
+	t.clientPool.Put(ID, client)
 
 	return client, nil
 }
 
 func (t *WordpressApi) DeleteClient(ID uuid.UUID) {
-	delete(t.clientPool, ID)
+	t.clientPool.Delete(ID)
 }
 
-func (t *WordpressApi) NewClient(ctx context.Context, urlStr string, userName string, password string) (wordpressinterface.WordpressClient, error) {
-	return NewClient(ctx, urlStr, model.BasicAuthentication{
-		Username: userName,
-		Password: password,
-	})
+// PoolStats return the stats of client pool
+func (t *WordpressApi) PoolStats() clientpool.Stats {
+	return t.clientPool.Stats()
+}
+
+func (t *WordpressApi) NewClient(ctx context.Context, urlStr string, auth model.Authentication, httpOptions httpclient.Options) (wordpressinterface.WordpressClient, error) {
+	return t.newClient(ctx, urlStr, auth, httpOptions)
 }
 
 func (t *WordpressApi) NewAnonymousClient(ctx context.Context, urlStr string) wordpressinterface.WordpressClient {
-	return NewAnonymousClient(ctx, urlStr)
+	return NewAnonymousClientWithHTTPClient(ctx, t.httpClients.DefaultClient(), urlStr)
+}
+
+func (t *WordpressApi) newClient(ctx context.Context, urlStr string, auth model.Authentication, httpOptions httpclient.Options) (*Client, error) {
+	httpClient, err := t.httpClients.Client(httpOptions)
+	if err != nil {
+		return nil, fmt.Errorf("newClient: %w", err)
+	}
+
+	return NewClientWithHTTPClient(ctx, httpClient, urlStr, auth)
 }
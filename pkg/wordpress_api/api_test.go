@@ -0,0 +1,59 @@
+package wordpressapi_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	clientpool "github.com/ray31245/seo_cluster/pkg/client_pool"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	wordpressAPI "github.com/ray31245/seo_cluster/pkg/wordpress_api"
+	"github.com/ray31245/seo_cluster/pkg/wordpress_api/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWordpressApi_ClientPoolConcurrent should be run with -race
+func TestWordpressApi_ClientPoolConcurrent(t *testing.T) {
+	site := newFakeSite(t)
+
+	api := wordpressAPI.NewWordpressApi(clientpool.Options{IdleTTL: time.Millisecond, MaxSize: 2}, httpclient.NewDefaultFactory())
+	IDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	auth := model.Authentication{Mode: model.AuthModeJWT, Username: testUser, Password: testPassword}
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+
+	for i := range 30 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ID := IDs[i%len(IDs)]
+
+			for j := range 10 {
+				switch (i + j) % 3 {
+				case 0:
+					client, err := api.GetClient(ctx, ID, site.server.URL, auth, httpclient.Options{})
+					if assert.NoError(t, err) {
+						_, err = client.RetrieveUserMe(ctx)
+						assert.NoError(t, err)
+					}
+				case 1:
+					_, err := api.UpdateClient(ctx, ID, site.server.URL, auth, httpclient.Options{})
+					assert.NoError(t, err)
+				default:
+					api.DeleteClient(ID)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	stats := api.PoolStats()
+	assert.LessOrEqual(t, stats.Size, 2)
+	assert.Positive(t, stats.Hits+stats.Misses)
+}
@@ -3,31 +3,60 @@ package wordpressapi
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"sync"
 
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
 	"github.com/ray31245/seo_cluster/pkg/wordpress_api/model"
 	"github.com/ray31245/seo_cluster/pkg/wordpress_api/origin"
 )
 
 type Client struct {
-	baseURL   string
-	basicAuth model.BasicAuthentication
-	// avoid duplicate login
+	baseURL    string
+	httpClient *http.Client
+	// auth is guarded by stateLock, its mode and credential are not changed after creation
+	auth model.Authentication
+	// lock make the concurrent re-login of requests share one login
 	lock *sync.Mutex
+	// stateLock guard the token and cookies of auth and the login state below, it is not held during request
+	stateLock sync.RWMutex
+	// loginGen is increased by every login, loginErr is the result of last login
+	loginGen uint64
+	loginErr error
 }
 
-func NewClient(ctx context.Context, urlStr string, basicAuth model.BasicAuthentication) (*Client, error) {
-	log.Println("following url is used to login")
-	log.Println(urlStr)
+// NewClient login by the auth mode and check the credential by retrieving user me
+func NewClient(ctx context.Context, urlStr string, auth model.Authentication) (*Client, error) {
+	return NewClientWithHTTPClient(ctx, httpclient.NewDefaultClient(), urlStr, auth)
+}
+
+// NewClientWithHTTPClient is NewClient sending the requests by httpClient
+func NewClientWithHTTPClient(ctx context.Context, httpClient *http.Client, urlStr string, auth model.Authentication) (*Client, error) {
+	slog.InfoContext(ctx, "login wordpress", slog.String("url", urlStr), slog.String("auth_mode", string(auth.Mode)))
+
+	mode, err := model.ParseAuthMode(string(auth.Mode))
+	if err != nil {
+		return nil, fmt.Errorf("NewClient: %w", err)
+	}
+
+	auth.Mode = mode
 
 	res := &Client{
-		baseURL:   urlStr,
-		basicAuth: basicAuth,
-		lock:      &sync.Mutex{},
+		baseURL:    urlStr,
+		httpClient: httpClient,
+		auth:       auth,
+		lock:       &sync.Mutex{},
+	}
+
+	if res.auth.NeedLogin() {
+		err = res.Login(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("NewClient: %w", err)
+		}
 	}
 
-	_, err := res.RetrieveUserMe(ctx)
+	_, err = res.RetrieveUserMe(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -36,9 +65,15 @@ func NewClient(ctx context.Context, urlStr string, basicAuth model.BasicAuthenti
 }
 
 func NewAnonymousClient(ctx context.Context, urlStr string) *Client {
+	return NewAnonymousClientWithHTTPClient(ctx, httpclient.NewDefaultClient(), urlStr)
+}
+
+// NewAnonymousClientWithHTTPClient is NewAnonymousClient sending the requests by httpClient
+func NewAnonymousClientWithHTTPClient(_ context.Context, httpClient *http.Client, urlStr string) *Client {
 	res := &Client{
-		baseURL: urlStr,
-		basicAuth: model.BasicAuthentication{
+		baseURL:    urlStr,
+		httpClient: httpClient,
+		auth: model.Authentication{
 			IsAnonymous: true,
 		},
 		lock: &sync.Mutex{},
@@ -47,22 +82,45 @@ func NewAnonymousClient(ctx context.Context, urlStr string) *Client {
 	return res
 }
 
+// AuthMode return the auth mode of client
+func (c *Client) AuthMode() model.AuthMode {
+	return c.auth.Mode
+}
+
 func (c *Client) RetrieveUserMe(ctx context.Context) (model.RetrieveUserMeResponse, error) {
-	res, err := origin.RetrieveUserMe(ctx, c.baseURL, c.basicAuth, model.ContextView)
-	if err != nil {
-		return model.RetrieveUserMeResponse{}, fmt.Errorf("retrieve user me error: %w", err)
+	res := model.RetrieveUserMeResponse{}
+
+	var err error
+
+	task := func(auth model.Authentication) error {
+		res, err = origin.RetrieveUserMe(ctx, c.httpClient, c.baseURL, auth, model.ContextView)
+		if err != nil {
+			return fmt.Errorf("retrieve user me error: %w", err)
+		}
+
+		return nil
 	}
+	err = c.retry(ctx, task)
 
-	return res, nil
+	return res, err
 }
 
 func (c *Client) ListTag(ctx context.Context, args model.ListTagArgs) (model.ListTagResponse, error) {
-	res, _, err := origin.ListTag(ctx, c.baseURL, c.basicAuth, args)
-	if err != nil {
-		return model.ListTagResponse{}, fmt.Errorf("list tag error: %w", err)
+	res := model.ListTagResponse{}
+
+	var err error
+
+	task := func(auth model.Authentication) error {
+		res, _, err = origin.ListTag(ctx, c.httpClient, c.baseURL, auth, args)
+		if err != nil {
+			return fmt.Errorf("list tag error: %w", err)
+		}
+
+		return nil
 	}
+	err = c.retry(ctx, task)
 
-	return res, nil
+	return res, err
 }
 
 func (c *Client) ListTagAll(ctx context.Context) (model.ListTagResponse, error) {
@@ -73,9 +131,23 @@ func (c *Client) ListTagAll(ctx context.Context) (model.ListTagResponse, error)
 	}
 
 	for {
-		listRes, page, err := origin.ListTag(ctx, c.baseURL, c.basicAuth, listArgs)
+		listRes := model.ListTagResponse{}
+		page := model.PageSchema{}
+
+		task := func(auth model.Authentication) error {
+			var err error
+
+			listRes, page, err = origin.ListTag(ctx, c.httpClient, c.baseURL, auth, listArgs)
+			if err != nil {
+				return fmt.Errorf("list tag all error: %w", err)
+			}
+
+			return nil
+		}
+
+		err := c.retry(ctx, task)
 		if err != nil {
-			return model.ListTagResponse{}, fmt.Errorf("list tag all error: %w", err)
+			return model.ListTagResponse{}, err
 		}
 
 		res = append(res, listRes...)
@@ -91,73 +163,207 @@ func (c *Client) ListTagAll(ctx context.Context) (model.ListTagResponse, error)
 }
 
 func (c *Client) CreateTag(ctx context.Context, args model.CreateTagArgs) (model.CreateTagResponse, error) {
-	res, err := origin.CreateTag(ctx, c.baseURL, c.basicAuth, args)
-	if err != nil {
-		return model.CreateTagResponse{}, fmt.Errorf("create tag error: %w", err)
+	res := model.CreateTagResponse{}
+
+	var err error
+
+	task := func(auth model.Authentication) error {
+		res, err = origin.CreateTag(ctx, c.httpClient, c.baseURL, auth, args)
+		if err != nil {
+			return fmt.Errorf("create tag error: %w", err)
+		}
+
+		return nil
 	}
+	err = c.retry(ctx, task)
 
-	return res, nil
+	return res, err
 }
 
 func (c *Client) ListCategory(ctx context.Context, args model.ListCategoryArgs) (model.ListCategoryResponse, error) {
-	res, err := origin.ListCategory(ctx, c.baseURL, c.basicAuth, args)
-	if err != nil {
-		return model.ListCategoryResponse{}, fmt.Errorf("list category error: %w", err)
+	res := model.ListCategoryResponse{}
+
+	var err error
+
+	task := func(auth model.Authentication) error {
+		res, err = origin.ListCategory(ctx, c.httpClient, c.baseURL, auth, args)
+		if err != nil {
+			return fmt.Errorf("list category error: %w", err)
+		}
+
+		return nil
 	}
+	err = c.retry(ctx, task)
 
-	return res, nil
+	return res, err
+}
+
+func (c *Client) CreateCategory(ctx context.Context, args model.CreateCategoryArgs) (model.CreateCategoryResponse, error) {
+	res := model.CreateCategoryResponse{}
+
+	var err error
+
+	task := func(auth model.Authentication) error {
+		res, err = origin.CreateCategory(ctx, c.httpClient, c.baseURL, auth, args)
+		if err != nil {
+			return fmt.Errorf("create category error: %w", err)
+		}
+
+		return nil
+	}
+	err = c.retry(ctx, task)
+
+	return res, err
+}
+
+func (c *Client) UpdateCategory(ctx context.Context, args model.UpdateCategoryArgs) (model.UpdateCategoryResponse, error) {
+	res := model.UpdateCategoryResponse{}
+
+	var err error
+
+	task := func(auth model.Authentication) error {
+		res, err = origin.UpdateCategory(ctx, c.httpClient, c.baseURL, auth, args)
+		if err != nil {
+			return fmt.Errorf("update category error: %w", err)
+		}
+
+		return nil
+	}
+	err = c.retry(ctx, task)
+
+	return res, err
+}
+
+func (c *Client) DeleteCategory(ctx context.Context, args model.DeleteCategoryArgs) error {
+	task := func(auth model.Authentication) error {
+		err := origin.DeleteCategory(ctx, c.httpClient, c.baseURL, auth, args)
+		if err != nil {
+			return fmt.Errorf("delete category error: %w", err)
+		}
+
+		return nil
+	}
+
+	return c.retry(ctx, task)
 }
 
 func (c *Client) GetCountOfArticle(ctx context.Context, req model.ListArticleArgs) (int, error) {
-	_, page, err := origin.ListArticle(ctx, c.baseURL, c.basicAuth, req)
-	if err != nil {
-		return 0, fmt.Errorf("list article error: %w", err)
+	page := model.PageSchema{}
+
+	var err error
+
+	task := func(auth model.Authentication) error {
+		_, page, err = origin.ListArticle(ctx, c.httpClient, c.baseURL, auth, req)
+		if err != nil {
+			return fmt.Errorf("list article error: %w", err)
+		}
+
+		return nil
 	}
+	err = c.retry(ctx, task)
 
-	return page.Total, nil
+	return page.Total, err
 }
 
 func (c *Client) ListArticle(ctx context.Context, args model.ListArticleArgs) (model.ListArticleResponse, error) {
-	res, _, err := origin.ListArticle(ctx, c.baseURL, c.basicAuth, args)
-	if err != nil {
-		return model.ListArticleResponse{}, fmt.Errorf("list article error: %w", err)
+	res := model.ListArticleResponse{}
+
+	var err error
+
+	task := func(auth model.Authentication) error {
+		res, _, err = origin.ListArticle(ctx, c.httpClient, c.baseURL, auth, args)
+		if err != nil {
+			return fmt.Errorf("list article error: %w", err)
+		}
+
+		return nil
 	}
+	err = c.retry(ctx, task)
 
-	return res, nil
+	return res, err
 }
 
 func (c *Client) CreateArticle(ctx context.Context, args model.CreateArticleArgs) (model.CreateArticleResponse, error) {
-	res, err := origin.CreateArticle(ctx, c.baseURL, c.basicAuth, args)
-	if err != nil {
-		return model.CreateArticleResponse{}, fmt.Errorf("create article error: %w", err)
+	res := model.CreateArticleResponse{}
+
+	var err error
+
+	task := func(auth model.Authentication) error {
+		res, err = origin.CreateArticle(ctx, c.httpClient, c.baseURL, auth, args)
+		if err != nil {
+			return fmt.Errorf("create article error: %w", err)
+		}
+
+		return nil
 	}
+	err = c.retry(ctx, task)
 
-	return res, nil
+	return res, err
 }
 
 func (c *Client) UpdateArticle(ctx context.Context, args model.UpdateArticleArgs) (model.UpdateArticleResponse, error) {
-	res, err := origin.UpdateArticle(ctx, c.baseURL, c.basicAuth, args)
-	if err != nil {
-		return model.UpdateArticleResponse{}, fmt.Errorf("update article error: %w", err)
+	res := model.UpdateArticleResponse{}
+
+	var err error
+
+	task := func(auth model.Authentication) error {
+		res, err = origin.UpdateArticle(ctx, c.httpClient, c.baseURL, auth, args)
+		if err != nil {
+			return fmt.Errorf("update article error: %w", err)
+		}
+
+		return nil
 	}
+	err = c.retry(ctx, task)
 
-	return res, nil
+	return res, err
+}
+
+func (c *Client) DeleteArticle(ctx context.Context, args model.DeleteArticleArgs) error {
+	task := func(auth model.Authentication) error {
+		err := origin.DeleteArticle(ctx, c.httpClient, c.baseURL, auth, args)
+		if err != nil {
+			return fmt.Errorf("delete article error: %w", err)
+		}
+
+		return nil
+	}
+
+	return c.retry(ctx, task)
 }
 
 func (c *Client) RetrieveArticle(ctx context.Context, args model.RetrieveArticleArgs) (model.RetrieveArticleResponse, error) {
-	res, err := origin.RetrieveArticle(ctx, c.baseURL, c.basicAuth, args)
-	if err != nil {
-		return model.RetrieveArticleResponse{}, fmt.Errorf("retrieve article error: %w", err)
+	res := model.RetrieveArticleResponse{}
+
+	var err error
+
+	task := func(auth model.Authentication) error {
+		res, err = origin.RetrieveArticle(ctx, c.httpClient, c.baseURL, auth, args)
+		if err != nil {
+			return fmt.Errorf("retrieve article error: %w", err)
+		}
+
+		return nil
 	}
+	err = c.retry(ctx, task)
 
-	return res, nil
+	return res, err
 }
 
 func (c *Client) CreateComment(ctx context.Context, args model.CreateCommentArgs) (model.CreateCommentResponse, error) {
-	res, err := origin.CreateComment(ctx, c.baseURL, c.basicAuth, args)
-	if err != nil {
-		return model.CreateCommentResponse{}, fmt.Errorf("create comment error: %w", err)
+	res := model.CreateCommentResponse{}
+
+	var err error
+
+	task := func(auth model.Authentication) error {
+		res, err = origin.CreateComment(ctx, c.httpClient, c.baseURL, auth, args)
+		if err != nil {
+			return fmt.Errorf("create comment error: %w", err)
+		}
+
+		return nil
 	}
+	err = c.retry(ctx, task)
 
-	return res, nil
+	return res, err
 }
@@ -0,0 +1,304 @@
+package wordpressapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	wordpressAPI "github.com/ray31245/seo_cluster/pkg/wordpress_api"
+	wordpressError "github.com/ray31245/seo_cluster/pkg/wordpress_api/error"
+	"github.com/ray31245/seo_cluster/pkg/wordpress_api/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUser     = "admin"
+	testPassword = "abcd efgh ijkl"
+	jwtSecret    = "secret"
+)
+
+// fakeSite act as wordpress with jwt-auth plugin, the credentials issued before revoke are rejected
+type fakeSite struct {
+	lock sync.Mutex
+	// generation is increased by revoke
+	generation int
+	// tokenTTL is the lifetime of jwt
+	tokenTTL time.Duration
+	tokens   int
+	logins   int
+	server   *httptest.Server
+}
+
+func newFakeSite(t *testing.T) *fakeSite {
+	t.Helper()
+
+	site := &fakeSite{tokenTTL: time.Hour}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/wp-json/wp/v2/users/me", site.userMe)
+	mux.HandleFunc("/wp-json/jwt-auth/v1/token", site.token)
+	mux.HandleFunc("/wp-login.php", site.login)
+	mux.HandleFunc("/wp-admin/admin-ajax.php", site.nonce)
+
+	site.server = httptest.NewServer(mux)
+	t.Cleanup(site.server.Close)
+
+	return site
+}
+
+func (s *fakeSite) revoke() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.generation++
+}
+
+func (s *fakeSite) session() string {
+	return fmt.Sprintf("session-%d", s.generation)
+}
+
+func (s *fakeSite) authorized(r *http.Request) bool {
+	if user, password, ok := r.BasicAuth(); ok {
+		return user == testUser && password == testPassword
+	}
+
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		claims := jwt.MapClaims{}
+
+		_, err := jwt.ParseWithClaims(bearer, claims, func(_ *jwt.Token) (interface{}, error) {
+			return []byte(jwtSecret), nil
+		})
+
+		return err == nil && claims["gen"] == float64(s.generation)
+	}
+
+	cookie, err := r.Cookie("wordpress_logged_in_hash")
+
+	return err == nil && cookie.Value == s.session() && r.Header.Get("X-WP-Nonce") == "nonce-"+s.session()
+}
+
+func (s *fakeSite) userMe(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.authorized(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"code":"rest_not_logged_in","message":"not logged in"}`))
+
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(model.RetrieveUserMeResponse{UserSchema: model.UserSchema{ID: 1, Name: testUser}})
+}
+
+func (s *fakeSite) token(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	args := model.JWTTokenArgs{}
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil || args.Username != testUser || args.Password != testPassword {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"gen": s.generation,
+		"exp": time.Now().Add(s.tokenTTL).Unix(),
+	}).SignedString([]byte(jwtSecret))
+	s.tokens++
+
+	_ = json.NewEncoder(w).Encode(model.JWTTokenResponse{Token: token, UserNicename: testUser})
+}
+
+func (s *fakeSite) login(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, err := r.Cookie("wordpress_test_cookie"); err != nil || r.FormValue("log") != testUser || r.FormValue("pwd") != testPassword {
+		// wp-login.php show the form again with the error
+		_, _ = w.Write([]byte("<html>login error</html>"))
+
+		return
+	}
+
+	s.logins++
+
+	http.SetCookie(w, &http.Cookie{Name: "wordpress_logged_in_hash", Value: s.session()})
+	http.Redirect(w, r, "/wp-admin/", http.StatusFound)
+}
+
+func (s *fakeSite) nonce(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	cookie, err := r.Cookie("wordpress_logged_in_hash")
+	if r.URL.Query().Get("action") != "rest-nonce" || err != nil || cookie.Value != s.session() {
+		_, _ = w.Write([]byte("0"))
+
+		return
+	}
+
+	_, _ = w.Write([]byte("nonce-" + s.session()))
+}
+
+func TestNewClient_ApplicationPassword(t *testing.T) {
+	site := newFakeSite(t)
+
+	client, err := wordpressAPI.NewClient(context.Background(), site.server.URL, model.Authentication{
+		Mode:     model.AuthModeApplicationPassword,
+		Username: testUser,
+		Password: testPassword,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.AuthModeApplicationPassword, client.AuthMode())
+
+	_, err = wordpressAPI.NewClient(context.Background(), site.server.URL, model.Authentication{
+		Mode:     model.AuthModeApplicationPassword,
+		Username: testUser,
+		Password: "wrong",
+	})
+	require.ErrorIs(t, err, wordpressError.ErrHTTPUnauthorized)
+}
+
+func TestNewClient_DefaultMode(t *testing.T) {
+	site := newFakeSite(t)
+
+	client, err := wordpressAPI.NewClient(context.Background(), site.server.URL, model.Authentication{
+		Username: testUser,
+		Password: testPassword,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.AuthModeBasic, client.AuthMode())
+
+	_, err = wordpressAPI.NewClient(context.Background(), site.server.URL, model.Authentication{Mode: "oauth"})
+	require.ErrorIs(t, err, wordpressError.ErrAuthModeNotSupport)
+}
+
+func TestClient_JWT(t *testing.T) {
+	ctx := context.Background()
+	site := newFakeSite(t)
+
+	client, err := wordpressAPI.NewClient(ctx, site.server.URL, model.Authentication{
+		Mode:     model.AuthModeJWT,
+		Username: testUser,
+		Password: testPassword,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, site.tokens)
+
+	t.Run("token is issued again when it is rejected", func(t *testing.T) {
+		site.revoke()
+
+		me, err := client.RetrieveUserMe(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, testUser, me.Name)
+		assert.Equal(t, 2, site.tokens)
+	})
+
+	t.Run("token is issued again before it expire", func(t *testing.T) {
+		site.tokenTTL = 30 * time.Second
+		require.NoError(t, client.Login(ctx))
+		assert.Equal(t, 3, site.tokens)
+
+		_, err := client.RetrieveUserMe(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 4, site.tokens)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := wordpressAPI.NewClient(ctx, site.server.URL, model.Authentication{
+			Mode:     model.AuthModeJWT,
+			Username: testUser,
+			Password: "wrong",
+		})
+		require.ErrorIs(t, err, wordpressError.ErrHTTPForbidden)
+	})
+}
+
+func TestClient_ConcurrentLogin(t *testing.T) {
+	ctx := context.Background()
+	site := newFakeSite(t)
+
+	client, err := wordpressAPI.NewClient(ctx, site.server.URL, model.Authentication{
+		Mode:     model.AuthModeJWT,
+		Username: testUser,
+		Password: testPassword,
+	})
+	require.NoError(t, err)
+
+	site.revoke()
+
+	// the requests rejected by the same token share one login
+	var wg sync.WaitGroup
+
+	errs := make(chan error, 10)
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := client.RetrieveUserMe(ctx)
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, site.tokens)
+}
+
+func TestClient_Cookie(t *testing.T) {
+	ctx := context.Background()
+	site := newFakeSite(t)
+
+	client, err := wordpressAPI.NewClient(ctx, site.server.URL, model.Authentication{
+		Mode:     model.AuthModeCookie,
+		Username: testUser,
+		Password: testPassword,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, site.logins)
+
+	t.Run("login again when the session is rejected", func(t *testing.T) {
+		site.revoke()
+
+		_, err := client.RetrieveUserMe(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, site.logins)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := wordpressAPI.NewClient(ctx, site.server.URL, model.Authentication{
+			Mode:     model.AuthModeCookie,
+			Username: testUser,
+			Password: "wrong",
+		})
+		require.ErrorIs(t, err, wordpressError.ErrLoginFailed)
+	})
+}
+
+func TestClient_Anonymous(t *testing.T) {
+	site := newFakeSite(t)
+
+	client := wordpressAPI.NewAnonymousClient(context.Background(), site.server.URL)
+
+	_, err := client.RetrieveUserMe(context.Background())
+	require.ErrorIs(t, err, wordpressError.ErrHTTPUnauthorized)
+	assert.Equal(t, 0, site.logins+site.tokens)
+}
@@ -13,6 +13,9 @@ var (
 	ErrHTTPUnauthorized    = fmt.Errorf("%w: unauthorized", ErrHTTPStatusCodeError)
 	ErrHTTPForbidden       = fmt.Errorf("%w: forbidden", ErrHTTPStatusCodeError)
 	ErrHTTPNotFound        = fmt.Errorf("%w: not found", ErrHTTPStatusCodeError)
+	ErrAuthModeNotSupport  = errors.New("auth mode not support")
+	// ErrLoginFailed is returned when the login of auth mode get no credential
+	ErrLoginFailed = errors.New("login failed")
 )
 
 func NewHTTPStatusCodeError(statusCode int) error {
@@ -0,0 +1,173 @@
+package wordpressapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	wordpressError "github.com/ray31245/seo_cluster/pkg/wordpress_api/error"
+	"github.com/ray31245/seo_cluster/pkg/wordpress_api/model"
+	"github.com/ray31245/seo_cluster/pkg/wordpress_api/origin"
+)
+
+// tokenRefreshMargin is the time before expiration of jwt to issue the token again
+const tokenRefreshMargin = time.Minute
+
+// retry run f with a snapshot of current auth, and run it again after login if the auth is rejected.
+// Requests run concurrently, only the login is serialized and shared by the requests waiting for it.
+// The auth modes need no login are not locked at all
+func (c *Client) retry(ctx context.Context, f func(auth model.Authentication) error) error {
+	if !c.auth.NeedLogin() {
+		err := f(c.auth)
+		if err != nil {
+			return fmt.Errorf("retry error: %w", err)
+		}
+
+		return nil
+	}
+
+	auth, gen := c.session()
+
+	// issue the jwt again ahead of expiration instead of waiting the request is rejected
+	if tokenExpiring(auth) {
+		err := c.relogin(ctx, gen)
+		if err != nil {
+			return fmt.Errorf("retry error: %w", err)
+		}
+
+		auth, gen = c.session()
+	}
+
+	err := f(auth)
+	if errors.Is(err, wordpressError.ErrHTTPUnauthorized) || errors.Is(err, wordpressError.ErrHTTPForbidden) {
+		err = c.relogin(ctx, gen)
+		if err != nil {
+			return fmt.Errorf("retry error: %w", err)
+		}
+
+		auth, _ = c.session()
+
+		err = f(auth)
+		if err != nil {
+			return fmt.Errorf("retry error: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("retry error: %w", err)
+	}
+
+	return nil
+}
+
+// Login obtain the token or cookies of auth mode, basic auth and application password need no login
+func (c *Client) Login(ctx context.Context) error {
+	if !c.auth.NeedLogin() {
+		return nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.login(ctx)
+}
+
+// login must be called with lock held
+func (c *Client) login(ctx context.Context) error {
+	auth, err := c.issueAuth(ctx)
+
+	c.stateLock.Lock()
+	defer c.stateLock.Unlock()
+
+	c.loginGen++
+	c.loginErr = nil
+
+	if err != nil {
+		c.loginErr = fmt.Errorf("login error: %w", err)
+
+		return c.loginErr
+	}
+
+	c.auth = auth
+
+	slog.DebugContext(ctx, "login wordpress success", slog.String("url", c.baseURL))
+
+	return nil
+}
+
+// issueAuth return the auth with the token or cookies obtained by its credential
+func (c *Client) issueAuth(ctx context.Context) (model.Authentication, error) {
+	auth, _ := c.session()
+
+	switch auth.Mode {
+	case model.AuthModeJWT:
+		resData, err := origin.JWTToken(ctx, c.httpClient, c.baseURL, model.JWTTokenArgs{
+			Username: auth.Username,
+			Password: auth.Password,
+		})
+		if err != nil {
+			return model.Authentication{}, fmt.Errorf("issueAuth: %w", err)
+		}
+
+		auth.Token = resData.Token
+		auth.TokenExpire = tokenExpire(resData.Token)
+	case model.AuthModeCookie:
+		cookies, err := origin.CookieLogin(ctx, c.httpClient, c.baseURL, auth.Username, auth.Password)
+		if err != nil {
+			return model.Authentication{}, fmt.Errorf("issueAuth: %w", err)
+		}
+
+		nonce, err := origin.RestNonce(ctx, c.httpClient, c.baseURL, cookies)
+		if err != nil {
+			return model.Authentication{}, fmt.Errorf("issueAuth: %w", err)
+		}
+
+		auth.Cookies = cookies
+		auth.Nonce = nonce
+	}
+
+	return auth, nil
+}
+
+// relogin login again unless another login is done after the one of gen,
+// so the requests rejected by the same auth share one login and its result
+func (c *Client) relogin(ctx context.Context, gen uint64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.stateLock.RLock()
+	currentGen, loginErr := c.loginGen, c.loginErr
+	c.stateLock.RUnlock()
+
+	if currentGen != gen {
+		return loginErr
+	}
+
+	return c.login(ctx)
+}
+
+// session return the auth and the generation of login it come from
+func (c *Client) session() (model.Authentication, uint64) {
+	c.stateLock.RLock()
+	defer c.stateLock.RUnlock()
+
+	return c.auth, c.loginGen
+}
+
+// tokenExpiring report the jwt of auth will expire within tokenRefreshMargin
+func tokenExpiring(auth model.Authentication) bool {
+	return auth.Mode == model.AuthModeJWT && !auth.TokenExpire.IsZero() && time.Until(auth.TokenExpire) < tokenRefreshMargin
+}
+
+// tokenExpire return the expiration of jwt, zero time if it is unknown
+func tokenExpire(token string) time.Time {
+	claims := jwt.RegisteredClaims{}
+
+	_, _, err := jwt.NewParser().ParseUnverified(token, &claims)
+	if err != nil || claims.ExpiresAt == nil {
+		return time.Time{}
+	}
+
+	return claims.ExpiresAt.Time
+}
@@ -162,6 +162,13 @@ type UpdateArticleArgs struct {
 	Tags []int `json:"tags,omitempty"`
 }
 
+type DeleteArticleArgs struct {
+	// Unique identifier for the post.
+	ID int `json:"id,omitempty"`
+	// Whether to bypass Trash and force deletion.
+	Force bool `json:"force,omitempty"`
+}
+
 type RetrieveArticleArgs struct {
 	// Unique identifier for the post.
 	ID int `json:"id,omitempty"`
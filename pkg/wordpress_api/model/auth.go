@@ -0,0 +1,14 @@
+package model
+
+type JWTTokenArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// JWTTokenResponse is the response of jwt-auth plugin
+type JWTTokenResponse struct {
+	Token           string `json:"token"`
+	UserEmail       string `json:"user_email"`
+	UserNicename    string `json:"user_nicename"`
+	UserDisplayName string `json:"user_display_name"`
+}
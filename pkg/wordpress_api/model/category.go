@@ -43,3 +43,32 @@ type ListCategoryArgs struct {
 type ListCategoriesSchema []CategorySchema
 
 type ListCategoryResponse ListCategoriesSchema
+
+type CreateCategoryArgs struct {
+	// HTML title for the term.
+	Name string `json:"name"`
+	// HTML description of the term.
+	Description string `json:"description,omitempty"`
+	// The parent term ID.
+	Parent int `json:"parent,omitempty"`
+}
+
+type CreateCategoryResponse CategorySchema
+
+type UpdateCategoryArgs struct {
+	// Unique identifier for the term.
+	ID int `json:"id,omitempty"`
+	// HTML title for the term.
+	Name string `json:"name,omitempty"`
+	// HTML description of the term.
+	Description string `json:"description,omitempty"`
+	// The parent term ID.
+	Parent int `json:"parent,omitempty"`
+}
+
+type UpdateCategoryResponse CategorySchema
+
+type DeleteCategoryArgs struct {
+	// Unique identifier for the term.
+	ID int `json:"id,omitempty"`
+}
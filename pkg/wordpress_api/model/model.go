@@ -1,5 +1,13 @@
 package model
 
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	wordpressError "github.com/ray31245/seo_cluster/pkg/wordpress_api/error"
+)
+
 const (
 	// context
 	ContextEdit  ApiContext = "edit"
@@ -9,17 +17,72 @@ const (
 	// order
 	OrderAsc  ApiOrder = "asc"
 	OrderDesc ApiOrder = "desc"
+
+	// AuthModeBasic send the login password by basic auth, it require basic auth plugin
+	AuthModeBasic AuthMode = "basic"
+	// AuthModeApplicationPassword send the application password of user by basic auth
+	AuthModeApplicationPassword AuthMode = "application_password"
+	// AuthModeJWT send the token issued by jwt-auth plugin, the token is issued again when it expire
+	AuthModeJWT AuthMode = "jwt"
+	// AuthModeCookie send the cookies of wp-login.php with rest nonce
+	AuthModeCookie AuthMode = "cookie"
 )
 
 type (
 	ApiContext string
 	ApiOrder   string
+	AuthMode   string
 )
 
-type BasicAuthentication struct {
-	Username    string `json:"username"`
-	Password    string `json:"password"`
-	IsAnonymous bool   `json:"isAnonymous"`
+// Authentication is the credential of site and the state obtained by login of auth mode
+type Authentication struct {
+	Mode        AuthMode `json:"mode"`
+	Username    string   `json:"username"`
+	Password    string   `json:"password"`
+	IsAnonymous bool     `json:"isAnonymous"`
+	// Token is the jwt issued by jwt-auth plugin in AuthModeJWT
+	Token       string    `json:"-"`
+	TokenExpire time.Time `json:"-"`
+	// Cookies and Nonce are the logged in cookies and rest nonce in AuthModeCookie
+	Cookies []*http.Cookie `json:"-"`
+	Nonce   string         `json:"-"`
+}
+
+// Apply set the credential to request
+func (a Authentication) Apply(req *http.Request) {
+	if a.IsAnonymous {
+		return
+	}
+
+	switch a.Mode {
+	case AuthModeJWT:
+		req.Header.Set("Authorization", "Bearer "+a.Token)
+	case AuthModeCookie:
+		for _, cookie := range a.Cookies {
+			req.AddCookie(cookie)
+		}
+
+		req.Header.Set("X-WP-Nonce", a.Nonce)
+	default:
+		req.SetBasicAuth(a.Username, a.Password)
+	}
+}
+
+// NeedLogin return true if the credential is obtained by login, which can be renewed when it is rejected
+func (a Authentication) NeedLogin() bool {
+	return !a.IsAnonymous && (a.Mode == AuthModeJWT || a.Mode == AuthModeCookie)
+}
+
+// ParseAuthMode return AuthModeBasic for empty mode
+func ParseAuthMode(mode string) (AuthMode, error) {
+	switch AuthMode(mode) {
+	case "":
+		return AuthModeBasic, nil
+	case AuthModeBasic, AuthModeApplicationPassword, AuthModeJWT, AuthModeCookie:
+		return AuthMode(mode), nil
+	default:
+		return "", fmt.Errorf("ParseAuthMode: %w: %q", wordpressError.ErrAuthModeNotSupport, mode)
+	}
 }
 
 type ErrorResponse struct {
@@ -11,7 +11,7 @@ import (
 	"github.com/ray31245/seo_cluster/pkg/wordpress_api/model"
 )
 
-func ListArticle(ctx context.Context, baseURL string, basicAuth model.BasicAuthentication, args model.ListArticleArgs) (model.ListArticleResponse, model.PageSchema, error) {
+func ListArticle(ctx context.Context, httpClient *http.Client, baseURL string, auth model.Authentication, args model.ListArticleArgs) (model.ListArticleResponse, model.PageSchema, error) {
 	param, err := json.Marshal(args)
 	if err != nil {
 		return model.ListArticleResponse{}, model.PageSchema{}, fmt.Errorf("marshal error: %w", err)
@@ -28,7 +28,7 @@ func ListArticle(ctx context.Context, baseURL string, basicAuth model.BasicAuthe
 
 	route := "posts"
 
-	resBody, header, err := doRequest(ctx, baseURL, http.MethodGet, route, basicAuth, paramsMap, nil)
+	resBody, header, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, route, auth, paramsMap, nil)
 	if err != nil {
 		return model.ListArticleResponse{}, model.PageSchema{}, fmt.Errorf("list article error: %w", err)
 	}
@@ -64,7 +64,7 @@ func ListArticle(ctx context.Context, baseURL string, basicAuth model.BasicAuthe
 	return resData, page, nil
 }
 
-func CreateArticle(ctx context.Context, baseURL string, basicAuth model.BasicAuthentication, args model.CreateArticleArgs) (model.CreateArticleResponse, error) {
+func CreateArticle(ctx context.Context, httpClient *http.Client, baseURL string, auth model.Authentication, args model.CreateArticleArgs) (model.CreateArticleResponse, error) {
 	bytesData, err := util.EscapeHTMLMarshal(args)
 	if err != nil {
 		return model.CreateArticleResponse{}, fmt.Errorf("marshal error: %w", err)
@@ -74,7 +74,7 @@ func CreateArticle(ctx context.Context, baseURL string, basicAuth model.BasicAut
 
 	route := "posts"
 
-	resBody, _, err := doRequest(ctx, baseURL, http.MethodPost, route, basicAuth, nil, bytesData)
+	resBody, _, err := doRequest(ctx, httpClient, baseURL, http.MethodPost, route, auth, nil, bytesData)
 	if err != nil {
 		return model.CreateArticleResponse{}, fmt.Errorf("create article error: %w", err)
 	}
@@ -87,7 +87,7 @@ func CreateArticle(ctx context.Context, baseURL string, basicAuth model.BasicAut
 	return resData, nil
 }
 
-func UpdateArticle(ctx context.Context, baseURL string, basicAuth model.BasicAuthentication, args model.UpdateArticleArgs) (model.UpdateArticleResponse, error) {
+func UpdateArticle(ctx context.Context, httpClient *http.Client, baseURL string, auth model.Authentication, args model.UpdateArticleArgs) (model.UpdateArticleResponse, error) {
 	bytesData, err := util.EscapeHTMLMarshal(args)
 	if err != nil {
 		return model.UpdateArticleResponse{}, fmt.Errorf("marshal error: %w", err)
@@ -95,7 +95,7 @@ func UpdateArticle(ctx context.Context, baseURL string, basicAuth model.BasicAut
 
 	route := fmt.Sprintf("posts/%d", args.ID)
 
-	resBody, _, err := doRequest(ctx, baseURL, http.MethodPost, route, basicAuth, nil, bytesData)
+	resBody, _, err := doRequest(ctx, httpClient, baseURL, http.MethodPost, route, auth, nil, bytesData)
 	if err != nil {
 		return model.UpdateArticleResponse{}, fmt.Errorf("update article error: %w", err)
 	}
@@ -108,7 +108,7 @@ func UpdateArticle(ctx context.Context, baseURL string, basicAuth model.BasicAut
 	return resData, nil
 }
 
-func RetrieveArticle(ctx context.Context, baseURL string, basicAuth model.BasicAuthentication, args model.RetrieveArticleArgs) (model.RetrieveArticleResponse, error) {
+func RetrieveArticle(ctx context.Context, httpClient *http.Client, baseURL string, auth model.Authentication, args model.RetrieveArticleArgs) (model.RetrieveArticleResponse, error) {
 	param, err := json.Marshal(args)
 	if err != nil {
 		return model.RetrieveArticleResponse{}, fmt.Errorf("marshal error: %w", err)
@@ -125,7 +125,7 @@ func RetrieveArticle(ctx context.Context, baseURL string, basicAuth model.BasicA
 
 	// paramsMap["_fields"] = "id,title,content"
 
-	resBody, _, err := doRequest(ctx, baseURL, http.MethodGet, route, basicAuth, paramsMap, nil)
+	resBody, _, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, route, auth, paramsMap, nil)
 	if err != nil {
 		return model.RetrieveArticleResponse{}, fmt.Errorf("retrieve article error: %w", err)
 	}
@@ -137,3 +137,19 @@ func RetrieveArticle(ctx context.Context, baseURL string, basicAuth model.BasicA
 
 	return resData, nil
 }
+
+func DeleteArticle(ctx context.Context, httpClient *http.Client, baseURL string, auth model.Authentication, args model.DeleteArticleArgs) error {
+	paramsMap := map[string]interface{}{}
+	if args.Force {
+		paramsMap["force"] = true
+	}
+
+	route := fmt.Sprintf("posts/%d", args.ID)
+
+	_, _, err := doRequest(ctx, httpClient, baseURL, http.MethodDelete, route, auth, paramsMap, nil)
+	if err != nil {
+		return fmt.Errorf("delete article error: %w", err)
+	}
+
+	return nil
+}
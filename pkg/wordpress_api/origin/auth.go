@@ -0,0 +1,159 @@
+package origin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ray31245/seo_cluster/pkg/util"
+	wordpressError "github.com/ray31245/seo_cluster/pkg/wordpress_api/error"
+	"github.com/ray31245/seo_cluster/pkg/wordpress_api/model"
+)
+
+const (
+	JWTTokenPath  = "wp-json/jwt-auth/v1/token"
+	LoginPath     = "wp-login.php"
+	AdminAjaxPath = "wp-admin/admin-ajax.php"
+	// loggedInCookiePrefix is the prefix of cookie set by wp-login.php after login
+	loggedInCookiePrefix = "wordpress_logged_in_"
+	testCookieName       = "wordpress_test_cookie"
+)
+
+// JWTToken issue the token by jwt-auth plugin
+func JWTToken(ctx context.Context, httpClient *http.Client, baseURL string, args model.JWTTokenArgs) (model.JWTTokenResponse, error) {
+	bytesData, err := util.EscapeHTMLMarshal(args)
+	if err != nil {
+		return model.JWTTokenResponse{}, fmt.Errorf("marshal error: %w", err)
+	}
+
+	reqURL, err := url.JoinPath(baseURL, JWTTokenPath)
+	if err != nil {
+		return model.JWTTokenResponse{}, fmt.Errorf("parse url error: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(bytesData))
+	if err != nil {
+		return model.JWTTokenResponse{}, fmt.Errorf("new request error: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+
+	resBody, _, err := doLoginRequest(httpClient, req)
+	if err != nil {
+		return model.JWTTokenResponse{}, fmt.Errorf("jwt token error: %w", err)
+	}
+
+	resData := model.JWTTokenResponse{}
+	if err := json.Unmarshal(resBody, &resData); err != nil {
+		return model.JWTTokenResponse{}, fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	if resData.Token == "" {
+		return model.JWTTokenResponse{}, fmt.Errorf("jwt token error: %w: empty token", wordpressError.ErrLoginFailed)
+	}
+
+	return resData, nil
+}
+
+// CookieLogin login by wp-login.php and return the logged in cookies
+func CookieLogin(ctx context.Context, httpClient *http.Client, baseURL string, username string, password string) ([]*http.Cookie, error) {
+	reqURL, err := url.JoinPath(baseURL, LoginPath)
+	if err != nil {
+		return nil, fmt.Errorf("parse url error: %w", err)
+	}
+
+	form := url.Values{}
+	form.Set("log", username)
+	form.Set("pwd", password)
+	form.Set("rememberme", "forever")
+	form.Set("testcookie", "1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("new request error: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	// wp-login.php refuse the login without test cookie
+	req.AddCookie(&http.Cookie{Name: testCookieName, Value: "WP Cookie check"})
+
+	_, cookies, err := doLoginRequest(httpClient, req)
+	if err != nil {
+		return nil, fmt.Errorf("cookie login error: %w", err)
+	}
+
+	for _, cookie := range cookies {
+		if strings.HasPrefix(cookie.Name, loggedInCookiePrefix) {
+			return cookies, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cookie login error: %w: no logged in cookie", wordpressError.ErrLoginFailed)
+}
+
+// RestNonce get the nonce of rest api for the logged in cookies
+func RestNonce(ctx context.Context, httpClient *http.Client, baseURL string, cookies []*http.Cookie) (string, error) {
+	reqURL, err := url.JoinPath(baseURL, AdminAjaxPath)
+	if err != nil {
+		return "", fmt.Errorf("parse url error: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL+"?action=rest-nonce", nil)
+	if err != nil {
+		return "", fmt.Errorf("new request error: %w", err)
+	}
+
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	resBody, _, err := doLoginRequest(httpClient, req)
+	if err != nil {
+		return "", fmt.Errorf("rest nonce error: %w", err)
+	}
+
+	nonce := strings.TrimSpace(string(resBody))
+	// admin-ajax.php return 0 if the cookies are not logged in
+	if nonce == "" || nonce == "0" {
+		return "", fmt.Errorf("rest nonce error: %w: invalid nonce %q", wordpressError.ErrLoginFailed, nonce)
+	}
+
+	return nonce, nil
+}
+
+// doLoginRequest do request without following redirect, wp-login.php redirect after setting cookies
+func doLoginRequest(httpClient *http.Client, req *http.Request) ([]byte, []*http.Cookie, error) {
+	client := *httpClient
+	client.CheckRedirect = func(_ *http.Request, _ []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("request error: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read response error: %w", err)
+	}
+
+	// redirect is the success of wp-login.php
+	if res.StatusCode/100 == 3 { //nolint:mnd
+		return resBody, res.Cookies(), nil
+	}
+
+	statusCodeErr := wordpressError.NewHTTPStatusCodeError(res.StatusCode)
+	if statusCodeErr != nil {
+		return nil, nil, fmt.Errorf("request error: %w with message: %s", statusCodeErr, resBody)
+	}
+
+	return resBody, res.Cookies(), nil
+}
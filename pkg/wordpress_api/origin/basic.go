@@ -21,7 +21,7 @@ const (
 	APIPath = "wp-json/wp/v2"
 )
 
-func doRequest(ctx context.Context, baseURL string, method string, route string, basicAuth model.BasicAuthentication, parameter map[string]interface{}, body []byte) ([]byte, *RespHeader, error) {
+func doRequest(ctx context.Context, httpClient *http.Client, baseURL string, method string, route string, auth model.Authentication, parameter map[string]interface{}, body []byte) ([]byte, *RespHeader, error) {
 	reqURL, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, nil, fmt.Errorf("parse url error: %w", err)
@@ -42,17 +42,13 @@ func doRequest(ctx context.Context, baseURL string, method string, route string,
 		return nil, nil, fmt.Errorf("new request error: %w", err)
 	}
 
-	if !basicAuth.IsAnonymous {
-		req.SetBasicAuth(basicAuth.Username, basicAuth.Password)
-	}
+	auth.Apply(req)
 
 	if body != nil {
 		req.Header.Add("Content-Type", "application/json")
 	}
 
-	client := &http.Client{}
-
-	res, err := client.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return nil, nil, fmt.Errorf("request error: %w", err)
 	}
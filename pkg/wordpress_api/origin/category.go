@@ -10,7 +10,7 @@ import (
 )
 
 // ListCategory is a function to list category
-func ListCategory(ctx context.Context, baseURL string, basicAuth model.BasicAuthentication, args model.ListCategoryArgs) (model.ListCategoryResponse, error) {
+func ListCategory(ctx context.Context, httpClient *http.Client, baseURL string, auth model.Authentication, args model.ListCategoryArgs) (model.ListCategoryResponse, error) {
 	param, err := json.Marshal(args)
 	if err != nil {
 		return model.ListCategoryResponse{}, fmt.Errorf("marshal error: %w", err)
@@ -27,7 +27,7 @@ func ListCategory(ctx context.Context, baseURL string, basicAuth model.BasicAuth
 
 	route := "categories"
 
-	resBody, _, err := doRequest(ctx, baseURL, http.MethodGet, route, basicAuth, paramsMap, nil)
+	resBody, _, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, route, auth, paramsMap, nil)
 	if err != nil {
 		return model.ListCategoryResponse{}, fmt.Errorf("list category error: %w", err)
 	}
@@ -41,3 +41,59 @@ func ListCategory(ctx context.Context, baseURL string, basicAuth model.BasicAuth
 
 	return resData, nil
 }
+
+// CreateCategory is a function to create category
+func CreateCategory(ctx context.Context, httpClient *http.Client, baseURL string, auth model.Authentication, args model.CreateCategoryArgs) (model.CreateCategoryResponse, error) {
+	bytesData, err := json.Marshal(args)
+	if err != nil {
+		return model.CreateCategoryResponse{}, fmt.Errorf("marshal error: %w", err)
+	}
+
+	route := "categories"
+
+	resBody, _, err := doRequest(ctx, httpClient, baseURL, http.MethodPost, route, auth, nil, bytesData)
+	if err != nil {
+		return model.CreateCategoryResponse{}, fmt.Errorf("create category error: %w", err)
+	}
+
+	resData := model.CreateCategoryResponse{}
+	if err := json.Unmarshal(resBody, &resData); err != nil {
+		return model.CreateCategoryResponse{}, fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	return resData, nil
+}
+
+// UpdateCategory is a function to update category
+func UpdateCategory(ctx context.Context, httpClient *http.Client, baseURL string, auth model.Authentication, args model.UpdateCategoryArgs) (model.UpdateCategoryResponse, error) {
+	bytesData, err := json.Marshal(args)
+	if err != nil {
+		return model.UpdateCategoryResponse{}, fmt.Errorf("marshal error: %w", err)
+	}
+
+	route := fmt.Sprintf("categories/%d", args.ID)
+
+	resBody, _, err := doRequest(ctx, httpClient, baseURL, http.MethodPost, route, auth, nil, bytesData)
+	if err != nil {
+		return model.UpdateCategoryResponse{}, fmt.Errorf("update category error: %w", err)
+	}
+
+	resData := model.UpdateCategoryResponse{}
+	if err := json.Unmarshal(resBody, &resData); err != nil {
+		return model.UpdateCategoryResponse{}, fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	return resData, nil
+}
+
+// DeleteCategory is a function to delete category, terms do not support trash so it is always forced
+func DeleteCategory(ctx context.Context, httpClient *http.Client, baseURL string, auth model.Authentication, args model.DeleteCategoryArgs) error {
+	route := fmt.Sprintf("categories/%d", args.ID)
+
+	_, _, err := doRequest(ctx, httpClient, baseURL, http.MethodDelete, route, auth, map[string]interface{}{"force": true}, nil)
+	if err != nil {
+		return fmt.Errorf("delete category error: %w", err)
+	}
+
+	return nil
+}
@@ -10,7 +10,7 @@ import (
 )
 
 // ListComment is a function to list comment.
-func ListComment(ctx context.Context, baseURL string, basicAuth model.BasicAuthentication, args model.ListCommentArgs) (model.ListCommentResponse, error) {
+func ListComment(ctx context.Context, httpClient *http.Client, baseURL string, auth model.Authentication, args model.ListCommentArgs) (model.ListCommentResponse, error) {
 	param, err := json.Marshal(args)
 	if err != nil {
 		return model.ListCommentResponse{}, fmt.Errorf("marshal error: %w", err)
@@ -27,7 +27,7 @@ func ListComment(ctx context.Context, baseURL string, basicAuth model.BasicAuthe
 
 	route := "comments"
 
-	resBody, _, err := doRequest(ctx, baseURL, http.MethodGet, route, basicAuth, paramsMap, nil)
+	resBody, _, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, route, auth, paramsMap, nil)
 	if err != nil {
 		return model.ListCommentResponse{}, fmt.Errorf("list comment error: %w", err)
 	}
@@ -43,7 +43,7 @@ func ListComment(ctx context.Context, baseURL string, basicAuth model.BasicAuthe
 }
 
 // CreateComment is a function to create comment.
-func CreateComment(ctx context.Context, baseURL string, basicAuth model.BasicAuthentication, args model.CreateCommentArgs) (model.CreateCommentResponse, error) {
+func CreateComment(ctx context.Context, httpClient *http.Client, baseURL string, auth model.Authentication, args model.CreateCommentArgs) (model.CreateCommentResponse, error) {
 	bytesData, err := json.Marshal(args)
 	if err != nil {
 		return model.CreateCommentResponse{}, fmt.Errorf("marshal error: %w", err)
@@ -51,7 +51,7 @@ func CreateComment(ctx context.Context, baseURL string, basicAuth model.BasicAut
 
 	route := "comments"
 
-	resBody, _, err := doRequest(ctx, baseURL, http.MethodPost, route, basicAuth, nil, bytesData)
+	resBody, _, err := doRequest(ctx, httpClient, baseURL, http.MethodPost, route, auth, nil, bytesData)
 	if err != nil {
 		return model.CreateCommentResponse{}, fmt.Errorf("create comment error: %w", err)
 	}
@@ -11,7 +11,7 @@ import (
 )
 
 // ListTag is a function to list tag
-func ListTag(ctx context.Context, baseURL string, basicAuth model.BasicAuthentication, args model.ListTagArgs) (model.ListTagResponse, model.PageSchema, error) {
+func ListTag(ctx context.Context, httpClient *http.Client, baseURL string, auth model.Authentication, args model.ListTagArgs) (model.ListTagResponse, model.PageSchema, error) {
 	param, err := json.Marshal(args)
 	if err != nil {
 		return model.ListTagResponse{}, model.PageSchema{}, fmt.Errorf("marshal error: %w", err)
@@ -28,7 +28,7 @@ func ListTag(ctx context.Context, baseURL string, basicAuth model.BasicAuthentic
 
 	route := "tags"
 
-	resBody, header, err := doRequest(ctx, baseURL, http.MethodGet, route, basicAuth, paramsMap, nil)
+	resBody, header, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, route, auth, paramsMap, nil)
 	if err != nil {
 		return model.ListTagResponse{}, model.PageSchema{}, fmt.Errorf("list tag error: %w", err)
 	}
@@ -65,7 +65,7 @@ func ListTag(ctx context.Context, baseURL string, basicAuth model.BasicAuthentic
 }
 
 // CreateTag is a function to create tag
-func CreateTag(ctx context.Context, BaseUrl string, basicAuth model.BasicAuthentication, args model.CreateTagArgs) (model.CreateTagResponse, error) {
+func CreateTag(ctx context.Context, httpClient *http.Client, BaseUrl string, auth model.Authentication, args model.CreateTagArgs) (model.CreateTagResponse, error) {
 	bytesData, err := json.Marshal(args)
 	if err != nil {
 		return model.CreateTagResponse{}, fmt.Errorf("marshal error: %w", err)
@@ -73,7 +73,7 @@ func CreateTag(ctx context.Context, BaseUrl string, basicAuth model.BasicAuthent
 
 	route := "tags"
 
-	resBody, _, err := doRequest(ctx, BaseUrl, http.MethodPost, route, basicAuth, nil, bytesData)
+	resBody, _, err := doRequest(ctx, httpClient, BaseUrl, http.MethodPost, route, auth, nil, bytesData)
 	if err != nil {
 		return model.CreateTagResponse{}, fmt.Errorf("create tag error: %w", err)
 	}
@@ -9,7 +9,7 @@ import (
 	"github.com/ray31245/seo_cluster/pkg/wordpress_api/model"
 )
 
-func RetrieveUserMe(ctx context.Context, baseURL string, basicAuth model.BasicAuthentication, apiContext model.ApiContext) (model.RetrieveUserMeResponse, error) {
+func RetrieveUserMe(ctx context.Context, httpClient *http.Client, baseURL string, auth model.Authentication, apiContext model.ApiContext) (model.RetrieveUserMeResponse, error) {
 	paramsMap := map[string]interface{}{}
 	if apiContext != "" {
 		paramsMap["context"] = apiContext
@@ -19,7 +19,7 @@ func RetrieveUserMe(ctx context.Context, baseURL string, basicAuth model.BasicAu
 
 	// paramsMap["_fields"] = "id,name,slug,email,roles,avatar_urls"
 
-	resBody, _, err := doRequest(ctx, baseURL, http.MethodGet, route, basicAuth, paramsMap, nil)
+	resBody, _, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, route, auth, paramsMap, nil)
 	if err != nil {
 		return model.RetrieveUserMeResponse{}, fmt.Errorf("retrieve user me error: %w", err)
 	}
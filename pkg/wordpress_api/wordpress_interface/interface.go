@@ -4,14 +4,16 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
 	"github.com/ray31245/seo_cluster/pkg/wordpress_api/model"
 )
 
 type WordpressAPI interface {
-	GetClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string) (WordpressClient, error)
-	UpdateClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string) (WordpressClient, error)
+	// httpOptions override the http options of api for the site
+	GetClient(ctx context.Context, ID uuid.UUID, urlStr string, auth model.Authentication, httpOptions httpclient.Options) (WordpressClient, error)
+	UpdateClient(ctx context.Context, ID uuid.UUID, urlStr string, auth model.Authentication, httpOptions httpclient.Options) (WordpressClient, error)
 	DeleteClient(ID uuid.UUID)
-	NewClient(ctx context.Context, urlStr string, userName string, password string) (WordpressClient, error)
+	NewClient(ctx context.Context, urlStr string, auth model.Authentication, httpOptions httpclient.Options) (WordpressClient, error)
 	NewAnonymousClient(ctx context.Context, urlStr string) WordpressClient
 }
 
@@ -21,9 +23,13 @@ type WordpressClient interface {
 	ListTagAll(ctx context.Context) (model.ListTagResponse, error)
 	CreateTag(ctx context.Context, args model.CreateTagArgs) (model.CreateTagResponse, error)
 	ListCategory(ctx context.Context, args model.ListCategoryArgs) (model.ListCategoryResponse, error)
+	CreateCategory(ctx context.Context, args model.CreateCategoryArgs) (model.CreateCategoryResponse, error)
+	UpdateCategory(ctx context.Context, args model.UpdateCategoryArgs) (model.UpdateCategoryResponse, error)
+	DeleteCategory(ctx context.Context, args model.DeleteCategoryArgs) error
 	ListArticle(ctx context.Context, args model.ListArticleArgs) (model.ListArticleResponse, error)
 	CreateArticle(ctx context.Context, args model.CreateArticleArgs) (model.CreateArticleResponse, error)
 	UpdateArticle(ctx context.Context, args model.UpdateArticleArgs) (model.UpdateArticleResponse, error)
+	DeleteArticle(ctx context.Context, args model.DeleteArticleArgs) error
 	RetrieveArticle(ctx context.Context, args model.RetrieveArticleArgs) (model.RetrieveArticleResponse, error)
 	CreateComment(ctx context.Context, args model.CreateCommentArgs) (model.CreateCommentResponse, error)
 }
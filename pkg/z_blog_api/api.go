@@ -2,7 +2,11 @@ package zblogapi
 
 import (
 	"context"
+	"fmt"
 
+	clientpool "github.com/ray31245/seo_cluster/pkg/client_pool"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	"github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
 	zInterface "github.com/ray31245/seo_cluster/pkg/z_blog_api/z_blog_Interface"
 
 	"github.com/google/uuid"
@@ -11,52 +15,74 @@ import (
 var _ zInterface.ZBlogAPI = (*ZBlogAPI)(nil)
 
 type ZBlogAPI struct {
-	// TODO: clientPool should be sync.Map
 	// key is site id or user id
-	clientPool map[uuid.UUID]*Client
+	clientPool  *clientpool.Pool[*Client]
+	httpClients *httpclient.Factory
 }
 
-func NewZBlogAPI() *ZBlogAPI {
+// NewZBlogAPI create the api, the http clients of sites are built by httpClients with the options of site
+func NewZBlogAPI(poolOptions clientpool.Options, httpClients *httpclient.Factory) *ZBlogAPI {
 	return &ZBlogAPI{
-		clientPool: make(map[uuid.UUID]*Client),
+		clientPool:  clientpool.NewPool[*Client](poolOptions),
+		httpClients: httpClients,
 	}
 }
 
-func (t *ZBlogAPI) GetClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string) (zInterface.ZBlogAPIClient, error) {
-	client, ok := t.clientPool[ID]
-
-	var err error
-	if !ok {
-		client, err = NewClient(ctx, urlStr, userName, password)
-		if err != nil {
-			return nil, err
-		}
-
-		t.clientPool[ID] = client
+func (t *ZBlogAPI) GetClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string, httpOptions httpclient.Options) (zInterface.ZBlogAPIClient, error) {
+	client, err := t.clientPool.Get(ctx, ID, func(ctx context.Context) (*Client, error) {
+		return t.newClient(ctx, urlStr, userName, password, httpOptions)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return client, nil
 }
 
-func (t *ZBlogAPI) UpdateClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string) (zInterface.ZBlogAPIClient, error) {
-	client, err := NewClient(ctx, urlStr, userName, password)
+func (t *ZBlogAPI) UpdateClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string, httpOptions httpclient.Options) (zInterface.ZBlogAPIClient, error) {
+	client, err := t.newClient(ctx, urlStr, userName, password, httpOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	t.clientPool[ID] = client
+	t.clientPool.Put(ID, client)
 
 	return client, nil
 }
 
 func (t *ZBlogAPI) DeleteClient(ID uuid.UUID) {
-	delete(t.clientPool, ID)
+	t.clientPool.Delete(ID)
 }
 
-func (t *ZBlogAPI) NewClient(ctx context.Context, urlStr string, userName string, password string) (zInterface.ZBlogAPIClient, error) {
-	return NewClient(ctx, urlStr, userName, password)
+// PoolStats return the stats of client pool
+func (t *ZBlogAPI) PoolStats() clientpool.Stats {
+	return t.clientPool.Stats()
+}
+
+// ClientStats return the login state of clients in pool, key is site id or user id
+func (t *ZBlogAPI) ClientStats() map[uuid.UUID]model.ClientStats {
+	res := make(map[uuid.UUID]model.ClientStats)
+
+	t.clientPool.Range(func(ID uuid.UUID, client *Client) {
+		res[ID] = client.Stats()
+	})
+
+	return res
+}
+
+func (t *ZBlogAPI) NewClient(ctx context.Context, urlStr string, userName string, password string, httpOptions httpclient.Options) (zInterface.ZBlogAPIClient, error) {
+	return t.newClient(ctx, urlStr, userName, password, httpOptions)
 }
 
 func (t *ZBlogAPI) NewAnonymousClient(ctx context.Context, urlStr string) zInterface.ZBlogAPIClient {
-	return NewAnonymousClient(ctx, urlStr)
+	return NewAnonymousClientWithHTTPClient(ctx, t.httpClients.DefaultClient(), urlStr)
+}
+
+func (t *ZBlogAPI) newClient(ctx context.Context, urlStr string, userName string, password string, httpOptions httpclient.Options) (*Client, error) {
+	httpClient, err := t.httpClients.Client(httpOptions)
+	if err != nil {
+		return nil, fmt.Errorf("newClient: %w", err)
+	}
+
+	return NewClientWithHTTPClient(ctx, httpClient, urlStr, userName, password)
 }
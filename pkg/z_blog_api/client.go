@@ -3,37 +3,50 @@ package zblogapi
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"sync"
+	"time"
 
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
 	"github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
 	"github.com/ray31245/seo_cluster/pkg/z_blog_api/origin"
 )
 
 type Client struct {
-	baseURL  string
-	token    string
-	userName string
-	password string
-	// avoid duplicate login
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	userName   string
+	password   string
+	// lock make the concurrent re-login of requests share one login
 	lock        *sync.Mutex
 	isAnonymous bool
+	// stateLock guard token and the login state below, it is not held during request
+	stateLock   sync.RWMutex
+	loginAt     time.Time
+	tokenExpire time.Time
+	logins      int
+	// loginGen is increased by every login, loginErr is the result of last login
+	loginGen uint64
+	loginErr error
 }
 
 func NewClient(ctx context.Context, urlStr string, userName string, password string) (*Client, error) {
-	log.Println("following url is used to login")
-	log.Println(urlStr)
-	log.Println("following username is used to login")
-	log.Println(userName)
-	log.Println("following password is used to login")
-	log.Println(password)
+	return NewClientWithHTTPClient(ctx, httpclient.NewDefaultClient(), urlStr, userName, password)
+}
+
+// NewClientWithHTTPClient is NewClient sending the requests by httpClient
+func NewClientWithHTTPClient(ctx context.Context, httpClient *http.Client, urlStr string, userName string, password string) (*Client, error) {
+	slog.InfoContext(ctx, "login zblog", slog.String("url", urlStr), slog.String("username", userName))
 
 	res := &Client{
-		baseURL:  urlStr,
-		lock:     &sync.Mutex{},
-		userName: userName,
-		password: password,
-		token:    "YmV2aXN8fHw3ZWYxMmJkNTQ1ZmU5MTRhNTMwYTFlYjMyODUxYTA5YTg4YjE0OGRmYjExN2Y2ODRkZmZmNzM1ZjM2YTcwMmI4MTcyMjQxODY0OA==",
+		baseURL:    urlStr,
+		httpClient: httpClient,
+		lock:       &sync.Mutex{},
+		userName:   userName,
+		password:   password,
+		token:      "YmV2aXN8fHw3ZWYxMmJkNTQ1ZmU5MTRhNTMwYTFlYjMyODUxYTA5YTg4YjE0OGRmYjExN2Y2ODRkZmZmNzM1ZjM2YTcwMmI4MTcyMjQxODY0OA==",
 	}
 
 	err := res.Login(ctx)
@@ -45,8 +58,14 @@ func NewClient(ctx context.Context, urlStr string, userName string, password str
 }
 
 func NewAnonymousClient(ctx context.Context, urlStr string) *Client {
+	return NewAnonymousClientWithHTTPClient(ctx, httpclient.NewDefaultClient(), urlStr)
+}
+
+// NewAnonymousClientWithHTTPClient is NewAnonymousClient sending the requests by httpClient
+func NewAnonymousClientWithHTTPClient(_ context.Context, httpClient *http.Client, urlStr string) *Client {
 	res := &Client{
 		baseURL:     urlStr,
+		httpClient:  httpClient,
 		lock:        &sync.Mutex{},
 		isAnonymous: true,
 	}
@@ -55,25 +74,106 @@ func NewAnonymousClient(ctx context.Context, urlStr string) *Client {
 }
 
 func (t *Client) Login(ctx context.Context) error {
-	resData, err := origin.Login(ctx, t.baseURL, t.token, t.userName, t.password)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.login(ctx)
+}
+
+// login must be called with lock held
+func (t *Client) login(ctx context.Context) error {
+	resData, err := origin.Login(ctx, t.httpClient, t.baseURL, t.currentToken(), t.userName, t.password)
+
+	t.stateLock.Lock()
+	defer t.stateLock.Unlock()
+
+	t.loginGen++
+	t.loginErr = err
+
 	if err != nil {
-		return fmt.Errorf("login error: %w", err)
+		t.loginErr = fmt.Errorf("login error: %w", err)
+
+		return t.loginErr
 	}
 
 	t.token = resData.Data.Token
+	t.loginAt = time.Now()
+	t.tokenExpire = time.Time{}
+	// some sites report zero or past time when token is not expired
+	if resData.Data.ExpireTime.After(t.loginAt) {
+		t.tokenExpire = resData.Data.ExpireTime.Time
+	}
+	t.logins++
 
-	log.Println("login success")
+	slog.DebugContext(ctx, "login zblog success", slog.String("url", t.baseURL))
 
 	return nil
 }
 
+// relogin login again unless another login is done after the one of gen,
+// so the requests rejected by the same token share one login and its result
+func (t *Client) relogin(ctx context.Context, gen uint64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.stateLock.RLock()
+	currentGen, loginErr := t.loginGen, t.loginErr
+	t.stateLock.RUnlock()
+
+	if currentGen != gen {
+		return loginErr
+	}
+
+	return t.login(ctx)
+}
+
+func (t *Client) currentToken() string {
+	t.stateLock.RLock()
+	defer t.stateLock.RUnlock()
+
+	return t.token
+}
+
+// session return the token and the generation of login it come from
+func (t *Client) session() (string, uint64) {
+	t.stateLock.RLock()
+	defer t.stateLock.RUnlock()
+
+	return t.token, t.loginGen
+}
+
+// Stats return the login state of client
+func (t *Client) Stats() model.ClientStats {
+	t.stateLock.RLock()
+	defer t.stateLock.RUnlock()
+
+	res := model.ClientStats{
+		LoginAt:       t.loginAt,
+		TokenExpireAt: t.tokenExpire,
+		Logins:        t.logins,
+	}
+	if !t.loginAt.IsZero() {
+		res.TokenAgeSeconds = int64(time.Since(t.loginAt).Seconds())
+	}
+
+	return res
+}
+
+// tokenExpiring report the token will expire within tokenRefreshMargin
+func (t *Client) tokenExpiring() bool {
+	t.stateLock.RLock()
+	defer t.stateLock.RUnlock()
+
+	return !t.tokenExpire.IsZero() && time.Until(t.tokenExpire) < tokenRefreshMargin
+}
+
 func (t *Client) ListMember(ctx context.Context) ([]model.Member, error) {
 	res := model.ListMemberResponse{}
 
 	var err error
 
-	task := func() error {
-		res, err = origin.ListMember(ctx, t.baseURL, t.token)
+	task := func(token string) error {
+		res, err = origin.ListMember(ctx, t.httpClient, t.baseURL, token)
 		if err != nil {
 			return fmt.Errorf("ListMember: %w", err)
 		}
@@ -92,8 +192,8 @@ func (t *Client) PostMember(ctx context.Context, mem model.PostMemberRequest) er
 		mem.ID = "0"
 	}
 
-	task := func() error {
-		err = origin.PostMember(ctx, t.baseURL, t.token, mem)
+	task := func(token string) error {
+		err = origin.PostMember(ctx, t.httpClient, t.baseURL, token, mem)
 		if err != nil {
 			return fmt.Errorf("PostMember: %w", err)
 		}
@@ -110,8 +210,8 @@ func (t *Client) PostArticle(ctx context.Context, art model.PostArticleRequest)
 
 	var err error
 
-	task := func() error {
-		res, err = origin.PostArticle(ctx, t.baseURL, t.token, art)
+	task := func(token string) error {
+		res, err = origin.PostArticle(ctx, t.httpClient, t.baseURL, token, art)
 		if err != nil {
 			return fmt.Errorf("PostArticle: %w", err)
 		}
@@ -128,8 +228,8 @@ func (t *Client) GetArticle(ctx context.Context, id string) (model.Article, erro
 
 	var err error
 
-	task := func() error {
-		res, err = origin.GetArticle(ctx, t.baseURL, t.token, id)
+	task := func(token string) error {
+		res, err = origin.GetArticle(ctx, t.httpClient, t.baseURL, token, id)
 		if err != nil {
 			return fmt.Errorf("GetArticle: %w", err)
 		}
@@ -139,7 +239,7 @@ func (t *Client) GetArticle(ctx context.Context, id string) (model.Article, erro
 	if !t.isAnonymous {
 		err = t.retry(ctx, task)
 	} else {
-		err = task()
+		err = task(t.currentToken())
 	}
 
 	return res.Data.Post, err
@@ -150,8 +250,8 @@ func (t *Client) ListArticle(ctx context.Context, req model.ListArticleRequest)
 
 	var err error
 
-	task := func() error {
-		res, err = origin.ListArticle(ctx, t.baseURL, t.token, req)
+	task := func(token string) error {
+		res, err = origin.ListArticle(ctx, t.httpClient, t.baseURL, token, req)
 		if err != nil {
 			return fmt.Errorf("ListArticle: %w", err)
 		}
@@ -161,7 +261,7 @@ func (t *Client) ListArticle(ctx context.Context, req model.ListArticleRequest)
 	if !t.isAnonymous {
 		err = t.retry(ctx, task)
 	} else {
-		err = task()
+		err = task(t.currentToken())
 	}
 
 	return res.Data.List, err
@@ -172,8 +272,8 @@ func (t *Client) GetCountOfArticle(ctx context.Context, req model.ListArticleReq
 
 	var err error
 
-	task := func() error {
-		res, err = origin.ListArticle(ctx, t.baseURL, t.token, req)
+	task := func(token string) error {
+		res, err = origin.ListArticle(ctx, t.httpClient, t.baseURL, token, req)
 		if err != nil {
 			return fmt.Errorf("ListArticle: %w", err)
 		}
@@ -188,8 +288,8 @@ func (t *Client) GetCountOfArticle(ctx context.Context, req model.ListArticleReq
 func (t *Client) DeleteArticle(ctx context.Context, id string) error {
 	var err error
 
-	task := func() error {
-		err = origin.DeleteArticle(ctx, t.baseURL, t.token, id)
+	task := func(token string) error {
+		err = origin.DeleteArticle(ctx, t.httpClient, t.baseURL, token, id)
 		if err != nil {
 			return fmt.Errorf("DeleteArticle: %w", err)
 		}
@@ -204,8 +304,8 @@ func (t *Client) DeleteArticle(ctx context.Context, id string) error {
 func (t *Client) PostComment(ctx context.Context, comment model.PostCommentRequest) error {
 	var err error
 
-	task := func() error {
-		err = origin.PostComment(ctx, t.baseURL, t.token, comment)
+	task := func(token string) error {
+		err = origin.PostComment(ctx, t.httpClient, t.baseURL, token, comment)
 		if err != nil {
 			return fmt.Errorf("PostComment: %w", err)
 		}
@@ -222,8 +322,8 @@ func (t *Client) ListCategory(ctx context.Context) ([]model.Category, error) {
 
 	var err error
 
-	task := func() error {
-		res, err = origin.ListCategory(ctx, t.baseURL, t.token)
+	task := func(token string) error {
+		res, err = origin.ListCategory(ctx, t.httpClient, t.baseURL, token)
 		if err != nil {
 			return fmt.Errorf("ListCategory: %w", err)
 		}
@@ -235,13 +335,47 @@ func (t *Client) ListCategory(ctx context.Context) ([]model.Category, error) {
 	return res.Data.List, err
 }
 
+func (t *Client) PostCategory(ctx context.Context, cate model.PostCategoryRequest) (model.Category, error) {
+	res := model.PostCategoryResponse{}
+
+	var err error
+
+	task := func(token string) error {
+		res, err = origin.PostCategory(ctx, t.httpClient, t.baseURL, token, cate)
+		if err != nil {
+			return fmt.Errorf("PostCategory: %w", err)
+		}
+
+		return nil
+	}
+	err = t.retry(ctx, task)
+
+	return res.Data.Category, err
+}
+
+func (t *Client) DeleteCategory(ctx context.Context, id uint32) error {
+	var err error
+
+	task := func(token string) error {
+		err = origin.DeleteCategory(ctx, t.httpClient, t.baseURL, token, id)
+		if err != nil {
+			return fmt.Errorf("DeleteCategory: %w", err)
+		}
+
+		return nil
+	}
+	err = t.retry(ctx, task)
+
+	return err
+}
+
 func (t *Client) ListTag(ctx context.Context, req model.ListTagRequest) ([]model.Tag, error) {
 	res := model.ListTagResponse{}
 
 	var err error
 
-	task := func() error {
-		res, err = origin.ListTag(ctx, t.baseURL, t.token, req)
+	task := func(token string) error {
+		res, err = origin.ListTag(ctx, t.httpClient, t.baseURL, token, req)
 		if err != nil {
 			return fmt.Errorf("ListTag: %w", err)
 		}
@@ -264,8 +398,8 @@ func (t *Client) ListTagAll(ctx context.Context) ([]model.Tag, error) {
 			Page: 1,
 		},
 	}
-	task := func() error {
-		taskRes, err = origin.ListTag(ctx, t.baseURL, t.token, taskReq)
+	task := func(token string) error {
+		taskRes, err = origin.ListTag(ctx, t.httpClient, t.baseURL, token, taskReq)
 		if err != nil {
 			return fmt.Errorf("ListTagAll: %w", err)
 		}
@@ -295,8 +429,8 @@ func (t *Client) PostTag(ctx context.Context, tag model.PostTagRequest) (model.T
 
 	var err error
 
-	task := func() error {
-		res, err = origin.PostTag(ctx, t.baseURL, t.token, tag)
+	task := func(token string) error {
+		res, err = origin.PostTag(ctx, t.httpClient, t.baseURL, token, tag)
 		if err != nil {
 			return fmt.Errorf("PostTag: %w", err)
 		}
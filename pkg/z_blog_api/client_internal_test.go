@@ -7,9 +7,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
 	zBlogErr "github.com/ray31245/seo_cluster/pkg/z_blog_api/error"
+	zModel "github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
 	"github.com/ray31245/seo_cluster/pkg/z_blog_api/origin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -137,11 +141,12 @@ func TestClient_Login(t *testing.T) {
 			assert := assert.New(t)
 			require := require.New(t)
 			tr := &Client{
-				baseURL:  tt.fields.baseURL,
-				token:    tt.fields.token,
-				userName: tt.fields.userName,
-				password: tt.fields.password,
-				lock:     &sync.Mutex{},
+				baseURL:    tt.fields.baseURL,
+				httpClient: httpclient.NewDefaultClient(),
+				token:      tt.fields.token,
+				userName:   tt.fields.userName,
+				password:   tt.fields.password,
+				lock:       &sync.Mutex{},
 			}
 
 			err := tr.Login(context.Background())
@@ -161,3 +166,188 @@ func TestClient_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_TokenRefresh(t *testing.T) {
+	t.Parallel()
+
+	var (
+		logins   atomic.Int32
+		tokenTTL atomic.Int64
+	)
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("act") {
+		case "login":
+			logins.Add(1)
+
+			expire := time.Now().Add(time.Duration(tokenTTL.Load())).Unix()
+			_, _ = fmt.Fprintf(w, `{"code":200,"data":{"token":"%s","expire_time":%d}}`, TestToken, expire)
+		default:
+			_, _ = w.Write([]byte(`{"code":200,"data":{"list":[]}}`))
+		}
+	}))
+
+	t.Cleanup(svr.Close)
+
+	tokenTTL.Store(int64(time.Hour))
+
+	client, err := NewClient(context.Background(), svr.URL, TestUserName, TestPassword)
+	require.NoError(t, err)
+
+	stats := client.Stats()
+	assert.Equal(t, 1, stats.Logins)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), stats.TokenExpireAt, 2*time.Second)
+
+	t.Run("token is kept before it expire", func(t *testing.T) {
+		_, err := client.ListMember(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), logins.Load())
+	})
+
+	t.Run("login again ahead of expiration", func(t *testing.T) {
+		tokenTTL.Store(int64(time.Minute))
+		require.NoError(t, client.Login(context.Background()))
+		assert.Equal(t, int32(2), logins.Load())
+
+		_, err := client.ListMember(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), logins.Load())
+		assert.Equal(t, 3, client.Stats().Logins)
+	})
+
+	t.Run("expiration is unknown", func(t *testing.T) {
+		tokenTTL.Store(0)
+		require.NoError(t, client.Login(context.Background()))
+
+		_, err := client.ListMember(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int32(4), logins.Load())
+		assert.True(t, client.Stats().TokenExpireAt.IsZero())
+	})
+}
+
+// newTokenServer return the server accepting only the token of last login,
+// expire make the current token rejected, delay is added to every request except login
+func newTokenServer(t testing.TB, delay time.Duration) (svr *httptest.Server, logins *atomic.Int32, inFlight *atomic.Int32, maxInFlight *atomic.Int32, expire func()) {
+	t.Helper()
+
+	var (
+		lock  sync.Mutex
+		token string
+	)
+
+	logins, inFlight, maxInFlight = &atomic.Int32{}, &atomic.Int32{}, &atomic.Int32{}
+
+	svr = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("act") == "login" {
+			lock.Lock()
+			token = fmt.Sprintf("token-%d", logins.Add(1))
+			_, _ = fmt.Fprintf(w, `{"code":200,"data":{"token":"%s"}}`, token)
+			lock.Unlock()
+
+			return
+		}
+
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		time.Sleep(delay)
+
+		lock.Lock()
+		valid := r.Header.Get("Authorization") == "Bearer "+token
+		lock.Unlock()
+
+		if !valid {
+			http.Error(w, `{"code":401,"message":"unauthorized"}`, http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"code":200,"data":{"tag":{"ID":"1","Name":"tag"}}}`))
+	}))
+
+	t.Cleanup(svr.Close)
+
+	expire = func() {
+		lock.Lock()
+		token = ""
+		lock.Unlock()
+	}
+
+	return svr, logins, inFlight, maxInFlight, expire
+}
+
+func TestClient_ConcurrentRelogin(t *testing.T) {
+	t.Parallel()
+
+	svr, logins, _, maxInFlight, expire := newTokenServer(t, 20*time.Millisecond)
+
+	client, err := NewClient(context.Background(), svr.URL, TestUserName, TestPassword)
+	require.NoError(t, err)
+
+	const requests = 20
+
+	postTags := func() {
+		var wg sync.WaitGroup
+
+		errs := make(chan error, requests)
+
+		for i := range requests {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				_, err := client.PostTag(context.Background(), zModel.PostTagRequest{Name: fmt.Sprintf("tag%d", i)})
+				errs <- err
+			}()
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("requests run concurrently", func(t *testing.T) {
+		postTags()
+		assert.Equal(t, int32(1), logins.Load())
+		assert.Greater(t, maxInFlight.Load(), int32(1))
+	})
+
+	t.Run("rejected requests share one login", func(t *testing.T) {
+		expire()
+		postTags()
+		assert.Equal(t, int32(2), logins.Load())
+		assert.Equal(t, 2, client.Stats().Logins)
+	})
+}
+
+// BenchmarkClient_PostTag measure the throughput of posting tags of article concurrently by one client
+func BenchmarkClient_PostTag(b *testing.B) {
+	svr, _, _, _, _ := newTokenServer(b, time.Millisecond)
+
+	client, err := NewClient(context.Background(), svr.URL, TestUserName, TestPassword)
+	require.NoError(b, err)
+
+	b.SetParallelism(4)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, err := client.PostTag(context.Background(), zModel.PostTagRequest{Name: "tag"})
+			if err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
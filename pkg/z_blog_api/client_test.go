@@ -485,3 +485,77 @@ func TestClient_ListCategory(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_DeleteCategory(t *testing.T) {
+	t.Parallel()
+
+	srv := newFullMockServer()
+	faultSrv := newMockFaultServer(origin.ModCategory, origin.ActDelete)
+
+	t.Cleanup(func() {
+		srv.Close()
+		faultSrv.Close()
+	})
+
+	type fields struct {
+		baseURL  string
+		userName string
+		password string
+	}
+
+	type args struct {
+		id uint32
+	}
+
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantErr    bool
+		expectErrs []error
+	}{
+		{
+			name: "normal",
+			fields: fields{
+				baseURL:  srv.URL,
+				userName: zAPI.TestUserName,
+				password: zAPI.TestPassword,
+			},
+			args: args{
+				id: 1,
+			},
+		},
+		{
+			name: "fault",
+			fields: fields{
+				baseURL:  faultSrv.URL,
+				userName: zAPI.TestUserName,
+				password: zAPI.TestPassword,
+			},
+			args: args{
+				id: 1,
+			},
+			wantErr: true,
+			expectErrs: []error{
+				zBlogErr.ErrHTTPInternal,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require := require.New(t)
+			tr, err := zAPI.NewClient(context.Background(), tt.fields.baseURL, tt.fields.userName, tt.fields.password)
+			require.NoError(err)
+
+			if err := tr.DeleteCategory(context.Background(), tt.args.id); (err != nil) != tt.wantErr {
+				t.Errorf("Client.DeleteCategory() error = %v, wantErr %v", err, tt.wantErr)
+			} else if len(tt.expectErrs) > 0 {
+				for _, expectErr := range tt.expectErrs {
+					require.ErrorIs(err, expectErr)
+				}
+			}
+		})
+	}
+}
@@ -4,22 +4,39 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	zBlogErr "github.com/ray31245/seo_cluster/pkg/z_blog_api/error"
 )
 
-func (t *Client) retry(ctx context.Context, f func() error) error {
-	t.lock.Lock()
-	defer t.lock.Unlock()
+// tokenRefreshMargin is the time before expiration of token to login again
+const tokenRefreshMargin = 5 * time.Minute
 
-	err := f()
+// retry run f with current token, and run it again after login if the token is rejected.
+// Requests run concurrently, only the login is serialized and shared by the requests waiting for it.
+func (t *Client) retry(ctx context.Context, f func(token string) error) error {
+	token, gen := t.session()
+
+	// login ahead of expiration instead of waiting the request is rejected
+	if !t.isAnonymous && t.tokenExpiring() {
+		err := t.relogin(ctx, gen)
+		if err != nil {
+			return fmt.Errorf("retry error: %w", err)
+		}
+
+		token, gen = t.session()
+	}
+
+	err := f(token)
 	if errors.Is(err, zBlogErr.ErrHTTPUnauthorized) || errors.Is(err, zBlogErr.ErrHTTPForbidden) || errors.Is(err, zBlogErr.ErrIllegalAccess) {
-		err = t.Login(ctx)
+		err = t.relogin(ctx, gen)
 		if err != nil {
 			return fmt.Errorf("retry error: %w", err)
 		}
 
-		err = f()
+		token, _ = t.session()
+
+		err = f(token)
 		if err != nil {
 			return fmt.Errorf("retry error: %w", err)
 		}
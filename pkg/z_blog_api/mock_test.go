@@ -56,7 +56,8 @@ func fullRouteTable() routeTable {
 			"delete": deleteArticleHandler,
 		},
 		"category": map[string]http.HandlerFunc{
-			"list": ListCategoryHandler,
+			"list":   ListCategoryHandler,
+			"delete": deleteCategoryHandler,
 		},
 	}
 }
@@ -242,6 +243,27 @@ var deleteArticleHandler = func(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+var deleteCategoryHandler = func(w http.ResponseWriter, r *http.Request) {
+	ok := validator(w, r, http.MethodGet, origin.ModCategory, origin.ActDelete)
+	if !ok {
+		return
+	}
+
+	// assert delete info is correct
+	id := r.URL.Query().Get("id")
+	if id == "" || id == "0" {
+		http.Error(w, `{"code":400,"message":"bad request"}`, http.StatusBadRequest)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write([]byte(`{"code":200}`))
+	if err != nil {
+		http.Error(w, `{"code":500,"message":"internal server error"}`, http.StatusInternalServerError)
+	}
+}
+
 var ListCategoryHandler = func(w http.ResponseWriter, r *http.Request) {
 	ok := validator(w, r, http.MethodGet, origin.ModCategory, origin.ActList)
 	if !ok {
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/ray31245/seo_cluster/pkg/util"
 )
 
@@ -100,6 +102,17 @@ type ListCategoryResponse struct {
 	Data Data[Category] `json:"data"`
 }
 
+type PostCategoryResponse struct {
+	BasicResponse
+	Data struct {
+		Category Category `json:"category"`
+	} `json:"data"`
+}
+
+type DeleteCategoryResponse struct {
+	BasicResponse
+}
+
 type Data[E any] struct {
 	List    []E     `json:"list"`
 	PageBar PageBar `json:"pagebar"`
@@ -190,3 +203,25 @@ type PostTagRequest struct {
 	ID   uint32 `json:"ID"`
 	Name string `json:"Name"`
 }
+
+// PostCategoryRequest create category if ID is zero, otherwise update the category of ID
+type PostCategoryRequest struct {
+	ID   uint32 `json:"ID"`
+	Name string `json:"Name"`
+	// ParentID and Intro are omitted when empty to keep them on update
+	ParentID uint32 `json:"ParentID,omitempty"`
+	Intro    string `json:"Intro,omitempty"`
+}
+
+// ----stats----
+
+// ClientStats is the login state of client
+type ClientStats struct {
+	LoginAt time.Time `json:"login_at"`
+	// TokenExpireAt is zero if the site does not report the expiration of token
+	TokenExpireAt time.Time `json:"token_expire_at"`
+	// TokenAgeSeconds is the seconds since the token is issued
+	TokenAgeSeconds int64 `json:"token_age_seconds"`
+	// Logins is the number of login including the first one
+	Logins int `json:"logins"`
+}
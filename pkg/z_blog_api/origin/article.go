@@ -10,13 +10,13 @@ import (
 	"github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
 )
 
-func PostArticle(ctx context.Context, baseURL string, token string, art model.PostArticleRequest) (model.PostArticleResponse, error) {
+func PostArticle(ctx context.Context, httpClient *http.Client, baseURL string, token string, art model.PostArticleRequest) (model.PostArticleResponse, error) {
 	bytesData, err := util.EscapeHTMLMarshal(art)
 	if err != nil {
 		return model.PostArticleResponse{}, fmt.Errorf("marshal error: %w", err)
 	}
 
-	resBody, err := doRequest(ctx, baseURL, http.MethodPost, token, map[string]interface{}{ParamMod: ModPost, ParamAct: ActPost}, bytesData)
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodPost, token, map[string]interface{}{ParamMod: ModPost, ParamAct: ActPost}, bytesData)
 	if err != nil {
 		return model.PostArticleResponse{}, fmt.Errorf("post article error: %w", err)
 	}
@@ -29,13 +29,13 @@ func PostArticle(ctx context.Context, baseURL string, token string, art model.Po
 	return resData, nil
 }
 
-func GetArticle(ctx context.Context, baseURL string, token string, id string) (model.GetArticleResponse, error) {
+func GetArticle(ctx context.Context, httpClient *http.Client, baseURL string, token string, id string) (model.GetArticleResponse, error) {
 	paramsMap := map[string]interface{}{}
 	paramsMap["mod"] = "post"
 	paramsMap["act"] = "get"
 	paramsMap["id"] = id
 
-	resBody, err := doRequest(ctx, baseURL, http.MethodGet, token, paramsMap, nil)
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, token, paramsMap, nil)
 	if err != nil {
 		return model.GetArticleResponse{}, fmt.Errorf("get article error: %w", err)
 	}
@@ -48,7 +48,7 @@ func GetArticle(ctx context.Context, baseURL string, token string, id string) (m
 	return resData, nil
 }
 
-func ListArticle(ctx context.Context, baseURL string, token string, req model.ListArticleRequest) (model.ListArticleResponse, error) {
+func ListArticle(ctx context.Context, httpClient *http.Client, baseURL string, token string, req model.ListArticleRequest) (model.ListArticleResponse, error) {
 	params, err := json.Marshal(req)
 	if err != nil {
 		return model.ListArticleResponse{}, fmt.Errorf("marshal error: %w", err)
@@ -64,7 +64,7 @@ func ListArticle(ctx context.Context, baseURL string, token string, req model.Li
 	paramsMap[ParamMod] = ModPost
 	paramsMap[ParamAct] = ActList
 
-	resBody, err := doRequest(ctx, baseURL, http.MethodGet, token, paramsMap, nil)
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, token, paramsMap, nil)
 	if err != nil {
 		return model.ListArticleResponse{}, fmt.Errorf("list article error: %w", err)
 	}
@@ -77,13 +77,13 @@ func ListArticle(ctx context.Context, baseURL string, token string, req model.Li
 	return resData, nil
 }
 
-func DeleteArticle(ctx context.Context, baseURL string, token string, id string) error {
+func DeleteArticle(ctx context.Context, httpClient *http.Client, baseURL string, token string, id string) error {
 	paramsMap := map[string]interface{}{}
 	paramsMap["mod"] = "post"
 	paramsMap["act"] = "delete"
 	paramsMap["id"] = id
 
-	resBody, err := doRequest(ctx, baseURL, http.MethodGet, token, paramsMap, nil)
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, token, paramsMap, nil)
 	if err != nil {
 		return fmt.Errorf("delete article error: %w", err)
 	}
@@ -32,7 +32,7 @@ const (
 	ParamAct = "act"
 )
 
-func doRequest(ctx context.Context, baseURL string, method string, token string, parameter map[string]interface{}, body []byte) ([]byte, error) {
+func doRequest(ctx context.Context, httpClient *http.Client, baseURL string, method string, token string, parameter map[string]interface{}, body []byte) ([]byte, error) {
 	reqURL, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("parse url error: %w", err)
@@ -54,9 +54,7 @@ func doRequest(ctx context.Context, baseURL string, method string, token string,
 
 	req.Header.Add("Authorization", "Bearer "+token)
 
-	client := &http.Client{}
-
-	res, err := client.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request error: %w", err)
 	}
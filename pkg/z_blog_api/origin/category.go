@@ -6,11 +6,12 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/ray31245/seo_cluster/pkg/util"
 	"github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
 )
 
-func ListCategory(ctx context.Context, baseURL string, token string) (model.ListCategoryResponse, error) {
-	resBody, err := doRequest(ctx, baseURL, http.MethodGet, token, map[string]interface{}{ParamMod: ModCategory, ParamAct: ActList}, nil)
+func ListCategory(ctx context.Context, httpClient *http.Client, baseURL string, token string) (model.ListCategoryResponse, error) {
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, token, map[string]interface{}{ParamMod: ModCategory, ParamAct: ActList}, nil)
 	if err != nil {
 		return model.ListCategoryResponse{}, fmt.Errorf("list category error: %w", err)
 	}
@@ -22,3 +23,36 @@ func ListCategory(ctx context.Context, baseURL string, token string) (model.List
 
 	return resData, nil
 }
+
+func PostCategory(ctx context.Context, httpClient *http.Client, baseURL string, token string, req model.PostCategoryRequest) (model.PostCategoryResponse, error) {
+	bytesData, err := util.EscapeHTMLMarshal(req)
+	if err != nil {
+		return model.PostCategoryResponse{}, fmt.Errorf("marshal error: %w", err)
+	}
+
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodPost, token, map[string]interface{}{ParamMod: ModCategory, ParamAct: ActPost}, bytesData)
+	if err != nil {
+		return model.PostCategoryResponse{}, fmt.Errorf("post category error: %w", err)
+	}
+
+	resData := model.PostCategoryResponse{}
+	if err := json.Unmarshal(resBody, &resData); err != nil {
+		return model.PostCategoryResponse{}, fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	return resData, nil
+}
+
+func DeleteCategory(ctx context.Context, httpClient *http.Client, baseURL string, token string, id uint32) error {
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, token, map[string]interface{}{ParamMod: ModCategory, ParamAct: ActDelete, "id": id}, nil)
+	if err != nil {
+		return fmt.Errorf("delete category error: %w", err)
+	}
+
+	resData := model.DeleteCategoryResponse{}
+	if err := json.Unmarshal(resBody, &resData); err != nil {
+		return fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	return nil
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
 )
 
-func Login(ctx context.Context, baseURL string, token string, userName string, password string) (model.LoginResponse, error) {
+func Login(ctx context.Context, httpClient *http.Client, baseURL string, token string, userName string, password string) (model.LoginResponse, error) {
 	data := map[string]interface{}{}
 	data["username"] = userName
 	data["password"] = password
@@ -20,7 +20,7 @@ func Login(ctx context.Context, baseURL string, token string, userName string, p
 		return model.LoginResponse{}, fmt.Errorf("marshal error: %w", err)
 	}
 
-	resBody, err := doRequest(ctx, baseURL, http.MethodPost, token, map[string]interface{}{ParamMod: ModMember, ParamAct: ActLogin}, bytesData)
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodPost, token, map[string]interface{}{ParamMod: ModMember, ParamAct: ActLogin}, bytesData)
 	if err != nil {
 		return model.LoginResponse{}, fmt.Errorf("login error: %w", err)
 	}
@@ -33,8 +33,8 @@ func Login(ctx context.Context, baseURL string, token string, userName string, p
 	return resData, nil
 }
 
-func ListMember(ctx context.Context, baseURL string, token string) (model.ListMemberResponse, error) {
-	resBody, err := doRequest(ctx, baseURL, http.MethodGet, token, map[string]interface{}{ParamMod: ModMember, ParamAct: ActList}, nil)
+func ListMember(ctx context.Context, httpClient *http.Client, baseURL string, token string) (model.ListMemberResponse, error) {
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, token, map[string]interface{}{ParamMod: ModMember, ParamAct: ActList}, nil)
 	if err != nil {
 		return model.ListMemberResponse{}, fmt.Errorf("list member error: %w", err)
 	}
@@ -47,13 +47,13 @@ func ListMember(ctx context.Context, baseURL string, token string) (model.ListMe
 	return resData, nil
 }
 
-func PostMember(ctx context.Context, baseURL string, token string, member model.PostMemberRequest) error {
+func PostMember(ctx context.Context, httpClient *http.Client, baseURL string, token string, member model.PostMemberRequest) error {
 	bytesData, err := json.Marshal(member)
 	if err != nil {
 		return fmt.Errorf("marshal error: %w", err)
 	}
 
-	resBody, err := doRequest(ctx, baseURL, http.MethodPost, token, map[string]interface{}{ParamMod: ModMember, ParamAct: ActPost}, bytesData)
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodPost, token, map[string]interface{}{ParamMod: ModMember, ParamAct: ActPost}, bytesData)
 	if err != nil {
 		return fmt.Errorf("post member error: %w", err)
 	}
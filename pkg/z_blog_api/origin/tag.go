@@ -10,7 +10,7 @@ import (
 	"github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
 )
 
-func ListTag(ctx context.Context, baseURL string, token string, req model.ListTagRequest) (model.ListTagResponse, error) {
+func ListTag(ctx context.Context, httpClient *http.Client, baseURL string, token string, req model.ListTagRequest) (model.ListTagResponse, error) {
 	params, err := json.Marshal(req)
 	if err != nil {
 		return model.ListTagResponse{}, fmt.Errorf("marshal error: %w", err)
@@ -26,7 +26,7 @@ func ListTag(ctx context.Context, baseURL string, token string, req model.ListTa
 	paramsMap[ParamMod] = ModTag
 	paramsMap[ParamAct] = ActList
 
-	resBody, err := doRequest(ctx, baseURL, http.MethodGet, token, paramsMap, nil)
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodGet, token, paramsMap, nil)
 	if err != nil {
 		return model.ListTagResponse{}, fmt.Errorf("list tag error: %w", err)
 	}
@@ -39,13 +39,13 @@ func ListTag(ctx context.Context, baseURL string, token string, req model.ListTa
 	return resData, nil
 }
 
-func PostTag(ctx context.Context, baseURL string, token string, req model.PostTagRequest) (model.PostTagResponse, error) {
+func PostTag(ctx context.Context, httpClient *http.Client, baseURL string, token string, req model.PostTagRequest) (model.PostTagResponse, error) {
 	bytesData, err := util.EscapeHTMLMarshal(req)
 	if err != nil {
 		return model.PostTagResponse{}, fmt.Errorf("marshal error: %w", err)
 	}
 
-	resBody, err := doRequest(ctx, baseURL, http.MethodPost, token, map[string]interface{}{ParamMod: ModTag, ParamAct: ActPost}, bytesData)
+	resBody, err := doRequest(ctx, httpClient, baseURL, http.MethodPost, token, map[string]interface{}{ParamMod: ModTag, ParamAct: ActPost}, bytesData)
 	if err != nil {
 		return model.PostTagResponse{}, fmt.Errorf("post tag error: %w", err)
 	}
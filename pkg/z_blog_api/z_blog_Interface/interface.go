@@ -3,24 +3,29 @@ package zinterface
 import (
 	"context"
 
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
 	"github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
 
 	"github.com/google/uuid"
 )
 
 type ZBlogAPI interface {
-	GetClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string) (ZBlogAPIClient, error)
-	UpdateClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string) (ZBlogAPIClient, error)
+	// httpOptions override the http options of api for the site
+	GetClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string, httpOptions httpclient.Options) (ZBlogAPIClient, error)
+	UpdateClient(ctx context.Context, ID uuid.UUID, urlStr string, userName string, password string, httpOptions httpclient.Options) (ZBlogAPIClient, error)
 	DeleteClient(ID uuid.UUID)
-	NewClient(ctx context.Context, urlStr string, userName string, password string) (ZBlogAPIClient, error)
+	NewClient(ctx context.Context, urlStr string, userName string, password string, httpOptions httpclient.Options) (ZBlogAPIClient, error)
 	NewAnonymousClient(ctx context.Context, urlStr string) ZBlogAPIClient
 }
 
 type ZBlogAPIClient interface {
 	ListCategory(ctx context.Context) ([]model.Category, error)
+	PostCategory(ctx context.Context, cate model.PostCategoryRequest) (model.Category, error)
+	DeleteCategory(ctx context.Context, id uint32) error
 	GetArticle(ctx context.Context, id string) (model.Article, error)
 	ListArticle(ctx context.Context, req model.ListArticleRequest) ([]model.Article, error)
 	PostArticle(ctx context.Context, art model.PostArticleRequest) (model.Article, error)
+	DeleteArticle(ctx context.Context, id string) error
 	PostComment(ctx context.Context, comment model.PostCommentRequest) error
 	GetCountOfArticle(ctx context.Context, req model.ListArticleRequest) (int, error)
 	ListTag(ctx context.Context, req model.ListTagRequest) ([]model.Tag, error)
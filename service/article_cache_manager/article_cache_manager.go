@@ -1,20 +1,53 @@
 package articlecachemanager
 
 import (
+	"time"
+
 	dbInterface "github.com/ray31245/seo_cluster/pkg/db/db_interface"
 	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/service/article_cache_manager/model"
 )
 
 type ArticleCacheManager struct {
 	dbInterface.ArticleCacheDAOInterface
+	importJobs importJobs
+	inventory  InventoryRecorder
+}
+
+// InventoryRecorder record the articles added to the article cache
+type InventoryRecorder interface {
+	RecordInflow(count int)
 }
 
 func NewArticleCacheManager(dao dbInterface.ArticleCacheDAOInterface) *ArticleCacheManager {
 	return &ArticleCacheManager{
 		ArticleCacheDAOInterface: dao,
+		importJobs:               importJobs{jobs: map[string]*model.ImportProgress{}},
 	}
 }
 
+// SetInventoryRecorder set the recorder to track the imported articles, nil to disable
+func (a *ArticleCacheManager) SetInventoryRecorder(inventory InventoryRecorder) {
+	a.inventory = inventory
+}
+
+func (a *ArticleCacheManager) recordInflow(count int) {
+	if a.inventory == nil {
+		return
+	}
+
+	a.inventory.RecordInflow(count)
+}
+
+// isInStock report the article can be published automatically, the same as CountReadyToPublishArticleCache
+func isInStock(article dbModel.ArticleCache, now time.Time) bool {
+	if article.Status == dbModel.ArticleCacheStatusReserved || article.Status == dbModel.ArticleCacheStatusExpired {
+		return false
+	}
+
+	return article.ExpiresAt == nil || article.ExpiresAt.After(now)
+}
+
 func (a *ArticleCacheManager) ListPublishLaterArticleCache(titleKeyword, contentKeyword string, op dbModel.Operator, page, limit int) ([]dbModel.ArticleCache, int, int64, error) {
 	return a.ArticleCacheDAOInterface.ListPublishLaterArticleCachePaginator(titleKeyword, contentKeyword, op, page, limit)
 }
@@ -31,10 +64,6 @@ func (a *ArticleCacheManager) DeleteArticleCache(IDs []string) error {
 	return a.ArticleCacheDAOInterface.DeleteArticleCacheByIDs(IDs)
 }
 
-func (a *ArticleCacheManager) UpdateArticleCacheStatus(IDs []string, status dbModel.ArticleCacheStatus) error {
-	return a.ArticleCacheDAOInterface.UpdateArticleCacheStatusByIDs(IDs, status)
-}
-
-func (a *ArticleCacheManager) EditArticleCache(id, title, content string) error {
-	return a.ArticleCacheDAOInterface.EditArticleCache(id, title, content)
+func (a *ArticleCacheManager) UpdateArticleCachePriority(IDs []string, priority int) error {
+	return a.ArticleCacheDAOInterface.UpdateArticleCachePriorityByIDs(IDs, priority)
 }
@@ -0,0 +1,106 @@
+package articlecachemanager_test
+
+import (
+	"testing"
+	"time"
+
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleCacheManager_ListReadyToPublishByPriority(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	m := newTestManager(t)
+
+	articles := []dbModel.ArticleCache{
+		{Title: "old", Content: "c"},
+		{Title: "reserved", Content: "c", Status: dbModel.ArticleCacheStatusReserved, Priority: 100},
+		{Title: "urgent", Content: "c", Priority: 10},
+		{Title: "new", Content: "c"},
+		{Title: "urgent later", Content: "c", Priority: 10},
+	}
+	for _, article := range articles {
+		require.NoError(m.AddArticleToCache(article))
+	}
+
+	res, err := m.ListReadyToPublishArticleCacheByLimit(3)
+	require.NoError(err)
+
+	titles := []string{}
+	for _, article := range res {
+		titles = append(titles, article.Title)
+	}
+
+	assert.Equal([]string{"urgent", "urgent later", "old"}, titles)
+
+	require.NoError(m.UpdateArticleCachePriority([]string{res[2].ID.String()}, 20))
+
+	res, err = m.ListReadyToPublishArticleCacheByLimit(1)
+	require.NoError(err)
+	assert.Equal("old", res[0].Title)
+}
+
+func TestArticleCacheManager_ExpireArticleCache(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	m := newTestManager(t)
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	articles := []dbModel.ArticleCache{
+		{Title: "never", Content: "c"},
+		{Title: "expired", Content: "c", ExpiresAt: &past},
+		{Title: "reserved expired", Content: "c", Status: dbModel.ArticleCacheStatusReserved, ExpiresAt: &past},
+		{Title: "alive", Content: "c", ExpiresAt: &future},
+	}
+	for _, article := range articles {
+		require.NoError(m.AddArticleToCache(article))
+	}
+
+	// expired article will be skipped before the expire job run
+	res, err := m.ListReadyToPublishArticleCacheByLimit(10)
+	require.NoError(err)
+	assert.Len(res, 2)
+
+	count, err := m.ExpireArticleCache(time.Now())
+	require.NoError(err)
+	assert.Equal(int64(2), count)
+
+	expired, _, totalRows, err := m.ListExpiredArticleCache("", "", dbModel.NoneOperator, 0, 10)
+	require.NoError(err)
+	assert.Equal(int64(2), totalRows)
+	require.Len(expired, 2)
+
+	for _, article := range expired {
+		assert.Equal(dbModel.ArticleCacheStatusExpired, article.Status)
+
+		// expiry is recorded in revision history
+		revisions, _, _, err := m.ListArticleCacheRevisions(article.ID.String(), 0, 10)
+		require.NoError(err)
+		require.Len(revisions, 2)
+		assert.Equal(dbModel.ArticleCacheRevisionActionStatus, revisions[0].Action)
+		assert.Equal(dbModel.ArticleCacheStatusExpired, revisions[0].Status)
+		assert.Empty(revisions[0].Editor)
+		assert.Equal(dbModel.ArticleCacheRevisionActionOriginal, revisions[1].Action)
+	}
+
+	cacheCount, err := m.CountArticleCache()
+	require.NoError(err)
+	assert.Equal(int64(2), cacheCount)
+
+	// clear the expire time, the article will not be expired again
+	alive, err := m.FindArticleCacheByTitle("alive")
+	require.NoError(err)
+	require.NoError(m.UpdateArticleCacheExpiresAt([]string{alive.ID.String()}, nil))
+
+	count, err = m.ExpireArticleCache(future.Add(time.Hour))
+	require.NoError(err)
+	assert.Equal(int64(0), count)
+}
@@ -0,0 +1,55 @@
+package articlecachemanager
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+)
+
+const expireInterval = 10 * time.Minute
+
+// StartExpireArticleCache periodically move the expired articles to expired status
+func (a *ArticleCacheManager) StartExpireArticleCache(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				// Exit the loop if the context is cancelled
+				return
+			case <-time.After(expireInterval):
+				count, err := a.ExpireArticleCache(time.Now())
+				if err != nil {
+					slog.ErrorContext(ctx, "ExpireArticleCache", logger.Err(err))
+
+					continue
+				}
+
+				if count > 0 {
+					slog.InfoContext(ctx, "article cache expired", slog.Int64("count", count))
+				}
+			}
+		}
+	}()
+}
+
+func (a *ArticleCacheManager) ExpireArticleCache(now time.Time) (int64, error) {
+	count, err := a.ArticleCacheDAOInterface.ExpireArticleCache(now)
+	if err != nil {
+		return 0, fmt.Errorf("ExpireArticleCache: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListExpiredArticleCache list the articles dropped by expiry, latest expired first
+func (a *ArticleCacheManager) ListExpiredArticleCache(titleKeyword, contentKeyword string, op dbModel.Operator, page, limit int) ([]dbModel.ArticleCache, int, int64, error) {
+	return a.ArticleCacheDAOInterface.ListExpiredArticleCachePaginator(titleKeyword, contentKeyword, op, page, limit)
+}
+
+func (a *ArticleCacheManager) UpdateArticleCacheExpiresAt(IDs []string, expiresAt *time.Time) error {
+	return a.ArticleCacheDAOInterface.UpdateArticleCacheExpiresAtByIDs(IDs, expiresAt)
+}
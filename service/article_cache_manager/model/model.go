@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+)
+
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl"
+	FormatCSV   Format = "csv"
+)
+
+type DuplicateMode string
+
+const (
+	// DuplicateSkip keep the existing article cache and skip the imported one
+	DuplicateSkip DuplicateMode = "skip"
+	// DuplicateOverwrite replace the existing article cache with the imported one
+	DuplicateOverwrite DuplicateMode = "overwrite"
+	// DuplicateAllow add the imported one as a new article cache
+	DuplicateAllow DuplicateMode = "allow"
+)
+
+// Record is the format of article cache in import/export file
+type Record struct {
+	ID        string                     `json:"id"`
+	Title     string                     `json:"title"`
+	Content   string                     `json:"content"`
+	Status    dbModel.ArticleCacheStatus `json:"status"`
+	Priority  int                        `json:"priority"`
+	ExpiresAt *time.Time                 `json:"expires_at"`
+	CreatedAt time.Time                  `json:"created_at"`
+	UpdatedAt time.Time                  `json:"updated_at"`
+}
+
+func FromDBArticleCache(article dbModel.ArticleCache) Record {
+	return Record{
+		ID:        article.ID.String(),
+		Title:     article.Title,
+		Content:   article.Content,
+		Status:    article.Status,
+		Priority:  article.Priority,
+		ExpiresAt: article.ExpiresAt,
+		CreatedAt: article.CreatedAt,
+		UpdatedAt: article.UpdatedAt,
+	}
+}
+
+type ImportProgress struct {
+	Processed  int        `json:"processed"`
+	Created    int        `json:"created"`
+	Overwrote  int        `json:"overwrote"`
+	Skipped    int        `json:"skipped"`
+	Failed     int        `json:"failed"`
+	Errors     []string   `json:"errors"`
+	IsDone     bool       `json:"is_done"`
+	Error      string     `json:"error"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// RevisionDiff unified diff between two revisions of article cache
+type RevisionDiff struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	TitleDiff   string `json:"title_diff"`
+	ContentDiff string `json:"content_diff"`
+}
@@ -0,0 +1,170 @@
+package articlecachemanager
+
+import (
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/service/article_cache_manager/model"
+)
+
+// currentRevisionName name of the current article in diff
+const currentRevisionName = "current"
+
+func (a *ArticleCacheManager) EditArticleCache(id, title, content, editor string) error {
+	article, err := a.ArticleCacheDAOInterface.GetArticleCacheByID(id)
+	if err != nil {
+		return fmt.Errorf("EditArticleCache: %w", err)
+	}
+
+	err = a.ensureOriginalRevision(*article)
+	if err != nil {
+		return fmt.Errorf("EditArticleCache: %w", err)
+	}
+
+	err = a.ArticleCacheDAOInterface.EditArticleCache(id, title, content)
+	if err != nil {
+		return fmt.Errorf("EditArticleCache: %w", err)
+	}
+
+	article.Title = title
+	article.Content = content
+
+	err = a.createRevision(*article, dbModel.ArticleCacheRevisionActionEdit, editor)
+	if err != nil {
+		return fmt.Errorf("EditArticleCache: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateArticleCacheStatus update the status of articles, the revisions are recorded by dao
+func (a *ArticleCacheManager) UpdateArticleCacheStatus(IDs []string, status dbModel.ArticleCacheStatus, editor string) error {
+	err := a.ArticleCacheDAOInterface.UpdateArticleCacheStatusByIDs(IDs, status, editor)
+	if err != nil {
+		return fmt.Errorf("UpdateArticleCacheStatus: %w", err)
+	}
+
+	return nil
+}
+
+// ListArticleCacheRevisions list the revisions of article cache, latest first
+func (a *ArticleCacheManager) ListArticleCacheRevisions(articleCacheID string, page, limit int) ([]dbModel.ArticleCacheRevision, int, int64, error) {
+	return a.ArticleCacheDAOInterface.ListArticleCacheRevisionsPaginator(articleCacheID, page, limit)
+}
+
+// DiffArticleCacheRevision diff title and content between two revisions,
+// toID empty means diff with the current article
+func (a *ArticleCacheManager) DiffArticleCacheRevision(fromID, toID string) (model.RevisionDiff, error) {
+	from, err := a.ArticleCacheDAOInterface.GetArticleCacheRevision(fromID)
+	if err != nil {
+		return model.RevisionDiff{}, fmt.Errorf("DiffArticleCacheRevision: %w", err)
+	}
+
+	to := dbModel.ArticleCacheRevision{}
+	toName := currentRevisionName
+
+	if toID == "" {
+		article, err := a.ArticleCacheDAOInterface.GetArticleCacheByID(from.ArticleCacheID.String())
+		if err != nil {
+			return model.RevisionDiff{}, fmt.Errorf("DiffArticleCacheRevision: %w", err)
+		}
+
+		to.Title = article.Title
+		to.Content = article.Content
+	} else {
+		revision, err := a.ArticleCacheDAOInterface.GetArticleCacheRevision(toID)
+		if err != nil {
+			return model.RevisionDiff{}, fmt.Errorf("DiffArticleCacheRevision: %w", err)
+		}
+
+		to = *revision
+		toName = toID
+	}
+
+	titleDiff, err := unifiedDiff(from.Title, to.Title, fromID, toName)
+	if err != nil {
+		return model.RevisionDiff{}, fmt.Errorf("DiffArticleCacheRevision: %w", err)
+	}
+
+	contentDiff, err := unifiedDiff(from.Content, to.Content, fromID, toName)
+	if err != nil {
+		return model.RevisionDiff{}, fmt.Errorf("DiffArticleCacheRevision: %w", err)
+	}
+
+	return model.RevisionDiff{
+		From:        fromID,
+		To:          toName,
+		TitleDiff:   titleDiff,
+		ContentDiff: contentDiff,
+	}, nil
+}
+
+// RestoreArticleCacheRevision restore title and content of article cache to the revision,
+// status is not restored because it is controlled by publish flow
+func (a *ArticleCacheManager) RestoreArticleCacheRevision(revisionID, editor string) error {
+	revision, err := a.ArticleCacheDAOInterface.GetArticleCacheRevision(revisionID)
+	if err != nil {
+		return fmt.Errorf("RestoreArticleCacheRevision: %w", err)
+	}
+
+	article, err := a.ArticleCacheDAOInterface.GetArticleCacheByID(revision.ArticleCacheID.String())
+	if err != nil {
+		return fmt.Errorf("RestoreArticleCacheRevision: %w", err)
+	}
+
+	err = a.ArticleCacheDAOInterface.EditArticleCache(article.ID.String(), revision.Title, revision.Content)
+	if err != nil {
+		return fmt.Errorf("RestoreArticleCacheRevision: %w", err)
+	}
+
+	article.Title = revision.Title
+	article.Content = revision.Content
+
+	err = a.createRevision(*article, dbModel.ArticleCacheRevisionActionRestore, editor)
+	if err != nil {
+		return fmt.Errorf("RestoreArticleCacheRevision: %w", err)
+	}
+
+	return nil
+}
+
+// ensureOriginalRevision keep the article before its first change, so the first change can be undone
+func (a *ArticleCacheManager) ensureOriginalRevision(article dbModel.ArticleCache) error {
+	count, err := a.ArticleCacheDAOInterface.CountArticleCacheRevisions(article.ID.String())
+	if err != nil {
+		return fmt.Errorf("ensureOriginalRevision: %w", err)
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	return a.createRevision(article, dbModel.ArticleCacheRevisionActionOriginal, "")
+}
+
+func (a *ArticleCacheManager) createRevision(article dbModel.ArticleCache, action dbModel.ArticleCacheRevisionAction, editor string) error {
+	err := a.ArticleCacheDAOInterface.CreateArticleCacheRevision(&dbModel.ArticleCacheRevision{
+		ArticleCacheID: article.ID,
+		Action:         action,
+		Editor:         editor,
+		Title:          article.Title,
+		Content:        article.Content,
+		Status:         article.Status,
+	})
+	if err != nil {
+		return fmt.Errorf("createRevision: %w", err)
+	}
+
+	return nil
+}
+
+func unifiedDiff(from, to, fromName, toName string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from),
+		B:        difflib.SplitLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	})
+}
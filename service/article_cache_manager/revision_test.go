@@ -0,0 +1,69 @@
+package articlecachemanager_test
+
+import (
+	"testing"
+
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleCacheManager_Revision(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	m := newTestManager(t)
+	require.NoError(m.AddArticleToCache(dbModel.ArticleCache{Title: "title", Content: "line 1\nline 2\n"}))
+
+	article, err := m.FindArticleCacheByTitle("title")
+	require.NoError(err)
+	id := article.ID.String()
+
+	require.NoError(m.EditArticleCache(id, "bad title", "line 1\nbad line\n", "editor"))
+	require.NoError(m.UpdateArticleCacheStatus([]string{id}, dbModel.ArticleCacheStatusReserved, "editor"))
+
+	revisions, _, totalRows, err := m.ListArticleCacheRevisions(id, 0, 10)
+	require.NoError(err)
+	require.Equal(int64(3), totalRows)
+
+	actions := []dbModel.ArticleCacheRevisionAction{}
+	for _, revision := range revisions {
+		actions = append(actions, revision.Action)
+	}
+
+	assert.Equal([]dbModel.ArticleCacheRevisionAction{
+		dbModel.ArticleCacheRevisionActionStatus,
+		dbModel.ArticleCacheRevisionActionEdit,
+		dbModel.ArticleCacheRevisionActionOriginal,
+	}, actions)
+	assert.Equal("editor", revisions[0].Editor)
+	assert.Equal(dbModel.ArticleCacheStatusReserved, revisions[0].Status)
+
+	original := revisions[2]
+
+	diff, err := m.DiffArticleCacheRevision(original.ID.String(), "")
+	require.NoError(err)
+	assert.Contains(diff.ContentDiff, "-line 2")
+	assert.Contains(diff.ContentDiff, "+bad line")
+	assert.Contains(diff.TitleDiff, "+bad title")
+
+	require.NoError(m.RestoreArticleCacheRevision(original.ID.String(), "another editor"))
+
+	restored, err := m.GetArticleCache(id)
+	require.NoError(err)
+	assert.Equal("title", restored.Title)
+	assert.Equal("line 1\nline 2\n", restored.Content)
+	// status is not restored
+	assert.Equal(dbModel.ArticleCacheStatusReserved, restored.Status)
+
+	diff, err = m.DiffArticleCacheRevision(original.ID.String(), "")
+	require.NoError(err)
+	assert.Empty(diff.ContentDiff)
+
+	require.NoError(m.DeleteArticleCache([]string{id}))
+
+	_, _, totalRows, err = m.ListArticleCacheRevisions(id, 0, 10)
+	require.NoError(err)
+	assert.Equal(int64(0), totalRows)
+}
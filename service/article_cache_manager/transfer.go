@@ -0,0 +1,494 @@
+package articlecachemanager
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	"github.com/ray31245/seo_cluster/service/article_cache_manager/model"
+)
+
+const (
+	exportBatchSize  = 100
+	progressInterval = 100
+	maxImportErrors  = 100
+	importJobTTL     = 24 * time.Hour
+)
+
+var (
+	ErrInvalidFormat        = errors.New("invalid format, must be jsonl or csv")
+	ErrInvalidDuplicateMode = errors.New("invalid duplicate mode, must be skip, overwrite or allow")
+	ErrImportJobNotFound    = errors.New("import job not found")
+)
+
+var csvHeader = []string{"id", "title", "content", "status", "priority", "expires_at", "created_at", "updated_at"} //nolint:gochecknoglobals // csv header of export file
+
+type importJobs struct {
+	lock sync.RWMutex
+	jobs map[string]*model.ImportProgress
+}
+
+// ParseFormat parse format string, the format will be inferred from file name if it is empty
+func ParseFormat(format string, fileName string) (model.Format, error) {
+	if format == "" {
+		if strings.HasSuffix(strings.ToLower(fileName), ".csv") {
+			return model.FormatCSV, nil
+		}
+
+		return model.FormatJSONL, nil
+	}
+
+	f := model.Format(strings.ToLower(format))
+	if f != model.FormatJSONL && f != model.FormatCSV {
+		return "", ErrInvalidFormat
+	}
+
+	return f, nil
+}
+
+func ParseDuplicateMode(mode string) (model.DuplicateMode, error) {
+	if mode == "" {
+		return model.DuplicateSkip, nil
+	}
+
+	m := model.DuplicateMode(strings.ToLower(mode))
+	if m != model.DuplicateSkip && m != model.DuplicateOverwrite && m != model.DuplicateAllow {
+		return "", ErrInvalidDuplicateMode
+	}
+
+	return m, nil
+}
+
+// ExportArticleCache stream all article cache to w, return the number of exported article cache
+func (a *ArticleCacheManager) ExportArticleCache(w io.Writer, format model.Format) (int, error) {
+	var (
+		count   int
+		writeFn func(model.Record) error
+		flushFn func() error
+	)
+
+	switch format {
+	case model.FormatJSONL:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+
+		writeFn = func(r model.Record) error { return encoder.Encode(r) }
+		flushFn = func() error { return nil }
+	case model.FormatCSV:
+		csvWriter := csv.NewWriter(w)
+
+		err := csvWriter.Write(csvHeader)
+		if err != nil {
+			return 0, fmt.Errorf("ExportArticleCache: %w", err)
+		}
+
+		writeFn = func(r model.Record) error {
+			expiresAt := ""
+			if r.ExpiresAt != nil {
+				expiresAt = r.ExpiresAt.Format(time.RFC3339Nano)
+			}
+
+			return csvWriter.Write([]string{r.ID, r.Title, r.Content, string(r.Status), strconv.Itoa(r.Priority), expiresAt, r.CreatedAt.Format(time.RFC3339Nano), r.UpdatedAt.Format(time.RFC3339Nano)})
+		}
+		flushFn = func() error {
+			csvWriter.Flush()
+
+			return csvWriter.Error()
+		}
+	default:
+		return 0, fmt.Errorf("ExportArticleCache: %w", ErrInvalidFormat)
+	}
+
+	err := a.ArticleCacheDAOInterface.IterateArticleCache(exportBatchSize, func(articles []dbModel.ArticleCache) error {
+		for _, article := range articles {
+			err := writeFn(model.FromDBArticleCache(article))
+			if err != nil {
+				return err
+			}
+
+			count++
+		}
+
+		return flushFn()
+	})
+	if err != nil {
+		return count, fmt.Errorf("ExportArticleCache: %w", err)
+	}
+
+	return count, nil
+}
+
+// ImportArticleCache read article cache from r and add them to cache,
+// onProgress is called periodically and when the import is done, it can be nil
+func (a *ArticleCacheManager) ImportArticleCache(ctx context.Context, r io.Reader, format model.Format, mode model.DuplicateMode, onProgress func(model.ImportProgress)) (model.ImportProgress, error) {
+	progress := model.ImportProgress{StartedAt: time.Now(), Errors: []string{}}
+
+	report := func() {
+		if onProgress != nil {
+			onProgress(progress)
+		}
+	}
+
+	next, err := newRecordReader(r, format)
+	if err != nil {
+		return progress, fmt.Errorf("ImportArticleCache: %w", err)
+	}
+
+	var (
+		readErr error
+		// inflow count the created articles in stock of auto publish
+		inflow int
+	)
+
+	for {
+		if ctx.Err() != nil {
+			readErr = ctx.Err()
+
+			break
+		}
+
+		record, err := next()
+
+		var recordErr invalidRecordError
+
+		if errors.Is(err, io.EOF) {
+			break
+		} else if errors.As(err, &recordErr) {
+			progress.Processed++
+			progress.Failed++
+
+			if len(progress.Errors) < maxImportErrors {
+				progress.Errors = append(progress.Errors, fmt.Sprintf("record %d: %v", progress.Processed, err))
+			}
+
+			continue
+		} else if err != nil {
+			// the file is broken, can not continue to read
+			readErr = err
+
+			break
+		}
+
+		progress.Processed++
+
+		article, result, err := a.importRecord(record, mode)
+		if err != nil {
+			progress.Failed++
+			if len(progress.Errors) < maxImportErrors {
+				progress.Errors = append(progress.Errors, fmt.Sprintf("record %d: %v", progress.Processed, err))
+			}
+		} else {
+			switch result {
+			case importCreated:
+				progress.Created++
+
+				if isInStock(article, time.Now()) {
+					inflow++
+				}
+			case importOverwrote:
+				progress.Overwrote++
+			case importSkipped:
+				progress.Skipped++
+			}
+		}
+
+		if progress.Processed%progressInterval == 0 {
+			report()
+		}
+	}
+
+	a.recordInflow(inflow)
+
+	finishedAt := time.Now()
+	progress.IsDone = true
+	progress.FinishedAt = &finishedAt
+
+	if readErr != nil {
+		progress.Error = readErr.Error()
+	}
+
+	report()
+
+	if readErr != nil {
+		return progress, fmt.Errorf("ImportArticleCache: %w", readErr)
+	}
+
+	return progress, nil
+}
+
+// invalidRecordError is a record can be read but not valid, the import can continue
+type invalidRecordError struct {
+	err error
+}
+
+func (e invalidRecordError) Error() string {
+	return e.err.Error()
+}
+
+func (e invalidRecordError) Unwrap() error {
+	return e.err
+}
+
+type importResult int
+
+const (
+	importCreated importResult = iota
+	importOverwrote
+	importSkipped
+)
+
+// importRecord add or overwrite the article cache of record, it return the imported article
+func (a *ArticleCacheManager) importRecord(record model.Record, mode model.DuplicateMode) (dbModel.ArticleCache, importResult, error) {
+	article, err := validateRecord(record)
+	if err != nil {
+		return article, 0, err
+	}
+
+	existing, err := a.findDuplicate(article)
+	if err != nil {
+		return article, 0, err
+	}
+
+	if existing != nil {
+		switch mode {
+		case model.DuplicateSkip:
+			return article, importSkipped, nil
+		case model.DuplicateOverwrite:
+			article.ID = existing.ID
+
+			err = a.ensureOriginalRevision(*existing)
+			if err != nil {
+				return article, 0, err
+			}
+
+			err = a.ArticleCacheDAOInterface.OverwriteArticleCache(article)
+			if err != nil {
+				return article, 0, err
+			}
+
+			err = a.createRevision(article, dbModel.ArticleCacheRevisionActionImport, "")
+			if err != nil {
+				return article, 0, err
+			}
+
+			return article, importOverwrote, nil
+		case model.DuplicateAllow:
+			if existing.ID == article.ID {
+				// keep the existing one, add the imported one with new id
+				article.ID = uuid.Nil
+			}
+		}
+	}
+
+	err = a.ArticleCacheDAOInterface.AddArticleToCache(article)
+	if err != nil {
+		return article, 0, err
+	}
+
+	return article, importCreated, nil
+}
+
+// findDuplicate find the existing article cache with same id, or same title
+func (a *ArticleCacheManager) findDuplicate(article dbModel.ArticleCache) (*dbModel.ArticleCache, error) {
+	if article.ID != uuid.Nil {
+		existing, err := a.ArticleCacheDAOInterface.GetArticleCacheByID(article.ID.String())
+		if err == nil {
+			return existing, nil
+		} else if !dbErr.IsNotfoundErr(err) {
+			return nil, err
+		}
+	}
+
+	existing, err := a.ArticleCacheDAOInterface.FindArticleCacheByTitle(article.Title)
+	if dbErr.IsNotfoundErr(err) {
+		return nil, nil //nolint:nilnil // not found is not an error here
+	} else if err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+func validateRecord(record model.Record) (dbModel.ArticleCache, error) {
+	if record.Title == "" || record.Content == "" {
+		return dbModel.ArticleCache{}, errors.New("title and content are required")
+	}
+
+	if record.Status == "" {
+		record.Status = dbModel.ArticleCacheStatusDefault
+	}
+
+	if !slices.Contains(dbModel.ArticleCacheStatues, record.Status) {
+		return dbModel.ArticleCache{}, fmt.Errorf("%w: %s", dbErr.ErrInvalidArticleCacheStatus, record.Status)
+	}
+
+	article := dbModel.ArticleCache{
+		Title:     record.Title,
+		Content:   record.Content,
+		Status:    record.Status,
+		Priority:  record.Priority,
+		ExpiresAt: record.ExpiresAt,
+	}
+
+	if record.ID != "" {
+		id, err := uuid.Parse(record.ID)
+		if err != nil {
+			return dbModel.ArticleCache{}, fmt.Errorf("invalid id: %w", err)
+		}
+
+		article.ID = id
+	}
+
+	article.CreatedAt = record.CreatedAt
+	article.UpdatedAt = record.UpdatedAt
+
+	return article, nil
+}
+
+func newRecordReader(r io.Reader, format model.Format) (func() (model.Record, error), error) {
+	switch format {
+	case model.FormatJSONL:
+		decoder := json.NewDecoder(r)
+
+		return func() (model.Record, error) {
+			var record model.Record
+
+			err := decoder.Decode(&record)
+
+			return record, err
+		}, nil
+	case model.FormatCSV:
+		csvReader := csv.NewReader(r)
+		csvReader.FieldsPerRecord = -1
+
+		header, err := csvReader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("newRecordReader: %w", err)
+		}
+
+		index := map[string]int{}
+		for i, h := range header {
+			index[strings.TrimSpace(strings.ToLower(h))] = i
+		}
+
+		field := func(row []string, name string) string {
+			i, ok := index[name]
+			if !ok || i >= len(row) {
+				return ""
+			}
+
+			return row[i]
+		}
+
+		return func() (model.Record, error) {
+			row, err := csvReader.Read()
+			if err != nil {
+				return model.Record{}, err //nolint:wrapcheck // io.EOF should be returned as is
+			}
+
+			record := model.Record{
+				ID:      field(row, "id"),
+				Title:   field(row, "title"),
+				Content: field(row, "content"),
+				Status:  dbModel.ArticleCacheStatus(field(row, "status")),
+			}
+
+			if priority := field(row, "priority"); priority != "" {
+				record.Priority, err = strconv.Atoi(priority)
+				if err != nil {
+					return model.Record{}, invalidRecordError{fmt.Errorf("invalid priority: %w", err)}
+				}
+			}
+
+			if expiresAt := field(row, "expires_at"); expiresAt != "" {
+				t, err := time.Parse(time.RFC3339Nano, expiresAt)
+				if err != nil {
+					return model.Record{}, invalidRecordError{fmt.Errorf("invalid expires_at: %w", err)}
+				}
+
+				record.ExpiresAt = &t
+			}
+
+			// invalid time will be ignored, and set to now when created
+			record.CreatedAt, _ = time.Parse(time.RFC3339Nano, field(row, "created_at"))
+			record.UpdatedAt, _ = time.Parse(time.RFC3339Nano, field(row, "updated_at"))
+
+			return record, nil
+		}, nil
+	default:
+		return nil, ErrInvalidFormat
+	}
+}
+
+// StartImportJob import the file in background and remove it when done, return the job id to query progress
+func (a *ArticleCacheManager) StartImportJob(ctx context.Context, filePath string, format model.Format, mode model.DuplicateMode) string {
+	jobID := uuid.New().String()
+
+	a.setImportProgress(jobID, model.ImportProgress{StartedAt: time.Now(), Errors: []string{}})
+
+	go func() {
+		defer os.Remove(filePath)
+
+		f, err := os.Open(filePath)
+		if err != nil {
+			slog.ErrorContext(ctx, "StartImportJob", slog.String("job_id", jobID), logger.Err(err))
+
+			finishedAt := time.Now()
+			a.setImportProgress(jobID, model.ImportProgress{IsDone: true, Error: err.Error(), FinishedAt: &finishedAt, Errors: []string{}})
+
+			return
+		}
+		defer f.Close()
+
+		_, err = a.ImportArticleCache(ctx, f, format, mode, func(progress model.ImportProgress) {
+			a.setImportProgress(jobID, progress)
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "StartImportJob", slog.String("job_id", jobID), logger.Err(err))
+		}
+	}()
+
+	return jobID
+}
+
+func (a *ArticleCacheManager) GetImportJob(jobID string) (model.ImportProgress, error) {
+	a.importJobs.lock.RLock()
+	defer a.importJobs.lock.RUnlock()
+
+	progress, ok := a.importJobs.jobs[jobID]
+	if !ok {
+		return model.ImportProgress{}, ErrImportJobNotFound
+	}
+
+	return *progress, nil
+}
+
+func (a *ArticleCacheManager) setImportProgress(jobID string, progress model.ImportProgress) {
+	a.importJobs.lock.Lock()
+	defer a.importJobs.lock.Unlock()
+
+	// remove finished jobs which are too old
+	for id, job := range a.importJobs.jobs {
+		if job.FinishedAt != nil && time.Since(*job.FinishedAt) > importJobTTL {
+			delete(a.importJobs.jobs, id)
+		}
+	}
+
+	// copy errors, the slice is still appended by the import loop
+	progress.Errors = slices.Clone(progress.Errors)
+	a.importJobs.jobs[jobID] = &progress
+}
@@ -0,0 +1,152 @@
+package articlecachemanager_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ray31245/seo_cluster/pkg/db"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	articleCacheManager "github.com/ray31245/seo_cluster/service/article_cache_manager"
+	"github.com/ray31245/seo_cluster/service/article_cache_manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(t *testing.T) *articleCacheManager.ArticleCacheManager {
+	t.Helper()
+
+	database, err := db.NewDB(filepath.Join(t.TempDir(), "article_cache.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	dao, err := database.NewArticleCacheDAO()
+	require.NoError(t, err)
+
+	return articleCacheManager.NewArticleCacheManager(dao)
+}
+
+type inflowRecorder struct {
+	count atomic.Int32
+}
+
+func (r *inflowRecorder) RecordInflow(count int) {
+	r.count.Add(int32(count))
+}
+
+func TestArticleCacheManager_ExportImport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format model.Format
+	}{
+		{name: "jsonl", format: model.FormatJSONL},
+		{name: "csv", format: model.FormatCSV},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+			require := require.New(t)
+
+			src := newTestManager(t)
+			require.NoError(src.AddArticleToCache(dbModel.ArticleCache{Title: "t1", Content: "c1, \"quoted\"\nmultiline"}))
+			require.NoError(src.AddArticleToCache(dbModel.ArticleCache{Title: "t2", Content: "<p>c2</p>", Status: dbModel.ArticleCacheStatusReserved}))
+
+			buf := bytes.Buffer{}
+			count, err := src.ExportArticleCache(&buf, tt.format)
+			require.NoError(err)
+			assert.Equal(2, count)
+
+			dst := newTestManager(t)
+			inventory := &inflowRecorder{}
+			dst.SetInventoryRecorder(inventory)
+
+			progress, err := dst.ImportArticleCache(context.Background(), bytes.NewReader(buf.Bytes()), tt.format, model.DuplicateSkip, nil)
+			require.NoError(err)
+			assert.Equal(2, progress.Created)
+			assert.True(progress.IsDone)
+			// reserved article is not in stock of auto publish
+			assert.Equal(int32(1), inventory.count.Load())
+
+			buf2 := bytes.Buffer{}
+			_, err = dst.ExportArticleCache(&buf2, tt.format)
+			require.NoError(err)
+			assert.ElementsMatch(strings.Split(buf.String(), "\n"), strings.Split(buf2.String(), "\n"))
+		})
+	}
+}
+
+func TestArticleCacheManager_ImportDuplicate(t *testing.T) {
+	t.Parallel()
+
+	const data = `{"title":"exist","content":"new content","status":"reserved"}
+{"title":"new","content":"content"}
+{"title":"invalid","content":"content","status":"unknown"}
+`
+
+	tests := []struct {
+		name          string
+		mode          model.DuplicateMode
+		wantCreated   int
+		wantOverwrote int
+		wantSkipped   int
+		wantCount     int64
+		wantContent   string
+	}{
+		{name: "skip", mode: model.DuplicateSkip, wantCreated: 1, wantSkipped: 1, wantCount: 2, wantContent: "old content"},
+		{name: "overwrite", mode: model.DuplicateOverwrite, wantCreated: 1, wantOverwrote: 1, wantCount: 2, wantContent: "new content"},
+		{name: "allow", mode: model.DuplicateAllow, wantCreated: 2, wantCount: 3, wantContent: "old content"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+			require := require.New(t)
+
+			m := newTestManager(t)
+			require.NoError(m.AddArticleToCache(dbModel.ArticleCache{Title: "exist", Content: "old content"}))
+
+			progress, err := m.ImportArticleCache(context.Background(), strings.NewReader(data), model.FormatJSONL, tt.mode, nil)
+			require.NoError(err)
+			assert.Equal(3, progress.Processed)
+			assert.Equal(tt.wantCreated, progress.Created)
+			assert.Equal(tt.wantOverwrote, progress.Overwrote)
+			assert.Equal(tt.wantSkipped, progress.Skipped)
+			assert.Equal(1, progress.Failed)
+			assert.Len(progress.Errors, 1)
+
+			count, err := m.CountArticleCache()
+			require.NoError(err)
+			assert.Equal(tt.wantCount, count)
+
+			exist, err := m.FindArticleCacheByTitle("exist")
+			require.NoError(err)
+			assert.Equal(tt.wantContent, exist.Content)
+		})
+	}
+}
+
+func TestArticleCacheManager_ImportCSVInvalidPriority(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	const data = "title,content,priority\nt1,c1,high\nt2,c2,5\n"
+
+	m := newTestManager(t)
+
+	progress, err := m.ImportArticleCache(context.Background(), strings.NewReader(data), model.FormatCSV, model.DuplicateSkip, nil)
+	require.NoError(err)
+	assert.Equal(2, progress.Processed)
+	assert.Equal(1, progress.Created)
+	assert.Equal(1, progress.Failed)
+
+	article, err := m.FindArticleCacheByTitle("t2")
+	require.NoError(err)
+	assert.Equal(5, article.Priority)
+}
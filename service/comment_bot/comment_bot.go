@@ -4,16 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"math"
 	"time"
 
 	aiAssistInterface "github.com/ray31245/seo_cluster/pkg/ai_assist/ai_assist_interface"
 	"github.com/ray31245/seo_cluster/pkg/ai_assist/model"
+	cmsDriverInterface "github.com/ray31245/seo_cluster/pkg/cms_driver/cms_driver_interface"
+	cmsModel "github.com/ray31245/seo_cluster/pkg/cms_driver/model"
 	dbInterface "github.com/ray31245/seo_cluster/pkg/db/db_interface"
 	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
-	zModel "github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
-	zInterface "github.com/ray31245/seo_cluster/pkg/z_blog_api/z_blog_Interface"
+	"github.com/ray31245/seo_cluster/pkg/logger"
 )
 
 const (
@@ -28,16 +29,16 @@ const (
 )
 
 type CommentBot struct {
-	zBlogAPI       zInterface.ZBlogAPI
+	cmsDrivers     cmsDriverInterface.Registry
 	siteDAO        dbInterface.SiteDAOInterface
 	configDAO      dbInterface.KVConfigDAOInterface
 	commentUserDAO dbInterface.CommentUserDAOInterface
 	aiAssist       aiAssistInterface.AIAssistInterface
 }
 
-func NewCommentBot(zBlogAPI zInterface.ZBlogAPI, configDAO dbInterface.KVConfigDAOInterface, siteDAO dbInterface.SiteDAOInterface, commentUserDAO dbInterface.CommentUserDAOInterface, aiAssist aiAssistInterface.AIAssistInterface) *CommentBot {
+func NewCommentBot(cmsDrivers cmsDriverInterface.Registry, configDAO dbInterface.KVConfigDAOInterface, siteDAO dbInterface.SiteDAOInterface, commentUserDAO dbInterface.CommentUserDAOInterface, aiAssist aiAssistInterface.AIAssistInterface) *CommentBot {
 	return &CommentBot{
-		zBlogAPI:       zBlogAPI,
+		cmsDrivers:     cmsDrivers,
 		siteDAO:        siteDAO,
 		configDAO:      configDAO,
 		commentUserDAO: commentUserDAO,
@@ -55,7 +56,7 @@ func (c CommentBot) StartCycleComment(ctx context.Context) {
 			case <-time.After(randomTime()):
 				// Proceed with the publishing cycle after a random duration
 				if err := c.cycleComment(ctx); err != nil {
-					log.Printf("Error in cycleComment: %v", err)
+					slog.ErrorContext(ctx, "cycleComment", logger.Err(err))
 				}
 			}
 		}
@@ -70,12 +71,12 @@ func (c CommentBot) cycleComment(ctx context.Context) error {
 	}
 
 	if isStopAutoComment {
-		log.Println("cycleComment is stopped")
+		slog.InfoContext(ctx, "cycleComment is stopped")
 
 		return nil
 	}
 
-	log.Println("cycleComment running...")
+	slog.InfoContext(ctx, "cycleComment running")
 
 	sites, err := c.siteDAO.ListSitesRandom()
 	if err != nil {
@@ -83,11 +84,11 @@ func (c CommentBot) cycleComment(ctx context.Context) error {
 	}
 
 	for _, site := range sites {
-		log.Printf("site url %s, start cycleComment", site.URL)
+		slog.InfoContext(ctx, "start cycleComment", logger.SiteID(site.ID), slog.String("url", site.URL))
 
 		articles, err := c.listArticleForComment(ctx, site)
 		if err != nil {
-			log.Printf("site url %s, listArticleForComment error: %v", site.URL, err)
+			slog.ErrorContext(ctx, "listArticleForComment", logger.SiteID(site.ID), logger.Err(err))
 
 			continue
 		}
@@ -99,10 +100,10 @@ func (c CommentBot) cycleComment(ctx context.Context) error {
 			if err != nil {
 				continueErrorCount++
 
-				log.Printf("site url %s, article id %s, Comment error: %v", site.URL, a.ID, err)
+				slog.ErrorContext(ctx, "Comment", logger.SiteID(site.ID), logger.ArticleID(a.ID), logger.Err(err))
 
 				if continueErrorCount > maxContinueErrorCount {
-					log.Printf("site url %s, article id %s, Comment error count > 3, break", site.URL, a.ID)
+					slog.WarnContext(ctx, "Comment: too many continuous errors, skip site", logger.SiteID(site.ID), logger.ArticleID(a.ID))
 
 					break
 				}
@@ -127,16 +128,17 @@ func (c CommentBot) cycleComment(ctx context.Context) error {
 	return nil
 }
 
-func (c CommentBot) listArticleForComment(ctx context.Context, site dbModel.Site) ([]zModel.Article, error) {
-	res := []zModel.Article{}
-	client := c.zBlogAPI.NewAnonymousClient(ctx, site.URL)
-	// get all articles
-	articles, err := client.ListArticle(ctx, zModel.ListArticleRequest{
-		PageRequest: zModel.PageRequest{
-			SortBy: "PostTime",
-			Order:  "desc",
-		},
-	})
+func (c CommentBot) listArticleForComment(ctx context.Context, site dbModel.Site) ([]cmsModel.Article, error) {
+	res := []cmsModel.Article{}
+
+	driver, err := c.cmsDrivers.Driver(site.CmsType)
+	if err != nil {
+		return nil, fmt.Errorf("listArticleForComment: %w", err)
+	}
+
+	client := driver.NewAnonymousClient(ctx, site.URL)
+	// get latest articles
+	articles, err := client.ListArticle(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("listArticleForComment: %w", err)
 	}
@@ -155,8 +157,8 @@ func (c CommentBot) listArticleForComment(ctx context.Context, site dbModel.Site
 	return res, nil
 }
 
-func computeGap(article zModel.Article) int {
-	hours := time.Since(article.PostTime.Time).Hours() + 1
+func computeGap(article cmsModel.Article) int {
+	hours := time.Since(article.PostTime).Hours() + 1
 	// hours should be at least 1
 	if hours < 1 {
 		hours = 1
@@ -168,69 +170,56 @@ func computeGap(article zModel.Article) int {
 	return int(math.Sqrt(hours)*coefficientOfGape*float64(article.CommNums+1)) - int(hours)*2
 }
 
-func (c CommentBot) Comment(ctx context.Context, site dbModel.Site, article zModel.Article) error {
-	log.Printf("site url %s, article id %s, start comment", site.URL, article.ID)
-
-	var err error
-	if site.CmsType == dbModel.CMSTypeWordPress {
-		err = c.commentWordPress(ctx, site, article)
-	} else if site.CmsType == dbModel.CMSTypeZBlog {
-		err = c.commentZBlog(ctx, site, article)
-	} else {
-		err = errors.New("unsupported cms type")
-	}
+func (c CommentBot) Comment(ctx context.Context, site dbModel.Site, article cmsModel.Article) error {
+	slog.InfoContext(ctx, "start comment", logger.SiteID(site.ID), logger.ArticleID(article.ID))
 
+	driver, err := c.cmsDrivers.Driver(site.CmsType)
 	if err != nil {
 		return fmt.Errorf("Comment: %w", err)
 	}
 
-	return nil
-}
-
-func (c CommentBot) commentZBlog(ctx context.Context, site dbModel.Site, article zModel.Article) error {
 	commentUser, err := c.commentUserDAO.GetRandomCommentUser()
 	if err != nil {
-		return fmt.Errorf("commentZBlog: %w", err)
+		return fmt.Errorf("Comment: %w", err)
 	}
 
-	client, err := c.zBlogAPI.GetClient(ctx, commentUser.ID, site.URL, commentUser.Name, commentUser.Password)
+	cred := cmsModel.NewSiteCredential(site)
+	cred.UserName = commentUser.Name
+	cred.Password = commentUser.Password
+
+	client, err := driver.GetClient(ctx, commentUser.ID, cred)
 	if err != nil {
-		return fmt.Errorf("commentZBlog: %w", err)
+		return fmt.Errorf("Comment: %w", err)
 	}
 
-	article, err = client.GetArticle(ctx, string(article.ID))
+	article, err = client.GetArticle(ctx, article.ID)
 	if err != nil {
-		return fmt.Errorf("commentZBlog: %w", err)
+		return fmt.Errorf("Comment: %w", err)
 	}
 
 	// comment
-	comment, err := c.comment(ctx, article)
+	comment, err := c.comment(ctx, site, article)
 	if err != nil {
-		return fmt.Errorf("commentZBlog: %w", err)
+		return fmt.Errorf("Comment: %w", err)
 	}
 
-	err = client.PostComment(ctx, zModel.PostCommentRequest{LogID: string(article.ID), Content: comment.Comment})
+	err = client.PostComment(ctx, article.ID, comment.Comment)
 	if err != nil {
-		return fmt.Errorf("commentZBlog: %w", err)
+		return fmt.Errorf("Comment: %w", err)
 	}
 
-	log.Printf("site url %s, article id %s,score: %d, comment success", site.URL, article.ID, comment.Score)
-
-	return nil
-}
+	slog.InfoContext(ctx, "comment success", logger.SiteID(site.ID), logger.ArticleID(article.ID), slog.Int("score", comment.Score))
 
-func (c CommentBot) commentWordPress(ctx context.Context, site dbModel.Site, article zModel.Article) error {
-	// TODO: implement commentWordPress
 	return nil
 }
 
-func (c CommentBot) comment(ctx context.Context, article zModel.Article) (model.CommentResponse, error) {
+func (c CommentBot) comment(ctx context.Context, site dbModel.Site, article cmsModel.Article) (model.CommentResponse, error) {
 	if ok := c.aiAssist.TryLock(); !ok {
 		return model.CommentResponse{}, fmt.Errorf("Comment: %w", errors.New("AIAssist is locked"))
 	}
 	defer c.aiAssist.Unlock()
 
-	comment, err := c.aiAssist.Comment(ctx, []byte(article.Content))
+	comment, err := c.aiAssist.Comment(ctx, model.Language(site.Language), []byte(article.Content))
 	if err != nil {
 		return model.CommentResponse{}, fmt.Errorf("Comment: %w", err)
 	}
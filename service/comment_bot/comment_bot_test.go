@@ -5,13 +5,12 @@ import (
 	"testing"
 	"time"
 
-	"github.com/ray31245/seo_cluster/pkg/util"
-	zModel "github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
+	cmsModel "github.com/ray31245/seo_cluster/pkg/cms_driver/model"
 )
 
 func Test_computeGap(t *testing.T) {
 	type args struct {
-		article zModel.Article
+		article cmsModel.Article
 	}
 
 	tests := []struct {
@@ -22,9 +21,9 @@ func Test_computeGap(t *testing.T) {
 		{
 			name: "test1",
 			args: args{
-				article: zModel.Article{
-					PostTime: util.UnixTime{Time: time.Now().Add(-time.Hour * 30)},
-					CommNums: util.StringNumber(9),
+				article: cmsModel.Article{
+					PostTime: time.Now().Add(-time.Hour * 30),
+					CommNums: 9,
 				},
 			},
 		},
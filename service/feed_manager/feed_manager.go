@@ -0,0 +1,343 @@
+package feedmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	dbInterface "github.com/ray31245/seo_cluster/pkg/db/db_interface"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	feedModel "github.com/ray31245/seo_cluster/pkg/feed_reader/model"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	publishModel "github.com/ray31245/seo_cluster/service/publish_manager/model"
+	rewritemanager "github.com/ray31245/seo_cluster/service/rewrite_manager"
+	rewriteModel "github.com/ray31245/seo_cluster/service/rewrite_manager/model"
+)
+
+const (
+	defaultIntervalMinutes = 60
+	minIntervalMinutes     = 5
+	checkInterval          = time.Minute
+)
+
+var (
+	ErrInvalidDefaultStatus = errors.New("default status must be default or reserved")
+	ErrInvalidURL           = errors.New("invalid url")
+)
+
+type FeedFetcher interface {
+	Fetch(ctx context.Context, url string) (feedModel.Feed, error)
+}
+
+type PrePublisher interface {
+	PrePublishWithStatus(article publishModel.Article, status dbModel.ArticleCacheStatus) error
+}
+
+type Rewriter interface {
+	DefaultRewriteWorkFlow(ctx context.Context, articleContent string, isGenImageList bool) (rewriteModel.RewriteResult, error)
+}
+
+type FeedManager struct {
+	feedDAO   dbInterface.FeedDAOInterface
+	fetcher   FeedFetcher
+	publisher PrePublisher
+	rewriter  Rewriter
+	fetchLock sync.Mutex
+}
+
+func NewFeedManager(feedDAO dbInterface.FeedDAOInterface, fetcher FeedFetcher, publisher PrePublisher, rewriter Rewriter) *FeedManager {
+	return &FeedManager{
+		feedDAO:   feedDAO,
+		fetcher:   fetcher,
+		publisher: publisher,
+		rewriter:  rewriter,
+	}
+}
+
+func (f *FeedManager) CreateFeed(name, url string, intervalMinutes int, defaultStatus dbModel.ArticleCacheStatus, isRewrite bool, ttlHours int, isActive bool) (dbModel.Feed, error) {
+	feed := dbModel.Feed{
+		Name:            name,
+		URL:             url,
+		IntervalMinutes: intervalMinutes,
+		DefaultStatus:   defaultStatus,
+		IsRewrite:       isRewrite,
+		TTLHours:        ttlHours,
+		IsActive:        isActive,
+	}
+
+	err := normalizeFeed(&feed)
+	if err != nil {
+		return dbModel.Feed{}, fmt.Errorf("CreateFeed: %w", err)
+	}
+
+	res, err := f.feedDAO.CreateFeed(&feed)
+	if err != nil {
+		return dbModel.Feed{}, fmt.Errorf("CreateFeed: %w", err)
+	}
+
+	return res, nil
+}
+
+func (f *FeedManager) UpdateFeed(id, name, url string, intervalMinutes int, defaultStatus dbModel.ArticleCacheStatus, isRewrite bool, ttlHours int, isActive bool) error {
+	feed, err := f.feedDAO.GetFeed(id)
+	if err != nil {
+		return fmt.Errorf("UpdateFeed: %w", err)
+	}
+
+	feed.Name = name
+	feed.URL = url
+	feed.IntervalMinutes = intervalMinutes
+	feed.DefaultStatus = defaultStatus
+	feed.IsRewrite = isRewrite
+	feed.TTLHours = ttlHours
+	feed.IsActive = isActive
+
+	err = normalizeFeed(feed)
+	if err != nil {
+		return fmt.Errorf("UpdateFeed: %w", err)
+	}
+
+	err = f.feedDAO.UpdateFeed(feed)
+	if err != nil {
+		return fmt.Errorf("UpdateFeed: %w", err)
+	}
+
+	return nil
+}
+
+func (f *FeedManager) DeleteFeed(id string) error {
+	err := f.feedDAO.DeleteFeed(id)
+	if err != nil {
+		return fmt.Errorf("DeleteFeed: %w", err)
+	}
+
+	return nil
+}
+
+func (f *FeedManager) GetFeed(id string) (*dbModel.Feed, error) {
+	feed, err := f.feedDAO.GetFeed(id)
+	if err != nil {
+		return nil, fmt.Errorf("GetFeed: %w", err)
+	}
+
+	return feed, nil
+}
+
+func (f *FeedManager) ListFeeds() ([]dbModel.Feed, error) {
+	feeds, err := f.feedDAO.ListFeeds()
+	if err != nil {
+		return nil, fmt.Errorf("ListFeeds: %w", err)
+	}
+
+	return feeds, nil
+}
+
+func (f *FeedManager) StartCycleFetchFeed(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				// Exit the loop if the context is cancelled
+				return
+			case <-time.After(checkInterval):
+				if err := f.cycleFetchFeed(ctx); err != nil {
+					slog.ErrorContext(ctx, "cycleFetchFeed", logger.Err(err))
+				}
+			}
+		}
+	}()
+}
+
+func (f *FeedManager) cycleFetchFeed(ctx context.Context) error {
+	feeds, err := f.feedDAO.ListActiveFeeds()
+	if err != nil {
+		return fmt.Errorf("cycleFetchFeed: %w", err)
+	}
+
+	now := time.Now()
+
+	var multiErr error
+
+	for _, feed := range feeds {
+		if !feed.IsDue(now) {
+			continue
+		}
+
+		_, err := f.FetchFeed(ctx, feed.ID.String())
+		if err != nil {
+			multiErr = errors.Join(multiErr, err)
+		}
+	}
+
+	if multiErr != nil {
+		return fmt.Errorf("cycleFetchFeed: %w", multiErr)
+	}
+
+	return nil
+}
+
+// FetchFeed fetch the feed immediately, return the number of new items added to article cache
+func (f *FeedManager) FetchFeed(ctx context.Context, id string) (int, error) {
+	f.fetchLock.Lock()
+	defer f.fetchLock.Unlock()
+
+	feed, err := f.feedDAO.GetFeed(id)
+	if err != nil {
+		return 0, fmt.Errorf("FetchFeed: %w", err)
+	}
+
+	newItems, fetchErr := f.fetchFeed(ctx, *feed)
+
+	lastError := ""
+	if fetchErr != nil {
+		lastError = fetchErr.Error()
+	}
+
+	err = f.feedDAO.UpdateFeedFetchState(id, time.Now(), newItems, lastError)
+	if err != nil {
+		return newItems, fmt.Errorf("FetchFeed: %w", errors.Join(fetchErr, err))
+	}
+
+	if fetchErr != nil {
+		return newItems, fmt.Errorf("FetchFeed: %w", fetchErr)
+	}
+
+	return newItems, nil
+}
+
+func (f *FeedManager) fetchFeed(ctx context.Context, feed dbModel.Feed) (int, error) {
+	slog.InfoContext(ctx, "fetching feed", slog.String("feed_id", feed.ID.String()), slog.String("url", feed.URL))
+
+	content, err := f.fetcher.Fetch(ctx, feed.URL)
+	if err != nil {
+		return 0, fmt.Errorf("fetchFeed: %w", err)
+	}
+
+	var (
+		multiErr error
+		newItems int
+	)
+
+	// feed list newest item first, add the oldest one to cache first
+	for i := len(content.Items) - 1; i >= 0; i-- {
+		isNew, err := f.processItem(ctx, feed, content.Items[i])
+		if err != nil {
+			multiErr = errors.Join(multiErr, err)
+
+			continue
+		}
+
+		if isNew {
+			newItems++
+		}
+	}
+
+	if multiErr != nil {
+		return newItems, fmt.Errorf("fetchFeed: %w", multiErr)
+	}
+
+	return newItems, nil
+}
+
+func (f *FeedManager) processItem(ctx context.Context, feed dbModel.Feed, item feedModel.Item) (bool, error) {
+	key := item.Key()
+	if key == "" {
+		return false, nil
+	}
+
+	isExist, err := f.feedDAO.IsFeedItemExist(feed.ID.String(), key, item.Link)
+	if err != nil {
+		return false, fmt.Errorf("processItem: %w", err)
+	}
+
+	if isExist {
+		return false, nil
+	}
+
+	article := publishModel.Article{Title: item.Title, Content: item.Content}
+
+	if feed.TTLHours > 0 {
+		expiresAt := time.Now().Add(time.Duration(feed.TTLHours) * time.Hour)
+		article.ExpiresAt = &expiresAt
+	}
+
+	if feed.IsRewrite {
+		res, err := f.rewriter.DefaultRewriteWorkFlow(ctx, item.Content, true)
+		if errors.Is(err, rewritemanager.ErrSourceTooShort) {
+			// the content will not grow, mark it fetched to avoid checking it again
+			slog.InfoContext(ctx, "skip feed item", slog.String("key", key), logger.Err(err))
+
+			return false, f.markItemFetched(feed, item)
+		} else if err != nil {
+			return false, fmt.Errorf("processItem: %w", err)
+		}
+
+		article.Title = res.Title
+		article.Content = res.Content
+	}
+
+	if article.Title == "" || article.Content == "" {
+		slog.InfoContext(ctx, "skip feed item: data is not complete", slog.String("key", key))
+
+		return false, f.markItemFetched(feed, item)
+	}
+
+	err = f.publisher.PrePublishWithStatus(article, feed.DefaultStatus)
+	if err != nil {
+		return false, fmt.Errorf("processItem: %w", err)
+	}
+
+	err = f.markItemFetched(feed, item)
+	if err != nil {
+		return false, fmt.Errorf("processItem: %w", err)
+	}
+
+	return true, nil
+}
+
+func (f *FeedManager) markItemFetched(feed dbModel.Feed, item feedModel.Item) error {
+	err := f.feedDAO.CreateFeedItem(&dbModel.FeedItem{
+		FeedID:  feed.ID,
+		ItemKey: item.Key(),
+		GUID:    item.GUID,
+		Link:    item.Link,
+		Title:   item.Title,
+	})
+	if err != nil {
+		return fmt.Errorf("markItemFetched: %w", err)
+	}
+
+	return nil
+}
+
+func normalizeFeed(feed *dbModel.Feed) error {
+	if !strings.HasPrefix(feed.URL, "http://") && !strings.HasPrefix(feed.URL, "https://") {
+		return ErrInvalidURL
+	}
+
+	if feed.DefaultStatus == "" {
+		feed.DefaultStatus = dbModel.ArticleCacheStatusDefault
+	}
+
+	if feed.DefaultStatus != dbModel.ArticleCacheStatusDefault && feed.DefaultStatus != dbModel.ArticleCacheStatusReserved {
+		return ErrInvalidDefaultStatus
+	}
+
+	if feed.TTLHours < 0 {
+		feed.TTLHours = 0
+	}
+
+	if feed.IntervalMinutes <= 0 {
+		feed.IntervalMinutes = defaultIntervalMinutes
+	}
+
+	if feed.IntervalMinutes < minIntervalMinutes {
+		feed.IntervalMinutes = minIntervalMinutes
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package feedmanager
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ray31245/seo_cluster/pkg/db"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	feedModel "github.com/ray31245/seo_cluster/pkg/feed_reader/model"
+	publishModel "github.com/ray31245/seo_cluster/service/publish_manager/model"
+	rewriteModel "github.com/ray31245/seo_cluster/service/rewrite_manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockFetcher struct {
+	feed feedModel.Feed
+}
+
+func (m *mockFetcher) Fetch(_ context.Context, _ string) (feedModel.Feed, error) {
+	return m.feed, nil
+}
+
+type mockPublisher struct {
+	articles []publishModel.Article
+	statuses []dbModel.ArticleCacheStatus
+}
+
+func (m *mockPublisher) PrePublishWithStatus(article publishModel.Article, status dbModel.ArticleCacheStatus) error {
+	m.articles = append(m.articles, article)
+	m.statuses = append(m.statuses, status)
+
+	return nil
+}
+
+type mockRewriter struct{}
+
+func (m mockRewriter) DefaultRewriteWorkFlow(_ context.Context, articleContent string, _ bool) (rewriteModel.RewriteResult, error) {
+	return rewriteModel.RewriteResult{Title: "rewritten", Content: "rewritten " + articleContent}, nil
+}
+
+func TestFeedManager_FetchFeed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		isRewrite     bool
+		defaultStatus dbModel.ArticleCacheStatus
+		rounds        []feedModel.Feed
+		wantNewItems  []int
+		wantTitles    []string
+	}{
+		{
+			name:          "dedup by guid and link",
+			defaultStatus: dbModel.ArticleCacheStatusReserved,
+			rounds: []feedModel.Feed{
+				{Items: []feedModel.Item{
+					{GUID: "2", Link: "https://example.com/2", Title: "second", Content: "content 2"},
+					{GUID: "1", Link: "https://example.com/1", Title: "first", Content: "content 1"},
+				}},
+				{Items: []feedModel.Item{
+					{GUID: "3", Link: "https://example.com/3", Title: "third", Content: "content 3"},
+					{GUID: "2", Link: "https://example.com/2", Title: "second", Content: "content 2"},
+					// guid changed but link is the same
+					{GUID: "1-new", Link: "https://example.com/1", Title: "first", Content: "content 1"},
+				}},
+			},
+			wantNewItems: []int{2, 1},
+			wantTitles:   []string{"first", "second", "third"},
+		},
+		{
+			name:      "rewrite",
+			isRewrite: true,
+			rounds: []feedModel.Feed{
+				{Items: []feedModel.Item{
+					{Link: "https://example.com/1", Title: "first", Content: "content 1"},
+				}},
+			},
+			wantNewItems: []int{1},
+			wantTitles:   []string{"rewritten"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+			require := require.New(t)
+
+			database, err := db.NewDB(filepath.Join(t.TempDir(), "feed.db"))
+			require.NoError(err)
+			t.Cleanup(func() { database.Close() })
+
+			feedDAO, err := database.NewFeedDAO()
+			require.NoError(err)
+
+			fetcher := &mockFetcher{}
+			publisher := &mockPublisher{}
+			f := NewFeedManager(feedDAO, fetcher, publisher, mockRewriter{})
+
+			feed, err := f.CreateFeed("test", "https://example.com/feed", 0, tt.defaultStatus, tt.isRewrite, 0, true)
+			require.NoError(err)
+
+			for i, round := range tt.rounds {
+				fetcher.feed = round
+
+				newItems, err := f.FetchFeed(context.Background(), feed.ID.String())
+				require.NoError(err)
+				assert.Equal(tt.wantNewItems[i], newItems)
+			}
+
+			titles := []string{}
+			for _, article := range publisher.articles {
+				titles = append(titles, article.Title)
+			}
+
+			assert.Equal(tt.wantTitles, titles)
+
+			for _, status := range publisher.statuses {
+				assert.Equal(feed.DefaultStatus, status)
+			}
+
+			got, err := f.GetFeed(feed.ID.String())
+			require.NoError(err)
+			assert.NotNil(got.LastFetchedAt)
+			assert.Empty(got.LastError)
+		})
+	}
+}
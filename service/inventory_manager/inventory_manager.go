@@ -0,0 +1,260 @@
+package inventorymanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	dbInterface "github.com/ray31245/seo_cluster/pkg/db/db_interface"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	"github.com/ray31245/seo_cluster/service/inventory_manager/model"
+	webhookModel "github.com/ray31245/seo_cluster/service/webhook_manager/model"
+	webhookInterface "github.com/ray31245/seo_cluster/service/webhook_manager/webhook_interface"
+)
+
+const (
+	ConfigAlertThresholdDays = "inventory_alert_threshold_days"
+	ConfigWindowDays         = "inventory_window_days"
+
+	defaultAlertThresholdDays = 3
+	defaultWindowDays         = 7
+	maxWindowDays             = 30
+
+	checkInterval = time.Hour
+	day           = 24 * time.Hour
+)
+
+var ErrInvalidWindowDays = fmt.Errorf("window days must be between 1 and %d", maxWindowDays)
+
+type DAO struct {
+	dbInterface.InventoryDAOInterface
+	dbInterface.ArticleCacheDAOInterface
+	dbInterface.SiteDAOInterface
+	dbInterface.KVConfigDAOInterface
+}
+
+// DemandEstimator estimate the number of articles a site consumes per day
+type DemandEstimator interface {
+	ExpectedDailyDemand(site dbModel.Site) float64
+}
+
+type InventoryManager struct {
+	dao      DAO
+	demand   DemandEstimator
+	notifier webhookInterface.Notifier
+
+	alertLock sync.Mutex
+	// isAlerted avoid alerting again until the stock recovers
+	isAlerted bool
+}
+
+func NewInventoryManager(dao DAO, demand DemandEstimator) *InventoryManager {
+	return &InventoryManager{
+		dao:    dao,
+		demand: demand,
+	}
+}
+
+// SetNotifier set the notifier to emit low inventory events, nil to disable
+func (i *InventoryManager) SetNotifier(notifier webhookInterface.Notifier) {
+	i.notifier = notifier
+}
+
+func (i *InventoryManager) RecordInflow(count int) {
+	i.recordFlow(dbModel.InventoryFlowIn, count)
+}
+
+func (i *InventoryManager) RecordOutflow(count int) {
+	i.recordFlow(dbModel.InventoryFlowOut, count)
+}
+
+func (i *InventoryManager) recordFlow(direction dbModel.InventoryFlowDirection, count int) {
+	if count <= 0 {
+		return
+	}
+
+	err := i.dao.CreateInventoryFlow(&dbModel.InventoryFlow{Direction: direction, Count: count})
+	if err != nil {
+		slog.Error("recordFlow", logger.Err(err))
+	}
+}
+
+// Forecast compute how long the article cache lasts at the rates observed in the window and the current site demand
+func (i *InventoryManager) Forecast(now time.Time) (model.Forecast, error) {
+	windowDays, err := i.GetWindowDays()
+	if err != nil {
+		return model.Forecast{}, fmt.Errorf("Forecast: %w", err)
+	}
+
+	thresholdDays, err := i.GetAlertThresholdDays()
+	if err != nil {
+		return model.Forecast{}, fmt.Errorf("Forecast: %w", err)
+	}
+
+	stock, err := i.dao.CountReadyToPublishArticleCache()
+	if err != nil {
+		return model.Forecast{}, fmt.Errorf("Forecast: %w", err)
+	}
+
+	pendingLack, err := i.dao.SumLackCount()
+	if err != nil {
+		return model.Forecast{}, fmt.Errorf("Forecast: %w", err)
+	}
+
+	since := now.Add(-time.Duration(windowDays) * day)
+
+	inflow, err := i.dao.SumInventoryFlowSince(dbModel.InventoryFlowIn, since)
+	if err != nil {
+		return model.Forecast{}, fmt.Errorf("Forecast: %w", err)
+	}
+
+	outflow, err := i.dao.SumInventoryFlowSince(dbModel.InventoryFlowOut, since)
+	if err != nil {
+		return model.Forecast{}, fmt.Errorf("Forecast: %w", err)
+	}
+
+	sites, err := i.dao.ListSites()
+	if err != nil {
+		return model.Forecast{}, fmt.Errorf("Forecast: %w", err)
+	}
+
+	expectedDemand := 0.0
+	for _, site := range sites {
+		expectedDemand += i.demand.ExpectedDailyDemand(site)
+	}
+
+	forecast := model.Forecast{
+		Stock:                stock,
+		PendingLack:          pendingLack,
+		WindowDays:           windowDays,
+		InflowPerDay:         float64(inflow) / float64(windowDays),
+		OutflowPerDay:        float64(outflow) / float64(windowDays),
+		ExpectedDemandPerDay: expectedDemand,
+		ThresholdDays:        thresholdDays,
+		ForecastAt:           now,
+	}
+	forecast.DemandPerDay = max(forecast.OutflowPerDay, forecast.ExpectedDemandPerDay)
+	forecast.NetPerDay = forecast.DemandPerDay - forecast.InflowPerDay
+	forecast.DaysUntilEmpty = daysUntilEmpty(stock-int64(pendingLack), forecast.NetPerDay)
+	forecast.IsLow = thresholdDays > 0 && forecast.DaysUntilEmpty != nil && *forecast.DaysUntilEmpty < float64(thresholdDays)
+
+	return forecast, nil
+}
+
+// daysUntilEmpty return nil if the available stock never runs dry
+func daysUntilEmpty(available int64, netPerDay float64) *float64 {
+	days := 0.0
+	if available <= 0 {
+		return &days
+	}
+
+	if netPerDay <= 0 {
+		return nil
+	}
+
+	days = float64(available) / netPerDay
+
+	return &days
+}
+
+// StartInventoryCheck periodically check the forecast, alert when it is low and clean up the old flows
+func (i *InventoryManager) StartInventoryCheck(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				// Exit the loop if the context is cancelled
+				return
+			case <-time.After(checkInterval):
+				_, err := i.CheckInventory(time.Now())
+				if err != nil {
+					slog.ErrorContext(ctx, "CheckInventory", logger.Err(err))
+				}
+			}
+		}
+	}()
+}
+
+// CheckInventory forecast the inventory and alert once when it falls below the threshold
+func (i *InventoryManager) CheckInventory(now time.Time) (model.Forecast, error) {
+	var errs error
+
+	_, err := i.dao.DeleteInventoryFlowBefore(now.Add(-maxWindowDays * day))
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	forecast, err := i.Forecast(now)
+	if err != nil {
+		return model.Forecast{}, fmt.Errorf("CheckInventory: %w", errors.Join(errs, err))
+	}
+
+	i.alertLock.Lock()
+	defer i.alertLock.Unlock()
+
+	if !forecast.IsLow {
+		i.isAlerted = false
+	} else if !i.isAlerted {
+		i.isAlerted = true
+		i.notify(webhookModel.EventInventoryLow, webhookModel.InventoryLowData{
+			Stock:          forecast.Stock,
+			DemandPerDay:   forecast.DemandPerDay,
+			DaysUntilEmpty: *forecast.DaysUntilEmpty,
+			ThresholdDays:  forecast.ThresholdDays,
+		})
+	}
+
+	if errs != nil {
+		return forecast, fmt.Errorf("CheckInventory: %w", errs)
+	}
+
+	return forecast, nil
+}
+
+func (i *InventoryManager) notify(event webhookModel.Event, data any) {
+	if i.notifier == nil {
+		return
+	}
+
+	i.notifier.Notify(event, data)
+}
+
+// SetAlertThresholdDays set the days until empty to alert below, 0 to disable alert
+func (i *InventoryManager) SetAlertThresholdDays(days int) error {
+	return i.dao.UpsertByKeyInt(ConfigAlertThresholdDays, days)
+}
+
+func (i *InventoryManager) GetAlertThresholdDays() (int, error) {
+	days, err := i.dao.GetIntByKeyWithDefault(ConfigAlertThresholdDays, defaultAlertThresholdDays)
+	if err != nil {
+		return 0, fmt.Errorf("GetAlertThresholdDays: %w", err)
+	}
+
+	return days, nil
+}
+
+// SetWindowDays set the number of past days to average the inflow and outflow
+func (i *InventoryManager) SetWindowDays(days int) error {
+	if days < 1 || days > maxWindowDays {
+		return fmt.Errorf("SetWindowDays: %w", ErrInvalidWindowDays)
+	}
+
+	return i.dao.UpsertByKeyInt(ConfigWindowDays, days)
+}
+
+func (i *InventoryManager) GetWindowDays() (int, error) {
+	days, err := i.dao.GetIntByKeyWithDefault(ConfigWindowDays, defaultWindowDays)
+	if err != nil {
+		return 0, fmt.Errorf("GetWindowDays: %w", err)
+	}
+
+	if days < 1 || days > maxWindowDays {
+		days = defaultWindowDays
+	}
+
+	return days, nil
+}
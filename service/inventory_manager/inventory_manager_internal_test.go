@@ -0,0 +1,188 @@
+package inventorymanager
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	cmsdriver "github.com/ray31245/seo_cluster/pkg/cms_driver"
+	"github.com/ray31245/seo_cluster/pkg/db"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	publishmanager "github.com/ray31245/seo_cluster/service/publish_manager"
+	webhookModel "github.com/ray31245/seo_cluster/service/webhook_manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNotifier struct {
+	events []webhookModel.Event
+}
+
+func (m *mockNotifier) Notify(event webhookModel.Event, _ any) {
+	m.events = append(m.events, event)
+}
+
+func newTestInventoryManager(t *testing.T) (*InventoryManager, DAO) {
+	t.Helper()
+
+	database, err := db.NewDB(filepath.Join(t.TempDir(), "inventory.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	inventoryDAO, err := database.NewInventoryDAO()
+	require.NoError(t, err)
+
+	articleCacheDAO, err := database.NewArticleCacheDAO()
+	require.NoError(t, err)
+
+	siteDAO, err := database.NewSiteDAO()
+	require.NoError(t, err)
+
+	configDAO, err := database.NewKVConfigDAO()
+	require.NoError(t, err)
+
+	dao := DAO{
+		InventoryDAOInterface:    inventoryDAO,
+		ArticleCacheDAOInterface: articleCacheDAO,
+		SiteDAOInterface:         siteDAO,
+		KVConfigDAOInterface:     configDAO,
+	}
+
+	return NewInventoryManager(dao, publishmanager.NewPublishManager(cmsdriver.NewRegistry(cmsdriver.NewWordpressDriver(nil)), publishmanager.DAO{}, nil)), dao
+}
+
+func TestInventoryManager_Forecast(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		inflow             int
+		outflow            int
+		wantDemandPerDay   float64
+		wantDaysUntilEmpty *float64
+	}{
+		{
+			// demand follows the publish cycles of the wordpress site
+			name:               "no history",
+			wantDemandPerDay:   4.5,
+			wantDaysUntilEmpty: ptr(10.0),
+		},
+		{
+			name:               "outflow faster than cycles",
+			inflow:             7,
+			outflow:            70,
+			wantDemandPerDay:   10,
+			wantDaysUntilEmpty: ptr(5.0),
+		},
+		{
+			name:             "inflow keep up with demand",
+			inflow:           70,
+			outflow:          35,
+			wantDemandPerDay: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+			require := require.New(t)
+
+			i, dao := newTestInventoryManager(t)
+
+			site, err := dao.CreateSite(&dbModel.Site{URL: "https://example.com", CmsType: dbModel.CMSTypeWordPress})
+			require.NoError(err)
+			require.NoError(dao.IncreaseLackCount(site.ID.String(), 5))
+
+			for range 50 {
+				require.NoError(dao.AddArticleToCache(dbModel.ArticleCache{Title: "t", Content: "c"}))
+			}
+
+			// reserved article is not in stock
+			require.NoError(dao.AddArticleToCache(dbModel.ArticleCache{Title: "t", Content: "c", Status: dbModel.ArticleCacheStatusReserved}))
+
+			i.RecordInflow(tt.inflow)
+			i.RecordOutflow(tt.outflow)
+
+			forecast, err := i.Forecast(time.Now())
+			require.NoError(err)
+			assert.Equal(int64(50), forecast.Stock)
+			assert.Equal(5, forecast.PendingLack)
+			assert.Equal(defaultWindowDays, forecast.WindowDays)
+			assert.InDelta(tt.wantDemandPerDay, forecast.DemandPerDay, 0.001)
+
+			if tt.wantDaysUntilEmpty == nil {
+				assert.Nil(forecast.DaysUntilEmpty)
+			} else {
+				require.NotNil(forecast.DaysUntilEmpty)
+				assert.InDelta(*tt.wantDaysUntilEmpty, *forecast.DaysUntilEmpty, 0.001)
+			}
+		})
+	}
+}
+
+func TestInventoryManager_CheckInventory(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	i, dao := newTestInventoryManager(t)
+	notifier := &mockNotifier{}
+	i.SetNotifier(notifier)
+
+	// 9 articles last 2 days for a wordpress site
+	_, err := dao.CreateSite(&dbModel.Site{URL: "https://example.com", CmsType: dbModel.CMSTypeWordPress})
+	require.NoError(err)
+
+	for range 9 {
+		require.NoError(dao.AddArticleToCache(dbModel.ArticleCache{Title: "t", Content: "c"}))
+	}
+
+	forecast, err := i.CheckInventory(time.Now())
+	require.NoError(err)
+	assert.True(forecast.IsLow)
+
+	// alert only once until the stock recovers
+	_, err = i.CheckInventory(time.Now())
+	require.NoError(err)
+	assert.Equal([]webhookModel.Event{webhookModel.EventInventoryLow}, notifier.events)
+
+	require.NoError(i.SetAlertThresholdDays(1))
+
+	forecast, err = i.CheckInventory(time.Now())
+	require.NoError(err)
+	assert.False(forecast.IsLow)
+
+	require.NoError(i.SetAlertThresholdDays(3))
+
+	_, err = i.CheckInventory(time.Now())
+	require.NoError(err)
+	assert.Len(notifier.events, 2)
+
+	// old flows are cleaned up
+	i.RecordOutflow(100)
+
+	_, err = i.CheckInventory(time.Now().Add((maxWindowDays + 1) * day))
+	require.NoError(err)
+
+	outflow, err := dao.SumInventoryFlowSince(dbModel.InventoryFlowOut, time.Time{})
+	require.NoError(err)
+	assert.Zero(outflow)
+}
+
+func TestInventoryManager_SetWindowDays(t *testing.T) {
+	t.Parallel()
+
+	i, _ := newTestInventoryManager(t)
+
+	require.ErrorIs(t, i.SetWindowDays(0), ErrInvalidWindowDays)
+	require.ErrorIs(t, i.SetWindowDays(maxWindowDays+1), ErrInvalidWindowDays)
+	require.NoError(t, i.SetWindowDays(14))
+
+	days, err := i.GetWindowDays()
+	require.NoError(t, err)
+	assert.Equal(t, 14, days)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Forecast is the snapshot of article cache stock and how long it lasts at the current rates
+type Forecast struct {
+	// Stock is the number of articles ready to be published automatically
+	Stock int64 `json:"stock"`
+	// PendingLack is the number of articles the sites are waiting for
+	PendingLack int `json:"pending_lack"`
+	WindowDays  int `json:"window_days"`
+	// InflowPerDay and OutflowPerDay are the average rates observed in the window
+	InflowPerDay  float64 `json:"inflow_per_day"`
+	OutflowPerDay float64 `json:"outflow_per_day"`
+	// ExpectedDemandPerDay is the consumption expected by the publish cycles of all sites
+	ExpectedDemandPerDay float64 `json:"expected_demand_per_day"`
+	// DemandPerDay is the greater of OutflowPerDay and ExpectedDemandPerDay
+	DemandPerDay float64 `json:"demand_per_day"`
+	// NetPerDay is the stock consumed per day, negative means the stock is growing
+	NetPerDay float64 `json:"net_per_day"`
+	// DaysUntilEmpty is nil if the stock never runs dry at the current rates
+	DaysUntilEmpty *float64  `json:"days_until_empty"`
+	ThresholdDays  int       `json:"threshold_days"`
+	IsLow          bool      `json:"is_low"`
+	ForecastAt     time.Time `json:"forecast_at"`
+}
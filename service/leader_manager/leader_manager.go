@@ -0,0 +1,184 @@
+package leadermanager
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	dbInterface "github.com/ray31245/seo_cluster/pkg/db/db_interface"
+	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	"github.com/ray31245/seo_cluster/service/leader_manager/model"
+)
+
+const (
+	LeaseName = "publish_manager"
+
+	defaultTTL       = 30 * time.Second
+	defaultHeartbeat = 10 * time.Second
+)
+
+// LeaderManager elect one leader among the service instances sharing the same database,
+// only the leader runs the background loops, the clocks of instances are assumed to be in sync
+type LeaderManager struct {
+	dao       dbInterface.LeaderLeaseDAOInterface
+	holderID  string
+	ttl       time.Duration
+	heartbeat time.Duration
+	onElected func(ctx context.Context)
+	// done is closed after the lease is released by Start
+	done chan struct{}
+
+	lock         sync.Mutex
+	isLeader     bool
+	lastRenewed  time.Time
+	cancelLeader context.CancelFunc
+}
+
+// NewLeaderManager create a leader manager, onElected is called with a context
+// which is cancelled when the instance loses the leadership
+func NewLeaderManager(dao dbInterface.LeaderLeaseDAOInterface, holderID string, onElected func(ctx context.Context)) *LeaderManager {
+	return &LeaderManager{
+		dao:       dao,
+		holderID:  holderID,
+		ttl:       defaultTTL,
+		heartbeat: defaultHeartbeat,
+		onElected: onElected,
+		done:      make(chan struct{}),
+	}
+}
+
+// SetLeaseTiming set the ttl of lease and the interval to renew it, must be called before Start
+func (l *LeaderManager) SetLeaseTiming(ttl, heartbeat time.Duration) {
+	l.ttl = ttl
+	l.heartbeat = heartbeat
+}
+
+// Start campaign for the leadership and renew it by heartbeat, the lease is released when ctx is done
+func (l *LeaderManager) Start(ctx context.Context) {
+	go func() {
+		defer close(l.done)
+
+		l.tick(ctx, time.Now())
+
+		for {
+			select {
+			case <-ctx.Done():
+				l.stop()
+
+				return
+			case <-time.After(l.heartbeat):
+				l.tick(ctx, time.Now())
+			}
+		}
+	}()
+}
+
+// Wait block until the lease is released after ctx of Start is done, it is called on shutdown
+func (l *LeaderManager) Wait() {
+	<-l.done
+}
+
+func (l *LeaderManager) tick(ctx context.Context, now time.Time) {
+	isAcquired, err := l.dao.TryAcquireLeaderLease(LeaseName, l.holderID, now, l.ttl)
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if err != nil {
+		slog.ErrorContext(ctx, "LeaderManager tick", logger.Err(err))
+
+		// other instance can take over once the lease expires, step down before that
+		if l.isLeader && now.Sub(l.lastRenewed) >= l.ttl-l.heartbeat {
+			l.stepDown()
+		}
+
+		return
+	}
+
+	if !isAcquired {
+		if l.isLeader {
+			l.stepDown()
+		}
+
+		return
+	}
+
+	l.lastRenewed = now
+
+	if !l.isLeader {
+		l.elect(ctx)
+	}
+}
+
+func (l *LeaderManager) elect(ctx context.Context) {
+	slog.InfoContext(ctx, "instance is elected as leader", slog.String("instance_id", l.holderID))
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	l.isLeader = true
+	l.cancelLeader = cancel
+
+	if l.onElected != nil {
+		// do not block the heartbeat
+		go l.onElected(leaderCtx)
+	}
+}
+
+func (l *LeaderManager) stepDown() {
+	slog.Info("instance steps down from leader", slog.String("instance_id", l.holderID))
+
+	l.isLeader = false
+	if l.cancelLeader != nil {
+		l.cancelLeader()
+		l.cancelLeader = nil
+	}
+}
+
+func (l *LeaderManager) stop() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if !l.isLeader {
+		return
+	}
+
+	l.stepDown()
+
+	// release the lease for other instance to take over without waiting for expiry
+	err := l.dao.ReleaseLeaderLease(LeaseName, l.holderID)
+	if err != nil {
+		slog.Error("LeaderManager stop", slog.String("instance_id", l.holderID), logger.Err(err))
+	}
+}
+
+func (l *LeaderManager) IsLeader() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.isLeader
+}
+
+func (l *LeaderManager) HolderID() string {
+	return l.holderID
+}
+
+func (l *LeaderManager) Status() (model.LeaderStatus, error) {
+	status := model.LeaderStatus{
+		HolderID: l.holderID,
+		IsLeader: l.IsLeader(),
+	}
+
+	lease, err := l.dao.GetLeaderLease(LeaseName)
+	if dbErr.IsNotfoundErr(err) {
+		return status, nil
+	} else if err != nil {
+		return model.LeaderStatus{}, fmt.Errorf("Status: %w", err)
+	}
+
+	status.LeaderID = lease.HolderID
+	status.ExpiresAt = lease.ExpiresAt
+
+	return status, nil
+}
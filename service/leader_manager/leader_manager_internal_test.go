@@ -0,0 +1,135 @@
+package leadermanager
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ray31245/seo_cluster/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// instance is a service instance in test, running counts the background loops running
+type instance struct {
+	*LeaderManager
+	running atomic.Int32
+	elected atomic.Int32
+}
+
+func newInstance(t *testing.T, dsn, holderID string) *instance {
+	t.Helper()
+
+	database, err := db.NewDB(dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	dao, err := database.NewLeaderLeaseDAO()
+	require.NoError(t, err)
+
+	i := &instance{}
+	i.LeaderManager = NewLeaderManager(dao, holderID, func(ctx context.Context) {
+		i.elected.Add(1)
+		i.running.Add(1)
+
+		go func() {
+			<-ctx.Done()
+			i.running.Add(-1)
+		}()
+	})
+
+	return i
+}
+
+func TestLeaderManager_Failover(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dsn := filepath.Join(t.TempDir(), "leader.db")
+	a := newInstance(t, dsn, "a")
+	b := newInstance(t, dsn, "b")
+
+	ctx := context.Background()
+	now := time.Now()
+
+	a.tick(ctx, now)
+	b.tick(ctx, now)
+	assert.True(a.IsLeader())
+	assert.False(b.IsLeader())
+
+	// leader renew the lease by heartbeat
+	now = now.Add(defaultHeartbeat)
+	a.tick(ctx, now)
+	b.tick(ctx, now)
+	assert.True(a.IsLeader())
+	assert.False(b.IsLeader())
+
+	// a crashed without releasing the lease, b take over after the lease expired
+	b.tick(ctx, now.Add(defaultTTL-time.Second))
+	assert.False(b.IsLeader())
+
+	now = now.Add(defaultTTL + time.Second)
+	b.tick(ctx, now)
+	assert.True(b.IsLeader())
+
+	// a come back and find the lease taken
+	a.tick(ctx, now)
+	assert.False(a.IsLeader())
+
+	require.Eventually(func() bool {
+		return a.running.Load() == 0 && b.running.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	status, err := a.Status()
+	require.NoError(err)
+	assert.Equal("a", status.HolderID)
+	assert.False(status.IsLeader)
+	assert.Equal("b", status.LeaderID)
+}
+
+func TestLeaderManager_Start(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	const (
+		ttl       = 300 * time.Millisecond
+		heartbeat = 50 * time.Millisecond
+	)
+
+	dsn := filepath.Join(t.TempDir(), "leader.db")
+	a := newInstance(t, dsn, "a")
+	b := newInstance(t, dsn, "b")
+	a.SetLeaseTiming(ttl, heartbeat)
+	b.SetLeaseTiming(ttl, heartbeat)
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	defer cancelA()
+
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+
+	a.Start(ctxA)
+	assert.Eventually(a.IsLeader, time.Second, 10*time.Millisecond)
+
+	b.Start(ctxB)
+
+	// only one instance runs the background loops
+	time.Sleep(2 * ttl)
+	assert.True(a.IsLeader())
+	assert.False(b.IsLeader())
+	assert.Equal(int32(1), a.running.Load()+b.running.Load())
+
+	// a shutdown and release the lease, b take over without waiting for expiry
+	cancelA()
+	a.Wait()
+	assert.False(a.IsLeader())
+
+	assert.Eventually(func() bool {
+		return b.IsLeader() && a.running.Load() == 0 && b.running.Load() == 1
+	}, ttl/2, 10*time.Millisecond)
+	assert.Equal(int32(1), a.elected.Load())
+	assert.Equal(int32(1), b.elected.Load())
+}
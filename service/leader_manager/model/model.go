@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type LeaderStatus struct {
+	// HolderID is the id of this instance
+	HolderID string `json:"holder_id"`
+	IsLeader bool   `json:"is_leader"`
+	// LeaderID is the holder of the lease, it may be expired
+	LeaderID  string    `json:"leader_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,153 @@
+package publishmanager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ray31245/seo_cluster/service/publish_manager/model"
+)
+
+const defaultMaxPublishConcurrency = 4
+
+// siteLeases allow at most one in-flight article per site,
+// and at most maxConcurrency in-flight articles of all sites
+type siteLeases struct {
+	lock   sync.Mutex
+	leases map[uuid.UUID]chan struct{}
+	slots  chan struct{}
+
+	waiting   int
+	total     waitStats
+	siteStats map[uuid.UUID]*waitStats
+}
+
+type waitStats struct {
+	acquired  int64
+	totalWait time.Duration
+	maxWait   time.Duration
+}
+
+func (w *waitStats) add(wait time.Duration) {
+	w.acquired++
+	w.totalWait += wait
+	w.maxWait = max(w.maxWait, wait)
+}
+
+func (w *waitStats) toModel() model.LeaseWaitStats {
+	res := model.LeaseWaitStats{
+		Acquired:    w.acquired,
+		TotalWaitMs: w.totalWait.Milliseconds(),
+		MaxWaitMs:   w.maxWait.Milliseconds(),
+	}
+
+	if w.acquired > 0 {
+		res.AverageWaitMs = w.totalWait.Milliseconds() / w.acquired
+	}
+
+	return res
+}
+
+func newSiteLeases(maxConcurrency int) *siteLeases {
+	return &siteLeases{
+		leases:    map[uuid.UUID]chan struct{}{},
+		slots:     make(chan struct{}, max(maxConcurrency, 1)),
+		siteStats: map[uuid.UUID]*waitStats{},
+	}
+}
+
+func (l *siteLeases) siteLease(siteID uuid.UUID) chan struct{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	lease, ok := l.leases[siteID]
+	if !ok {
+		lease = make(chan struct{}, 1)
+		l.leases[siteID] = lease
+	}
+
+	return lease
+}
+
+// acquire wait for the lease of site and a global slot, the returned release must be called after publishing
+func (l *siteLeases) acquire(ctx context.Context, siteID uuid.UUID) (func(), error) {
+	lease := l.siteLease(siteID)
+	start := time.Now()
+
+	l.lock.Lock()
+	l.waiting++
+	l.lock.Unlock()
+
+	defer func() {
+		l.lock.Lock()
+		l.waiting--
+		l.lock.Unlock()
+	}()
+
+	// take the site lease first, so that waiting for a busy site does not occupy a global slot
+	select {
+	case lease <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("acquire: %w", ctx.Err())
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		<-lease
+
+		return nil, fmt.Errorf("acquire: %w", ctx.Err())
+	}
+
+	l.record(siteID, time.Since(start))
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			<-l.slots
+			<-lease
+		})
+	}, nil
+}
+
+func (l *siteLeases) record(siteID uuid.UUID, wait time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.total.add(wait)
+
+	stats, ok := l.siteStats[siteID]
+	if !ok {
+		stats = &waitStats{}
+		l.siteStats[siteID] = stats
+	}
+
+	stats.add(wait)
+}
+
+// isBusy report whether the site has an in-flight article
+func (l *siteLeases) isBusy(siteID uuid.UUID) bool {
+	return len(l.siteLease(siteID)) > 0
+}
+
+func (l *siteLeases) stats() model.PublishLockStats {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	res := model.PublishLockStats{
+		MaxConcurrency: cap(l.slots),
+		InFlight:       len(l.slots),
+		Waiting:        l.waiting,
+		Total:          l.total.toModel(),
+		Sites:          map[string]model.LeaseWaitStats{},
+	}
+
+	for siteID, stats := range l.siteStats {
+		res.Sites[siteID.String()] = stats.toModel()
+	}
+
+	return res
+}
@@ -0,0 +1,197 @@
+package publishmanager
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ray31245/seo_cluster/pkg/db"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_siteLeases_acquire(t *testing.T) {
+	t.Parallel()
+
+	const (
+		maxConcurrency = 2
+		sites          = 4
+		publishPerSite = 3
+	)
+
+	l := newSiteLeases(maxConcurrency)
+	siteIDs := make([]uuid.UUID, sites)
+
+	for i := range siteIDs {
+		siteIDs[i] = uuid.New()
+	}
+
+	var (
+		wg          sync.WaitGroup
+		inFlight    atomic.Int32
+		maxInFlight atomic.Int32
+		perSite     sync.Map
+	)
+
+	for _, siteID := range siteIDs {
+		counter := &atomic.Int32{}
+		perSite.Store(siteID, counter)
+
+		for range publishPerSite {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				release, err := l.acquire(context.Background(), siteID)
+				if !assert.NoError(t, err) {
+					return
+				}
+				defer release()
+
+				// each site has at most one in-flight article
+				assert.Equal(t, int32(1), counter.Add(1))
+				defer counter.Add(-1)
+
+				n := inFlight.Add(1)
+				defer inFlight.Add(-1)
+
+				for {
+					m := maxInFlight.Load()
+					if n <= m || maxInFlight.CompareAndSwap(m, n) {
+						break
+					}
+				}
+
+				time.Sleep(10 * time.Millisecond)
+			}()
+		}
+	}
+
+	wg.Wait()
+
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(maxConcurrency))
+
+	stats := l.stats()
+	assert.Equal(t, maxConcurrency, stats.MaxConcurrency)
+	assert.Zero(t, stats.InFlight)
+	assert.Zero(t, stats.Waiting)
+	assert.Equal(t, int64(sites*publishPerSite), stats.Total.Acquired)
+	assert.Positive(t, stats.Total.MaxWaitMs)
+	assert.Len(t, stats.Sites, sites)
+
+	for _, siteID := range siteIDs {
+		assert.Equal(t, int64(publishPerSite), stats.Sites[siteID.String()].Acquired)
+	}
+}
+
+func Test_siteLeases_acquireCancel(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	l := newSiteLeases(1)
+	siteID := uuid.New()
+
+	release, err := l.acquire(context.Background(), siteID)
+	require.NoError(err)
+	require.True(l.isBusy(siteID))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// site is busy
+	_, err = l.acquire(ctx, siteID)
+	require.ErrorIs(err, context.DeadlineExceeded)
+
+	// global slot is full
+	_, err = l.acquire(ctx, uuid.New())
+	require.ErrorIs(err, context.DeadlineExceeded)
+
+	// release twice is no-op
+	release()
+	release()
+	require.False(l.isBusy(siteID))
+
+	release, err = l.acquire(context.Background(), uuid.New())
+	require.NoError(err)
+	release()
+}
+
+func TestPublishManager_claimArticles(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	database, err := db.NewDB(filepath.Join(t.TempDir(), "publish.db"))
+	require.NoError(err)
+	t.Cleanup(func() { database.Close() })
+
+	articleCacheDAO, err := database.NewArticleCacheDAO()
+	require.NoError(err)
+
+	for range 5 {
+		require.NoError(articleCacheDAO.AddArticleToCache(dbModel.ArticleCache{Title: "t", Content: "c"}))
+	}
+
+	// two instances sharing the database
+	p := NewPublishManager(nil, DAO{ArticleCacheDAOInterface: articleCacheDAO}, nil)
+	other := NewPublishManager(nil, DAO{ArticleCacheDAOInterface: articleCacheDAO}, nil)
+
+	first, inFlight, err := p.claimArticles(3)
+	require.NoError(err)
+	assert.Len(first, 3)
+	assert.Zero(inFlight)
+
+	for _, article := range first {
+		assert.Equal(dbModel.ArticleCacheStatusInBuffer, article.Status)
+	}
+
+	// the lack is covered by in-flight articles
+	second, inFlight, err := other.claimArticles(3)
+	require.NoError(err)
+	assert.Empty(second)
+	assert.Equal(3, inFlight)
+
+	// the other instance do not take the same article
+	second, _, err = other.claimArticles(5)
+	require.NoError(err)
+	assert.Len(second, 2)
+
+	for _, a := range second {
+		for _, b := range first {
+			assert.NotEqual(a.ID, b.ID)
+		}
+	}
+
+	// the claim of other instance is not released
+	p.unclaimArticles(second)
+
+	_, inFlight, err = p.claimArticles(5)
+	require.NoError(err)
+	assert.Equal(5, inFlight)
+
+	p.unclaimArticles(first)
+	other.unclaimArticles(second)
+
+	third, inFlight, err := p.claimArticles(5)
+	require.NoError(err)
+	assert.Len(third, 5)
+	assert.Zero(inFlight)
+
+	p.unclaimArticles(third)
+
+	// the claim of crashed instance expires
+	p.claimTTL = -time.Second
+	_, _, err = p.claimArticles(5)
+	require.NoError(err)
+
+	fourth, inFlight, err := other.claimArticles(5)
+	require.NoError(err)
+	assert.Len(fourth, 5)
+	assert.Zero(inFlight)
+}
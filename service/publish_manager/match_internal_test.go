@@ -0,0 +1,62 @@
+package publishmanager
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	aiAssistInterface "github.com/ray31245/seo_cluster/pkg/ai_assist/ai_assist_interface"
+	aiAssistModel "github.com/ray31245/seo_cluster/pkg/ai_assist/model"
+	"github.com/ray31245/seo_cluster/pkg/db"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/service/publish_manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSelectCategoryAI struct {
+	aiAssistInterface.AIAssistInterface
+	req aiAssistModel.SelectCategoryRequest
+}
+
+func (m *mockSelectCategoryAI) SelectCategory(_ context.Context, req aiAssistModel.SelectCategoryRequest) (aiAssistModel.SelectCategoryResponse, error) {
+	m.req = req
+
+	return aiAssistModel.SelectCategoryResponse{}, nil
+}
+
+func TestPublishManager_findFirstMatchCategory(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	database, err := db.NewDB(filepath.Join(t.TempDir(), "publish.db"))
+	require.NoError(err)
+	t.Cleanup(func() { database.Close() })
+
+	siteDAO, err := database.NewSiteDAO()
+	require.NoError(err)
+
+	configDAO, err := database.NewKVConfigDAO()
+	require.NoError(err)
+
+	now := time.Now()
+
+	for i, lang := range []aiAssistModel.Language{aiAssistModel.LanguageEnglish, aiAssistModel.LanguageSimplifiedChinese, aiAssistModel.LanguageEnglish} {
+		site, err := siteDAO.CreateSite(&dbModel.Site{URL: fmt.Sprintf("http://site%d.test", i), LackCount: 1, CmsType: dbModel.CMSTypeZBlog, Language: string(lang)})
+		require.NoError(err)
+		require.NoError(siteDAO.CreateCategory(&dbModel.Category{Name: string(lang), SiteID: site.ID, LastPublished: now.Add(time.Duration(i) * time.Minute)}))
+	}
+
+	ai := &mockSelectCategoryAI{}
+	p := NewPublishManager(nil, DAO{SiteDAOInterface: siteDAO, KVConfigDAOInterface: configDAO}, ai)
+
+	cate, err := p.findFirstMatchCategory(context.Background(), model.Article{Content: "content"})
+	require.NoError(err)
+	assert.Equal(string(aiAssistModel.LanguageEnglish), cate.Name)
+	assert.Equal(aiAssistModel.LanguageEnglish, ai.req.Language)
+	// only the categories of sites in the same language are matched
+	assert.Len(ai.req.CategoriesOption, 2)
+}
@@ -3,45 +3,44 @@ package model
 import (
 	"time"
 
-	"github.com/ray31245/seo_cluster/pkg/util"
-	wordpressModel "github.com/ray31245/seo_cluster/pkg/wordpress_api/model"
-	zModel "github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
+	cmsModel "github.com/ray31245/seo_cluster/pkg/cms_driver/model"
 )
 
 type Article struct {
 	Title   string `json:"Title"`
 	IsTop   bool   `json:"IsTop"`
 	Content string `json:"Content"`
-	CateID  uint32 `json:"CateID"`
+	// CateID is the id of category in CMS
+	CateID string `json:"CateID"`
+	// Priority and ExpiresAt are only used when the article is added to cache
+	Priority  int        `json:"Priority"`
+	ExpiresAt *time.Time `json:"ExpiresAt"`
 }
 
-func (a *Article) ToZBlogCreateRequest() zModel.PostArticleRequest {
-	var isTop uint8 = 0
-	if a.IsTop {
-		isTop = 1
-	}
-
-	return zModel.PostArticleRequest{
-		Title:    a.Title,
-		IsTop:    isTop,
-		Content:  a.Content,
-		CateID:   a.CateID,
-		Intro:    a.Content,
-		PostTime: &util.UnixTime{Time: time.Now()},
+// ToCMSArticle convert to the article posted by CMS driver
+func (a *Article) ToCMSArticle() cmsModel.Article {
+	return cmsModel.Article{
+		Title:   a.Title,
+		IsTop:   a.IsTop,
+		Content: a.Content,
+		CateID:  a.CateID,
 	}
 }
 
-func (a *Article) ToWordpressCreateArgs(status wordpressModel.ArticleStatus) wordpressModel.CreateArticleArgs {
-	date := wordpressModel.Date{
-		Time: time.Now(),
-	}
+// LeaseWaitStats is the time spent waiting for publish leases
+type LeaseWaitStats struct {
+	Acquired      int64 `json:"acquired"`
+	TotalWaitMs   int64 `json:"total_wait_ms"`
+	MaxWaitMs     int64 `json:"max_wait_ms"`
+	AverageWaitMs int64 `json:"average_wait_ms"`
+}
 
-	return wordpressModel.CreateArticleArgs{
-		Title:      a.Title,
-		Sticky:     a.IsTop,
-		Content:    a.Content,
-		Categories: []uint32{a.CateID},
-		Status:     status,
-		Date:       &date,
-	}
+type PublishLockStats struct {
+	MaxConcurrency int `json:"max_concurrency"`
+	// InFlight is the number of articles being published
+	InFlight int `json:"in_flight"`
+	// Waiting is the number of publishes waiting for a site lease or a global slot
+	Waiting int                       `json:"waiting"`
+	Total   LeaseWaitStats            `json:"total"`
+	Sites   map[string]LeaseWaitStats `json:"sites"`
 }
@@ -0,0 +1,37 @@
+package publishmanager
+
+import (
+	"sync"
+	"testing"
+
+	webhookModel "github.com/ray31245/seo_cluster/service/webhook_manager/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockNotifier struct {
+	lock   sync.Mutex
+	events []webhookModel.Event
+}
+
+func (m *mockNotifier) Notify(event webhookModel.Event, _ any) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.events = append(m.events, event)
+}
+
+func TestPublishManager_checkCacheLow(t *testing.T) {
+	t.Parallel()
+
+	notifier := &mockNotifier{}
+	p := NewPublishManager(nil, DAO{}, nil)
+	p.SetNotifier(notifier)
+
+	p.checkCacheLow(1, 3)
+	p.checkCacheLow(0, 3)
+	assert.Len(t, notifier.events, 1, "alert once while the cache stays low")
+
+	p.checkCacheLow(3, 3)
+	p.checkCacheLow(1, 3)
+	assert.Equal(t, []webhookModel.Event{webhookModel.EventArticleCacheLow, webhookModel.EventArticleCacheLow}, notifier.events, "alert again after the cache recovers")
+}
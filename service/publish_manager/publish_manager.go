@@ -4,10 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
 	"runtime"
+	"slices"
 	"sort"
-	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -16,24 +17,34 @@ import (
 	"github.com/google/uuid"
 	aiAssistInterface "github.com/ray31245/seo_cluster/pkg/ai_assist/ai_assist_interface"
 	aiAssistModel "github.com/ray31245/seo_cluster/pkg/ai_assist/model"
+	cmsDriverInterface "github.com/ray31245/seo_cluster/pkg/cms_driver/cms_driver_interface"
+	cmsModel "github.com/ray31245/seo_cluster/pkg/cms_driver/model"
 	dbInterface "github.com/ray31245/seo_cluster/pkg/db/db_interface"
 	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
 	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/pkg/logger"
 	"github.com/ray31245/seo_cluster/pkg/util"
-	wordpressModel "github.com/ray31245/seo_cluster/pkg/wordpress_api/model"
-	wordpressInterface "github.com/ray31245/seo_cluster/pkg/wordpress_api/wordpress_interface"
-	zModel "github.com/ray31245/seo_cluster/pkg/z_blog_api/model"
-	zInterface "github.com/ray31245/seo_cluster/pkg/z_blog_api/z_blog_Interface"
 	"github.com/ray31245/seo_cluster/service/publish_manager/model"
+	rewritemanager "github.com/ray31245/seo_cluster/service/rewrite_manager"
+	rewriteModel "github.com/ray31245/seo_cluster/service/rewrite_manager/model"
+	webhookModel "github.com/ray31245/seo_cluster/service/webhook_manager/model"
+	webhookInterface "github.com/ray31245/seo_cluster/service/webhook_manager/webhook_interface"
 )
 
 const (
 	ConfigUnCateName  = "un_cate_name"
 	TagsBlockList     = "tags_block_list"
 	IsStopAutoPublish = "is_stop_auto_publish"
+	ArticleCacheTTL   = "article_cache_ttl_hours"
 
 	maxKeyWords                  = 5
 	updateArticleTagSignalBuffer = 100000
+	defaultMaxUpdateTagThreads   = 5
+	// maxMatchRetry is the times to match again when the matched site has no lack any more
+	maxMatchRetry = 3
+	// defaultClaimTTL is how long an article is claimed by publishByLack,
+	// the claim of a crashed instance expires after it, so the article can be published by others
+	defaultClaimTTL = time.Hour
 )
 
 var ErrNoCategoryNeedToBePublished = errors.New("no category need to be published")
@@ -54,30 +65,129 @@ type DAO struct {
 }
 
 type PublishManager struct {
-	zAPI                    zInterface.ZBlogAPI
-	wordpressAPI            wordpressInterface.WordpressAPI
+	cmsDrivers              cmsDriverInterface.Registry
 	aiAssist                aiAssistInterface.AIAssistInterface
 	dao                     DAO
-	publishLock             sync.Mutex
+	leases                  *siteLeases
+	instanceID              string
+	claimTTL                time.Duration
 	updateTagSignal         chan updateArticleTagSignal
 	maxUpdateTagThreads     int
 	updateArticleTagThreads atomic.Int32
+	notifier                webhookInterface.Notifier
+	siteRewriter            SiteRewriter
+	inventory               InventoryRecorder
+
+	cacheLowLock sync.Mutex
+	// isCacheLowAlerted avoid alerting again until the article cache recovers
+	isCacheLowAlerted bool
+}
+
+// SiteRewriter rewrite the article for the site it is about to be published to
+type SiteRewriter interface {
+	RewriteForSite(ctx context.Context, siteID string, articleContent string) (rewriteModel.RewriteResult, bool, error)
+}
+
+// InventoryRecorder record the articles added to and consumed from the article cache
+type InventoryRecorder interface {
+	RecordInflow(count int)
+	RecordOutflow(count int)
 }
 
 var ErrStopAutoPublish = errors.New("system is set to stop auto publish, break the cycle")
 
-func NewPublishManager(zAPI zInterface.ZBlogAPI, wordpressAPI wordpressInterface.WordpressAPI, dao DAO, aiAssist aiAssistInterface.AIAssistInterface) *PublishManager {
+func NewPublishManager(cmsDrivers cmsDriverInterface.Registry, dao DAO, aiAssist aiAssistInterface.AIAssistInterface) *PublishManager {
 	updateArticleTagSignal := make(chan updateArticleTagSignal, updateArticleTagSignalBuffer)
 
 	return &PublishManager{
-		zAPI:            zAPI,
-		wordpressAPI:    wordpressAPI,
-		aiAssist:        aiAssist,
-		dao:             dao,
-		updateTagSignal: updateArticleTagSignal,
+		cmsDrivers:          cmsDrivers,
+		aiAssist:            aiAssist,
+		dao:                 dao,
+		leases:              newSiteLeases(defaultMaxPublishConcurrency),
+		instanceID:          uuid.NewString(),
+		claimTTL:            defaultClaimTTL,
+		updateTagSignal:     updateArticleTagSignal,
+		maxUpdateTagThreads: defaultMaxUpdateTagThreads,
 	}
 }
 
+// SetMaxPublishConcurrency set the max number of articles published at the same time,
+// must be called before publishing starts
+func (p *PublishManager) SetMaxPublishConcurrency(n int) {
+	p.leases = newSiteLeases(n)
+}
+
+// SetInstanceID set the id of service instance which claims the articles to publish, default is a random id
+func (p *PublishManager) SetInstanceID(ID string) {
+	p.instanceID = ID
+}
+
+// PublishLockStats return the concurrency and lease wait time of publishing
+func (p *PublishManager) PublishLockStats() model.PublishLockStats {
+	return p.leases.stats()
+}
+
+// SetNotifier set the notifier to emit publish events, nil to disable
+func (p *PublishManager) SetNotifier(notifier webhookInterface.Notifier) {
+	p.notifier = notifier
+}
+
+// SetSiteRewriter set the rewriter to rewrite article for each site before publishing, nil to disable
+func (p *PublishManager) SetSiteRewriter(rewriter SiteRewriter) {
+	p.siteRewriter = rewriter
+}
+
+// SetInventoryRecorder set the recorder to track the article cache inflow and outflow, nil to disable
+func (p *PublishManager) SetInventoryRecorder(inventory InventoryRecorder) {
+	p.inventory = inventory
+}
+
+func (p *PublishManager) recordInflow(count int) {
+	if p.inventory == nil {
+		return
+	}
+
+	p.inventory.RecordInflow(count)
+}
+
+func (p *PublishManager) recordOutflow(count int) {
+	if p.inventory == nil {
+		return
+	}
+
+	p.inventory.RecordOutflow(count)
+}
+
+func (p *PublishManager) notify(event webhookModel.Event, data any) {
+	if p.notifier == nil {
+		return
+	}
+
+	p.notifier.Notify(event, data)
+}
+
+// checkCacheLow emit the cache low event once when the cache can not cover the lack, and reset after it recovers
+func (p *PublishManager) checkCacheLow(cacheCount int, lackCount int) {
+	p.cacheLowLock.Lock()
+	defer p.cacheLowLock.Unlock()
+
+	if cacheCount >= lackCount {
+		p.isCacheLowAlerted = false
+
+		return
+	}
+
+	if p.isCacheLowAlerted {
+		return
+	}
+
+	p.isCacheLowAlerted = true
+	p.notify(webhookModel.EventArticleCacheLow, webhookModel.ArticleCacheLowData{
+		CacheCount: cacheCount,
+		LackCount:  lackCount,
+	})
+}
+
 // AveragePublish average publish article to all site and category
 func (p *PublishManager) AveragePublish(ctx context.Context, article model.Article) error {
 	isStopAutoPublish, err := p.dao.GetBoolByKeyWithDefault(IsStopAutoPublish, false)
@@ -89,35 +199,61 @@ func (p *PublishManager) AveragePublish(ctx context.Context, article model.Artic
 		return fmt.Errorf("AveragePublish: %w", ErrStopAutoPublish)
 	}
 
+	for range maxMatchRetry {
+		isPublished, err := p.averagePublish(ctx, article)
+		if err != nil {
+			return fmt.Errorf("AveragePublish: %w", err)
+		}
+
+		if isPublished {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("AveragePublish: %w", ErrNoCategoryNeedToBePublished)
+}
+
+// averagePublish publish article to the first match category,
+// return false if the site has no lack any more when its lease is acquired
+func (p *PublishManager) averagePublish(ctx context.Context, article model.Article) (bool, error) {
 	cate, err := p.findFirstMatchCategory(ctx, article)
 	if err != nil {
-		return fmt.Errorf("AveragePublish: %w", err)
+		return false, fmt.Errorf("averagePublish: %w", err)
+	}
+
+	release, err := p.leases.acquire(ctx, cate.SiteID)
+	if err != nil {
+		return false, fmt.Errorf("averagePublish: %w", err)
 	}
+	defer release()
 
-	log.Printf("category id %s, site id %s in AveragePublish", cate.ID, cate.SiteID)
+	// other article may be published to the site while waiting for the lease
+	site, err := p.dao.GetSite(cate.SiteID.String())
+	if err != nil {
+		return false, fmt.Errorf("averagePublish: %w", err)
+	}
 
-	// set category id
-	if cate.Site.CmsType == dbModel.CMSTypeWordPress {
-		article.CateID = cate.WordpressID
-	} else if cate.Site.CmsType == dbModel.CMSTypeZBlog {
-		article.CateID = cate.ZBlogID
-	} else {
-		return fmt.Errorf("AveragePublish: %w", errors.New("cms type not support"))
+	if site.LackCount <= 0 {
+		return false, nil
 	}
 
+	slog.InfoContext(ctx, "averagePublish", slog.String("category_id", cate.ID.String()), logger.SiteID(cate.SiteID))
+
+	article.CateID = cate.CMSCategoryID
+
 	// do publish
-	err = p.doPublish(ctx, article, cate.Site)
+	err = p.publishArticle(ctx, article, *site)
 	if err != nil {
-		return errors.Join(PublishErr{SiteID: cate.SiteID, CateID: cate.ID}, err)
+		return false, errors.Join(PublishErr{SiteID: cate.SiteID, CateID: cate.ID}, err)
 	}
 
 	// mark last published
 	err = p.dao.MarkPublished(cate.ID.String())
 	if err != nil {
-		return fmt.Errorf("AveragePublish: %w", err)
+		return false, fmt.Errorf("averagePublish: %w", err)
 	}
 
-	return nil
+	return true, nil
 }
 
 func (p *PublishManager) DirectPublish(ctx context.Context, cateID string, article model.Article) error {
@@ -126,19 +262,7 @@ func (p *PublishManager) DirectPublish(ctx context.Context, cateID string, artic
 		return fmt.Errorf("DirectPublish: %w", err)
 	}
 
-	site, err := p.dao.GetSite(cate.SiteID.String())
-	if err != nil {
-		return fmt.Errorf("DirectPublish: %w", err)
-	}
-
-	// set category id
-	if site.CmsType == dbModel.CMSTypeWordPress {
-		article.CateID = cate.WordpressID
-	} else if site.CmsType == dbModel.CMSTypeZBlog {
-		article.CateID = cate.ZBlogID
-	} else {
-		return fmt.Errorf("DirectPublish: %w", errors.New("cms type not support"))
-	}
+	article.CateID = cate.CMSCategoryID
 
 	err = p.doPublish(ctx, article, cate.Site)
 	if err != nil {
@@ -227,14 +351,7 @@ func (p *PublishManager) SpecifyPublish(ctx context.Context, cateID string, arti
 		Content: articleCache.Content,
 	}
 
-	// set category id
-	if cate.Site.CmsType == dbModel.CMSTypeWordPress {
-		article.CateID = cate.WordpressID
-	} else if cate.Site.CmsType == dbModel.CMSTypeZBlog {
-		article.CateID = cate.ZBlogID
-	} else {
-		return fmt.Errorf("SpecifyPublish: %w", errors.New("cms type not support"))
-	}
+	article.CateID = cate.CMSCategoryID
 
 	err = p.doPublish(ctx, article, cate.Site)
 	if err != nil {
@@ -247,6 +364,8 @@ func (p *PublishManager) SpecifyPublish(ctx context.Context, cateID string, arti
 		return fmt.Errorf("SpecifyPublish: %w", err)
 	}
 
+	p.recordOutflow(1)
+
 	return nil
 }
 
@@ -263,23 +382,14 @@ func (p *PublishManager) startPublishWorker(ctx context.Context, wg *sync.WaitGr
 				continue
 			}
 
-			cate, err := p.MatchCategory(ctx, site.Categories, s.Article)
+			cate, err := p.MatchCategory(ctx, site.Categories, s.Article, aiAssistModel.Language(site.Language))
 			if err != nil {
 				errCh <- err
 
 				continue
 			}
 
-			// set category id
-			if cate.Site.CmsType == dbModel.CMSTypeWordPress {
-				s.Article.CateID = cate.WordpressID
-			} else if cate.Site.CmsType == dbModel.CMSTypeZBlog {
-				s.Article.CateID = cate.ZBlogID
-			} else {
-				errCh <- errors.New("cms type not support")
-
-				continue
-			}
+			s.Article.CateID = cate.CMSCategoryID
 
 			err = p.doPublish(ctx, s.Article, cate.Site)
 			if err != nil {
@@ -306,7 +416,21 @@ func (p *PublishManager) findFirstMatchCategory(ctx context.Context, article mod
 		return nil, fmt.Errorf("FindFirstMatchCategory: %w", ErrNoCategoryNeedToBePublished)
 	}
 
-	cate, err := p.MatchCategory(ctx, cates, article)
+	// prefer the sites without in-flight article, so that different sites publish in parallel
+	idleCates := slices.DeleteFunc(slices.Clone(cates), func(cate dbModel.Category) bool {
+		return p.leases.isBusy(cate.SiteID)
+	})
+	if len(idleCates) > 0 {
+		cates = idleCates
+	}
+
+	// categories may belong to sites of different languages, match in the language of the site published longest ago
+	lang := cates[0].Site.Language
+	cates = slices.DeleteFunc(cates, func(cate dbModel.Category) bool {
+		return cate.Site.Language != lang
+	})
+
+	cate, err := p.MatchCategory(ctx, cates, article, aiAssistModel.Language(lang))
 	if err != nil {
 		return nil, fmt.Errorf("FindFirstMatchCategory: %w", err)
 	}
@@ -314,7 +438,7 @@ func (p *PublishManager) findFirstMatchCategory(ctx context.Context, article mod
 	return cate, nil
 }
 
-func (p *PublishManager) MatchCategory(ctx context.Context, cates []dbModel.Category, article model.Article) (*dbModel.Category, error) {
+func (p *PublishManager) MatchCategory(ctx context.Context, cates []dbModel.Category, article model.Article, lang aiAssistModel.Language) (*dbModel.Category, error) {
 	notMatchCate := dbModel.Category{}
 	cateOpts := []aiAssistModel.CategoryOption{}
 
@@ -341,6 +465,7 @@ func (p *PublishManager) MatchCategory(ctx context.Context, cates []dbModel.Cate
 		aiAssistModel.SelectCategoryRequest{
 			Text:             []byte(article.Content),
 			CategoriesOption: cateOpts,
+			Language:         lang,
 		},
 	)
 	if err != nil {
@@ -369,82 +494,104 @@ func (p *PublishManager) MatchCategory(ctx context.Context, cates []dbModel.Cate
 	return cate, nil
 }
 
+// doPublish publish article to site, wait for the lease of site before publishing
 func (p *PublishManager) doPublish(ctx context.Context, article model.Article, site dbModel.Site) error {
-	var err error
-	if site.CmsType == dbModel.CMSTypeWordPress {
-		_, err = p.doPublishWordPress(ctx, article, site)
-	} else if site.CmsType == dbModel.CMSTypeZBlog {
-		_, err = p.doPublishZblog(ctx, article, site)
-	} else {
-		err = errors.New("cms type not support")
-	}
-
+	release, err := p.leases.acquire(ctx, site.ID)
 	if err != nil {
 		return fmt.Errorf("doPublish: %w", err)
 	}
+	defer release()
+
+	return p.publishArticle(ctx, article, site)
+}
+
+// publishArticle publish article to site, the caller must hold the lease of site
+func (p *PublishManager) publishArticle(ctx context.Context, article model.Article, site dbModel.Site) error {
+	var artID string
+
+	article, err := p.rewriteForSite(ctx, article, site)
+	if err == nil {
+		artID, err = p.postArticle(ctx, article, site)
+	}
+
+	if err != nil {
+		p.notify(webhookModel.EventArticlePublishFail, webhookModel.ArticlePublishFailData{
+			SiteID:  site.ID.String(),
+			SiteURL: site.URL,
+			CateID:  article.CateID,
+			Title:   article.Title,
+			Error:   err.Error(),
+		})
+
+		return fmt.Errorf("publishArticle: %w", err)
+	}
+
+	p.notify(webhookModel.EventArticlePublished, webhookModel.ArticlePublishedData{
+		SiteID:    site.ID.String(),
+		SiteURL:   site.URL,
+		CateID:    article.CateID,
+		ArticleID: artID,
+		Title:     article.Title,
+	})
 
 	return nil
 }
 
-func (p *PublishManager) doPublishWordPress(ctx context.Context, article model.Article, site dbModel.Site) (wordpressModel.CreateArticleResponse, error) {
-	// set post article request
-	postArticle := article.ToWordpressCreateArgs(wordpressModel.StatusPublish)
+// postArticle post article to site, return the id of article in site
+func (p *PublishManager) postArticle(ctx context.Context, article model.Article, site dbModel.Site) (string, error) {
+	driver, err := p.cmsDrivers.Driver(site.CmsType)
+	if err != nil {
+		return "", fmt.Errorf("postArticle: %w", err)
+	}
 
-	// get wordpress api client
-	client, err := p.wordpressAPI.GetClient(ctx, site.ID, site.URL, site.UserName, site.Password)
+	client, err := driver.GetClient(ctx, site.ID, cmsModel.NewSiteCredential(site))
 	if err != nil {
-		return wordpressModel.CreateArticleResponse{}, fmt.Errorf("doPublishWordPress: %w", err)
+		return "", fmt.Errorf("postArticle: %w", err)
 	}
 
-	// post article
-	postArt, err := client.CreateArticle(ctx, postArticle)
+	postArt, err := client.PostArticle(ctx, article.ToCMSArticle())
 	if err != nil {
-		return wordpressModel.CreateArticleResponse{}, fmt.Errorf("doPublishWordPress: %w", err)
+		return "", fmt.Errorf("postArticle: %w", err)
 	}
 
 	// update article tag
 	err = p.pushUpdateTagSignal(updateArticleTagSignal{ArtContent: article.Content, ArtID: postArt.ID, Site: site})
 	if err != nil {
-		return wordpressModel.CreateArticleResponse{}, fmt.Errorf("doPublishWordPress: %w", err)
+		return "", fmt.Errorf("postArticle: %w", err)
 	}
 
-	return postArt, nil
+	return postArt.ID, nil
 }
 
-func (p *PublishManager) doPublishZblog(ctx context.Context, article model.Article, site dbModel.Site) (zModel.Article, error) {
-	// set post article request
-	postArticle := article.ToZBlogCreateRequest()
-
-	// get zblog api client
-	client, err := p.zAPI.GetClient(ctx, site.ID, site.URL, site.UserName, site.Password)
-	if err != nil {
-		return zModel.Article{}, fmt.Errorf("doPublishZblog: %w", err)
+// rewriteForSite rewrite the article with rewrite profile of site,
+// keep the article as it is if site does not rewrite on publish or the article is too short to rewrite
+func (p *PublishManager) rewriteForSite(ctx context.Context, article model.Article, site dbModel.Site) (model.Article, error) {
+	if p.siteRewriter == nil {
+		return article, nil
 	}
 
-	// post article
-	postArt, err := client.PostArticle(ctx, postArticle)
-	if err != nil {
-		return zModel.Article{}, fmt.Errorf("doPublishZblog: %w", err)
-	}
+	res, isRewritten, err := p.siteRewriter.RewriteForSite(ctx, site.ID.String(), article.Content)
+	if errors.Is(err, rewritemanager.ErrSourceTooShort) {
+		slog.InfoContext(ctx, "rewriteForSite: skip rewrite for site", logger.SiteID(site.ID), slog.String("url", site.URL), logger.Err(err))
 
-	artID, err := strconv.Atoi(string(postArt.ID))
-	if err != nil {
-		return zModel.Article{}, fmt.Errorf("doPublishZblog: %w", err)
+		return article, nil
+	} else if err != nil {
+		return model.Article{}, fmt.Errorf("rewriteForSite: %w", err)
 	}
 
-	// update article tag
-	err = p.pushUpdateTagSignal(updateArticleTagSignal{ArtContent: article.Content, ArtID: artID, Site: site})
-	if err != nil {
-		return zModel.Article{}, fmt.Errorf("doPublishZblog: %w", err)
+	if isRewritten {
+		article.Title = res.Title
+		article.Content = res.Content
 	}
 
-	return postArt, nil
+	return article, nil
 }
 
 type updateArticleTagSignal struct {
 	ArtContent string
-	ArtID      int
-	Site       dbModel.Site
+	// ArtID is the id of article in CMS
+	ArtID string
+	Site  dbModel.Site
 }
 
 func (p *PublishManager) StartUpdateArticleTagSignalLoop(ctx context.Context, threads int, maxThreads int) error {
@@ -462,10 +609,18 @@ func (p *PublishManager) StartUpdateArticleTagSignalLoop(ctx context.Context, th
 }
 
 func (p *PublishManager) pushUpdateTagSignal(signal updateArticleTagSignal) (err error) {
+	// the persistent threads only run on leader, handle the signal of this instance on demand
+	if p.updateArticleTagThreads.Load() == 0 {
+		err = p.newUpdateArticleTagSignalLoopThread(context.Background(), false)
+		if err != nil {
+			return fmt.Errorf("pushUpdateTagSignal: %w", err)
+		}
+	}
+
 	select {
 	case p.updateTagSignal <- signal:
 	default:
-		log.Println("updateTagSignal is full, open new goroutine to handle")
+		slog.Warn("updateTagSignal is full, open new goroutine to handle", logger.SiteID(signal.Site.ID), logger.ArticleID(signal.ArtID))
 
 		err = p.newUpdateArticleTagSignalLoopThread(context.Background(), false)
 		if err != nil {
@@ -507,11 +662,11 @@ func (p *PublishManager) updateArticleTagSignalLoop(ctx context.Context, isPersi
 
 			err := p.updateArticleTag(ctx, signal.ArtContent, signal.ArtID, signal.Site)
 			if err != nil {
-				log.Printf("Error in updateArticleTagSignalLoop: %v", err)
+				slog.ErrorContext(ctx, "updateArticleTagSignalLoop", logger.SiteID(signal.Site.ID), logger.ArticleID(signal.ArtID), logger.Err(err))
 			}
 		case <-idleCheck():
 			if isIdle {
-				log.Println("updateArticleTagSignalLoop is idle, exit")
+				slog.DebugContext(ctx, "updateArticleTagSignalLoop is idle, exit")
 
 				return
 			}
@@ -523,47 +678,35 @@ func (p *PublishManager) updateArticleTagSignalLoop(ctx context.Context, isPersi
 	}
 }
 
-func (p *PublishManager) updateArticleTag(ctx context.Context, artContent string, artID int, site dbModel.Site) error {
-	var err error
-	if site.CmsType == dbModel.CMSTypeWordPress {
-		err = p.updateArticleTagWordpress(ctx, artContent, artID, site)
-	} else if site.CmsType == dbModel.CMSTypeZBlog {
-		err = p.updateArticleTagZblog(ctx, artContent, artID, site)
-	} else {
-		err = errors.New("cms type not support")
-	}
-
+func (p *PublishManager) updateArticleTag(ctx context.Context, artContent string, artID string, site dbModel.Site) error {
+	driver, err := p.cmsDrivers.Driver(site.CmsType)
 	if err != nil {
 		return fmt.Errorf("updateArticleTag: %w", err)
 	}
 
-	return nil
-}
-
-func (p *PublishManager) updateArticleTagZblog(ctx context.Context, artContent string, artID int, site dbModel.Site) error {
-	client, err := p.zAPI.GetClient(ctx, site.ID, site.URL, site.UserName, site.Password)
+	client, err := driver.GetClient(ctx, site.ID, cmsModel.NewSiteCredential(site))
 	if err != nil {
-		return fmt.Errorf("updateArticleTagZblog: %w", err)
+		return fmt.Errorf("updateArticleTag: %w", err)
 	}
 
 	tagBlackList, err := p.GetTagsBlockList()
 	if err != nil && !dbErr.IsNotfoundErr(err) {
-		return fmt.Errorf("updateArticleTagZblog: %w", err)
+		return fmt.Errorf("updateArticleTag: %w", err)
 	}
 
 	siteTags, err := client.ListTagAll(ctx)
 	if err != nil {
-		return fmt.Errorf("updateArticleTagZblog: %w", err)
+		return fmt.Errorf("updateArticleTag: %w", err)
 	}
 
 	tagMatcher := NewTagMatcher(tagBlackList, siteTags)
 
-	matchedTags := []string{}
+	matchedTags := []cmsModel.Tag{}
 
 	// find matched tags
-	keywords, err := p.aiAssist.FindKeyWords(ctx, []byte(artContent))
+	keywords, err := p.aiAssist.FindKeyWords(ctx, aiAssistModel.Language(site.Language), []byte(artContent))
 	if err != nil {
-		return fmt.Errorf("updateArticleTagZblog: %w", err)
+		return fmt.Errorf("updateArticleTag: %w", err)
 	}
 
 	for _, keyword := range keywords.KeyWords {
@@ -571,120 +714,84 @@ func (p *PublishManager) updateArticleTagZblog(ctx context.Context, artContent s
 			continue
 		}
 
-		isMatch, matchTag := tagMatcher.IsTagInSite(keyword)
-		if isMatch {
-			keyword = matchTag.GetName()
+		if isMatch, matchTag := tagMatcher.IsTagInSite(keyword); isMatch {
+			matchedTags = append(matchedTags, cmsModel.Tag{ID: matchTag.GetID(), Name: matchTag.GetName(), Count: matchTag.GetCount()})
 		} else {
-			newTag, err := client.PostTag(ctx, zModel.PostTagRequest{Name: keyword})
+			newTag, err := client.CreateTag(ctx, keyword)
 			if err != nil {
-				log.Printf("Error in PostTag: %v, Err msg: %v", keyword, err)
+				slog.ErrorContext(ctx, "updateArticleTag: CreateTag", logger.SiteID(site.ID), logger.ArticleID(artID), slog.String("keyword", keyword), logger.Err(err))
 
 				continue
 			}
 
-			keyword = newTag.Name
+			matchedTags = append(matchedTags, newTag)
 		}
 
-		matchedTags = append(matchedTags, keyword)
-
 		if len(matchedTags) >= maxKeyWords {
 			break
 		}
 	}
 
-	_, err = client.PostArticle(ctx, zModel.PostArticleRequest{
-		ID:  uint32(artID),
-		Tag: strings.Join(matchedTags, ","),
-	})
+	err = client.UpdateArticleTags(ctx, artID, matchedTags)
 	if err != nil {
-		return fmt.Errorf("updateArticleTagZblog: %w", err)
+		return fmt.Errorf("updateArticleTag: %w", err)
 	}
 
 	return nil
 }
 
-func (p *PublishManager) updateArticleTagWordpress(ctx context.Context, artContent string, artID int, site dbModel.Site) error {
-	client, err := p.wordpressAPI.GetClient(ctx, site.ID, site.URL, site.UserName, site.Password)
-	if err != nil {
-		return fmt.Errorf("updateArticleWordpress: %w", err)
-	}
-
-	tagBlackList, err := p.GetTagsBlockList()
-	if err != nil && !dbErr.IsNotfoundErr(err) {
-		return fmt.Errorf("updateArticleWordpress: %w", err)
-	}
-
-	siteTags, err := client.ListTagAll(ctx)
+func (p *PublishManager) PrePublish(article model.Article) error {
+	err := p.PrePublishWithStatus(article, dbModel.ArticleCacheStatusDefault)
 	if err != nil {
-		return fmt.Errorf("updateArticleWordpress: %w", err)
+		return fmt.Errorf("PrePublish: %w", err)
 	}
 
-	tagMatcher := NewTagMatcher(tagBlackList, siteTags)
-
-	matchedTags := []int{}
+	return nil
+}
 
-	// find matched tags
-	keywords, err := p.aiAssist.FindKeyWords(ctx, []byte(artContent))
-	if err != nil {
-		return fmt.Errorf("updateArticleWordpress: %w", err)
+// PrePublishWithStatus add article to cache with specific status, reserved article will not be published automatically
+func (p *PublishManager) PrePublishWithStatus(article model.Article, status dbModel.ArticleCacheStatus) error {
+	cache := dbModel.ArticleCache{
+		Title:     article.Title,
+		Content:   article.Content,
+		Status:    status,
+		Priority:  article.Priority,
+		ExpiresAt: article.ExpiresAt,
 	}
 
-	for _, keyword := range keywords.KeyWords {
-		if tagMatcher.IsTagBlackList(keyword) {
-			continue
-		}
-
-		if isMatch, matchTag := tagMatcher.IsTagInSite(keyword); isMatch {
-			matchedTags = append(matchedTags, matchTag.GetID())
-		} else {
-			newTag, err := client.CreateTag(ctx, wordpressModel.CreateTagArgs{Name: keyword})
-			if err != nil {
-				log.Printf("Error in PostTag: %v, Err msg: %v", keyword, err)
-
-				continue
-			}
-
-			matchedTags = append(matchedTags, newTag.ID)
+	// use default ttl if expire time is not specified
+	if cache.ExpiresAt == nil {
+		ttl, err := p.GetArticleCacheTTL()
+		if err != nil {
+			return fmt.Errorf("PrePublishWithStatus: %w", err)
 		}
 
-		if len(matchedTags) >= maxKeyWords {
-			break
+		if ttl > 0 {
+			expiresAt := time.Now().Add(time.Duration(ttl) * time.Hour)
+			cache.ExpiresAt = &expiresAt
 		}
 	}
 
-	_, err = client.UpdateArticle(ctx, wordpressModel.UpdateArticleArgs{
-		ID:   artID,
-		Tags: matchedTags,
-	})
+	err := p.dao.AddArticleToCache(cache)
 	if err != nil {
-		return fmt.Errorf("updateArticleWordpress: %w", err)
-	}
-
-	return nil
-}
-
-func (p *PublishManager) PrePublish(article model.Article) error {
-	cache := dbModel.ArticleCache{
-		Title:   article.Title,
-		Content: article.Content,
+		return fmt.Errorf("PrePublishWithStatus: %w", err)
 	}
 
-	err := p.dao.AddArticleToCache(cache)
-	if err != nil {
-		return fmt.Errorf("PrePublish: %w", err)
+	// reserved article is not in stock of auto publish
+	if status != dbModel.ArticleCacheStatusReserved {
+		p.recordInflow(1)
 	}
 
 	return nil
 }
 
 func (p *PublishManager) StartRandomCyclePublishZblog(ctx context.Context) error {
-	lastCategory, err := p.dao.LastPublishedCategoryByCMSType(dbModel.CMSTypeZBlog)
+	lastCategory, err := p.dao.LastPublishedCategoryByCMSTypes(p.cmsTypesOfCadence(cmsModel.PublishCadenceRandomCycle))
 	if err == nil {
-		log.Printf("last Publish time %s in StartRandomCyclePublishZblog", lastCategory.LastPublished)
-		log.Printf("time now %s in StartRandomCyclePublishZblog", time.Now())
+		slog.InfoContext(ctx, "StartRandomCyclePublishZblog", slog.Time("last_published", lastCategory.LastPublished))
 
 		if time.Since(lastCategory.LastPublished).Minutes() > maxCycleTime {
-			log.Println("Duration is more than maxCycleTime in StartRandomCyclePublishZblog, cyclePublish forced to run")
+			slog.InfoContext(ctx, "StartRandomCyclePublishZblog: duration is more than maxCycleTime, cyclePublish forced to run")
 
 			err = p.CyclePublishZblog(ctx)
 			if err != nil {
@@ -701,12 +808,12 @@ func (p *PublishManager) StartRandomCyclePublishZblog(ctx context.Context) error
 
 			multi, err := p.multiOfArticleCount()
 			if err != nil {
-				log.Println("Error in multiOfArticleCount:", err)
+				slog.ErrorContext(ctx, "multiOfArticleCount", logger.Err(err))
 			} else if multi > 0 {
 				nextTime = nextTime / time.Duration(multi)
 			}
 
-			log.Printf("next time run cyclePublish is %s in StartRandomCyclePublishZblog", time.Now().Add(nextTime))
+			slog.InfoContext(ctx, "StartRandomCyclePublishZblog: next cyclePublish", slog.Time("next_time", time.Now().Add(nextTime)))
 			select {
 			case <-ctx.Done():
 				// Exit the loop if the context is cancelled
@@ -714,7 +821,7 @@ func (p *PublishManager) StartRandomCyclePublishZblog(ctx context.Context) error
 			case <-time.After(nextTime):
 				// Proceed with the publishing cycle after a random duration
 				if err := p.CyclePublishZblog(ctx); err != nil {
-					log.Println("Error during CyclePublishZblog:", err)
+					slog.ErrorContext(ctx, "CyclePublishZblog", logger.Err(err))
 				}
 			}
 		}
@@ -734,17 +841,12 @@ func (p *PublishManager) multiOfArticleCount() (int, error) {
 		return 0, fmt.Errorf("multiOfArticleCount: %w", err)
 	}
 
-	// expected count, preserver 200 articles
+	// expected count, preserver 200 articles.
+	// The allowance of site is the daily demand of its cadence rounded up, it keep +1 for zblog (0.83)
+	// and +5 for wordpress (4.5) as before, and give the other CMS the allowance of their cadence
 	expectedCount := 200
 	for _, site := range sites {
-		expectedCount += site.LackCount
-		if site.CmsType == dbModel.CMSTypeZBlog {
-			expectedCount += 1
-		} else if site.CmsType == dbModel.CMSTypeWordPress {
-			expectedCount += 5
-		} else {
-			return 0, fmt.Errorf("multiOfArticleCount: %w", errors.New("cms type not support"))
-		}
+		expectedCount += site.LackCount + int(math.Ceil(p.ExpectedDailyDemand(site)))
 	}
 
 	// if count is less than expectedCount, return 0
@@ -759,18 +861,15 @@ func (p *PublishManager) multiOfArticleCount() (int, error) {
 }
 
 func (p *PublishManager) CyclePublishZblog(ctx context.Context) error {
-	p.publishLock.Lock()
-	defer p.publishLock.Unlock()
-
 	return p.cyclePublishZblog(ctx)
 }
 
 func (p *PublishManager) cyclePublishZblog(ctx context.Context) error {
-	log.Println("cyclePublish running...")
+	slog.InfoContext(ctx, "cyclePublishZblog running")
 
-	sites, err := p.dao.ListSitesByCMSType(dbModel.CMSTypeZBlog)
+	sites, err := p.listSitesOfCadence(cmsModel.PublishCadenceRandomCycle)
 	if err != nil {
-		return fmt.Errorf("cyclePublish: %w", err)
+		return fmt.Errorf("cyclePublishZblog: %w", err)
 	}
 
 	for _, site := range sites {
@@ -780,7 +879,7 @@ func (p *PublishManager) cyclePublishZblog(ctx context.Context) error {
 
 		lackCount := randomNum()
 		if lackCount > 0 {
-			log.Printf("site id %s, lack count %d in cyclePublishZblog", site.ID, lackCount)
+			slog.InfoContext(ctx, "cyclePublishZblog", logger.SiteID(site.ID), slog.Any("lack_count", lackCount))
 
 			err := p.dao.IncreaseLackCount(site.ID.String(), int(lackCount))
 			if err != nil {
@@ -807,7 +906,7 @@ func (p *PublishManager) StartRandomCyclePublishWordPress(ctx context.Context) e
 		for {
 			multi, err := p.multiOfArticleCount()
 			if err != nil {
-				log.Println("Error in multiOfArticleCount:", err)
+				slog.ErrorContext(ctx, "multiOfArticleCount", logger.Err(err))
 			}
 
 			if multi <= 0 {
@@ -827,7 +926,7 @@ func (p *PublishManager) StartRandomCyclePublishWordPress(ctx context.Context) e
 				return timeArr[i].Before(timeArr[j])
 			})
 
-			log.Printf("timeArr is %v in StartRandomCyclePublishWordPress", timeArr)
+			slog.InfoContext(ctx, "StartRandomCyclePublishWordPress", slog.Any("time_points", timeArr))
 			select {
 			case <-ctx.Done():
 				return
@@ -835,7 +934,7 @@ func (p *PublishManager) StartRandomCyclePublishWordPress(ctx context.Context) e
 				timeArrSchedule(ctx, timeArr, func() {
 					err := p.CyclePublishWordPress(ctx)
 					if err != nil {
-						log.Println("Error during CyclePublishWordPress:", err)
+						slog.ErrorContext(ctx, "CyclePublishWordPress", logger.Err(err))
 					}
 				})
 			}
@@ -846,16 +945,13 @@ func (p *PublishManager) StartRandomCyclePublishWordPress(ctx context.Context) e
 }
 
 func (p *PublishManager) CyclePublishWordPress(ctx context.Context) error {
-	p.publishLock.Lock()
-	defer p.publishLock.Unlock()
-
 	return p.cyclePublishWordPress(ctx)
 }
 
 func (p *PublishManager) cyclePublishWordPress(ctx context.Context) error {
-	log.Println("cyclePublishWordPress running...")
+	slog.InfoContext(ctx, "cyclePublishWordPress running")
 
-	sites, err := p.dao.ListSitesByCMSType(dbModel.CMSTypeWordPress)
+	sites, err := p.listSitesOfCadence(cmsModel.PublishCadenceTimePoints)
 	if err != nil {
 		return fmt.Errorf("cyclePublishWordPress: %w", err)
 	}
@@ -865,7 +961,7 @@ func (p *PublishManager) cyclePublishWordPress(ctx context.Context) error {
 			continue
 		}
 
-		log.Printf("site id %s, lack count %d in cyclePublishWordPress", site.ID, 1)
+		slog.InfoContext(ctx, "cyclePublishWordPress", logger.SiteID(site.ID), slog.Int("lack_count", 1))
 
 		err := p.dao.IncreaseLackCount(site.ID.String(), 1)
 		if err != nil {
@@ -881,6 +977,52 @@ func (p *PublishManager) cyclePublishWordPress(ctx context.Context) error {
 	return nil
 }
 
+// ExpectedDailyDemand return the expected number of articles the site consumes per day
+// by the random publish cycles, without the speed up of multiOfArticleCount.
+// It is zero if the CMS type of site is not supported.
+func (p *PublishManager) ExpectedDailyDemand(site dbModel.Site) float64 {
+	driver, err := p.cmsDrivers.Driver(site.CmsType)
+	if err != nil {
+		return 0
+	}
+
+	return driver.ExpectedDailyDemand()
+}
+
+// cmsTypesOfCadence return the CMS types published by the random publish cycle of cadence
+func (p *PublishManager) cmsTypesOfCadence(cadence cmsModel.PublishCadence) []dbModel.CMSType {
+	res := []dbModel.CMSType{}
+
+	for _, cmsType := range p.cmsDrivers.CMSTypes() {
+		driver, err := p.cmsDrivers.Driver(cmsType)
+		if err != nil {
+			continue
+		}
+
+		if driver.PublishCadence() == cadence {
+			res = append(res, cmsType)
+		}
+	}
+
+	return res
+}
+
+// listSitesOfCadence list the sites published by the random publish cycle of cadence
+func (p *PublishManager) listSitesOfCadence(cadence cmsModel.PublishCadence) ([]dbModel.Site, error) {
+	sites := []dbModel.Site{}
+
+	for _, cmsType := range p.cmsTypesOfCadence(cadence) {
+		cmsSites, err := p.dao.ListSitesByCMSType(cmsType)
+		if err != nil {
+			return nil, fmt.Errorf("listSitesOfCadence: %w", err)
+		}
+
+		sites = append(sites, cmsSites...)
+	}
+
+	return sites, nil
+}
+
 func (p *PublishManager) StartPublishByLack(ctx context.Context) {
 	go func() {
 		for {
@@ -891,7 +1033,7 @@ func (p *PublishManager) StartPublishByLack(ctx context.Context) {
 			case <-time.After(time.Minute * 5):
 				// Proceed with the publishing cycle after a random duration
 				if err := p.PublishByLack(ctx); err != nil {
-					log.Println("Error during publishByLack:", err)
+					slog.ErrorContext(ctx, "PublishByLack", logger.Err(err))
 				}
 			}
 		}
@@ -901,14 +1043,11 @@ func (p *PublishManager) StartPublishByLack(ctx context.Context) {
 }
 
 func (p *PublishManager) PublishByLack(ctx context.Context) error {
-	if ok := p.publishLock.TryLock(); !ok {
-		return nil
-	}
-	defer p.publishLock.Unlock()
-
 	return p.publishByLack(ctx)
 }
 
+// publishByLack publish the cached articles to the sites lacking articles,
+// different sites are published in parallel, and it is safe to run concurrently
 func (p *PublishManager) publishByLack(ctx context.Context) error {
 	// get total lack count
 	totalLackCount, err := p.dao.SumLackCount()
@@ -916,51 +1055,146 @@ func (p *PublishManager) publishByLack(ctx context.Context) error {
 		return fmt.Errorf("publishByLack: %w", err)
 	}
 
-	articles, err := p.dao.ListReadyToPublishArticleCacheByLimit(totalLackCount)
+	articles, inFlight, err := p.claimArticles(totalLackCount)
 	if err != nil {
 		return fmt.Errorf("publishByLack: %w", err)
 	}
+	defer p.unclaimArticles(articles)
 
-	articleIDs := []string{}
-	for _, article := range articles {
-		articleIDs = append(articleIDs, article.ID.String())
-	}
+	p.checkCacheLow(len(articles)+inFlight, totalLackCount)
 
-	err = p.dao.UpdateArticleCacheStatusByIDs(articleIDs, dbModel.ArticleCacheStatusInBuffer)
-	if err != nil {
-		return fmt.Errorf("publishByLack: %w", err)
+	if len(articles) == 0 {
+		return nil
 	}
 
-	for _, article := range articles {
-		err := p.AveragePublish(ctx, model.Article{Title: article.Title, Content: article.Content})
-		if err != nil {
-			log.Printf("Error in AveragePublish: %v", err)
-
-			var pErr PublishErr
-			if errors.As(err, &pErr) {
-				// mark published, if error is PublishErr
-				// avoid publish to the same category
-				// usually caused by the site is down or the domain is expired
-				err = p.dao.MarkPublished(pErr.CateID.String())
+	var (
+		errs    error
+		errLock sync.Mutex
+		wg      sync.WaitGroup
+	)
+
+	jobs := make(chan dbModel.ArticleCache)
+	workers := min(cap(p.leases.slots), len(articles))
+
+	wg.Add(workers)
+
+	for range workers {
+		go func() {
+			defer wg.Done()
+
+			// the error of an article does not stop publishing the others
+			for article := range jobs {
+				err := p.publishCachedArticle(ctx, article)
 				if err != nil {
-					return fmt.Errorf("publishByLack: %w", err)
+					errLock.Lock()
+					errs = errors.Join(errs, fmt.Errorf("article cache id %s: %w", article.ID, err))
+					errLock.Unlock()
 				}
-			} else {
-				return fmt.Errorf("publishByLack: %w", err)
 			}
+		}()
+	}
 
-			continue
+DispatchLoop:
+	for _, article := range articles {
+		select {
+		case jobs <- article:
+		case <-ctx.Done():
+			errLock.Lock()
+			errs = errors.Join(errs, ctx.Err())
+			errLock.Unlock()
+
+			break DispatchLoop
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	if errs != nil {
+		return fmt.Errorf("publishByLack: %w", errs)
+	}
+
+	return nil
+}
+
+// publishCachedArticle publish the article and delete it from cache
+func (p *PublishManager) publishCachedArticle(ctx context.Context, article dbModel.ArticleCache) error {
+	err := p.AveragePublish(ctx, model.Article{Title: article.Title, Content: article.Content})
+	if err != nil {
+		slog.ErrorContext(ctx, "publishCachedArticle: AveragePublish", slog.String("article_cache_id", article.ID.String()), logger.Err(err))
+
+		var pErr PublishErr
+		if !errors.As(err, &pErr) || ctx.Err() != nil {
+			return fmt.Errorf("publishCachedArticle: %w", err)
 		}
 
-		err = p.dao.DeleteArticleCacheByIDs([]string{article.ID.String()})
+		// mark published, if error is PublishErr
+		// avoid publish to the same category
+		// usually caused by the site is down or the domain is expired
+		err = p.dao.MarkPublished(pErr.CateID.String())
 		if err != nil {
-			return fmt.Errorf("publishByLack: %w", err)
+			return fmt.Errorf("publishCachedArticle: %w", err)
 		}
+
+		return nil
+	}
+
+	err = p.dao.DeleteArticleCacheByIDs([]string{article.ID.String()})
+	if err != nil {
+		return fmt.Errorf("publishCachedArticle: %w", err)
 	}
 
+	p.recordOutflow(1)
+
 	return nil
 }
 
+// claimArticles claim the articles in database to fill the lack not covered by in-flight articles,
+// so that concurrent publishByLack, also the ones of other instances, do not publish the same article.
+// return the claimed articles and the number of articles claimed before
+func (p *PublishManager) claimArticles(totalLackCount int) ([]dbModel.ArticleCache, int, error) {
+	now := time.Now()
+
+	claimed, err := p.dao.CountClaimedArticleCache(now)
+	if err != nil {
+		return nil, 0, fmt.Errorf("claimArticles: %w", err)
+	}
+
+	inFlight := int(claimed)
+
+	need := totalLackCount - inFlight
+	if need <= 0 {
+		return nil, inFlight, nil
+	}
+
+	articles, err := p.dao.ClaimReadyToPublishArticleCache(need, p.instanceID, now, now.Add(p.claimTTL))
+	if err != nil {
+		// release the articles claimed before error
+		p.unclaimArticles(articles)
+
+		return nil, inFlight, fmt.Errorf("claimArticles: %w", err)
+	}
+
+	return articles, inFlight, nil
+}
+
+// unclaimArticles release the claims, the published articles are deleted already
+func (p *PublishManager) unclaimArticles(articles []dbModel.ArticleCache) {
+	if len(articles) == 0 {
+		return
+	}
+
+	IDs := make([]string, 0, len(articles))
+	for _, article := range articles {
+		IDs = append(IDs, article.ID.String())
+	}
+
+	err := p.dao.ReleaseArticleCacheClaim(IDs, p.instanceID)
+	if err != nil {
+		slog.Error("unclaimArticles", logger.Err(err))
+	}
+}
+
 func (p *PublishManager) CountArticleCache() (int64, error) {
 	return p.dao.CountArticleCache()
 }
@@ -991,6 +1225,20 @@ func (p *PublishManager) GetTagsBlockList() ([]string, error) {
 	return strings.Split(res.Value, ","), nil
 }
 
+// SetArticleCacheTTL set the default ttl hours of article cache, 0 means never expire
+func (p *PublishManager) SetArticleCacheTTL(hours int) error {
+	return p.dao.UpsertByKeyInt(ArticleCacheTTL, hours)
+}
+
+func (p *PublishManager) GetArticleCacheTTL() (int, error) {
+	hours, err := p.dao.GetIntByKeyWithDefault(ArticleCacheTTL, 0)
+	if err != nil {
+		return 0, fmt.Errorf("GetArticleCacheTTL: %w", err)
+	}
+
+	return hours, nil
+}
+
 func (p *PublishManager) StopAutoPublish() error {
 	return p.dao.UpsertByKeyBool(IsStopAutoPublish, true)
 }
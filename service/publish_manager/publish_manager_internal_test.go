@@ -0,0 +1,70 @@
+package publishmanager
+
+import (
+	"testing"
+
+	cmsdriver "github.com/ray31245/seo_cluster/pkg/cms_driver"
+	dbInterface "github.com/ray31245/seo_cluster/pkg/db/db_interface"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeArticleCount struct {
+	dbInterface.ArticleCacheDAOInterface
+	count int64
+}
+
+func (f fakeArticleCount) CountArticleCache() (int64, error) {
+	return f.count, nil
+}
+
+type fakeSites struct {
+	dbInterface.SiteDAOInterface
+	sites []dbModel.Site
+}
+
+func (f fakeSites) ListSites() ([]dbModel.Site, error) {
+	return f.sites, nil
+}
+
+func TestPublishManager_multiOfArticleCount(t *testing.T) {
+	t.Parallel()
+
+	cmsDrivers := cmsdriver.NewRegistry(
+		cmsdriver.NewZBlogDriver(nil),
+		cmsdriver.NewWordpressDriver(nil),
+		cmsdriver.NewGhostDriver(nil),
+	)
+	sites := fakeSites{sites: []dbModel.Site{
+		{CmsType: dbModel.CMSTypeZBlog, LackCount: 2},
+		{CmsType: dbModel.CMSTypeWordPress, LackCount: 3},
+		{CmsType: dbModel.CMSTypeGhost},
+		// CMS type not supported has no allowance
+		{CmsType: dbModel.CMSTypeMetaWeblog},
+	}}
+	// 200 preserved + zblog 2+1 + wordpress 3+5 + ghost 0+1
+	expectedCount := int64(212)
+
+	tests := []struct {
+		name  string
+		count int64
+		want  int
+	}{
+		{name: "not more than expected", count: expectedCount, want: 0},
+		{name: "more than expected", count: expectedCount + 1, want: 1},
+		{name: "multiple of expected", count: expectedCount * 3, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewPublishManager(cmsDrivers, DAO{ArticleCacheDAOInterface: fakeArticleCount{count: tt.count}, SiteDAOInterface: sites}, nil)
+
+			multi, err := p.multiOfArticleCount()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, multi)
+		})
+	}
+}
@@ -6,7 +6,7 @@ import (
 
 type tagInterface interface {
 	GetName() string
-	GetID() int
+	GetID() string
 	GetCount() int
 }
 
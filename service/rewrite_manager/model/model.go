@@ -0,0 +1,6 @@
+package model
+
+type RewriteResult struct {
+	Title   string
+	Content string
+}
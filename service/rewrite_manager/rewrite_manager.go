@@ -3,12 +3,15 @@ package rewritemanager
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	aiAssistInterface "github.com/ray31245/seo_cluster/pkg/ai_assist/ai_assist_interface"
 	aiAssistModel "github.com/ray31245/seo_cluster/pkg/ai_assist/model"
 	dbInterface "github.com/ray31245/seo_cluster/pkg/db/db_interface"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	webhookModel "github.com/ray31245/seo_cluster/service/webhook_manager/model"
+	webhookInterface "github.com/ray31245/seo_cluster/service/webhook_manager/webhook_interface"
 )
 
 const (
@@ -20,18 +23,38 @@ type RewriteManager struct {
 	aiAssist        aiAssistInterface.AIAssistInterface
 	configDAO       dbInterface.KVConfigDAOInterface
 	rewriteTestCase dbInterface.RewriteTestCaseDAOInterface
+	// siteRewriteProfile profiles override the default prompts for each site
+	siteRewriteProfile dbInterface.SiteRewriteProfileDAOInterface
+	notifier           webhookInterface.Notifier
 }
 
-func NewRewriteManager(aiAssist aiAssistInterface.AIAssistInterface, configDAO dbInterface.KVConfigDAOInterface, rewriteTestCaseDAO dbInterface.RewriteTestCaseDAOInterface) *RewriteManager {
+func NewRewriteManager(aiAssist aiAssistInterface.AIAssistInterface, configDAO dbInterface.KVConfigDAOInterface, rewriteTestCaseDAO dbInterface.RewriteTestCaseDAOInterface, siteRewriteProfileDAO dbInterface.SiteRewriteProfileDAOInterface) *RewriteManager {
 	return &RewriteManager{
-		aiAssist:        aiAssist,
-		configDAO:       configDAO,
-		rewriteTestCase: rewriteTestCaseDAO,
+		aiAssist:           aiAssist,
+		configDAO:          configDAO,
+		rewriteTestCase:    rewriteTestCaseDAO,
+		siteRewriteProfile: siteRewriteProfileDAO,
 	}
 }
 
-func (r *RewriteManager) Rewrite(ctx context.Context, text []byte) (aiAssistModel.RewriteResponse, error) {
-	res, err := r.aiAssist.Rewrite(ctx, text)
+// SetNotifier set the notifier to emit rewrite events, nil to disable
+func (r *RewriteManager) SetNotifier(notifier webhookInterface.Notifier) {
+	r.notifier = notifier
+}
+
+func (r *RewriteManager) notifyFailed(operation string, err error) {
+	if r.notifier == nil {
+		return
+	}
+
+	r.notifier.Notify(webhookModel.EventRewriteFailed, webhookModel.RewriteFailedData{
+		Operation: operation,
+		Error:     err.Error(),
+	})
+}
+
+func (r *RewriteManager) Rewrite(ctx context.Context, lang aiAssistModel.Language, text []byte) (aiAssistModel.RewriteResponse, error) {
+	res, err := r.aiAssist.Rewrite(ctx, lang, text)
 	if err != nil {
 		return aiAssistModel.RewriteResponse{}, fmt.Errorf("RewriteManager.Rewrite: %w", err)
 	}
@@ -39,8 +62,8 @@ func (r *RewriteManager) Rewrite(ctx context.Context, text []byte) (aiAssistMode
 	return res, nil
 }
 
-func (r *RewriteManager) ExtendRewrite(ctx context.Context, text []byte) (aiAssistModel.ExtendRewriteResponse, error) {
-	res, err := r.aiAssist.ExtendRewrite(ctx, text)
+func (r *RewriteManager) ExtendRewrite(ctx context.Context, lang aiAssistModel.Language, text []byte) (aiAssistModel.ExtendRewriteResponse, error) {
+	res, err := r.aiAssist.ExtendRewrite(ctx, lang, text)
 	if err != nil {
 		return aiAssistModel.ExtendRewriteResponse{}, fmt.Errorf("RewriteManager.ExtendRewrite: %w", err)
 	}
@@ -58,31 +81,11 @@ func (r *RewriteManager) CustomRewrite(ctx context.Context, systemPrompt string,
 }
 
 func (r *RewriteManager) DefaultRewrite(ctx context.Context, text []byte) (string, error) {
-	systemPrompt, err := r.GetDefaultSystemPrompt()
-	if err != nil {
-		return "", fmt.Errorf("RewriteManager.DefaultRewrite: %w", err)
-	}
-
-	prompt, err := r.GetDefaultPrompt()
-	if err != nil {
-		return "", fmt.Errorf("RewriteManager.DefaultRewrite: %w", err)
-	}
-
-	return r.CustomRewrite(ctx, systemPrompt, prompt, text)
+	return r.SiteRewrite(ctx, "", text)
 }
 
 func (r *RewriteManager) DefaultExtendRewrite(ctx context.Context, text []byte) (string, error) {
-	systemPrompt, err := r.GetDefaultExtendSystemPrompt()
-	if err != nil {
-		return "", fmt.Errorf("RewriteManager.DefaultExtendRewrite: %w", err)
-	}
-
-	prompt, err := r.GetDefaultPrompt()
-	if err != nil {
-		return "", fmt.Errorf("RewriteManager.DefaultExtendRewrite: %w", err)
-	}
-
-	return r.aiAssist.CustomRewrite(ctx, systemPrompt, prompt, text)
+	return r.SiteExtendRewrite(ctx, "", text)
 }
 
 func (r *RewriteManager) MultiSectionsRewrite(ctx context.Context, systemPrompt string, content string) (string, error) {
@@ -95,68 +98,57 @@ func (r *RewriteManager) MultiSectionsRewrite(ctx context.Context, systemPrompt
 }
 
 func (r *RewriteManager) DefaultMultiSectionsRewrite(ctx context.Context, content string) (string, error) {
-	systemPrompt, err := r.GetDefaultMultiSectionsSystemPrompt()
-	if err != nil {
-		return "", fmt.Errorf("RewriteManager.DefaultMultiSectionsRewrite: %w", err)
-	}
-
-	return r.MultiSectionsRewrite(ctx, systemPrompt, content)
+	return r.SiteMultiSectionsRewrite(ctx, "", content)
 }
 
 func (r *RewriteManager) DefaultMakeTitle(ctx context.Context, content string) (string, error) {
-	systemPrompt, err := r.GetDefaultMakeTitleSystemPrompt()
-	if err != nil {
-		return "", fmt.Errorf("RewriteManager.DefaultMakeTitle: %w", err)
-	}
-
-	prompt, err := r.GetDefaultMakeTitlePrompt()
-	if err != nil {
-		return "", fmt.Errorf("RewriteManager.DefaultMakeTitle: %w", err)
-	}
-
-	return r.aiAssist.MakeTitle(ctx, systemPrompt, prompt, []byte(content))
+	return r.SiteMakeTitle(ctx, "", content)
 }
 
-func (r *RewriteManager) RewriteUntil(ctx context.Context, text []byte) (res aiAssistModel.RewriteResponse, err error) {
-	log.Println("rewriting...")
+func (r *RewriteManager) RewriteUntil(ctx context.Context, lang aiAssistModel.Language, text []byte) (res aiAssistModel.RewriteResponse, err error) {
+	slog.InfoContext(ctx, "RewriteUntil")
 
 	r.aiAssist.Lock()
 	defer r.aiAssist.Unlock()
 
 	for range retryLimit {
-		res, err = r.aiAssist.Rewrite(ctx, text)
+		res, err = r.aiAssist.Rewrite(ctx, lang, text)
 		if err == nil {
 			return res, nil
 		}
 
-		log.Println("retrying...")
+		slog.WarnContext(ctx, "RewriteUntil: retrying", logger.Err(err))
 		<-time.After(retryDelay)
 	}
 
+	r.notifyFailed("RewriteUntil", err)
+
 	return aiAssistModel.RewriteResponse{}, fmt.Errorf("RewriteManager.RewriteUntil: %w", err)
 }
 
-func (r *RewriteManager) ExtendRewriteUntil(ctx context.Context, text []byte) (res aiAssistModel.ExtendRewriteResponse, err error) {
-	log.Println("extending rewriting...")
+func (r *RewriteManager) ExtendRewriteUntil(ctx context.Context, lang aiAssistModel.Language, text []byte) (res aiAssistModel.ExtendRewriteResponse, err error) {
+	slog.InfoContext(ctx, "ExtendRewriteUntil")
 
 	r.aiAssist.Lock()
 	defer r.aiAssist.Unlock()
 
 	for range retryLimit {
-		res, err = r.aiAssist.ExtendRewrite(ctx, text)
+		res, err = r.aiAssist.ExtendRewrite(ctx, lang, text)
 		if err == nil {
 			return res, nil
 		}
 
-		log.Println("retrying...")
+		slog.WarnContext(ctx, "ExtendRewriteUntil: retrying", logger.Err(err))
 		<-time.After(retryDelay)
 	}
 
+	r.notifyFailed("ExtendRewriteUntil", err)
+
 	return aiAssistModel.ExtendRewriteResponse{}, fmt.Errorf("RewriteManager.ExtendRewriteUntil: %w", err)
 }
 
 func (r *RewriteManager) CustomRewriteUntil(ctx context.Context, systemPrompt string, prompt string, content []byte) (res string, err error) {
-	log.Println("custom rewriting...")
+	slog.InfoContext(ctx, "CustomRewriteUntil")
 
 	r.aiAssist.Lock()
 	defer r.aiAssist.Unlock()
@@ -167,15 +159,17 @@ func (r *RewriteManager) CustomRewriteUntil(ctx context.Context, systemPrompt st
 			return res, nil
 		}
 
-		log.Println("retrying...")
+		slog.WarnContext(ctx, "CustomRewriteUntil: retrying", logger.Err(err))
 		<-time.After(retryDelay)
 	}
 
+	r.notifyFailed("CustomRewriteUntil", err)
+
 	return "", fmt.Errorf("RewriteManager.CustomRewriteUntil: %w", err)
 }
 
 func (r *RewriteManager) DefaultRewriteUntil(ctx context.Context, text []byte) (res string, err error) {
-	log.Println("default rewriting...")
+	slog.InfoContext(ctx, "DefaultRewriteUntil")
 
 	r.aiAssist.Lock()
 	defer r.aiAssist.Unlock()
@@ -186,15 +180,17 @@ func (r *RewriteManager) DefaultRewriteUntil(ctx context.Context, text []byte) (
 			return res, nil
 		}
 
-		log.Println("retrying...")
+		slog.WarnContext(ctx, "DefaultRewriteUntil: retrying", logger.Err(err))
 		<-time.After(retryDelay)
 	}
 
+	r.notifyFailed("DefaultRewriteUntil", err)
+
 	return "", fmt.Errorf("RewriteManager.DefaultRewriteUntil: %w", err)
 }
 
 func (r *RewriteManager) DefaultExtendRewriteUntil(ctx context.Context, text []byte) (res string, err error) {
-	log.Println("default extending rewriting...")
+	slog.InfoContext(ctx, "DefaultExtendRewriteUntil")
 
 	r.aiAssist.Lock()
 	defer r.aiAssist.Unlock()
@@ -205,15 +201,17 @@ func (r *RewriteManager) DefaultExtendRewriteUntil(ctx context.Context, text []b
 			return res, nil
 		}
 
-		log.Println("retrying...")
+		slog.WarnContext(ctx, "DefaultExtendRewriteUntil: retrying", logger.Err(err))
 		<-time.After(retryDelay)
 	}
 
+	r.notifyFailed("DefaultExtendRewriteUntil", err)
+
 	return "", fmt.Errorf("RewriteManager.DefaultExtendRewriteUntil: %w", err)
 }
 
 func (r *RewriteManager) MultiSectionsRewriteUntil(ctx context.Context, systemPrompt string, content string) (res string, err error) {
-	log.Println("multi sections rewriting...")
+	slog.InfoContext(ctx, "MultiSectionsRewriteUntil")
 
 	r.aiAssist.Lock()
 	defer r.aiAssist.Unlock()
@@ -224,15 +222,17 @@ func (r *RewriteManager) MultiSectionsRewriteUntil(ctx context.Context, systemPr
 			return res, nil
 		}
 
-		log.Println("retrying...")
+		slog.WarnContext(ctx, "MultiSectionsRewriteUntil: retrying", logger.Err(err))
 		<-time.After(retryDelay)
 	}
 
+	r.notifyFailed("MultiSectionsRewriteUntil", err)
+
 	return "", fmt.Errorf("RewriteManager.MultiSectionsRewriteUntil: %w", err)
 }
 
 func (r *RewriteManager) DefaultMakeTitleUntil(ctx context.Context, content string) (res string, err error) {
-	log.Println("default making title...")
+	slog.InfoContext(ctx, "DefaultMakeTitleUntil")
 
 	r.aiAssist.Lock()
 	defer r.aiAssist.Unlock()
@@ -243,9 +243,11 @@ func (r *RewriteManager) DefaultMakeTitleUntil(ctx context.Context, content stri
 			return res, nil
 		}
 
-		log.Println("retrying...")
+		slog.WarnContext(ctx, "DefaultMakeTitleUntil: retrying", logger.Err(err))
 		<-time.After(retryDelay)
 	}
 
+	r.notifyFailed("DefaultMakeTitleUntil", err)
+
 	return "", fmt.Errorf("RewriteManager.DefaultMakeTitleUntil: %w", err)
 }
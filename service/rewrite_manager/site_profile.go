@@ -0,0 +1,250 @@
+package rewritemanager
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	"github.com/ray31245/seo_cluster/service/rewrite_manager/model"
+)
+
+func (r *RewriteManager) GetSiteRewriteProfile(siteID string) (*dbModel.SiteRewriteProfile, error) {
+	profile, err := r.siteRewriteProfile.GetSiteRewriteProfile(siteID)
+	if err != nil {
+		return nil, fmt.Errorf("RewriteManager.GetSiteRewriteProfile: %w", err)
+	}
+
+	return profile, nil
+}
+
+func (r *RewriteManager) UpsertSiteRewriteProfile(profile dbModel.SiteRewriteProfile) error {
+	if profile.TargetLength < 0 {
+		profile.TargetLength = 0
+	}
+
+	err := r.siteRewriteProfile.UpsertSiteRewriteProfile(&profile)
+	if err != nil {
+		return fmt.Errorf("RewriteManager.UpsertSiteRewriteProfile: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RewriteManager) DeleteSiteRewriteProfile(siteID string) error {
+	err := r.siteRewriteProfile.DeleteSiteRewriteProfile(siteID)
+	if err != nil {
+		return fmt.Errorf("RewriteManager.DeleteSiteRewriteProfile: %w", err)
+	}
+
+	return nil
+}
+
+// siteProfile return the profile of site, empty profile if siteID is empty or site has no profile
+func (r *RewriteManager) siteProfile(siteID string) (dbModel.SiteRewriteProfile, error) {
+	if siteID == "" || r.siteRewriteProfile == nil {
+		return dbModel.SiteRewriteProfile{}, nil
+	}
+
+	profile, err := r.siteRewriteProfile.GetSiteRewriteProfile(siteID)
+	if dbErr.IsNotfoundErr(err) {
+		return dbModel.SiteRewriteProfile{}, nil
+	} else if err != nil {
+		return dbModel.SiteRewriteProfile{}, fmt.Errorf("siteProfile: %w", err)
+	}
+
+	return *profile, nil
+}
+
+// orDefault return value if it is not empty, otherwise the default one
+func orDefault(value string, getDefault func() (string, error)) (string, error) {
+	if value != "" {
+		return value, nil
+	}
+
+	return getDefault()
+}
+
+// withStyle append the tone and length requirement of profile to prompt
+func withStyle(prompt string, profile dbModel.SiteRewriteProfile) string {
+	requirements := []string{prompt}
+
+	if profile.Tone != "" {
+		requirements = append(requirements, fmt.Sprintf("Tone: %s", profile.Tone))
+	}
+
+	if profile.TargetLength > 0 {
+		requirements = append(requirements, fmt.Sprintf("Length: about %d characters", profile.TargetLength))
+	}
+
+	return strings.Join(requirements, "\n")
+}
+
+// SiteRewrite rewrite with the profile of site, siteID empty means the default prompts
+func (r *RewriteManager) SiteRewrite(ctx context.Context, siteID string, text []byte) (string, error) {
+	profile, err := r.siteProfile(siteID)
+	if err != nil {
+		return "", fmt.Errorf("RewriteManager.SiteRewrite: %w", err)
+	}
+
+	systemPrompt, err := orDefault(profile.SystemPrompt, r.GetDefaultSystemPrompt)
+	if err != nil {
+		return "", fmt.Errorf("RewriteManager.SiteRewrite: %w", err)
+	}
+
+	prompt, err := orDefault(profile.Prompt, r.GetDefaultPrompt)
+	if err != nil {
+		return "", fmt.Errorf("RewriteManager.SiteRewrite: %w", err)
+	}
+
+	return r.CustomRewrite(ctx, systemPrompt, withStyle(prompt, profile), text)
+}
+
+// SiteExtendRewrite extend rewrite with the profile of site, siteID empty means the default prompts
+func (r *RewriteManager) SiteExtendRewrite(ctx context.Context, siteID string, text []byte) (string, error) {
+	profile, err := r.siteProfile(siteID)
+	if err != nil {
+		return "", fmt.Errorf("RewriteManager.SiteExtendRewrite: %w", err)
+	}
+
+	systemPrompt, err := orDefault(profile.ExtendSystemPrompt, r.GetDefaultExtendSystemPrompt)
+	if err != nil {
+		return "", fmt.Errorf("RewriteManager.SiteExtendRewrite: %w", err)
+	}
+
+	// default extend rewrite share the prompt with rewrite
+	prompt, err := orDefault(profile.ExtendPrompt, r.GetDefaultPrompt)
+	if err != nil {
+		return "", fmt.Errorf("RewriteManager.SiteExtendRewrite: %w", err)
+	}
+
+	return r.aiAssist.CustomRewrite(ctx, systemPrompt, withStyle(prompt, profile), text)
+}
+
+// SiteMakeTitle make title with the profile of site, siteID empty means the default prompts
+func (r *RewriteManager) SiteMakeTitle(ctx context.Context, siteID string, content string) (string, error) {
+	profile, err := r.siteProfile(siteID)
+	if err != nil {
+		return "", fmt.Errorf("RewriteManager.SiteMakeTitle: %w", err)
+	}
+
+	systemPrompt, err := orDefault(profile.MakeTitleSystemPrompt, r.GetDefaultMakeTitleSystemPrompt)
+	if err != nil {
+		return "", fmt.Errorf("RewriteManager.SiteMakeTitle: %w", err)
+	}
+
+	prompt, err := orDefault(profile.MakeTitlePrompt, r.GetDefaultMakeTitlePrompt)
+	if err != nil {
+		return "", fmt.Errorf("RewriteManager.SiteMakeTitle: %w", err)
+	}
+
+	return r.aiAssist.MakeTitle(ctx, systemPrompt, prompt, []byte(content))
+}
+
+// SiteMultiSectionsRewrite multi sections rewrite with the profile of site, siteID empty means the default prompts
+func (r *RewriteManager) SiteMultiSectionsRewrite(ctx context.Context, siteID string, content string) (string, error) {
+	profile, err := r.siteProfile(siteID)
+	if err != nil {
+		return "", fmt.Errorf("RewriteManager.SiteMultiSectionsRewrite: %w", err)
+	}
+
+	systemPrompt, err := orDefault(profile.MultiSectionsSystemPrompt, r.GetDefaultMultiSectionsSystemPrompt)
+	if err != nil {
+		return "", fmt.Errorf("RewriteManager.SiteMultiSectionsRewrite: %w", err)
+	}
+
+	return r.MultiSectionsRewrite(ctx, withStyle(systemPrompt, profile), content)
+}
+
+// retryUntil retry f until success or reach the retry limit, notify the failure with operation name
+func (r *RewriteManager) retryUntil(ctx context.Context, operation string, f func() (string, error)) (res string, err error) {
+	r.aiAssist.Lock()
+	defer r.aiAssist.Unlock()
+
+	for range retryLimit {
+		res, err = f()
+		if err == nil {
+			return res, nil
+		}
+
+		slog.WarnContext(ctx, operation+": retrying", logger.Err(err))
+		<-time.After(retryDelay)
+	}
+
+	r.notifyFailed(operation, err)
+
+	return "", fmt.Errorf("RewriteManager.%s: %w", operation, err)
+}
+
+func (r *RewriteManager) SiteRewriteUntil(ctx context.Context, siteID string, text []byte) (string, error) {
+	ctx = logger.WithAttrs(ctx, logger.SiteID(siteID))
+	slog.InfoContext(ctx, "SiteRewriteUntil")
+
+	return r.retryUntil(ctx, "SiteRewriteUntil", func() (string, error) {
+		return r.SiteRewrite(ctx, siteID, text)
+	})
+}
+
+func (r *RewriteManager) SiteExtendRewriteUntil(ctx context.Context, siteID string, text []byte) (string, error) {
+	ctx = logger.WithAttrs(ctx, logger.SiteID(siteID))
+	slog.InfoContext(ctx, "SiteExtendRewriteUntil")
+
+	return r.retryUntil(ctx, "SiteExtendRewriteUntil", func() (string, error) {
+		return r.SiteExtendRewrite(ctx, siteID, text)
+	})
+}
+
+func (r *RewriteManager) SiteMakeTitleUntil(ctx context.Context, siteID string, content string) (string, error) {
+	ctx = logger.WithAttrs(ctx, logger.SiteID(siteID))
+	slog.InfoContext(ctx, "SiteMakeTitleUntil")
+
+	return r.retryUntil(ctx, "SiteMakeTitleUntil", func() (string, error) {
+		return r.SiteMakeTitle(ctx, siteID, content)
+	})
+}
+
+// SiteRewriteWorkFlow run RewriteWorkFlow with the profile of site
+func (r *RewriteManager) SiteRewriteWorkFlow(ctx context.Context, siteID string, articleContent string, isGenImageList bool) (model.RewriteResult, error) {
+	rewriteF := func(req string) (string, error) {
+		return r.SiteRewriteUntil(ctx, siteID, []byte(req))
+	}
+
+	extendRewriteF := func(req string) (string, error) {
+		return r.SiteExtendRewriteUntil(ctx, siteID, []byte(req))
+	}
+
+	makeTitleF := func(req string) (string, error) {
+		return r.SiteMakeTitleUntil(ctx, siteID, req)
+	}
+
+	res, err := r.RewriteWorkFlow(articleContent, rewriteF, extendRewriteF, makeTitleF, isGenImageList)
+	if err != nil {
+		return model.RewriteResult{}, fmt.Errorf("RewriteManager.SiteRewriteWorkFlow: %w", err)
+	}
+
+	return res, nil
+}
+
+// RewriteForSite rewrite the article for the site it is about to be published to,
+// return false if the site does not rewrite on publish
+func (r *RewriteManager) RewriteForSite(ctx context.Context, siteID string, articleContent string) (model.RewriteResult, bool, error) {
+	profile, err := r.siteProfile(siteID)
+	if err != nil {
+		return model.RewriteResult{}, false, fmt.Errorf("RewriteManager.RewriteForSite: %w", err)
+	}
+
+	if !profile.IsRewriteOnPublish {
+		return model.RewriteResult{}, false, nil
+	}
+
+	res, err := r.SiteRewriteWorkFlow(ctx, siteID, articleContent, true)
+	if err != nil {
+		return model.RewriteResult{}, false, fmt.Errorf("RewriteManager.RewriteForSite: %w", err)
+	}
+
+	return res, true, nil
+}
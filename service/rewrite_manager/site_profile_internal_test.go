@@ -0,0 +1,146 @@
+package rewritemanager
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ray31245/seo_cluster/pkg/ai_assist/model"
+	"github.com/ray31245/seo_cluster/pkg/db"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAIAssist echo the prompts it received
+type mockAIAssist struct{}
+
+func (m mockAIAssist) CustomRewrite(_ context.Context, systemPrompt string, prompt string, _ []byte) (string, error) {
+	return systemPrompt + "|" + prompt, nil
+}
+
+func (m mockAIAssist) Rewrite(_ context.Context, _ model.Language, _ []byte) (model.RewriteResponse, error) {
+	return model.RewriteResponse{}, nil
+}
+
+func (m mockAIAssist) ExtendRewrite(_ context.Context, _ model.Language, _ []byte) (model.ExtendRewriteResponse, error) {
+	return model.ExtendRewriteResponse{}, nil
+}
+
+func (m mockAIAssist) MultiSectionsRewrite(_ context.Context, systemPrompt string, _ string) (string, error) {
+	return systemPrompt, nil
+}
+
+func (m mockAIAssist) Comment(_ context.Context, _ model.Language, _ []byte) (model.CommentResponse, error) {
+	return model.CommentResponse{}, nil
+}
+
+func (m mockAIAssist) FindKeyWords(_ context.Context, _ model.Language, _ []byte) (model.FindKeyWordsResponse, error) {
+	return model.FindKeyWordsResponse{}, nil
+}
+
+func (m mockAIAssist) SelectCategory(_ context.Context, _ model.SelectCategoryRequest) (model.SelectCategoryResponse, error) {
+	return model.SelectCategoryResponse{}, nil
+}
+
+func (m mockAIAssist) MakeTitle(_ context.Context, systemPrompt string, prompt string, _ []byte) (string, error) {
+	return systemPrompt + "|" + prompt, nil
+}
+
+func (m mockAIAssist) Lock() {}
+
+func (m mockAIAssist) Unlock() {}
+
+func (m mockAIAssist) TryLock() bool { return true }
+
+func newTestRewriteManager(t *testing.T) *RewriteManager {
+	t.Helper()
+
+	database, err := db.NewDB(filepath.Join(t.TempDir(), "rewrite.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	configDAO, err := database.NewKVConfigDAO()
+	require.NoError(t, err)
+
+	testCaseDAO, err := database.NewRewriteTestCaseDAO()
+	require.NoError(t, err)
+
+	siteDAO, err := database.NewSiteDAO()
+	require.NoError(t, err)
+
+	r := NewRewriteManager(mockAIAssist{}, configDAO, testCaseDAO, siteDAO)
+	require.NoError(t, r.SetDefaultSystemPrompt("default system"))
+	require.NoError(t, r.SetDefaultPrompt("default prompt"))
+	require.NoError(t, r.SetDefaultExtendSystemPrompt("default extend system"))
+	require.NoError(t, r.SetDefaultMakeTitleSystemPrompt("default title system"))
+	require.NoError(t, r.SetDefaultMakeTitlePrompt("default title prompt"))
+	require.NoError(t, r.SetDefaultMultiSectionsSystemPrompt("default sections system"))
+
+	return r
+}
+
+func TestRewriteManager_SiteProfile(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx := context.Background()
+	r := newTestRewriteManager(t)
+	siteID := uuid.New()
+
+	// site without profile use the default prompts
+	res, err := r.SiteRewrite(ctx, siteID.String(), nil)
+	require.NoError(err)
+	assert.Equal("default system|default prompt", res)
+
+	require.NoError(r.UpsertSiteRewriteProfile(dbModel.SiteRewriteProfile{
+		SiteID:       siteID,
+		SystemPrompt: "site system",
+		Tone:         "casual",
+		TargetLength: 800,
+	}))
+
+	res, err = r.SiteRewrite(ctx, siteID.String(), nil)
+	require.NoError(err)
+	assert.Equal("site system|default prompt\nTone: casual\nLength: about 800 characters", res)
+
+	res, err = r.SiteExtendRewrite(ctx, siteID.String(), nil)
+	require.NoError(err)
+	assert.True(strings.HasPrefix(res, "default extend system|default prompt\nTone: casual"))
+
+	res, err = r.SiteMakeTitle(ctx, siteID.String(), "")
+	require.NoError(err)
+	assert.Equal("default title system|default title prompt", res)
+
+	res, err = r.SiteMultiSectionsRewrite(ctx, siteID.String(), "")
+	require.NoError(err)
+	assert.True(strings.HasPrefix(res, "default sections system"))
+
+	// default functions are not affected by site profile
+	res, err = r.DefaultRewrite(ctx, nil)
+	require.NoError(err)
+	assert.Equal("default system|default prompt", res)
+
+	// upsert replace the whole profile
+	require.NoError(r.UpsertSiteRewriteProfile(dbModel.SiteRewriteProfile{SiteID: siteID, MakeTitlePrompt: "site title prompt"}))
+
+	res, err = r.SiteMakeTitle(ctx, siteID.String(), "")
+	require.NoError(err)
+	assert.Equal("default title system|site title prompt", res)
+
+	res, err = r.SiteRewrite(ctx, siteID.String(), nil)
+	require.NoError(err)
+	assert.Equal("default system|default prompt", res)
+
+	_, isRewritten, err := r.RewriteForSite(ctx, siteID.String(), "content")
+	require.NoError(err)
+	assert.False(isRewritten)
+
+	require.NoError(r.DeleteSiteRewriteProfile(siteID.String()))
+
+	_, err = r.GetSiteRewriteProfile(siteID.String())
+	require.Error(err)
+}
@@ -0,0 +1,106 @@
+package rewritemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	"github.com/ray31245/seo_cluster/pkg/util"
+	"github.com/ray31245/seo_cluster/service/rewrite_manager/model"
+)
+
+const (
+	MinSrcLength = 1000
+	MinArtLength = 500
+)
+
+var ErrSourceTooShort = errors.New("data is not complete")
+
+type (
+	RewriteF       func(string) (string, error)
+	ExtendRewriteF func(string) (string, error)
+	MakeTitleF     func(string) (string, error)
+)
+
+// RewriteWorkFlow rewrite the html article content with the given steps,
+// extend the article if it is too short after rewriting, then make a title for it
+func (r *RewriteManager) RewriteWorkFlow(
+	articleContent string,
+	rewriteF RewriteF,
+	extendRewriteF ExtendRewriteF,
+	makeTitleF MakeTitleF,
+	isGenImageList bool,
+) (model.RewriteResult, error) {
+	originalArticle, err := util.HTMLToMd(articleContent)
+	if err != nil {
+		return model.RewriteResult{}, fmt.Errorf("RewriteManager.RewriteWorkFlow: %w", err)
+	}
+
+	// check data
+	if utf8.RuneCount([]byte(originalArticle)) < MinSrcLength {
+		return model.RewriteResult{}, fmt.Errorf("RewriteManager.RewriteWorkFlow: %w", ErrSourceTooShort)
+	}
+
+	res := model.RewriteResult{}
+
+	art, err := rewriteF(originalArticle)
+	if err != nil {
+		return model.RewriteResult{}, fmt.Errorf("RewriteManager.RewriteWorkFlow: %w", err)
+	}
+
+	if utf8.RuneCountInString(art) < MinArtLength {
+		extArt, err := extendRewriteF(articleContent)
+		if err != nil {
+			return model.RewriteResult{}, fmt.Errorf("RewriteManager.RewriteWorkFlow: %w", err)
+		}
+
+		art = extArt
+	}
+
+	title, err := makeTitleF(art)
+	if err != nil {
+		return model.RewriteResult{}, fmt.Errorf("RewriteManager.RewriteWorkFlow: %w", err)
+	}
+
+	res.Title = title
+
+	art = string(util.MdToHTML([]byte(art)))
+
+	if isGenImageList {
+		imgDiv, err := util.GenImageListEncodeDiv([]byte(articleContent))
+		if err != nil {
+			slog.Error("RewriteWorkFlow: GenImageListEncodeDiv", logger.Err(err))
+		} else {
+			art += imgDiv
+		}
+	}
+
+	res.Content = art
+
+	return res, nil
+}
+
+// DefaultRewriteWorkFlow run RewriteWorkFlow with the default prompts
+func (r *RewriteManager) DefaultRewriteWorkFlow(ctx context.Context, articleContent string, isGenImageList bool) (model.RewriteResult, error) {
+	rewriteF := func(req string) (string, error) {
+		return r.DefaultRewriteUntil(ctx, []byte(req))
+	}
+
+	extendRewriteF := func(req string) (string, error) {
+		return r.DefaultExtendRewriteUntil(ctx, []byte(req))
+	}
+
+	makeTitleF := func(req string) (string, error) {
+		return r.DefaultMakeTitleUntil(ctx, req)
+	}
+
+	res, err := r.RewriteWorkFlow(articleContent, rewriteF, extendRewriteF, makeTitleF, isGenImageList)
+	if err != nil {
+		return model.RewriteResult{}, fmt.Errorf("RewriteManager.DefaultRewriteWorkFlow: %w", err)
+	}
+
+	return res, nil
+}
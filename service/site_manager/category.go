@@ -0,0 +1,141 @@
+package sitemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cmsDriverInterface "github.com/ray31245/seo_cluster/pkg/cms_driver/cms_driver_interface"
+	cmsModel "github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	webhookModel "github.com/ray31245/seo_cluster/service/webhook_manager/model"
+)
+
+var ErrCategoryNotFound = errors.New("category not found")
+
+// CreateSiteCategory create the category in CMS of site, then mirror the categories of site to local.
+// It return the local category of created one.
+func (s SiteManager) CreateSiteCategory(ctx context.Context, siteID string, name string) (dbModel.Category, error) {
+	site, client, err := s.siteClient(ctx, siteID)
+	if err != nil {
+		return dbModel.Category{}, fmt.Errorf("CreateSiteCategory: %w", err)
+	}
+
+	cate, err := client.CreateCategory(ctx, cmsModel.CreateCategoryArgs{Name: name})
+	if err != nil {
+		return dbModel.Category{}, fmt.Errorf("CreateSiteCategory: %w", err)
+	}
+
+	site, err = s.resyncCategories(ctx, site)
+	if err != nil {
+		return dbModel.Category{}, fmt.Errorf("CreateSiteCategory: %w", err)
+	}
+
+	for _, category := range site.Categories {
+		if category.CMSCategoryID == cate.ID {
+			return category, nil
+		}
+	}
+
+	return dbModel.Category{}, fmt.Errorf("CreateSiteCategory: %w", ErrCategoryNotFound)
+}
+
+// RenameSiteCategory rename the category in CMS of site, categoryID is the local id of category
+func (s SiteManager) RenameSiteCategory(ctx context.Context, siteID string, categoryID string, name string) error {
+	site, client, err := s.siteClient(ctx, siteID)
+	if err != nil {
+		return fmt.Errorf("RenameSiteCategory: %w", err)
+	}
+
+	category, err := findSiteCategory(site, categoryID)
+	if err != nil {
+		return fmt.Errorf("RenameSiteCategory: %w", err)
+	}
+
+	_, err = client.RenameCategory(ctx, category.CMSCategoryID, name)
+	if err != nil {
+		return fmt.Errorf("RenameSiteCategory: %w", err)
+	}
+
+	_, err = s.resyncCategories(ctx, site)
+	if err != nil {
+		return fmt.Errorf("RenameSiteCategory: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSiteCategory delete the category in CMS of site, categoryID is the local id of category
+func (s SiteManager) DeleteSiteCategory(ctx context.Context, siteID string, categoryID string) error {
+	site, client, err := s.siteClient(ctx, siteID)
+	if err != nil {
+		return fmt.Errorf("DeleteSiteCategory: %w", err)
+	}
+
+	category, err := findSiteCategory(site, categoryID)
+	if err != nil {
+		return fmt.Errorf("DeleteSiteCategory: %w", err)
+	}
+
+	err = client.DeleteCategory(ctx, category.CMSCategoryID)
+	if err != nil {
+		return fmt.Errorf("DeleteSiteCategory: %w", err)
+	}
+
+	_, err = s.resyncCategories(ctx, site)
+	if err != nil {
+		return fmt.Errorf("DeleteSiteCategory: %w", err)
+	}
+
+	return nil
+}
+
+// siteClient return the site with the client of its CMS
+func (s SiteManager) siteClient(ctx context.Context, siteID string) (*dbModel.Site, cmsDriverInterface.Client, error) {
+	site, err := s.siteDAO.GetSite(siteID)
+	if dbErr.IsNotfoundErr(err) {
+		return nil, nil, fmt.Errorf("siteClient: %w", errors.Join(ErrSiteNotFound, err))
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("siteClient: %w", err)
+	}
+
+	driver, err := s.cmsDrivers.Driver(site.CmsType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("siteClient: %w", err)
+	}
+
+	client, err := driver.GetClient(ctx, site.ID, cmsModel.NewSiteCredential(*site))
+	if err != nil {
+		return nil, nil, fmt.Errorf("siteClient: %w", err)
+	}
+
+	return site, client, nil
+}
+
+// resyncCategories mirror the categories of site after they are changed in CMS, and return the reloaded site
+func (s SiteManager) resyncCategories(ctx context.Context, site *dbModel.Site) (*dbModel.Site, error) {
+	err := s.syncCategoryFromSite(ctx, site)
+	if err != nil {
+		return nil, fmt.Errorf("resyncCategories: %w", err)
+	}
+
+	s.notify(webhookModel.EventSiteCategorySynced, *site)
+
+	site, err = s.siteDAO.GetSite(site.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("resyncCategories: %w", err)
+	}
+
+	return site, nil
+}
+
+func findSiteCategory(site *dbModel.Site, categoryID string) (dbModel.Category, error) {
+	for _, category := range site.Categories {
+		if category.ID.String() == categoryID {
+			return category, nil
+		}
+	}
+
+	return dbModel.Category{}, fmt.Errorf("findSiteCategory: %w", ErrCategoryNotFound)
+}
@@ -0,0 +1,174 @@
+package sitemanager
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	cmsdriver "github.com/ray31245/seo_cluster/pkg/cms_driver"
+	cmsDriverInterface "github.com/ray31245/seo_cluster/pkg/cms_driver/cms_driver_interface"
+	cmsModel "github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	"github.com/ray31245/seo_cluster/pkg/db"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCMS is a zblog driver keeping the categories of every site in memory
+type fakeCMS struct {
+	categories []cmsModel.Category
+	nextID     uint32
+	// created is the args of created categories
+	created []cmsModel.CreateCategoryArgs
+}
+
+func (f *fakeCMS) CMSType() dbModel.CMSType { return dbModel.CMSTypeZBlog }
+
+func (f *fakeCMS) NewClient(_ context.Context, _ cmsModel.Credential) (cmsDriverInterface.Client, error) {
+	return f, nil
+}
+
+func (f *fakeCMS) GetClient(_ context.Context, _ uuid.UUID, _ cmsModel.Credential) (cmsDriverInterface.Client, error) {
+	return f, nil
+}
+
+func (f *fakeCMS) UpdateClient(_ context.Context, _ uuid.UUID, _ cmsModel.Credential) (cmsDriverInterface.Client, error) {
+	return f, nil
+}
+
+func (f *fakeCMS) DeleteClient(_ uuid.UUID) {}
+
+func (f *fakeCMS) NewAnonymousClient(_ context.Context, _ string) cmsDriverInterface.Client { return f }
+
+func (f *fakeCMS) PublishCadence() cmsModel.PublishCadence { return cmsModel.PublishCadenceRandomCycle }
+
+func (f *fakeCMS) ExpectedDailyDemand() float64 { return 1 }
+
+func (f *fakeCMS) ListCategory(_ context.Context) ([]cmsModel.Category, error) {
+	return slices.Clone(f.categories), nil
+}
+
+func (f *fakeCMS) CreateCategory(_ context.Context, args cmsModel.CreateCategoryArgs) (cmsModel.Category, error) {
+	f.nextID++
+	cate := cmsModel.Category{ID: strconv.Itoa(int(f.nextID)), Name: args.Name}
+	f.categories = append(f.categories, cate)
+	f.created = append(f.created, args)
+
+	return cate, nil
+}
+
+func (f *fakeCMS) RenameCategory(_ context.Context, ID string, name string) (cmsModel.Category, error) {
+	for i := range f.categories {
+		if f.categories[i].ID == ID {
+			f.categories[i].Name = name
+
+			return f.categories[i], nil
+		}
+	}
+
+	return cmsModel.Category{}, fmt.Errorf("category %s not found", ID)
+}
+
+func (f *fakeCMS) DeleteCategory(_ context.Context, ID string) error {
+	f.categories = slices.DeleteFunc(f.categories, func(cate cmsModel.Category) bool { return cate.ID == ID })
+
+	return nil
+}
+
+func (f *fakeCMS) GetArticle(_ context.Context, _ string) (cmsModel.Article, error) {
+	return cmsModel.Article{}, nil
+}
+
+func (f *fakeCMS) ListArticle(_ context.Context) ([]cmsModel.Article, error) { return nil, nil }
+
+func (f *fakeCMS) PostArticle(_ context.Context, article cmsModel.Article) (cmsModel.Article, error) {
+	return article, nil
+}
+
+func (f *fakeCMS) UpdateArticleTags(_ context.Context, _ string, _ []cmsModel.Tag) error { return nil }
+
+func (f *fakeCMS) DeleteArticle(_ context.Context, _ string) error { return nil }
+
+func (f *fakeCMS) ListTagAll(_ context.Context) ([]cmsModel.Tag, error) { return nil, nil }
+
+func (f *fakeCMS) CreateTag(_ context.Context, name string) (cmsModel.Tag, error) {
+	return cmsModel.Tag{Name: name}, nil
+}
+
+func (f *fakeCMS) PostComment(_ context.Context, _ string, _ string) error { return nil }
+
+func newTestSiteManager(t *testing.T, cms *fakeCMS) *SiteManager {
+	t.Helper()
+
+	database, err := db.NewDB(filepath.Join(t.TempDir(), "site.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	siteDAO, err := database.NewSiteDAO()
+	require.NoError(t, err)
+
+	categoryTemplateDAO, err := database.NewCategoryTemplateDAO()
+	require.NoError(t, err)
+
+	return NewSiteManager(cmsdriver.NewRegistry(cms), siteDAO, categoryTemplateDAO, siteDAO)
+}
+
+func categoryNames(site *dbModel.Site) []string {
+	res := []string{}
+	for _, cate := range site.Categories {
+		res = append(res, cate.Name)
+	}
+
+	slices.Sort(res)
+
+	return res
+}
+
+func TestSiteManager_SiteCategory(t *testing.T) {
+	ctx := context.Background()
+	cms := &fakeCMS{}
+	_, err := cms.CreateCategory(ctx, cmsModel.CreateCategoryArgs{Name: "news"})
+	require.NoError(t, err)
+
+	s := newTestSiteManager(t, cms)
+	_, err = s.AddSite(ctx, string(dbModel.CMSTypeZBlog), "http://zblog.test", "admin", "admin", "", "", 0, "")
+	require.NoError(t, err)
+
+	sites, err := s.ListSites()
+	require.NoError(t, err)
+	require.Len(t, sites, 1)
+
+	siteID := sites[0].ID.String()
+
+	created, err := s.CreateSiteCategory(ctx, siteID, "tech")
+	require.NoError(t, err)
+	assert.Equal(t, "tech", created.Name)
+	assert.Equal(t, "2", created.CMSCategoryID)
+
+	site, err := s.GetSite(siteID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"news", "tech"}, categoryNames(site))
+
+	require.NoError(t, s.RenameSiteCategory(ctx, siteID, created.ID.String(), "technology"))
+
+	site, err = s.GetSite(siteID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"news", "technology"}, categoryNames(site))
+
+	require.NoError(t, s.DeleteSiteCategory(ctx, siteID, created.ID.String()))
+
+	site, err = s.GetSite(siteID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"news"}, categoryNames(site))
+	assert.Equal(t, []cmsModel.Category{{ID: "1", Name: "news"}}, cms.categories)
+
+	err = s.DeleteSiteCategory(ctx, siteID, created.ID.String())
+	require.ErrorIs(t, err, ErrCategoryNotFound)
+
+	_, err = s.CreateSiteCategory(ctx, uuid.NewString(), "tech")
+	require.ErrorIs(t, err, ErrSiteNotFound)
+}
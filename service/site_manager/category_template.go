@@ -0,0 +1,183 @@
+package sitemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cmsDriverInterface "github.com/ray31245/seo_cluster/pkg/cms_driver/cms_driver_interface"
+	cmsModel "github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+)
+
+var (
+	ErrCategoryTemplateNotFound = errors.New("category template not found")
+	ErrInvalidCategoryTemplate  = errors.New("invalid category template")
+)
+
+// ApplyTemplateResult report the names of categories created in CMS and the ones already existed
+type ApplyTemplateResult struct {
+	Created []string `json:"created"`
+	Existed []string `json:"existed"`
+}
+
+func (s SiteManager) CreateCategoryTemplate(name string, categories []dbModel.CategoryTemplateItem) (dbModel.CategoryTemplate, error) {
+	template := dbModel.CategoryTemplate{Name: name, Categories: categories}
+
+	err := validateCategoryTemplate(template)
+	if err != nil {
+		return dbModel.CategoryTemplate{}, fmt.Errorf("CreateCategoryTemplate: %w", err)
+	}
+
+	template, err = s.categoryTemplateDAO.CreateCategoryTemplate(&template)
+	if err != nil {
+		return dbModel.CategoryTemplate{}, fmt.Errorf("CreateCategoryTemplate: %w", err)
+	}
+
+	return template, nil
+}
+
+func (s SiteManager) GetCategoryTemplate(id string) (*dbModel.CategoryTemplate, error) {
+	template, err := s.categoryTemplateDAO.GetCategoryTemplate(id)
+	if dbErr.IsNotfoundErr(err) {
+		return nil, fmt.Errorf("GetCategoryTemplate: %w", errors.Join(ErrCategoryTemplateNotFound, err))
+	} else if err != nil {
+		return nil, fmt.Errorf("GetCategoryTemplate: %w", err)
+	}
+
+	return template, nil
+}
+
+func (s SiteManager) ListCategoryTemplates() ([]dbModel.CategoryTemplate, error) {
+	templates, err := s.categoryTemplateDAO.ListCategoryTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("ListCategoryTemplates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// UpdateCategoryTemplate rename the template and replace its categories
+func (s SiteManager) UpdateCategoryTemplate(id string, name string, categories []dbModel.CategoryTemplateItem) error {
+	template, err := s.GetCategoryTemplate(id)
+	if err != nil {
+		return fmt.Errorf("UpdateCategoryTemplate: %w", err)
+	}
+
+	template.Name = name
+	template.Categories = categories
+
+	err = validateCategoryTemplate(*template)
+	if err != nil {
+		return fmt.Errorf("UpdateCategoryTemplate: %w", err)
+	}
+
+	err = s.categoryTemplateDAO.UpdateCategoryTemplate(template)
+	if err != nil {
+		return fmt.Errorf("UpdateCategoryTemplate: %w", err)
+	}
+
+	return nil
+}
+
+func (s SiteManager) DeleteCategoryTemplate(id string) error {
+	err := s.categoryTemplateDAO.DeleteCategoryTemplate(id)
+	if dbErr.IsNotfoundErr(err) {
+		return fmt.Errorf("DeleteCategoryTemplate: %w", errors.Join(ErrCategoryTemplateNotFound, err))
+	} else if err != nil {
+		return fmt.Errorf("DeleteCategoryTemplate: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyCategoryTemplate create the categories of template missing in CMS of site by name, then mirror them to local
+func (s SiteManager) ApplyCategoryTemplate(ctx context.Context, siteID string, templateID string) (ApplyTemplateResult, error) {
+	template, err := s.GetCategoryTemplate(templateID)
+	if err != nil {
+		return ApplyTemplateResult{}, fmt.Errorf("ApplyCategoryTemplate: %w", err)
+	}
+
+	site, client, err := s.siteClient(ctx, siteID)
+	if err != nil {
+		return ApplyTemplateResult{}, fmt.Errorf("ApplyCategoryTemplate: %w", err)
+	}
+
+	res, err := applyCategoryTemplate(ctx, client, *template)
+
+	// the categories created before error are mirrored too
+	_, syncErr := s.resyncCategories(ctx, site)
+
+	err = errors.Join(err, syncErr)
+	if err != nil {
+		return res, fmt.Errorf("ApplyCategoryTemplate: %w", err)
+	}
+
+	return res, nil
+}
+
+// applyCategoryTemplate create the categories of template in order, parent is created before its children
+func applyCategoryTemplate(ctx context.Context, client cmsDriverInterface.Client, template dbModel.CategoryTemplate) (ApplyTemplateResult, error) {
+	res := ApplyTemplateResult{Created: []string{}, Existed: []string{}}
+
+	categories, err := client.ListCategory(ctx)
+	if err != nil {
+		return res, fmt.Errorf("applyCategoryTemplate: %w", err)
+	}
+
+	// cmsIDs map the name of category to its id in CMS
+	cmsIDs := make(map[string]string, len(categories))
+	for _, cate := range categories {
+		cmsIDs[cate.Name] = cate.ID
+	}
+
+	for _, item := range template.Categories {
+		if _, ok := cmsIDs[item.Name]; ok {
+			res.Existed = append(res.Existed, item.Name)
+
+			continue
+		}
+
+		cate, err := client.CreateCategory(ctx, cmsModel.CreateCategoryArgs{
+			Name:        item.Name,
+			Description: item.Description,
+			ParentID:    cmsIDs[item.Parent],
+		})
+		if err != nil {
+			return res, fmt.Errorf("applyCategoryTemplate: %w", err)
+		}
+
+		cmsIDs[item.Name] = cate.ID
+		res.Created = append(res.Created, item.Name)
+	}
+
+	return res, nil
+}
+
+// validateCategoryTemplate check the names of categories are unique and parent is placed before its children
+func validateCategoryTemplate(template dbModel.CategoryTemplate) error {
+	if template.Name == "" {
+		return fmt.Errorf("validateCategoryTemplate: %w: empty name", ErrInvalidCategoryTemplate)
+	}
+
+	seen := make(map[string]bool, len(template.Categories))
+
+	for _, item := range template.Categories {
+		if item.Name == "" {
+			return fmt.Errorf("validateCategoryTemplate: %w: empty category name", ErrInvalidCategoryTemplate)
+		}
+
+		if seen[item.Name] {
+			return fmt.Errorf("validateCategoryTemplate: %w: duplicate category %q", ErrInvalidCategoryTemplate, item.Name)
+		}
+
+		if item.Parent != "" && !seen[item.Parent] {
+			return fmt.Errorf("validateCategoryTemplate: %w: parent %q of %q is not placed before it", ErrInvalidCategoryTemplate, item.Parent, item.Name)
+		}
+
+		seen[item.Name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package sitemanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	cmsModel "github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiteManager_CategoryTemplate(t *testing.T) {
+	s := newTestSiteManager(t, &fakeCMS{})
+
+	template, err := s.CreateCategoryTemplate("blog", []dbModel.CategoryTemplateItem{
+		{Name: "news"},
+		{Name: "local", Parent: "news", Description: "local news"},
+	})
+	require.NoError(t, err)
+
+	got, err := s.GetCategoryTemplate(template.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "blog", got.Name)
+	require.Len(t, got.Categories, 2)
+	assert.Equal(t, "news", got.Categories[0].Name)
+	assert.Equal(t, "news", got.Categories[1].Parent)
+
+	require.NoError(t, s.UpdateCategoryTemplate(template.ID.String(), "blog2", []dbModel.CategoryTemplateItem{{Name: "tech"}}))
+
+	templates, err := s.ListCategoryTemplates()
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, "blog2", templates[0].Name)
+	require.Len(t, templates[0].Categories, 1)
+	assert.Equal(t, "tech", templates[0].Categories[0].Name)
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := s.CreateCategoryTemplate("", nil)
+		require.ErrorIs(t, err, ErrInvalidCategoryTemplate)
+
+		_, err = s.CreateCategoryTemplate("dup", []dbModel.CategoryTemplateItem{{Name: "a"}, {Name: "a"}})
+		require.ErrorIs(t, err, ErrInvalidCategoryTemplate)
+
+		// parent must be placed before its children
+		_, err = s.CreateCategoryTemplate("order", []dbModel.CategoryTemplateItem{{Name: "child", Parent: "parent"}, {Name: "parent"}})
+		require.ErrorIs(t, err, ErrInvalidCategoryTemplate)
+	})
+
+	require.NoError(t, s.DeleteCategoryTemplate(template.ID.String()))
+	require.ErrorIs(t, s.DeleteCategoryTemplate(template.ID.String()), ErrCategoryTemplateNotFound)
+}
+
+func TestSiteManager_ApplyCategoryTemplate(t *testing.T) {
+	ctx := context.Background()
+	cms := &fakeCMS{}
+	_, err := cms.CreateCategory(ctx, cmsModel.CreateCategoryArgs{Name: "news"})
+	require.NoError(t, err)
+
+	s := newTestSiteManager(t, cms)
+
+	template, err := s.CreateCategoryTemplate("blog", []dbModel.CategoryTemplateItem{
+		{Name: "news"},
+		{Name: "local", Parent: "news", Description: "local news"},
+		{Name: "tech"},
+	})
+	require.NoError(t, err)
+
+	t.Run("template is applied when site is added", func(t *testing.T) {
+		res, err := s.AddSite(ctx, string(dbModel.CMSTypeZBlog), "http://zblog.test", "admin", "admin", "", "", 0, template.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, ApplyTemplateResult{Created: []string{"local", "tech"}, Existed: []string{"news"}}, res)
+
+		// parent of local is the cms id of news
+		assert.Equal(t, cmsModel.CreateCategoryArgs{Name: "local", Description: "local news", ParentID: "1"}, cms.created[1])
+
+		sites, err := s.ListSites()
+		require.NoError(t, err)
+		require.Len(t, sites, 1)
+
+		site, err := s.GetSite(sites[0].ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"local", "news", "tech"}, categoryNames(site))
+	})
+
+	t.Run("applying again create nothing", func(t *testing.T) {
+		sites, err := s.ListSites()
+		require.NoError(t, err)
+
+		res, err := s.ApplyCategoryTemplate(ctx, sites[0].ID.String(), template.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, ApplyTemplateResult{Created: []string{}, Existed: []string{"news", "local", "tech"}}, res)
+	})
+
+	t.Run("template not found", func(t *testing.T) {
+		_, err := s.AddSite(ctx, string(dbModel.CMSTypeZBlog), "http://other.test", "admin", "admin", "", "", 0, uuid.NewString())
+		require.ErrorIs(t, err, ErrCategoryTemplateNotFound)
+
+		sites, err := s.ListSites()
+		require.NoError(t, err)
+		assert.Len(t, sites, 1)
+	})
+}
@@ -0,0 +1,210 @@
+package sitemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	cmsModel "github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+)
+
+var ErrCloneSameSite = errors.New("source and target site are the same")
+
+// CloneSitePlan is the difference between the configuration of source site and target site.
+// Tag policy and uncategorized name are global config shared by all sites, so they are not part of plan.
+type CloneSitePlan struct {
+	SourceSiteID string `json:"source_site_id"`
+	TargetSiteID string `json:"target_site_id"`
+	// Categories is the categories of source site to create in CMS of target site, matched by name
+	Categories ApplyTemplateResult `json:"categories"`
+	// Settings is the per-site settings changed on target site
+	Settings []ConfigChange `json:"settings"`
+	// RewriteProfile is the fields of rewrite profile changed on target site
+	RewriteProfile []ConfigChange `json:"rewrite_profile"`
+}
+
+// ConfigChange is a field of configuration changed from the value of target site to the one of source site
+type ConfigChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// PreviewCloneSite return the changes of CloneSite without applying them
+func (s SiteManager) PreviewCloneSite(ctx context.Context, sourceSiteID string, targetSiteID string) (CloneSitePlan, error) {
+	plan, _, err := s.planCloneSite(ctx, sourceSiteID, targetSiteID)
+	if err != nil {
+		return CloneSitePlan{}, fmt.Errorf("PreviewCloneSite: %w", err)
+	}
+
+	return plan, nil
+}
+
+// CloneSite copy the configuration of source site to target site.
+// The categories missing in target site are created in its CMS, the existing ones are kept.
+// Language and rewrite profile of target site are replaced by the ones of source site,
+// a source site without rewrite profile leaves the one of target site unchanged.
+// It return the applied plan.
+func (s SiteManager) CloneSite(ctx context.Context, sourceSiteID string, targetSiteID string) (CloneSitePlan, error) {
+	plan, source, err := s.planCloneSite(ctx, sourceSiteID, targetSiteID)
+	if err != nil {
+		return CloneSitePlan{}, fmt.Errorf("CloneSite: %w", err)
+	}
+
+	target, client, err := s.siteClient(ctx, targetSiteID)
+	if err != nil {
+		return plan, fmt.Errorf("CloneSite: %w", err)
+	}
+
+	plan.Categories, err = applyCategoryTemplate(ctx, client, source.template)
+
+	// the categories created before error are mirrored too
+	target, syncErr := s.resyncCategories(ctx, target)
+
+	err = errors.Join(err, syncErr)
+	if err != nil {
+		return plan, fmt.Errorf("CloneSite: %w", err)
+	}
+
+	if len(plan.Settings) != 0 {
+		target.Language = source.site.Language
+
+		err = s.siteDAO.UpdateSite(target)
+		if err != nil {
+			return plan, fmt.Errorf("CloneSite: %w", err)
+		}
+	}
+
+	if source.profile != nil && len(plan.RewriteProfile) != 0 {
+		profile := *source.profile
+		profile.Base = dbModel.Base{}
+		profile.SiteID = target.ID
+
+		err = s.siteRewriteProfileDAO.UpsertSiteRewriteProfile(&profile)
+		if err != nil {
+			return plan, fmt.Errorf("CloneSite: %w", err)
+		}
+	}
+
+	return plan, nil
+}
+
+// cloneSource is the configuration of source site to clone
+type cloneSource struct {
+	site *dbModel.Site
+	// template is the categories of source site
+	template dbModel.CategoryTemplate
+	// profile is nil if source site has no rewrite profile
+	profile *dbModel.SiteRewriteProfile
+}
+
+func (s SiteManager) planCloneSite(ctx context.Context, sourceSiteID string, targetSiteID string) (CloneSitePlan, cloneSource, error) {
+	if sourceSiteID == targetSiteID {
+		return CloneSitePlan{}, cloneSource{}, fmt.Errorf("planCloneSite: %w", ErrCloneSameSite)
+	}
+
+	sourceSite, err := s.GetSite(sourceSiteID)
+	if err != nil {
+		return CloneSitePlan{}, cloneSource{}, fmt.Errorf("planCloneSite: %w", err)
+	}
+
+	target, client, err := s.siteClient(ctx, targetSiteID)
+	if err != nil {
+		return CloneSitePlan{}, cloneSource{}, fmt.Errorf("planCloneSite: %w", err)
+	}
+
+	source := cloneSource{site: sourceSite, template: dbModel.CategoryTemplate{Name: sourceSite.URL}}
+	for _, cate := range sourceSite.Categories {
+		source.template.Categories = append(source.template.Categories, dbModel.CategoryTemplateItem{Name: cate.Name})
+	}
+
+	source.profile, err = s.siteRewriteProfile(sourceSiteID)
+	if err != nil {
+		return CloneSitePlan{}, cloneSource{}, fmt.Errorf("planCloneSite: %w", err)
+	}
+
+	targetProfile, err := s.siteRewriteProfile(targetSiteID)
+	if err != nil {
+		return CloneSitePlan{}, cloneSource{}, fmt.Errorf("planCloneSite: %w", err)
+	}
+
+	plan := CloneSitePlan{
+		SourceSiteID:   sourceSiteID,
+		TargetSiteID:   targetSiteID,
+		Categories:     ApplyTemplateResult{Created: []string{}, Existed: []string{}},
+		Settings:       []ConfigChange{},
+		RewriteProfile: []ConfigChange{},
+	}
+
+	categories, err := client.ListCategory(ctx)
+	if err != nil {
+		return CloneSitePlan{}, cloneSource{}, fmt.Errorf("planCloneSite: %w", err)
+	}
+
+	for _, item := range source.template.Categories {
+		if slices.ContainsFunc(categories, func(cate cmsModel.Category) bool { return cate.Name == item.Name }) {
+			plan.Categories.Existed = append(plan.Categories.Existed, item.Name)
+		} else {
+			plan.Categories.Created = append(plan.Categories.Created, item.Name)
+		}
+	}
+
+	if target.Language != sourceSite.Language {
+		plan.Settings = append(plan.Settings, ConfigChange{Field: "language", From: target.Language, To: sourceSite.Language})
+	}
+
+	if source.profile != nil {
+		plan.RewriteProfile = diffRewriteProfile(targetProfile, *source.profile)
+	}
+
+	return plan, source, nil
+}
+
+// siteRewriteProfile return nil if site has no rewrite profile
+func (s SiteManager) siteRewriteProfile(siteID string) (*dbModel.SiteRewriteProfile, error) {
+	profile, err := s.siteRewriteProfileDAO.GetSiteRewriteProfile(siteID)
+	if dbErr.IsNotfoundErr(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("siteRewriteProfile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// diffRewriteProfile list the fields of profile changed from target to source, nil target is regarded as empty profile
+func diffRewriteProfile(target *dbModel.SiteRewriteProfile, source dbModel.SiteRewriteProfile) []ConfigChange {
+	if target == nil {
+		target = &dbModel.SiteRewriteProfile{}
+	}
+
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"system_prompt", target.SystemPrompt, source.SystemPrompt},
+		{"prompt", target.Prompt, source.Prompt},
+		{"extend_system_prompt", target.ExtendSystemPrompt, source.ExtendSystemPrompt},
+		{"extend_prompt", target.ExtendPrompt, source.ExtendPrompt},
+		{"make_title_system_prompt", target.MakeTitleSystemPrompt, source.MakeTitleSystemPrompt},
+		{"make_title_prompt", target.MakeTitlePrompt, source.MakeTitlePrompt},
+		{"multi_sections_system_prompt", target.MultiSectionsSystemPrompt, source.MultiSectionsSystemPrompt},
+		{"tone", target.Tone, source.Tone},
+		{"target_length", strconv.Itoa(target.TargetLength), strconv.Itoa(source.TargetLength)},
+		{"is_rewrite_on_publish", strconv.FormatBool(target.IsRewriteOnPublish), strconv.FormatBool(source.IsRewriteOnPublish)},
+	}
+
+	res := []ConfigChange{}
+
+	for _, field := range fields {
+		if field.from != field.to {
+			res = append(res, ConfigChange{Field: field.name, From: field.from, To: field.to})
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,99 @@
+package sitemanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	cmsModel "github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiteManager_CloneSite(t *testing.T) {
+	ctx := context.Background()
+	cms := &fakeCMS{}
+	s := newTestSiteManager(t, cms)
+
+	_, err := cms.CreateCategory(ctx, cmsModel.CreateCategoryArgs{Name: "news"})
+	require.NoError(t, err)
+	_, err = cms.CreateCategory(ctx, cmsModel.CreateCategoryArgs{Name: "tech"})
+	require.NoError(t, err)
+
+	_, err = s.AddSite(ctx, string(dbModel.CMSTypeZBlog), "http://source.test", "admin", "admin", "", "en", 0, "")
+	require.NoError(t, err)
+
+	// the target site only has news in its CMS
+	cms.categories = cms.categories[:1]
+
+	_, err = s.AddSite(ctx, string(dbModel.CMSTypeZBlog), "http://target.test", "admin", "admin", "", "", 0, "")
+	require.NoError(t, err)
+
+	sites, err := s.ListSites()
+	require.NoError(t, err)
+	require.Len(t, sites, 2)
+
+	sourceID, targetID := sites[0].ID.String(), sites[1].ID.String()
+	if sites[0].URL != "http://source.test" {
+		sourceID, targetID = targetID, sourceID
+	}
+
+	require.NoError(t, s.siteRewriteProfileDAO.UpsertSiteRewriteProfile(&dbModel.SiteRewriteProfile{
+		SiteID: uuid.MustParse(sourceID), Prompt: "source prompt", TargetLength: 800,
+	}))
+
+	target, err := s.GetSite(targetID)
+	require.NoError(t, err)
+
+	wantPlan := CloneSitePlan{
+		SourceSiteID: sourceID,
+		TargetSiteID: targetID,
+		Categories:   ApplyTemplateResult{Created: []string{"tech"}, Existed: []string{"news"}},
+		Settings:     []ConfigChange{{Field: "language", From: target.Language, To: "en"}},
+		RewriteProfile: []ConfigChange{
+			{Field: "prompt", From: "", To: "source prompt"},
+			{Field: "target_length", From: "0", To: "800"},
+		},
+	}
+
+	t.Run("preview does not change target site", func(t *testing.T) {
+		plan, err := s.PreviewCloneSite(ctx, sourceID, targetID)
+		require.NoError(t, err)
+		assert.Equal(t, wantPlan, plan)
+		assert.Empty(t, cms.created[2:])
+
+		_, err = s.siteRewriteProfileDAO.GetSiteRewriteProfile(targetID)
+		require.Error(t, err)
+	})
+
+	t.Run("clone", func(t *testing.T) {
+		plan, err := s.CloneSite(ctx, sourceID, targetID)
+		require.NoError(t, err)
+		assert.Equal(t, wantPlan, plan)
+
+		target, err := s.GetSite(targetID)
+		require.NoError(t, err)
+		assert.Equal(t, "en", target.Language)
+		assert.Equal(t, []string{"news", "tech"}, categoryNames(target))
+
+		profile, err := s.siteRewriteProfileDAO.GetSiteRewriteProfile(targetID)
+		require.NoError(t, err)
+		assert.Equal(t, "source prompt", profile.Prompt)
+		assert.Equal(t, 800, profile.TargetLength)
+	})
+
+	t.Run("clone again change nothing", func(t *testing.T) {
+		plan, err := s.PreviewCloneSite(ctx, sourceID, targetID)
+		require.NoError(t, err)
+		assert.Empty(t, plan.Categories.Created)
+		assert.Empty(t, plan.Settings)
+		assert.Empty(t, plan.RewriteProfile)
+	})
+
+	t.Run("same site", func(t *testing.T) {
+		_, err := s.CloneSite(ctx, sourceID, sourceID)
+		require.ErrorIs(t, err, ErrCloneSameSite)
+	})
+}
@@ -4,141 +4,148 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
 
 	"github.com/google/uuid"
+	aiAssistModel "github.com/ray31245/seo_cluster/pkg/ai_assist/model"
+	cmsDriverInterface "github.com/ray31245/seo_cluster/pkg/cms_driver/cms_driver_interface"
+	cmsModel "github.com/ray31245/seo_cluster/pkg/cms_driver/model"
 	dbInterface "github.com/ray31245/seo_cluster/pkg/db/db_interface"
 	dbErr "github.com/ray31245/seo_cluster/pkg/db/error"
 	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
-	wordpressModel "github.com/ray31245/seo_cluster/pkg/wordpress_api/model"
-	wordpressInterface "github.com/ray31245/seo_cluster/pkg/wordpress_api/wordpress_interface"
-	zInterface "github.com/ray31245/seo_cluster/pkg/z_blog_api/z_blog_Interface"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	webhookModel "github.com/ray31245/seo_cluster/service/webhook_manager/model"
+	webhookInterface "github.com/ray31245/seo_cluster/service/webhook_manager/webhook_interface"
 )
 
 var (
 	ErrSiteNotFound        = errors.New("site not found")
 	ErrCategoryNumNotMatch = errors.New("category number is not match")
+	ErrLanguageNotSupport  = errors.New("language not support")
 )
 
 // SiteManager is a struct that contains the necessary information for the site manager service.
 type SiteManager struct {
-	zAPI         zInterface.ZBlogAPI
-	WordpressAPI wordpressInterface.WordpressAPI
-	siteDAO      dbInterface.SiteDAOInterface
+	cmsDrivers          cmsDriverInterface.Registry
+	siteDAO             dbInterface.SiteDAOInterface
+	categoryTemplateDAO dbInterface.CategoryTemplateDAOInterface
+	// siteRewriteProfileDAO is used to clone rewrite profile between sites
+	siteRewriteProfileDAO dbInterface.SiteRewriteProfileDAOInterface
+	notifier              webhookInterface.Notifier
 }
 
 // NewSiteManager is a constructor for SiteManager.
-func NewSiteManager(zAPI zInterface.ZBlogAPI, wordpressAPI wordpressInterface.WordpressAPI, siteDAO dbInterface.SiteDAOInterface) *SiteManager {
+func NewSiteManager(
+	cmsDrivers cmsDriverInterface.Registry,
+	siteDAO dbInterface.SiteDAOInterface,
+	categoryTemplateDAO dbInterface.CategoryTemplateDAOInterface,
+	siteRewriteProfileDAO dbInterface.SiteRewriteProfileDAOInterface,
+) *SiteManager {
 	return &SiteManager{
-		zAPI:         zAPI,
-		WordpressAPI: wordpressAPI,
-		siteDAO:      siteDAO,
+		cmsDrivers:            cmsDrivers,
+		siteDAO:               siteDAO,
+		categoryTemplateDAO:   categoryTemplateDAO,
+		siteRewriteProfileDAO: siteRewriteProfileDAO,
 	}
 }
 
-// AddSite is a method that adds a site to the site manager.
-func (s SiteManager) AddSite(ctx context.Context, cmsType string, urlStr string, userName string, password string, expectCategoryNum uint8) error {
-	var err error
-	if cmsType == string(dbModel.CMSTypeWordPress) {
-		err = s.addWordPressSite(ctx, urlStr, userName, password, expectCategoryNum)
-	} else if cmsType == string(dbModel.CMSTypeZBlog) {
-		err = s.addZBlogSite(ctx, urlStr, userName, password, expectCategoryNum)
-	} else {
-		err = errors.New("CMS type not support")
+// SetNotifier is a method that sets the notifier to emit site events, nil to disable.
+func (s *SiteManager) SetNotifier(notifier webhookInterface.Notifier) {
+	s.notifier = notifier
+}
+
+func (s SiteManager) notify(event webhookModel.Event, site dbModel.Site) {
+	if s.notifier == nil {
+		return
 	}
 
-	if err != nil {
-		return fmt.Errorf("AddSite: %w", err)
+	data := webhookModel.SiteData{SiteURL: site.URL, CMSType: string(site.CmsType)}
+	if site.ID != uuid.Nil {
+		data.SiteID = site.ID.String()
 	}
 
-	return nil
+	s.notifier.Notify(event, data)
 }
 
-func (s SiteManager) addZBlogSite(ctx context.Context, urlStr string, userName string, password string, expectCategoryNum uint8) error {
-	// check site is valid
-	client, err := s.zAPI.NewClient(ctx, urlStr, userName, password)
-	if err != nil {
-		return fmt.Errorf("AddSite: %w", err)
-	}
-
-	// list category of site
-	categories, err := client.ListCategory(ctx)
-	if err != nil {
-		return fmt.Errorf("AddSite: %w", err)
+// AddSite is a method that adds a site to the site manager.
+// authMode is the way to authenticate wordpress site, empty means basic auth.
+// language is the language of content published to the site, empty means the default language.
+// templateID is the category template applied to the site after it is added, empty means no template.
+func (s SiteManager) AddSite(
+	ctx context.Context, cmsType string, urlStr string, userName string, password string, authMode string, language string, expectCategoryNum uint8, templateID string,
+) (ApplyTemplateResult, error) {
+	if language == "" {
+		language = string(aiAssistModel.DefaultLanguage)
 	}
 
-	if expectCategoryNum != 0 && uint8(len(categories)) != expectCategoryNum {
-		return fmt.Errorf("AddSite: %w", ErrCategoryNumNotMatch)
+	if !aiAssistModel.IsValidLanguage(aiAssistModel.Language(language)) {
+		return ApplyTemplateResult{}, fmt.Errorf("AddSite: %w", ErrLanguageNotSupport)
 	}
 
-	// add site
-	site, err := s.siteDAO.CreateSite(&dbModel.Site{URL: urlStr, UserName: userName, Password: password, CmsType: dbModel.CMSTypeZBlog})
+	driver, err := s.cmsDrivers.Driver(dbModel.CMSType(cmsType))
 	if err != nil {
-		return fmt.Errorf("AddSite: %w", err)
+		return ApplyTemplateResult{}, fmt.Errorf("AddSite: %w", err)
 	}
 
-	// add category
-	var multiErr error
-
-	for _, cate := range categories {
-		cateID, err := strconv.Atoi(cate.ID)
+	// check template exist before the site is added
+	if templateID != "" {
+		_, err = s.GetCategoryTemplate(templateID)
 		if err != nil {
-			multiErr = errors.Join(multiErr, err)
-
-			continue
-		}
-
-		err = s.siteDAO.CreateCategory(&dbModel.Category{SiteID: site.ID, ZBlogID: uint32(cateID), Name: cate.Name})
-		if err != nil {
-			multiErr = errors.Join(multiErr, err)
+			return ApplyTemplateResult{}, fmt.Errorf("AddSite: %w", err)
 		}
 	}
 
-	if multiErr != nil {
-		return fmt.Errorf("AddSite: %w", multiErr)
-	}
-
-	return nil
-}
+	site := dbModel.Site{URL: urlStr, UserName: userName, Password: password, AuthMode: authMode, Language: language, CmsType: driver.CMSType()}
 
-func (s SiteManager) addWordPressSite(ctx context.Context, urlStr string, userName string, password string, expectCategoryNum uint8) error {
 	// check site is valid
-	client, err := s.WordpressAPI.NewClient(ctx, urlStr, userName, password)
+	client, err := driver.NewClient(ctx, cmsModel.NewSiteCredential(site))
 	if err != nil {
-		return fmt.Errorf("AddSite: %w", err)
+		return ApplyTemplateResult{}, fmt.Errorf("AddSite: %w", err)
 	}
 
 	// list category of site
-	categories, err := client.ListCategory(ctx, wordpressModel.ListCategoryArgs{})
+	categories, err := client.ListCategory(ctx)
 	if err != nil {
-		return fmt.Errorf("AddSite: %w", err)
+		return ApplyTemplateResult{}, fmt.Errorf("AddSite: %w", err)
 	}
 
 	if expectCategoryNum != 0 && uint8(len(categories)) != expectCategoryNum {
-		return fmt.Errorf("AddSite: %w", ErrCategoryNumNotMatch)
+		return ApplyTemplateResult{}, fmt.Errorf("AddSite: %w", ErrCategoryNumNotMatch)
 	}
 
 	// add site
-	site, err := s.siteDAO.CreateSite(&dbModel.Site{URL: urlStr, UserName: userName, Password: password, CmsType: dbModel.CMSTypeWordPress})
+	site, err = s.siteDAO.CreateSite(&site)
 	if err != nil {
-		return fmt.Errorf("AddSite: %w", err)
+		return ApplyTemplateResult{}, fmt.Errorf("AddSite: %w", err)
 	}
 
 	// add category
 	var multiErr error
 
 	for _, cate := range categories {
-		err = s.siteDAO.CreateCategory(&dbModel.Category{SiteID: site.ID, WordpressID: uint32(cate.ID), Name: cate.Name})
+		category := dbModel.Category{SiteID: site.ID, Name: cate.Name, CMSCategoryID: cate.ID}
+
+		err = s.siteDAO.CreateCategory(&category)
 		if err != nil {
 			multiErr = errors.Join(multiErr, err)
 		}
 	}
 
 	if multiErr != nil {
-		return fmt.Errorf("AddSite: %w", multiErr)
+		return ApplyTemplateResult{}, fmt.Errorf("AddSite: %w", multiErr)
 	}
 
-	return nil
+	s.notify(webhookModel.EventSiteAdded, site)
+
+	if templateID == "" {
+		return ApplyTemplateResult{}, nil
+	}
+
+	res, err := s.ApplyCategoryTemplate(ctx, site.ID.String(), templateID)
+	if err != nil {
+		return res, fmt.Errorf("AddSite: %w", err)
+	}
+
+	return res, nil
 }
 
 // DeleteSite is a method that deletes a site from the site manager.
@@ -150,10 +157,8 @@ func (s SiteManager) DeleteSite(siteID string) error {
 		return fmt.Errorf("DeleteSite: %w", err)
 	}
 
-	if site.CmsType == dbModel.CMSTypeWordPress {
-		s.WordpressAPI.DeleteClient(uuid.MustParse(siteID))
-	} else if site.CmsType == dbModel.CMSTypeZBlog {
-		s.zAPI.DeleteClient(uuid.MustParse(siteID))
+	if driver, err := s.cmsDrivers.Driver(site.CmsType); err == nil {
+		driver.DeleteClient(site.ID)
 	}
 
 	err = s.siteDAO.DeleteSite(siteID)
@@ -168,6 +173,8 @@ func (s SiteManager) DeleteSite(siteID string) error {
 		return fmt.Errorf("DeleteSite: %w", err)
 	}
 
+	s.notify(webhookModel.EventSiteDeleted, *site)
+
 	return nil
 }
 
@@ -194,7 +201,11 @@ func (s SiteManager) GetSite(siteID string) (*dbModel.Site, error) {
 }
 
 // Update site
-func (s SiteManager) UpdateSite(ctx context.Context, ID string, urlStr string, userName string, password string) error {
+func (s SiteManager) UpdateSite(ctx context.Context, ID string, urlStr string, userName string, password string, authMode string, language string) error {
+	if language != "" && !aiAssistModel.IsValidLanguage(aiAssistModel.Language(language)) {
+		return fmt.Errorf("UpdateSite: %w", ErrLanguageNotSupport)
+	}
+
 	site, err := s.siteDAO.GetSite(ID)
 	if dbErr.IsNotfoundErr(err) {
 		return fmt.Errorf("UpdateSite: %w", errors.Join(ErrSiteNotFound, err))
@@ -210,20 +221,27 @@ func (s SiteManager) UpdateSite(ctx context.Context, ID string, urlStr string, u
 		site.UserName = userName
 	}
 
-	if password != "" {
+	// the redacted password sent back from the json of site keep the stored one
+	if password != "" && password != dbModel.RedactedSecret {
 		site.Password = password
 	}
 
-	if site.CmsType == dbModel.CMSTypeWordPress {
-		_, err = s.WordpressAPI.UpdateClient(ctx, site.ID, site.URL, site.UserName, site.Password)
-		if err != nil {
-			return fmt.Errorf("UpdateSite: %w", err)
-		}
-	} else if site.CmsType == dbModel.CMSTypeZBlog {
-		_, err = s.zAPI.UpdateClient(ctx, site.ID, site.URL, site.UserName, site.Password)
-		if err != nil {
-			return fmt.Errorf("UpdateSite: %w", err)
-		}
+	if authMode != "" {
+		site.AuthMode = authMode
+	}
+
+	if language != "" {
+		site.Language = language
+	}
+
+	driver, err := s.cmsDrivers.Driver(site.CmsType)
+	if err != nil {
+		return fmt.Errorf("UpdateSite: %w", err)
+	}
+
+	_, err = driver.UpdateClient(ctx, site.ID, cmsModel.NewSiteCredential(*site))
+	if err != nil {
+		return fmt.Errorf("UpdateSite: %w", err)
 	}
 
 	err = s.siteDAO.UpdateSite(site)
@@ -233,6 +251,43 @@ func (s SiteManager) UpdateSite(ctx context.Context, ID string, urlStr string, u
 		return fmt.Errorf("UpdateSite: %w", err)
 	}
 
+	s.notify(webhookModel.EventSiteUpdated, *site)
+
+	return nil
+}
+
+// SetSiteHTTPOptions replace the http options of site overriding the default ones, zero options clear them.
+// The client of site is created again with the options before they are saved.
+func (s SiteManager) SetSiteHTTPOptions(ctx context.Context, siteID string, options httpclient.Options) error {
+	err := httpclient.Validate(options)
+	if err != nil {
+		return fmt.Errorf("SetSiteHTTPOptions: %w", err)
+	}
+
+	site, err := s.GetSite(siteID)
+	if err != nil {
+		return fmt.Errorf("SetSiteHTTPOptions: %w", err)
+	}
+
+	site.HTTPOptions = options
+
+	driver, err := s.cmsDrivers.Driver(site.CmsType)
+	if err != nil {
+		return fmt.Errorf("SetSiteHTTPOptions: %w", err)
+	}
+
+	_, err = driver.UpdateClient(ctx, site.ID, cmsModel.NewSiteCredential(*site))
+	if err != nil {
+		return fmt.Errorf("SetSiteHTTPOptions: %w", err)
+	}
+
+	err = s.siteDAO.UpdateSiteHTTPOptions(siteID, options)
+	if dbErr.IsNotfoundErr(err) {
+		return fmt.Errorf("SetSiteHTTPOptions: %w", errors.Join(ErrSiteNotFound, err))
+	} else if err != nil {
+		return fmt.Errorf("SetSiteHTTPOptions: %w", err)
+	}
+
 	return nil
 }
 
@@ -262,108 +317,43 @@ func (s SiteManager) SyncCategoryFromSite(ctx context.Context, siteID string) er
 		return fmt.Errorf("SyncCategoryFromSite: %w", err)
 	}
 
-	if site.CmsType == dbModel.CMSTypeWordPress {
-		err = s.SyncCategoryFromSiteWordpress(ctx, site)
-		if err != nil {
-			return fmt.Errorf("SyncCategoryFromSite: %w", err)
-		}
-	} else if site.CmsType == dbModel.CMSTypeZBlog {
-		err = s.SyncCategoryFromSiteZblog(ctx, site)
-		if err != nil {
-			return fmt.Errorf("SyncCategoryFromSite: %w", err)
-		}
-	} else {
-		return fmt.Errorf("SyncCategoryFromSite: CMS type not support")
+	err = s.syncCategoryFromSite(ctx, site)
+	if err != nil {
+		return fmt.Errorf("SyncCategoryFromSite: %w", err)
 	}
 
+	s.notify(webhookModel.EventSiteCategorySynced, *site)
+
 	return nil
 }
 
-func (s SiteManager) SyncCategoryFromSiteZblog(ctx context.Context, site *dbModel.Site) error {
-	client, err := s.zAPI.GetClient(ctx, site.ID, site.URL, site.UserName, site.Password)
+// syncCategoryFromSite mirror the categories of site in CMS to local
+func (s SiteManager) syncCategoryFromSite(ctx context.Context, site *dbModel.Site) error {
+	driver, err := s.cmsDrivers.Driver(site.CmsType)
 	if err != nil {
-		return fmt.Errorf("SyncCategoryFromSiteZblog: %w", err)
+		return fmt.Errorf("syncCategoryFromSite: %w", err)
 	}
 
-	zCategories, err := client.ListCategory(ctx)
+	client, err := driver.GetClient(ctx, site.ID, cmsModel.NewSiteCredential(*site))
 	if err != nil {
-		return fmt.Errorf("SyncCategoryFromSiteZblog: %w", err)
+		return fmt.Errorf("syncCategoryFromSite: %w", err)
 	}
 
-	realCategories := []dbModel.Category{}
-
-	for _, cate := range zCategories {
-		catID, err := strconv.Atoi(cate.ID)
-		if err != nil {
-			return fmt.Errorf("SyncCategoryFromSiteZblog: %w", err)
-		}
-
-		realCategories = append(realCategories, dbModel.Category{SiteID: site.ID, ZBlogID: uint32(catID), Name: cate.Name})
-	}
-
-	currentCategories := site.Categories
-
-	getCMSID := func(category dbModel.Category) uint32 {
-		return category.ZBlogID
-	}
-
-	createCategory := func(category dbModel.Category) error {
-		err := s.siteDAO.CreateCategory(&category)
-		if err != nil {
-			return fmt.Errorf("createCategoryCallBack: %w", err)
-		}
-
-		return nil
-	}
-
-	updateCategory := func(category dbModel.Category) error {
-		err := s.siteDAO.UpdateCategory(&category)
-		if err != nil {
-			return fmt.Errorf("updateCategoryCallBack: %w", err)
-		}
-
-		return nil
-	}
-
-	deleteCategory := func(id string) error {
-		err := s.siteDAO.DeleteCategory(id)
-		if err != nil {
-			return fmt.Errorf("deleteCategoryCallBack: %w", err)
-		}
-
-		return nil
-	}
-
-	err = syncCategories(realCategories, currentCategories, getCMSID, createCategory, updateCategory, deleteCategory)
+	cmsCategories, err := client.ListCategory(ctx)
 	if err != nil {
-		return fmt.Errorf("SyncCategoryFromSiteZblog: %w", err)
+		return fmt.Errorf("syncCategoryFromSite: %w", err)
 	}
 
-	return nil
-}
-
-func (s SiteManager) SyncCategoryFromSiteWordpress(ctx context.Context, site *dbModel.Site) error {
-	client, err := s.WordpressAPI.GetClient(ctx, site.ID, site.URL, site.UserName, site.Password)
-	if err != nil {
-		return fmt.Errorf("SyncCategoryFromSiteWordpress: %w", err)
-	}
+	realCategories := []dbModel.Category{}
 
-	wordpressCategories, err := client.ListCategory(ctx, wordpressModel.ListCategoryArgs{})
-	if err != nil {
-		return fmt.Errorf("SyncCategoryFromSiteWordpress: %w", err)
-	}
+	for _, cate := range cmsCategories {
+		category := dbModel.Category{SiteID: site.ID, Name: cate.Name, CMSCategoryID: cate.ID}
 
-	categories := []dbModel.Category{}
-	for _, cate := range wordpressCategories {
-		categories = append(categories, dbModel.Category{SiteID: site.ID, WordpressID: uint32(cate.ID), Name: cate.Name})
+		realCategories = append(realCategories, category)
 	}
 
 	currentCategories := site.Categories
 
-	getCMSID := func(category dbModel.Category) uint32 {
-		return category.WordpressID
-	}
-
 	createCategory := func(category dbModel.Category) error {
 		err := s.siteDAO.CreateCategory(&category)
 		if err != nil {
@@ -391,24 +381,28 @@ func (s SiteManager) SyncCategoryFromSiteWordpress(ctx context.Context, site *db
 		return nil
 	}
 
-	err = syncCategories(categories, currentCategories, getCMSID, createCategory, updateCategory, deleteCategory)
+	err = syncCategories(realCategories, currentCategories, getCMSCategoryID, createCategory, updateCategory, deleteCategory)
 	if err != nil {
-		return fmt.Errorf("SyncCategoryFromSiteWordpress: %w", err)
+		return fmt.Errorf("syncCategoryFromSite: %w", err)
 	}
 
 	return nil
 }
 
+func getCMSCategoryID(category dbModel.Category) string {
+	return category.CMSCategoryID
+}
+
 func syncCategories(
-	realCategories []dbModel.Category, currentCategories []dbModel.Category, getCMSID func(dbModel.Category) uint32,
+	realCategories []dbModel.Category, currentCategories []dbModel.Category, getCMSID func(dbModel.Category) string,
 	createCategory func(dbModel.Category) error, updateCategory func(dbModel.Category) error, deleteCategory func(string) error,
 ) error {
-	expectedCategories := map[uint32]dbModel.Category{}
+	expectedCategories := map[string]dbModel.Category{}
 	for _, cate := range realCategories {
 		expectedCategories[getCMSID(cate)] = cate
 	}
 
-	currentCategoriesMap := map[uint32]dbModel.Category{}
+	currentCategoriesMap := map[string]dbModel.Category{}
 	for _, cate := range currentCategories {
 		currentCategoriesMap[getCMSID(cate)] = cate
 	}
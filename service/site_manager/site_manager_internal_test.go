@@ -0,0 +1,158 @@
+package sitemanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	cmsdriver "github.com/ray31245/seo_cluster/pkg/cms_driver"
+	cmsModel "github.com/ray31245/seo_cluster/pkg/cms_driver/model"
+	"github.com/ray31245/seo_cluster/pkg/db"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	httpclient "github.com/ray31245/seo_cluster/pkg/http_client"
+	"github.com/ray31245/seo_cluster/pkg/secret"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiteManager_SetSiteHTTPOptions(t *testing.T) {
+	ctx := context.Background()
+	s := newTestSiteManager(t, &fakeCMS{})
+
+	_, err := s.AddSite(ctx, string(dbModel.CMSTypeZBlog), "http://zblog.test", "admin", "admin", "", "", 0, "")
+	require.NoError(t, err)
+
+	sites, err := s.ListSites()
+	require.NoError(t, err)
+	require.Len(t, sites, 1)
+
+	siteID := sites[0].ID.String()
+	options := httpclient.Options{TimeoutSeconds: 10, UserAgent: "seo-cluster", Headers: map[string]string{"X-Site": "a"}}
+
+	require.NoError(t, s.SetSiteHTTPOptions(ctx, siteID, options))
+
+	site, err := s.GetSite(siteID)
+	require.NoError(t, err)
+	assert.Equal(t, options, site.HTTPOptions)
+
+	// zero options clear the ones of site
+	require.NoError(t, s.SetSiteHTTPOptions(ctx, siteID, httpclient.Options{}))
+
+	site, err = s.GetSite(siteID)
+	require.NoError(t, err)
+	assert.Equal(t, httpclient.Options{}, site.HTTPOptions)
+
+	err = s.SetSiteHTTPOptions(ctx, siteID, httpclient.Options{Proxy: "not a proxy"})
+	require.ErrorIs(t, err, httpclient.ErrInvalidProxy)
+}
+
+func TestSiteManager_EncryptedPassword(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "site.db")
+
+	newKeyring := func(primaryID string, IDs ...string) *secret.Keyring {
+		keys := map[string][]byte{}
+		for _, ID := range IDs {
+			keys[ID] = bytes.Repeat([]byte(ID[:1]), secret.KeySize)
+		}
+
+		keyring, err := secret.NewKeyring(primaryID, keys)
+		require.NoError(t, err)
+
+		return keyring
+	}
+
+	newSiteDAO := func(keyring *secret.Keyring) *db.SiteDAO {
+		database, err := db.NewDB(path)
+		require.NoError(t, err)
+		t.Cleanup(func() { database.Close() })
+
+		if keyring != nil {
+			database.UseSecretKeyring(keyring)
+		}
+
+		siteDAO, err := database.NewSiteDAO()
+		require.NoError(t, err)
+
+		return siteDAO
+	}
+
+	// the site added before encryption is enabled is kept as plaintext
+	plainDAO := newSiteDAO(nil)
+	_, err := plainDAO.CreateSite(&dbModel.Site{URL: "http://plain.test", Password: "plain", CmsType: dbModel.CMSTypeZBlog})
+	require.NoError(t, err)
+
+	cms := &fakeCMS{}
+	_, err = cms.CreateCategory(ctx, cmsModel.CreateCategoryArgs{Name: "news"})
+	require.NoError(t, err)
+
+	siteDAO := newSiteDAO(newKeyring("old", "old"))
+	s := NewSiteManager(cmsdriver.NewRegistry(cms), siteDAO, nil, siteDAO)
+
+	_, err = s.AddSite(ctx, string(dbModel.CMSTypeZBlog), "http://zblog.test", "admin", "secret password", "", "", 0, "")
+	require.NoError(t, err)
+
+	site := findSite(t, s, "http://zblog.test")
+	assert.Equal(t, "secret password", site.Password)
+	assert.Equal(t, "old", site.PasswordKeyID)
+	require.NotEmpty(t, site.Categories)
+
+	category, err := siteDAO.GetCategory(site.Categories[0].ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "secret password", category.Site.Password)
+
+	assert.Equal(t, "plain", findSite(t, s, "http://plain.test").Password)
+
+	require.NoError(t, s.UpdateSite(ctx, site.ID.String(), "", "", "changed password", "", ""))
+	assert.Equal(t, "changed password", findSite(t, s, "http://zblog.test").Password)
+
+	res, err := json.Marshal(site)
+	require.NoError(t, err)
+	assert.NotContains(t, string(res), "changed password")
+	assert.Contains(t, string(res), dbModel.RedactedSecret)
+
+	// the site sent back with the redacted password keep the password
+	require.NoError(t, s.UpdateSite(ctx, site.ID.String(), "", "", dbModel.RedactedSecret, "", ""))
+	assert.Equal(t, "changed password", findSite(t, s, "http://zblog.test").Password)
+
+	t.Run("password is not readable without key", func(t *testing.T) {
+		_, err := newSiteDAO(nil).ListSites()
+		require.ErrorIs(t, err, secret.ErrKeyNotConfigured)
+	})
+
+	t.Run("rotate", func(t *testing.T) {
+		count, err := newSiteDAO(newKeyring("new", "old", "new")).RotateSiteSecrets()
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		sites, err := newSiteDAO(newKeyring("new", "new")).ListSites()
+		require.NoError(t, err)
+		require.Len(t, sites, 2)
+
+		for _, site := range sites {
+			assert.Equal(t, "new", site.PasswordKeyID)
+		}
+	})
+}
+
+func findSite(t *testing.T, s *SiteManager, urlStr string) *dbModel.Site {
+	t.Helper()
+
+	sites, err := s.ListSites()
+	require.NoError(t, err)
+
+	for _, site := range sites {
+		if site.URL == urlStr {
+			res, err := s.GetSite(site.ID.String())
+			require.NoError(t, err)
+
+			return res
+		}
+	}
+
+	require.FailNow(t, "site not found", urlStr)
+
+	return nil
+}
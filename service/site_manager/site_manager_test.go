@@ -16,7 +16,7 @@ func Test_syncCategories(t *testing.T) {
 	type args struct {
 		realCategories    []dbModel.Category
 		currentCategories []dbModel.Category
-		getCMSID          func(dbModel.Category) uint32
+		getCMSID          func(dbModel.Category) string
 	}
 	tests := []struct {
 		name           string
@@ -30,31 +30,31 @@ func Test_syncCategories(t *testing.T) {
 			name: "normal",
 			args: args{
 				realCategories: []dbModel.Category{
-					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001")}, Name: "category1", ZBlogID: 1},
-					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002")}, Name: "category2", ZBlogID: 3},
-					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000003")}, Name: "category3", ZBlogID: 5},
-					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000004")}, Name: "category4", ZBlogID: 7},
-					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000005")}, Name: "category5", ZBlogID: 9},
+					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001")}, Name: "category1", CMSCategoryID: "1"},
+					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002")}, Name: "category2", CMSCategoryID: "3"},
+					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000003")}, Name: "category3", CMSCategoryID: "5"},
+					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000004")}, Name: "category4", CMSCategoryID: "7"},
+					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000005")}, Name: "category5", CMSCategoryID: "9"},
 				},
 				currentCategories: []dbModel.Category{
-					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001")}, Name: "category10", ZBlogID: 1},
-					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002")}, Name: "category2", ZBlogID: 2},
-					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000003")}, Name: "category3", ZBlogID: 4},
-					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000004")}, Name: "category4", ZBlogID: 6},
-					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000005")}, Name: "category5", ZBlogID: 8},
+					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001")}, Name: "category10", CMSCategoryID: "1"},
+					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002")}, Name: "category2", CMSCategoryID: "2"},
+					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000003")}, Name: "category3", CMSCategoryID: "4"},
+					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000004")}, Name: "category4", CMSCategoryID: "6"},
+					{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000005")}, Name: "category5", CMSCategoryID: "8"},
 				},
-				getCMSID: func(category dbModel.Category) uint32 {
-					return category.ZBlogID
+				getCMSID: func(category dbModel.Category) string {
+					return category.CMSCategoryID
 				},
 			},
 			expectedCreate: []dbModel.Category{
-				{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002")}, Name: "category2", ZBlogID: 3},
-				{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000003")}, Name: "category3", ZBlogID: 5},
-				{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000004")}, Name: "category4", ZBlogID: 7},
-				{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000005")}, Name: "category5", ZBlogID: 9},
+				{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002")}, Name: "category2", CMSCategoryID: "3"},
+				{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000003")}, Name: "category3", CMSCategoryID: "5"},
+				{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000004")}, Name: "category4", CMSCategoryID: "7"},
+				{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000005")}, Name: "category5", CMSCategoryID: "9"},
 			},
 			expectedUpdate: []dbModel.Category{
-				{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001")}, Name: "category10", ZBlogID: 1},
+				{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001")}, Name: "category10", CMSCategoryID: "1"},
 			},
 			expectedDelete: []dbModel.Category{
 				{Base: dbModel.Base{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002")}},
@@ -92,13 +92,13 @@ func Test_syncCategories(t *testing.T) {
 			}
 
 			for _, v := range gotCreate {
-				log.Printf("gotCreate ID: %s Name: %s CMSID: %d", v.ID, v.Name, tt.args.getCMSID(v))
+				log.Printf("gotCreate ID: %s Name: %s CMSID: %s", v.ID, v.Name, tt.args.getCMSID(v))
 			}
 			for _, v := range gotDelete {
-				log.Printf("gotDelete ID: %s Name: %s CMSID: %d", v.ID, v.Name, tt.args.getCMSID(v))
+				log.Printf("gotDelete ID: %s Name: %s CMSID: %s", v.ID, v.Name, tt.args.getCMSID(v))
 			}
 			for _, v := range gotUpdate {
-				log.Printf("gotUpdate ID: %s Name: %s CMSID: %d", v.ID, v.Name, tt.args.getCMSID(v))
+				log.Printf("gotUpdate ID: %s Name: %s CMSID: %s", v.ID, v.Name, tt.args.getCMSID(v))
 			}
 
 			assert.Equal(len(tt.expectedCreate), len(gotCreate))
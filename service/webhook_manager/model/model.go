@@ -0,0 +1,87 @@
+package model
+
+import "time"
+
+type Event string
+
+const (
+	EventPing               Event = "ping"
+	EventArticlePublished   Event = "article.published"
+	EventArticlePublishFail Event = "article.publish_failed"
+	EventArticleCacheLow    Event = "article_cache.low"
+	EventInventoryLow       Event = "inventory.low"
+	EventRewriteFailed      Event = "rewrite.failed"
+	EventSiteAdded          Event = "site.added"
+	EventSiteUpdated        Event = "site.updated"
+	EventSiteDeleted        Event = "site.deleted"
+	EventSiteCategorySynced Event = "site.category_synced"
+)
+
+var Events = []Event{
+	EventPing,
+	EventArticlePublished,
+	EventArticlePublishFail,
+	EventArticleCacheLow,
+	EventInventoryLow,
+	EventRewriteFailed,
+	EventSiteAdded,
+	EventSiteUpdated,
+	EventSiteDeleted,
+	EventSiteCategorySynced,
+}
+
+func IsValidEvent(event string) bool {
+	for _, e := range Events {
+		if string(e) == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Payload struct {
+	ID        string    `json:"id"`
+	Event     Event     `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type ArticlePublishedData struct {
+	SiteID    string `json:"site_id"`
+	SiteURL   string `json:"site_url"`
+	CateID    string `json:"cate_id"`
+	ArticleID string `json:"article_id"`
+	Title     string `json:"title"`
+}
+
+type ArticlePublishFailData struct {
+	SiteID  string `json:"site_id"`
+	SiteURL string `json:"site_url"`
+	CateID  string `json:"cate_id"`
+	Title   string `json:"title"`
+	Error   string `json:"error"`
+}
+
+type ArticleCacheLowData struct {
+	CacheCount int `json:"cache_count"`
+	LackCount  int `json:"lack_count"`
+}
+
+type InventoryLowData struct {
+	Stock          int64   `json:"stock"`
+	DemandPerDay   float64 `json:"demand_per_day"`
+	DaysUntilEmpty float64 `json:"days_until_empty"`
+	ThresholdDays  int     `json:"threshold_days"`
+}
+
+type RewriteFailedData struct {
+	Operation string `json:"operation"`
+	Error     string `json:"error"`
+}
+
+type SiteData struct {
+	SiteID  string `json:"site_id"`
+	SiteURL string `json:"site_url"`
+	CMSType string `json:"cms_type,omitempty"`
+}
@@ -0,0 +1,7 @@
+package webhookinterface
+
+import "github.com/ray31245/seo_cluster/service/webhook_manager/model"
+
+type Notifier interface {
+	Notify(event model.Event, data any)
+}
@@ -0,0 +1,359 @@
+package webhookmanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	dbInterface "github.com/ray31245/seo_cluster/pkg/db/db_interface"
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/pkg/logger"
+	"github.com/ray31245/seo_cluster/service/webhook_manager/model"
+)
+
+const (
+	HeaderEvent     = "X-Seo-Cluster-Event"
+	HeaderDelivery  = "X-Seo-Cluster-Delivery"
+	HeaderSignature = "X-Seo-Cluster-Signature"
+	HeaderTimestamp = "X-Seo-Cluster-Timestamp"
+
+	signaturePrefix = "sha256="
+
+	defaultMaxAttempts  = 5
+	defaultRetryDelay   = 2 * time.Second
+	defaultTimeout      = 10 * time.Second
+	maxResponseBodySize = 1024
+
+	// DefaultTolerance is the max age of timestamp accepted by Verify, older deliveries are treated as replay
+	DefaultTolerance = 5 * time.Minute
+)
+
+var (
+	ErrInvalidEvent = errors.New("invalid event")
+	ErrInvalidURL   = errors.New("invalid url")
+
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrExpiredTimestamp = errors.New("expired timestamp")
+)
+
+type WebhookManager struct {
+	dao         dbInterface.WebhookDAOInterface
+	httpClient  *http.Client
+	maxAttempts int
+	retryDelay  time.Duration
+}
+
+func NewWebhookManager(dao dbInterface.WebhookDAOInterface) *WebhookManager {
+	return &WebhookManager{
+		dao:         dao,
+		httpClient:  &http.Client{Timeout: defaultTimeout},
+		maxAttempts: defaultMaxAttempts,
+		retryDelay:  defaultRetryDelay,
+	}
+}
+
+func (w *WebhookManager) CreateWebhook(name, url, secret string, events []string, isActive bool) (dbModel.Webhook, error) {
+	err := validateWebhook(url, events)
+	if err != nil {
+		return dbModel.Webhook{}, fmt.Errorf("CreateWebhook: %w", err)
+	}
+
+	if secret == "" {
+		secret, err = generateSecret()
+		if err != nil {
+			return dbModel.Webhook{}, fmt.Errorf("CreateWebhook: %w", err)
+		}
+	}
+
+	webhook, err := w.dao.CreateWebhook(&dbModel.Webhook{
+		Name:     name,
+		URL:      url,
+		Secret:   secret,
+		Events:   strings.Join(events, ","),
+		IsActive: isActive,
+	})
+	if err != nil {
+		return dbModel.Webhook{}, fmt.Errorf("CreateWebhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (w *WebhookManager) UpdateWebhook(id, name, url, secret string, events []string, isActive bool) error {
+	err := validateWebhook(url, events)
+	if err != nil {
+		return fmt.Errorf("UpdateWebhook: %w", err)
+	}
+
+	webhook, err := w.dao.GetWebhook(id)
+	if err != nil {
+		return fmt.Errorf("UpdateWebhook: %w", err)
+	}
+
+	webhook.Name = name
+	webhook.URL = url
+	webhook.Events = strings.Join(events, ",")
+	webhook.IsActive = isActive
+
+	// keep the old secret if not provided
+	if secret != "" {
+		webhook.Secret = secret
+	}
+
+	err = w.dao.UpdateWebhook(webhook)
+	if err != nil {
+		return fmt.Errorf("UpdateWebhook: %w", err)
+	}
+
+	return nil
+}
+
+func (w *WebhookManager) DeleteWebhook(id string) error {
+	err := w.dao.DeleteWebhook(id)
+	if err != nil {
+		return fmt.Errorf("DeleteWebhook: %w", err)
+	}
+
+	return nil
+}
+
+func (w *WebhookManager) GetWebhook(id string) (*dbModel.Webhook, error) {
+	webhook, err := w.dao.GetWebhook(id)
+	if err != nil {
+		return nil, fmt.Errorf("GetWebhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (w *WebhookManager) ListWebhooks() ([]dbModel.Webhook, error) {
+	webhooks, err := w.dao.ListWebhooks()
+	if err != nil {
+		return nil, fmt.Errorf("ListWebhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (w *WebhookManager) ListDeliveries(webhookID string, page int, limit int) ([]dbModel.WebhookDelivery, int, int64, error) {
+	deliveries, totalPage, totalRows, err := w.dao.ListWebhookDeliveriesPaginator(webhookID, page, limit)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("ListDeliveries: %w", err)
+	}
+
+	return deliveries, totalPage, totalRows, nil
+}
+
+// TestDelivery send a ping event to the webhook synchronously and return the delivery log
+func (w *WebhookManager) TestDelivery(ctx context.Context, id string) (dbModel.WebhookDelivery, error) {
+	webhook, err := w.dao.GetWebhook(id)
+	if err != nil {
+		return dbModel.WebhookDelivery{}, fmt.Errorf("TestDelivery: %w", err)
+	}
+
+	delivery, err := w.deliver(ctx, *webhook, model.EventPing, map[string]string{"message": "this is a test delivery"}, 1)
+	if err != nil {
+		return delivery, fmt.Errorf("TestDelivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// Notify deliver the event to all active webhooks which subscribe it, in background
+func (w *WebhookManager) Notify(event model.Event, data any) {
+	webhooks, err := w.dao.ListActiveWebhooks()
+	if err != nil {
+		slog.Error("Notify", slog.String("event", string(event)), logger.Err(err))
+
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !isSubscribed(webhook, event) {
+			continue
+		}
+
+		go func(webhook dbModel.Webhook) {
+			_, err := w.deliver(context.Background(), webhook, event, data, w.maxAttempts)
+			if err != nil {
+				slog.Error("Notify", slog.String("webhook_id", webhook.ID.String()), slog.String("event", string(event)), logger.Err(err))
+			}
+		}(webhook)
+	}
+}
+
+func (w *WebhookManager) deliver(ctx context.Context, webhook dbModel.Webhook, event model.Event, data any, maxAttempts int) (dbModel.WebhookDelivery, error) {
+	delivery := dbModel.WebhookDelivery{
+		Base:      dbModel.Base{ID: uuid.New()},
+		WebhookID: webhook.ID,
+		Event:     string(event),
+	}
+
+	body, err := json.Marshal(model.Payload{
+		ID:        delivery.ID.String(),
+		Event:     event,
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		return delivery, fmt.Errorf("deliver: %w", err)
+	}
+
+	delivery.Payload = string(body)
+
+	err = w.dao.CreateWebhookDelivery(&delivery)
+	if err != nil {
+		return delivery, fmt.Errorf("deliver: %w", err)
+	}
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		delivery.Attempts = attempt
+
+		statusCode, respBody, sendErr := w.send(ctx, webhook, event, delivery.ID.String(), body)
+		delivery.StatusCode = statusCode
+		delivery.ResponseBody = respBody
+		delivery.IsSuccess = sendErr == nil
+		delivery.Error = ""
+
+		if sendErr != nil {
+			delivery.Error = sendErr.Error()
+		}
+
+		err = w.dao.UpdateWebhookDelivery(&delivery)
+		if err != nil {
+			slog.ErrorContext(ctx, "deliver", slog.String("webhook_id", webhook.ID.String()), logger.Err(err))
+		}
+
+		if sendErr == nil {
+			return delivery, nil
+		}
+
+		if attempt == maxAttempts {
+			break
+		}
+
+		// exponential backoff
+		select {
+		case <-ctx.Done():
+			return delivery, fmt.Errorf("deliver: %w", ctx.Err())
+		case <-time.After(w.retryDelay * time.Duration(1<<(attempt-1))):
+		}
+	}
+
+	return delivery, fmt.Errorf("deliver: %s", delivery.Error)
+}
+
+func (w *WebhookManager) send(ctx context.Context, webhook dbModel.Webhook, event model.Event, deliveryID string, body []byte) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", fmt.Errorf("send: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(event))
+	req.Header.Set(HeaderDelivery, deliveryID)
+	// timestamp is refreshed every attempt, so a retry is not rejected by receiver as replay
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("send: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	if err != nil {
+		return resp.StatusCode, "", fmt.Errorf("send: %w", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, string(respBody), fmt.Errorf("send: unexpected status code %s", strconv.Itoa(resp.StatusCode))
+	}
+
+	return resp.StatusCode, string(respBody), nil
+}
+
+// Sign return the HMAC-SHA256 signature of "<timestamp>.<body>", timestamp is the value of timestamp header.
+// Receivers should compare it with the signature header and reject old timestamp, see Verify
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify check the signature and timestamp headers of a delivery,
+// the delivery is rejected if its timestamp is not within tolerance of now
+func Verify(secret string, timestamp string, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Verify: %w: %w", ErrInvalidSignature, err)
+	}
+
+	if now.Sub(time.Unix(unix, 0)).Abs() > tolerance {
+		return fmt.Errorf("Verify: %w", ErrExpiredTimestamp)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return fmt.Errorf("Verify: %w", ErrInvalidSignature)
+	}
+
+	return nil
+}
+
+func isSubscribed(webhook dbModel.Webhook, event model.Event) bool {
+	// empty events means subscribe all events
+	if webhook.Events == "" {
+		return true
+	}
+
+	for _, e := range strings.Split(webhook.Events, ",") {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateWebhook(url string, events []string) error {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return ErrInvalidURL
+	}
+
+	for _, event := range events {
+		if !model.IsValidEvent(event) {
+			return fmt.Errorf("%w: %s", ErrInvalidEvent, event)
+		}
+	}
+
+	return nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("generateSecret: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
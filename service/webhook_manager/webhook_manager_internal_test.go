@@ -0,0 +1,183 @@
+package webhookmanager
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	dbModel "github.com/ray31245/seo_cluster/pkg/db/model"
+	"github.com/ray31245/seo_cluster/service/webhook_manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWebhookDAO struct {
+	lock       sync.Mutex
+	deliveries map[string]dbModel.WebhookDelivery
+}
+
+func (m *mockWebhookDAO) CreateWebhook(webhook *dbModel.Webhook) (dbModel.Webhook, error) {
+	return *webhook, nil
+}
+
+func (m *mockWebhookDAO) GetWebhook(_ string) (*dbModel.Webhook, error) {
+	return &dbModel.Webhook{}, nil
+}
+
+func (m *mockWebhookDAO) ListWebhooks() ([]dbModel.Webhook, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookDAO) ListActiveWebhooks() ([]dbModel.Webhook, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookDAO) UpdateWebhook(_ *dbModel.Webhook) error {
+	return nil
+}
+
+func (m *mockWebhookDAO) DeleteWebhook(_ string) error {
+	return nil
+}
+
+func (m *mockWebhookDAO) CreateWebhookDelivery(delivery *dbModel.WebhookDelivery) error {
+	return m.UpdateWebhookDelivery(delivery)
+}
+
+func (m *mockWebhookDAO) UpdateWebhookDelivery(delivery *dbModel.WebhookDelivery) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.deliveries[delivery.ID.String()] = *delivery
+
+	return nil
+}
+
+func (m *mockWebhookDAO) ListWebhookDeliveriesPaginator(_ string, _ int, _ int) ([]dbModel.WebhookDelivery, int, int64, error) {
+	return nil, 0, 0, nil
+}
+
+func TestWebhookManager_deliver(t *testing.T) {
+	t.Parallel()
+
+	const secret = "test_secret"
+
+	tests := []struct {
+		name         string
+		failTimes    int32
+		maxAttempts  int
+		wantErr      bool
+		wantAttempts int
+	}{
+		{
+			name:         "success at first attempt",
+			failTimes:    0,
+			maxAttempts:  3,
+			wantAttempts: 1,
+		},
+		{
+			name:         "success after retry",
+			failTimes:    2,
+			maxAttempts:  3,
+			wantAttempts: 3,
+		},
+		{
+			name:         "fail after max attempts",
+			failTimes:    5,
+			maxAttempts:  3,
+			wantErr:      true,
+			wantAttempts: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+			require := require.New(t)
+
+			var calls atomic.Int32
+
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil || Verify(secret, r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, DefaultTolerance, time.Now()) != nil {
+					http.Error(w, "invalid signature", http.StatusUnauthorized)
+
+					return
+				}
+
+				if calls.Add(1) <= tt.failTimes {
+					http.Error(w, "temporary error", http.StatusInternalServerError)
+
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			t.Cleanup(func() { svr.Close() })
+
+			dao := &mockWebhookDAO{deliveries: map[string]dbModel.WebhookDelivery{}}
+			w := NewWebhookManager(dao)
+			w.retryDelay = time.Millisecond
+
+			delivery, err := w.deliver(context.Background(), dbModel.Webhook{URL: svr.URL, Secret: secret}, model.EventPing, nil, tt.maxAttempts)
+			if tt.wantErr {
+				require.Error(err)
+			} else {
+				require.NoError(err)
+			}
+
+			assert.Equal(tt.wantAttempts, delivery.Attempts)
+			assert.Equal(!tt.wantErr, delivery.IsSuccess)
+			assert.Equal(delivery, dao.deliveries[delivery.ID.String()])
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	const secret = "secret"
+
+	body := []byte(`{"event":"ping"}`)
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := Sign(secret, timestamp, body)
+
+	require.NoError(t, Verify(secret, timestamp, signature, body, DefaultTolerance, now))
+	require.ErrorIs(t, Verify("other", timestamp, signature, body, DefaultTolerance, now), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, timestamp, signature, []byte(`{"event":"site.deleted"}`), DefaultTolerance, now), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, "invalid", signature, body, DefaultTolerance, now), ErrInvalidSignature)
+
+	// timestamp is signed, so a replay can not refresh it
+	newTimestamp := strconv.FormatInt(now.Add(time.Hour).Unix(), 10)
+	require.ErrorIs(t, Verify(secret, newTimestamp, signature, body, DefaultTolerance, now.Add(time.Hour)), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, timestamp, signature, body, DefaultTolerance, now.Add(time.Hour)), ErrExpiredTimestamp)
+}
+
+func Test_isSubscribed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		events string
+		event  model.Event
+		want   bool
+	}{
+		{name: "empty events subscribe all", events: "", event: model.EventSiteAdded, want: true},
+		{name: "subscribed", events: "site.added,site.deleted", event: model.EventSiteDeleted, want: true},
+		{name: "not subscribed", events: "site.added", event: model.EventArticlePublished, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, isSubscribed(dbModel.Webhook{Events: tt.events}, tt.event))
+		})
+	}
+}